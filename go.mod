@@ -5,7 +5,10 @@ go 1.12
 require (
 	github.com/DATA-DOG/go-sqlmock v1.3.3
 	github.com/asecurityteam/component-httpclient v0.2.0
+	github.com/asecurityteam/component-log v0.2.0
 	github.com/asecurityteam/component-producer/v2 v2.0.1
+	github.com/asecurityteam/component-stat v0.3.0
+	github.com/asecurityteam/logevent v1.4.0
 	github.com/asecurityteam/runhttp v0.4.0
 	github.com/asecurityteam/serverfull v0.4.0
 	github.com/asecurityteam/settings v0.4.0
@@ -14,8 +17,13 @@ require (
 	github.com/gobuffalo/packr/v2 v2.2.0
 	github.com/golang/mock v1.5.0
 	github.com/google/uuid v1.2.0
+	github.com/graphql-go/graphql v0.8.1
 	github.com/karrick/godirwalk v1.10.12 // indirect
 	github.com/lib/pq v1.9.0
 	github.com/pkg/errors v0.9.1
+	github.com/rs/xstats v0.0.0-20170813190920-c67367528e16
 	github.com/stretchr/testify v1.7.0
+	google.golang.org/grpc v1.43.0
+	google.golang.org/protobuf v1.27.1
+	gopkg.in/yaml.v3 v3.0.0-20210107192922-496545a6307b
 )
@@ -4,28 +4,73 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"net/http"
 	"os"
+	"strings"
+	"time"
 
+	log "github.com/asecurityteam/component-log"
 	producer "github.com/asecurityteam/component-producer/v2"
+	stat "github.com/asecurityteam/component-stat"
+	"github.com/asecurityteam/ipam-facade/pkg/assetcache"
 	"github.com/asecurityteam/ipam-facade/pkg/assetfetcher"
+	"github.com/asecurityteam/ipam-facade/pkg/assetgraph"
 	"github.com/asecurityteam/ipam-facade/pkg/assetstorer"
 	"github.com/asecurityteam/ipam-facade/pkg/dependencycheck"
 	"github.com/asecurityteam/ipam-facade/pkg/domain"
+	"github.com/asecurityteam/ipam-facade/pkg/filesource"
+	"github.com/asecurityteam/ipam-facade/pkg/grpcserver"
 	v1 "github.com/asecurityteam/ipam-facade/pkg/handlers/v1"
+	"github.com/asecurityteam/ipam-facade/pkg/infoblox"
 	"github.com/asecurityteam/ipam-facade/pkg/ipamfetcher"
+	"github.com/asecurityteam/ipam-facade/pkg/ipammerge"
+	"github.com/asecurityteam/ipam-facade/pkg/ipamvalidator"
+	"github.com/asecurityteam/ipam-facade/pkg/memstore"
+	"github.com/asecurityteam/ipam-facade/pkg/netbox"
+	"github.com/asecurityteam/ipam-facade/pkg/prefixlist"
+	"github.com/asecurityteam/ipam-facade/pkg/qualityanalyzer"
 	"github.com/asecurityteam/ipam-facade/pkg/sqldb"
 	"github.com/asecurityteam/ipam-facade/pkg/uuidgenerator"
+	"github.com/asecurityteam/runhttp"
 	"github.com/asecurityteam/serverfull"
 	"github.com/asecurityteam/settings"
 )
 
+const (
+	device42Source = "device42"
+	netBoxSource   = "netbox"
+	infobloxSource = "infoblox"
+	fileSource     = "file"
+
+	postgresStorage = "postgres"
+	memoryStorage   = "memory"
+
+	replaceSync = "replace"
+	swapSync    = "swap"
+
+	defaultSwapLockTimeout = 5 * time.Second
+)
+
 type config struct {
-	LambdaMode     bool   `description:"Use the Lambda SDK to start the system."`
-	LambdaFunction string `description:"the lambda function that should be called when running in LAMBDAMODE=true"`
-	Producer       *producer.Config
-	Postgres       *sqldb.PostgresConfig
-	Device42       *ipamfetcher.Device42ClientConfig
-	PageSize       int
+	LambdaMode       bool   `description:"Use the Lambda SDK to start the system."`
+	LambdaFunction   string `description:"the lambda function that should be called when running in LAMBDAMODE=true"`
+	Producer         *producer.Config
+	Storage          string        `description:"Where synced assets are stored, either postgres or memory."`
+	SyncMode         string        `description:"How a sync replaces the assets stored in postgres: replace deletes and inserts them in one transaction, while swap loads them into new tables and swaps those in, keeping the replaced tables for rollback."`
+	SwapLockTimeout  time.Duration `description:"How long a swap waits for the lookups reading the tables it replaces, or 0 to wait for as long as they take."`
+	Postgres         *sqldb.PostgresConfig
+	Memory           *memstore.Config
+	Cache            *assetcache.Config
+	GRPC             *grpcserver.Config
+	GraphQL          *assetgraph.Config
+	Source           string `description:"Comma-delimited list of the IPAM data sources to sync from, from the highest to the lowest precedence. Any of: device42, netbox, infoblox, file."`
+	Device42         *ipamfetcher.Device42ClientConfig
+	NetBox           *netbox.Config
+	Infoblox         *infoblox.Config
+	File             *filesource.Config
+	Merge            *ipammerge.Config
+	PageSize         int
+	InheritOwnership bool `description:"Inherit the ownership of the nearest ancestor subnet when the matched subnet has no customer."`
 }
 
 func (*config) Name() string {
@@ -35,16 +80,39 @@ func (*config) Name() string {
 type component struct {
 	Producer *producer.Component
 	Postgres *sqldb.PostgresComponent
+	Memory   *memstore.Component
+	Cache    *assetcache.Component
+	GRPC     *grpcserver.Component
+	GraphQL  *assetgraph.Component
 	Device42 *ipamfetcher.Device42ClientComponent
+	NetBox   *netbox.Component
+	Infoblox *infoblox.Component
+	File     *filesource.Component
+	Merge    *ipammerge.Component
+	// ownerResolver is loaded separately from the "Contact" settings group so that
+	// the established CONTACT_* environment variables keep working.
+	ownerResolver ipamfetcher.OwnerResolver
 }
 
 func (c *component) Settings() *config {
 	return &config{
-		LambdaMode: false,
-		Producer:   c.Producer.Settings(),
-		Postgres:   c.Postgres.Settings(),
-		Device42:   c.Device42.Settings(),
-		PageSize:   100,
+		LambdaMode:      false,
+		Producer:        c.Producer.Settings(),
+		Storage:         postgresStorage,
+		SyncMode:        replaceSync,
+		SwapLockTimeout: defaultSwapLockTimeout,
+		Postgres:        c.Postgres.Settings(),
+		Memory:          c.Memory.Settings(),
+		Cache:           c.Cache.Settings(),
+		GRPC:            c.GRPC.Settings(),
+		GraphQL:         c.GraphQL.Settings(),
+		Source:          device42Source,
+		Device42:        c.Device42.Settings(),
+		NetBox:          c.NetBox.Settings(),
+		Infoblox:        c.Infoblox.Settings(),
+		File:            c.File.Settings(),
+		Merge:           c.Merge.Settings(),
+		PageSize:        100,
 	}
 }
 
@@ -52,7 +120,15 @@ func newComponent() *component {
 	return &component{
 		Producer: producer.NewComponent(),
 		Postgres: sqldb.NewPostgresComponent(),
+		Memory:   memstore.NewComponent(),
+		Cache:    assetcache.NewComponent(),
+		GRPC:     grpcserver.NewComponent(),
+		GraphQL:  assetgraph.NewComponent(),
 		Device42: ipamfetcher.NewDevice42ClientComponent(),
+		NetBox:   netbox.NewComponent(),
+		Infoblox: infoblox.NewComponent(),
+		File:     filesource.NewComponent(),
+		Merge:    ipammerge.NewComponent(),
 	}
 }
 
@@ -67,57 +143,100 @@ func (c *component) New(ctx context.Context, conf *config) (func(context.Context
 		LogFn:         domain.LoggerFromContext,
 	}
 
-	pgdb, err := c.Postgres.New(ctx, conf.Postgres)
+	store, err := c.newStorage(ctx, conf)
 	if err != nil {
 		return nil, err
 	}
 
-	dc, err := c.Device42.New(ctx, conf.Device42)
+	cache, err := c.Cache.New(ctx, conf.Cache)
 	if err != nil {
 		return nil, err
 	}
+	cache.Wrapped = store.assetFetcher
+	cache.Generations = store.syncGenerations
 
-	deviceFetcher := ipamfetcher.NewDevice42DeviceFetcher(dc)
-	subnetFetcher := ipamfetcher.NewDevice42SubnetFetcher(dc)
-	customerFetcher := ipamfetcher.NewDevice42CustomerFetcher(dc)
-	ipamDataFetcher := &ipamfetcher.Client{
-		CustomerFetcher: customerFetcher,
-		DeviceFetcher:   deviceFetcher,
-		SubnetFetcher:   subnetFetcher,
+	ipamDataFetcher, sourceChecks, err := c.newSources(ctx, conf, store)
+	if err != nil {
+		return nil, err
 	}
 
-	assetFetcher := &assetfetcher.PostgresPhysicalAssetFetcher{DB: pgdb}
 	fetchHandler := &v1.FetchByIPAddressHandler{
 		LogFn:                domain.LoggerFromContext,
-		PhysicalAssetFetcher: assetFetcher,
+		PhysicalAssetFetcher: cache,
 	}
 	fetchPageHandler := &v1.FetchPageHandler{
 		LogFn:           domain.LoggerFromContext,
-		Fetcher:         assetFetcher,
+		Fetcher:         store.assetFetcher,
 		DefaultPageSize: conf.PageSize,
 	}
-	assetStorer := &assetstorer.PostgresPhysicalAssetStorer{DB: pgdb}
 	syncHandler := &v1.SyncIPAMDataHandler{
 		IPAMDataFetcher:     ipamDataFetcher,
+		IPAMDataValidator:   &ipamvalidator.RecordValidator{LogFn: domain.LoggerFromContext},
 		LogFn:               domain.LoggerFromContext,
-		PhysicalAssetStorer: assetStorer,
+		PhysicalAssetStorer: store.assetStorer,
+		QualityAnalyzer:     &qualityanalyzer.IPAMDataAnalyzer{},
+		QualityReportStorer: store.qualityReportStore,
+		// the cache is invalidated even when disabled here, for the instances that enable it
+		AssetCacheInvalidator: cache,
+		SyncLocker:            store.syncLocker,
+	}
+	qualityReportHandler := &v1.QualityReportHandler{
+		LogFn:                domain.LoggerFromContext,
+		QualityReportFetcher: store.qualityReportStore,
+	}
+	syncJobHandler := &v1.SyncJobHandler{
+		LogFn:                domain.LoggerFromContext,
+		SyncJobReportFetcher: store.qualityReportStore,
+	}
+
+	assetGraphQuerier, err := c.GraphQL.New(ctx, conf.GraphQL)
+	if err != nil {
+		return nil, err
+	}
+	assetGraphQuerier.Fetcher = store.assetGraph
+	assetGraphQuerier.LogFn = domain.LoggerFromContext
+	assetGraphQuerier.DefaultPageSize = conf.PageSize
+	graphQLHandler := &v1.GraphQLHandler{
+		LogFn:             domain.LoggerFromContext,
+		AssetGraphQuerier: assetGraphQuerier,
+	}
+
+	grpcServer, err := c.GRPC.New(ctx, conf.GRPC)
+	if err != nil {
+		return nil, err
+	}
+	grpcServer.FetchByIPAddressHandler = fetchHandler
+	grpcServer.FetchPageHandler = fetchPageHandler
+	grpcServer.EnqueueHandler = enqueueHandler
+
+	exportHandler := &v1.ExportHandler{
+		LogFn:         domain.LoggerFromContext,
+		AssetExporter: store.assetExporter,
+	}
+	prefixListHandler := &v1.PrefixListHandler{
+		LogFn:         domain.LoggerFromContext,
+		PrefixFetcher: &prefixlist.Fetcher{Graph: store.assetGraph},
 	}
 
 	dependencyCheckHandler := &v1.DependencyCheckHandler{
 		DependencyChecker: &dependencycheck.MultiDependencyCheck{
-			DependencyCheckList: []domain.DependencyCheck{pgdb, dc},
+			DependencyCheckList: append(store.checks, sourceChecks...),
 		},
 	}
 
 	handlers := map[string]serverfull.Function{
-		"fetchbyip":        serverfull.NewFunction(fetchHandler.Handle),
-		"sync":             serverfull.NewFunction(syncHandler.Handle),
-		"enqueue":          serverfull.NewFunction(enqueueHandler.Handle),
-		"fetchIPs":         serverfull.NewFunction(fetchPageHandler.FetchIPs),
-		"fetchNextIPs":     serverfull.NewFunction(fetchPageHandler.FetchNextIPs),
-		"fetchSubnets":     serverfull.NewFunction(fetchPageHandler.FetchSubnets),
-		"fetchNextSubnets": serverfull.NewFunction(fetchPageHandler.FetchNextSubnets),
-		"dependencycheck":  serverfull.NewFunction(dependencyCheckHandler.Handle),
+		"fetchbyip":          serverfull.NewFunction(fetchHandler.Handle),
+		"sync":               serverfull.NewFunction(syncHandler.Handle),
+		"syncWithResult":     serverfull.NewFunction(syncHandler.Sync),
+		"fetchSyncJob":       serverfull.NewFunction(syncJobHandler.Handle),
+		"enqueue":            serverfull.NewFunction(enqueueHandler.Handle),
+		"fetchIPs":           serverfull.NewFunction(fetchPageHandler.FetchIPs),
+		"fetchNextIPs":       serverfull.NewFunction(fetchPageHandler.FetchNextIPs),
+		"fetchSubnets":       serverfull.NewFunction(fetchPageHandler.FetchSubnets),
+		"fetchNextSubnets":   serverfull.NewFunction(fetchPageHandler.FetchNextSubnets),
+		"fetchQualityReport": serverfull.NewFunction(qualityReportHandler.Handle),
+		"graphql":            serverfull.NewFunction(graphQLHandler.Handle),
+		"dependencycheck":    serverfull.NewFunction(dependencyCheckHandler.Handle),
 	}
 
 	fetcher := &serverfull.StaticFetcher{Functions: handlers}
@@ -127,10 +246,307 @@ func (c *component) New(ctx context.Context, conf *config) (func(context.Context
 		}, nil
 	}
 	return func(ctx context.Context, source settings.Source) error {
-		return serverfull.StartHTTP(ctx, source, fetcher)
+		if conf.GRPC.Address != "" {
+			stop, err := startGRPC(ctx, source, grpcServer)
+			if err != nil {
+				return err
+			}
+			defer stop()
+		}
+		return startHTTP(ctx, source, fetcher, map[string]http.Handler{
+			"/v1/export/{kind}":            exportHandler,
+			"/v1/export/prefixes/{format}": prefixListHandler,
+		})
 	}, nil
 }
 
+// startHTTP runs the HTTP API as serverfull.StartHTTP does, adding GET routes for the export
+// handlers, which stream their responses or respond with other content than JSON rather than
+// returning a JSON document from a function.
+func startHTTP(ctx context.Context, source settings.Source, fetcher serverfull.Fetcher, exports map[string]http.Handler) error {
+	router := serverfull.NewRouter(&serverfull.RouterConfig{Fetcher: fetcher})
+	for pattern, handler := range exports {
+		router.Method(http.MethodGet, pattern, handler)
+	}
+	runtime := new(runhttp.Runtime)
+	err := settings.NewComponent(
+		ctx,
+		&settings.PrefixSource{Source: source, Prefix: []string{"serverfull"}},
+		runhttp.NewComponent().WithHandler(router),
+		runtime,
+	)
+	if err != nil {
+		return err
+	}
+	return runtime.Run()
+}
+
+// startGRPC starts the gRPC listener, logging and emitting metrics as configured for the
+// HTTP runtime.
+func startGRPC(ctx context.Context, source settings.Source, server *grpcserver.Server) (func(), error) {
+	runtimeSource := &settings.PrefixSource{Source: source, Prefix: []string{"serverfull", "runtime"}}
+	logger, err := log.New(ctx, runtimeSource)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := stat.New(ctx, runtimeSource)
+	if err != nil {
+		return nil, err
+	}
+	server.Logger = logger
+	server.Stat = stats
+	return server.Start()
+}
+
+// storage holds the implementations of the configured storage backend. Backends that may
+// be shared by several instances track the sync generation, so that every instance can
+// invalidate its cached lookups, and keep the state of incremental syncs, so that every
+// instance carries on from the last stored sync, with the stored records of a source that
+// incremental syncs merge their changes into.
+type storage struct {
+	assetFetcher       domain.Fetcher
+	assetGraph         domain.AssetGraphFetcher
+	assetExporter      domain.AssetExporter
+	assetStorer        domain.PhysicalAssetStorer
+	qualityReportStore qualityReportStore
+	syncGenerations    domain.SyncGenerationStore
+	incrementalState   domain.IncrementalSyncStateStore
+	storedSources      domain.StoredSourceFetcher
+	syncLocker         domain.SyncLocker
+	checks             []domain.DependencyCheck
+}
+
+type qualityReportStore interface {
+	domain.QualityReportStorer
+	domain.QualityReportFetcher
+	domain.SyncJobReportFetcher
+}
+
+// newStorage constructs the configured storage backend, along with its dependency checks.
+// The in-memory backend has no dependencies.
+func (c *component) newStorage(ctx context.Context, conf *config) (storage, error) {
+	switch strings.ToLower(strings.TrimSpace(conf.Storage)) {
+	case postgresStorage:
+		syncMode := strings.ToLower(strings.TrimSpace(conf.SyncMode))
+		if syncMode != replaceSync && syncMode != swapSync {
+			return storage{}, fmt.Errorf("unknown sync mode %q, expected replace or swap", conf.SyncMode)
+		}
+		if conf.SwapLockTimeout < 0 {
+			return storage{}, fmt.Errorf("the swap lock timeout must not be negative, got %s", conf.SwapLockTimeout)
+		}
+		pgdb, err := c.Postgres.New(ctx, conf.Postgres)
+		if err != nil {
+			return storage{}, err
+		}
+		var assetStorer domain.PhysicalAssetStorer = &assetstorer.PostgresPhysicalAssetStorer{DB: pgdb}
+		if syncMode == swapSync {
+			assetStorer = &assetstorer.PostgresSwapPhysicalAssetStorer{DB: pgdb, LockTimeout: conf.SwapLockTimeout}
+		}
+		return storage{
+			assetFetcher: &assetfetcher.PostgresPhysicalAssetFetcher{
+				DB:               pgdb,
+				InheritOwnership: conf.InheritOwnership,
+			},
+			assetGraph:         &assetfetcher.PostgresAssetGraphFetcher{DB: pgdb},
+			assetExporter:      &assetfetcher.PostgresAssetExporter{DB: pgdb},
+			assetStorer:        assetStorer,
+			qualityReportStore: &qualityanalyzer.PostgresQualityReportStore{DB: pgdb},
+			syncGenerations:    &assetcache.PostgresSyncGenerationStore{DB: pgdb},
+			incrementalState:   pgdb,
+			storedSources:      pgdb,
+			syncLocker:         pgdb,
+			checks:             []domain.DependencyCheck{pgdb},
+		}, nil
+	case memoryStorage:
+		store, err := c.Memory.New(ctx, conf.Memory)
+		if err != nil {
+			return storage{}, err
+		}
+		store.InheritOwnership = conf.InheritOwnership
+		return storage{
+			assetFetcher:       store,
+			assetGraph:         store,
+			assetExporter:      store,
+			assetStorer:        store,
+			qualityReportStore: &memstore.QualityReportStore{},
+			syncLocker:         store,
+			checks:             []domain.DependencyCheck{},
+		}, nil
+	default:
+		return storage{}, fmt.Errorf("unknown storage %q", conf.Storage)
+	}
+}
+
+// newSources constructs an IPAMDataFetcher that merges the configured IPAM data sources,
+// along with the dependency checks of their clients.
+func (c *component) newSources(ctx context.Context, conf *config, store storage) (domain.IPAMDataFetcher, []domain.DependencyCheck, error) {
+	precedence, err := c.Merge.New(ctx, conf.Merge)
+	if err != nil {
+		return nil, nil, err
+	}
+	names := make([]string, 0)
+	for _, name := range strings.Split(conf.Source, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	if len(names) > 1 {
+		// the stored records of merged sources are renumbered, so they cannot be used as the
+		// records of any one source
+		store.storedSources = nil
+	}
+	sources := make([]ipammerge.Source, 0, len(names))
+	checks := make([]domain.DependencyCheck, 0, len(names))
+	for _, name := range names {
+		fetcher, check, err := c.newSource(ctx, conf, store, name)
+		if err != nil {
+			return nil, nil, err
+		}
+		sources = append(sources, ipammerge.Source{Name: name, Fetcher: fetcher})
+		checks = append(checks, check)
+	}
+	fetcher, err := ipammerge.NewIPAMDataFetcher(sources, precedence, domain.LoggerFromContext)
+	if err != nil {
+		return nil, nil, err
+	}
+	return fetcher, checks, nil
+}
+
+// newSource constructs the IPAMDataFetcher for a single IPAM data source along
+// with the dependency check of its client. Incremental syncs keep their state in the
+// storage backend when it can, and otherwise in memory, and merge their changes into the
+// stored records of the source when it is the only one.
+func (c *component) newSource(ctx context.Context, conf *config, store storage, name string) (domain.IPAMDataFetcher, domain.DependencyCheck, error) {
+	switch name {
+	case device42Source:
+		dc, err := c.Device42.New(ctx, conf.Device42)
+		if err != nil {
+			return nil, nil, err
+		}
+		if dc.IncrementalSync {
+			fetcher := ipamfetcher.NewDevice42IncrementalFetcher(dc, c.ownerResolver)
+			fetcher.StateStore = store.incrementalState
+			if store.storedSources != nil {
+				fetcher.Stored = store.storedSources
+				fetcher.Source = name
+			}
+			return fetcher, dc, nil
+		}
+		return ipamfetcher.NewDevice42IPAMDataFetcher(dc, c.ownerResolver), dc, nil
+	case netBoxSource:
+		nc, err := c.NetBox.New(ctx, conf.NetBox)
+		if err != nil {
+			return nil, nil, err
+		}
+		return netbox.NewIPAMDataFetcher(nc, c.ownerResolver), nc, nil
+	case infobloxSource:
+		ic, err := c.Infoblox.New(ctx, conf.Infoblox)
+		if err != nil {
+			return nil, nil, err
+		}
+		return infoblox.NewIPAMDataFetcher(ic), ic, nil
+	case fileSource:
+		fc, err := c.File.New(ctx, conf.File)
+		if err != nil {
+			return nil, nil, err
+		}
+		return fc, fc, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown IPAM data source %q", name)
+	}
+}
+
+// migrate runs a schema migration command against the database configured by the
+// IPAMFACADE_POSTGRES_* settings: "up" applies the pending migrations, and "status" lists
+// every migration and whether it has been applied.
+func migrate(ctx context.Context, source settings.Source, command string) error {
+	if command != "up" && command != "status" {
+		return fmt.Errorf("unknown migrate command %q, expected up or status", command)
+	}
+	db, err := newPostgresCommandDB(ctx, source)
+	if err != nil {
+		return err
+	}
+	defer db.Conn().Close()
+
+	if command == "up" {
+		applied, err := db.Migrate(ctx)
+		if err != nil {
+			return err
+		}
+		for _, migration := range applied {
+			fmt.Printf("applied %04d_%s\n", migration.Version, migration.Name)
+		}
+		if len(applied) == 0 {
+			fmt.Println("the schema is up to date")
+		}
+		return nil
+	}
+
+	statuses, err := db.MigrationStatus(ctx)
+	if err != nil {
+		return err
+	}
+	for _, status := range statuses {
+		state := "pending"
+		switch {
+		case status.Modified:
+			state = "modified since it was applied at " + status.AppliedAt.Format(time.RFC3339)
+		case status.Unknown:
+			state = "applied by a newer version at " + status.AppliedAt.Format(time.RFC3339)
+		case status.Applied:
+			state = "applied at " + status.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Printf("%04d_%s: %s\n", status.Version, status.Name, state)
+	}
+	return nil
+}
+
+// rollback restores the physical assets replaced by the last sync in the swap sync mode, in
+// the database configured by the IPAMFACADE_POSTGRES_* settings, and invalidates the cached
+// lookups of running instances. Rolling back twice restores the assets of the last sync. The
+// rollback holds the sync lock, so it fails rather than run while a sync is storing assets.
+func rollback(ctx context.Context, source settings.Source) error {
+	db, err := newPostgresCommandDB(ctx, source)
+	if err != nil {
+		return err
+	}
+	defer db.Conn().Close()
+
+	unlock, err := db.LockSync(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	storer := &assetstorer.PostgresSwapPhysicalAssetStorer{DB: db, LockTimeout: defaultSwapLockTimeout}
+	// the rollback starts a new sync generation, so that running instances discard the
+	// lookups they cached from the replaced assets
+	if err = storer.Rollback(ctx); err != nil {
+		return err
+	}
+	fmt.Println("restored the previous generation of physical assets")
+	return nil
+}
+
+// newPostgresCommandDB connects to the database configured by the IPAMFACADE_POSTGRES_*
+// settings for a command, without applying any migrations.
+func newPostgresCommandDB(ctx context.Context, source settings.Source) (*sqldb.PostgresDB, error) {
+	postgresCmp := sqldb.NewPostgresComponent()
+	conf := postgresCmp.Settings()
+	g, err := settings.Convert(conf)
+	if err != nil {
+		return nil, err
+	}
+	prefixed := &settings.PrefixSource{Source: source, Prefix: []string{new(config).Name()}}
+	if err = settings.LoadGroups(ctx, prefixed, []settings.Group{g}); err != nil {
+		return nil, err
+	}
+	conf.SkipMigrations = true
+	return postgresCmp.New(ctx, conf)
+}
+
 func main() {
 	source, err := settings.NewEnvSource(os.Environ())
 	if err != nil {
@@ -138,6 +554,7 @@ func main() {
 	}
 	ctx := context.Background()
 	runner := new(func(context.Context, settings.Source) error)
+	ownerResolverCmp := ipamfetcher.NewOwnerResolverComponent()
 	cmp := newComponent()
 
 	// Print names and example values for all defined environment variables
@@ -146,10 +563,31 @@ func main() {
 	fs.Usage = func() {}
 	if err = fs.Parse(os.Args[1:]); err == flag.ErrHelp {
 		g, _ := settings.GroupFromComponent(cmp)
+		og, _ := settings.GroupFromComponent(ownerResolverCmp)
 		fmt.Println("Usage: ")
-		fmt.Println(settings.ExampleEnvGroups([]settings.Group{g}))
+		fmt.Println(settings.ExampleEnvGroups([]settings.Group{g, og}))
+		fmt.Println("Run \"migrate up\" to apply pending schema migrations, or \"migrate status\" to list them.")
+		fmt.Println("Run \"rollback\" to restore the assets replaced by the last sync in the swap sync mode.")
+		return
+	}
+	if fs.Arg(0) == "migrate" {
+		if err = migrate(ctx, source, fs.Arg(1)); err != nil {
+			panic(err.Error())
+		}
 		return
 	}
+	if fs.Arg(0) == "rollback" {
+		if err = rollback(ctx, source); err != nil {
+			panic(err.Error())
+		}
+		return
+	}
+
+	ownerResolver := new(ipamfetcher.OwnerResolver)
+	if err = settings.NewComponent(ctx, source, ownerResolverCmp, ownerResolver); err != nil {
+		panic(err.Error())
+	}
+	cmp.ownerResolver = *ownerResolver
 
 	err = settings.NewComponent(ctx, source, cmp, runner)
 	if err != nil {
@@ -0,0 +1,173 @@
+// Package assetcache caches the lookups of physical assets by IP address, discarding them
+// whenever a sync stores a new dataset.
+package assetcache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+)
+
+// Names of the metrics sent through the StatFn.
+const (
+	hitMetric          = "assetcache.hit"
+	missMetric         = "assetcache.miss"
+	invalidationMetric = "assetcache.invalidation"
+)
+
+// PhysicalAssetFetcher caches the lookups of the PhysicalAssetFetcher it wraps, both those
+// that found an asset and those that returned AssetNotFound, keeping the Size most recently
+// used. Other errors are not cached. The cache is disabled when Size is 0.
+//
+// InvalidateAssetCache discards the cached lookups once a sync has stored a new dataset. The
+// storage starts a new sync generation in the same transaction as it stores the dataset, and
+// every instance checks the generation in the Generations store, when there is one, at most
+// once per CheckInterval, in the background so that lookups never wait on it, and discards
+// its cached lookups when the generation has changed.
+type PhysicalAssetFetcher struct {
+	Wrapped       domain.PhysicalAssetFetcher
+	Generations   domain.SyncGenerationStore
+	StatFn        domain.StatFn
+	Size          int
+	CheckInterval time.Duration
+	now           func() time.Time
+
+	lock       sync.Mutex
+	entries    *lru
+	epoch      uint64
+	generation int64
+	known      bool
+	checkedAt  time.Time
+	checking   bool
+}
+
+// FetchPhysicalAsset returns the cached lookup of an IP address, or looks it up and caches
+// it.
+func (c *PhysicalAssetFetcher) FetchPhysicalAsset(ctx context.Context, ipAddress string) (domain.PhysicalAsset, error) {
+	if c.Size <= 0 {
+		return c.Wrapped.FetchPhysicalAsset(ctx, ipAddress)
+	}
+	stat := c.StatFn(ctx)
+
+	c.lock.Lock()
+	c.checkGeneration()
+	if entry, ok := c.cache().get(ipAddress); ok {
+		c.lock.Unlock()
+		stat.Count(hitMetric, 1)
+		return copyAsset(entry.asset), entry.err
+	}
+	// a lookup that started before the cache was cleared may return data that is out of date
+	epoch := c.epoch
+	c.lock.Unlock()
+	stat.Count(missMetric, 1)
+
+	asset, err := c.Wrapped.FetchPhysicalAsset(ctx, ipAddress)
+	if err != nil {
+		if _, ok := err.(domain.AssetNotFound); !ok {
+			return asset, err
+		}
+	}
+
+	c.lock.Lock()
+	if c.epoch == epoch {
+		c.cache().add(&lruEntry{key: ipAddress, asset: copyAsset(asset), err: err})
+	}
+	c.lock.Unlock()
+	return asset, err
+}
+
+// InvalidateAssetCache discards the cached lookups, and records the generation of the stored
+// dataset so that they are not discarded a second time when the generation is next checked.
+// The cached lookups are discarded even if the generation cannot be fetched, and are then
+// discarded again once it is.
+func (c *PhysicalAssetFetcher) InvalidateAssetCache(ctx context.Context) error {
+	var err error
+	var generation int64
+	if c.Generations != nil {
+		generation, err = c.Generations.FetchSyncGeneration(ctx)
+	}
+
+	c.lock.Lock()
+	if c.Generations != nil {
+		c.generation = generation
+		c.known = err == nil
+	}
+	c.clear()
+	c.lock.Unlock()
+
+	c.StatFn(ctx).Count(invalidationMetric, 1)
+	return err
+}
+
+// checkGeneration starts a background check of the sync generation when the last one is
+// older than the CheckInterval. It must be called with the lock held.
+func (c *PhysicalAssetFetcher) checkGeneration() {
+	if c.Generations == nil || c.checking || c.currentTime().Sub(c.checkedAt) < c.CheckInterval {
+		return
+	}
+	c.checking = true
+	go c.refreshGeneration(context.Background())
+}
+
+// refreshGeneration fetches the sync generation, and discards the cached lookups when it is
+// not the one they were made in. Until the generation is first fetched, it is unknown, so the
+// cached lookups are discarded then too. The cached lookups are kept when the generation
+// cannot be fetched.
+func (c *PhysicalAssetFetcher) refreshGeneration(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, c.CheckInterval)
+	defer cancel()
+	generation, err := c.Generations.FetchSyncGeneration(ctx)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.checking = false
+	c.checkedAt = c.currentTime()
+	if err != nil {
+		return
+	}
+	if !c.known || generation != c.generation {
+		c.generation = generation
+		c.known = true
+		c.clear()
+	}
+}
+
+// cache returns the cached lookups, creating them if needed. It must be called with the lock
+// held.
+func (c *PhysicalAssetFetcher) cache() *lru {
+	if c.entries == nil {
+		c.entries = newLRU(c.Size)
+	}
+	return c.entries
+}
+
+// clear discards the cached lookups, along with those still in progress. It must be called
+// with the lock held.
+func (c *PhysicalAssetFetcher) clear() {
+	c.entries = nil
+	c.epoch++
+}
+
+func (c *PhysicalAssetFetcher) currentTime() time.Time {
+	if c.now == nil {
+		return time.Now()
+	}
+	return c.now()
+}
+
+// copyAsset copies the slices of an asset, so that callers cannot change the cached one.
+func copyAsset(asset domain.PhysicalAsset) domain.PhysicalAsset {
+	if asset.Contacts != nil {
+		contacts := make([]domain.Contact, len(asset.Contacts))
+		copy(contacts, asset.Contacts)
+		asset.Contacts = contacts
+	}
+	if asset.InheritedFields != nil {
+		inheritedFields := make([]string, len(asset.InheritedFields))
+		copy(inheritedFields, asset.InheritedFields)
+		asset.InheritedFields = inheritedFields
+	}
+	return asset
+}
@@ -0,0 +1,307 @@
+package assetcache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+// countingStat records the counts sent to it, and ignores every other metric.
+type countingStat struct {
+	lock   sync.Mutex
+	counts map[string]float64
+}
+
+func (s *countingStat) Gauge(string, float64, ...string)        {}
+func (s *countingStat) Histogram(string, float64, ...string)    {}
+func (s *countingStat) Timing(string, time.Duration, ...string) {}
+func (s *countingStat) AddTags(...string)                       {}
+func (s *countingStat) GetTags() []string                       { return nil }
+
+func (s *countingStat) Count(stat string, count float64, _ ...string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.counts == nil {
+		s.counts = make(map[string]float64)
+	}
+	s.counts[stat] += count
+}
+
+func (s *countingStat) count(stat string) float64 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.counts[stat]
+}
+
+func newTestCache(wrapped domain.PhysicalAssetFetcher, size int) (*PhysicalAssetFetcher, *countingStat) {
+	stat := &countingStat{}
+	return &PhysicalAssetFetcher{
+		Wrapped:       wrapped,
+		StatFn:        func(context.Context) domain.Stat { return stat },
+		Size:          size,
+		CheckInterval: time.Minute,
+	}, stat
+}
+
+func TestPhysicalAssetFetcher_CachesHits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockFetcher := NewMockPhysicalAssetFetcher(ctrl)
+	cache, stat := newTestCache(mockFetcher, 10)
+
+	newAsset := func() domain.PhysicalAsset {
+		return domain.PhysicalAsset{IP: "10.0.0.1", SubnetID: 1, Contacts: []domain.Contact{{Type: "Technical", Name: "Alice"}}}
+	}
+	mockFetcher.EXPECT().FetchPhysicalAsset(gomock.Any(), "10.0.0.1").Return(newAsset(), nil).Times(1)
+
+	for i := 0; i < 3; i++ {
+		fetched, err := cache.FetchPhysicalAsset(context.Background(), "10.0.0.1")
+		require.Nil(t, err)
+		require.Equal(t, newAsset(), fetched)
+		// callers cannot change the cached asset
+		fetched.Contacts[0].Name = "Mallory"
+	}
+	require.Equal(t, float64(1), stat.count(missMetric))
+	require.Equal(t, float64(2), stat.count(hitMetric))
+}
+
+func TestPhysicalAssetFetcher_CachesNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockFetcher := NewMockPhysicalAssetFetcher(ctrl)
+	cache, stat := newTestCache(mockFetcher, 10)
+
+	notFound := domain.AssetNotFound{IP: "192.168.0.1"}
+	mockFetcher.EXPECT().FetchPhysicalAsset(gomock.Any(), "192.168.0.1").Return(domain.PhysicalAsset{}, notFound).Times(1)
+
+	for i := 0; i < 2; i++ {
+		_, err := cache.FetchPhysicalAsset(context.Background(), "192.168.0.1")
+		require.Equal(t, notFound, err)
+	}
+	require.Equal(t, float64(1), stat.count(hitMetric))
+}
+
+func TestPhysicalAssetFetcher_DoesNotCacheErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockFetcher := NewMockPhysicalAssetFetcher(ctrl)
+	cache, stat := newTestCache(mockFetcher, 10)
+
+	mockFetcher.EXPECT().FetchPhysicalAsset(gomock.Any(), "10.0.0.1").Return(domain.PhysicalAsset{}, errors.New("connection refused")).Times(2)
+
+	for i := 0; i < 2; i++ {
+		_, err := cache.FetchPhysicalAsset(context.Background(), "10.0.0.1")
+		require.Error(t, err)
+	}
+	require.Equal(t, float64(2), stat.count(missMetric))
+	require.Equal(t, float64(0), stat.count(hitMetric))
+}
+
+func TestPhysicalAssetFetcher_EvictsLeastRecentlyUsed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockFetcher := NewMockPhysicalAssetFetcher(ctrl)
+	cache, _ := newTestCache(mockFetcher, 2)
+
+	for _, ip := range []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"} {
+		mockFetcher.EXPECT().FetchPhysicalAsset(gomock.Any(), ip).Return(domain.PhysicalAsset{IP: ip}, nil).Times(1)
+	}
+	mockFetcher.EXPECT().FetchPhysicalAsset(gomock.Any(), "10.0.0.2").Return(domain.PhysicalAsset{IP: "10.0.0.2"}, nil).Times(1)
+
+	// 10.0.0.1 is used again before 10.0.0.3 is added, so 10.0.0.2 is evicted
+	for _, ip := range []string{"10.0.0.1", "10.0.0.2", "10.0.0.1", "10.0.0.3", "10.0.0.1", "10.0.0.3", "10.0.0.2"} {
+		asset, err := cache.FetchPhysicalAsset(context.Background(), ip)
+		require.Nil(t, err)
+		require.Equal(t, ip, asset.IP)
+	}
+	require.Equal(t, 2, cache.entries.len())
+}
+
+func TestPhysicalAssetFetcher_Disabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockFetcher := NewMockPhysicalAssetFetcher(ctrl)
+	cache, stat := newTestCache(mockFetcher, 0)
+
+	mockFetcher.EXPECT().FetchPhysicalAsset(gomock.Any(), "10.0.0.1").Return(domain.PhysicalAsset{IP: "10.0.0.1"}, nil).Times(2)
+
+	for i := 0; i < 2; i++ {
+		_, err := cache.FetchPhysicalAsset(context.Background(), "10.0.0.1")
+		require.Nil(t, err)
+	}
+	require.Equal(t, float64(0), stat.count(missMetric))
+}
+
+func TestPhysicalAssetFetcher_InvalidateAssetCache(t *testing.T) {
+	tc := []struct {
+		name          string
+		fetchErr      error
+		expectedError bool
+	}{
+		{
+			name: "generation fetched",
+		},
+		{
+			name:          "generation fetch failure",
+			fetchErr:      errors.New("connection refused"),
+			expectedError: true,
+		},
+	}
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockFetcher := NewMockPhysicalAssetFetcher(ctrl)
+			mockGenerations := NewMockSyncGenerationStore(ctrl)
+			cache, stat := newTestCache(mockFetcher, 10)
+			cache.Generations = mockGenerations
+			// the generation was checked recently, so lookups do not check it again
+			cache.now = func() time.Time { return time.Unix(100, 0) }
+			cache.checkedAt = time.Unix(100, 0)
+
+			mockFetcher.EXPECT().FetchPhysicalAsset(gomock.Any(), "10.0.0.1").Return(domain.PhysicalAsset{Location: "Old"}, nil)
+			mockFetcher.EXPECT().FetchPhysicalAsset(gomock.Any(), "10.0.0.1").Return(domain.PhysicalAsset{Location: "New"}, nil)
+			mockGenerations.EXPECT().FetchSyncGeneration(gomock.Any()).Return(int64(7), tt.fetchErr)
+
+			asset, err := cache.FetchPhysicalAsset(context.Background(), "10.0.0.1")
+			require.Nil(t, err)
+			require.Equal(t, "Old", asset.Location)
+
+			err = cache.InvalidateAssetCache(context.Background())
+			require.Equal(t, tt.expectedError, err != nil)
+			require.Equal(t, float64(1), stat.count(invalidationMetric))
+			// the generation of the stored dataset is recorded, when it could be fetched
+			require.Equal(t, !tt.expectedError, cache.known)
+
+			asset, err = cache.FetchPhysicalAsset(context.Background(), "10.0.0.1")
+			require.Nil(t, err)
+			require.Equal(t, "New", asset.Location)
+		})
+	}
+}
+
+func TestPhysicalAssetFetcher_InvalidateDuringLookup(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockFetcher := NewMockPhysicalAssetFetcher(ctrl)
+	cache, _ := newTestCache(mockFetcher, 10)
+
+	// the first lookup is answered from the old data, but only after the cache is invalidated
+	mockFetcher.EXPECT().FetchPhysicalAsset(gomock.Any(), "10.0.0.1").DoAndReturn(
+		func(ctx context.Context, ipAddress string) (domain.PhysicalAsset, error) {
+			require.Nil(t, cache.InvalidateAssetCache(ctx))
+			return domain.PhysicalAsset{Location: "Old"}, nil
+		})
+	mockFetcher.EXPECT().FetchPhysicalAsset(gomock.Any(), "10.0.0.1").Return(domain.PhysicalAsset{Location: "New"}, nil)
+
+	asset, err := cache.FetchPhysicalAsset(context.Background(), "10.0.0.1")
+	require.Nil(t, err)
+	require.Equal(t, "Old", asset.Location)
+	asset, err = cache.FetchPhysicalAsset(context.Background(), "10.0.0.1")
+	require.Nil(t, err)
+	require.Equal(t, "New", asset.Location)
+}
+
+func TestPhysicalAssetFetcher_RefreshGeneration(t *testing.T) {
+	tc := []struct {
+		name        string
+		known       bool
+		generation  int64
+		fetched     int64
+		fetchErr    error
+		expectClear bool
+	}{
+		{
+			name:        "first check",
+			fetched:     3,
+			expectClear: true,
+		},
+		{
+			name:       "same generation",
+			known:      true,
+			generation: 3,
+			fetched:    3,
+		},
+		{
+			name:        "new generation",
+			known:       true,
+			generation:  3,
+			fetched:     4,
+			expectClear: true,
+		},
+		{
+			name:       "fetch failure",
+			known:      true,
+			generation: 3,
+			fetchErr:   errors.New("connection refused"),
+		},
+	}
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockGenerations := NewMockSyncGenerationStore(ctrl)
+			cache, _ := newTestCache(nil, 10)
+			cache.Generations = mockGenerations
+			cache.now = func() time.Time { return time.Unix(100, 0) }
+			cache.known = tt.known
+			cache.generation = tt.generation
+			cache.checking = true
+			cache.cache().add(&lruEntry{key: "10.0.0.1"})
+
+			mockGenerations.EXPECT().FetchSyncGeneration(gomock.Any()).Return(tt.fetched, tt.fetchErr)
+			cache.refreshGeneration(context.Background())
+
+			require.False(t, cache.checking)
+			require.Equal(t, time.Unix(100, 0), cache.checkedAt)
+			_, cached := cache.cache().get("10.0.0.1")
+			require.Equal(t, !tt.expectClear, cached)
+			if tt.fetchErr == nil {
+				require.Equal(t, tt.fetched, cache.generation)
+			}
+		})
+	}
+}
+
+func TestPhysicalAssetFetcher_ChecksGenerationInBackground(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockFetcher := NewMockPhysicalAssetFetcher(ctrl)
+	mockGenerations := NewMockSyncGenerationStore(ctrl)
+	cache, _ := newTestCache(mockFetcher, 10)
+	cache.Generations = mockGenerations
+
+	checked := make(chan struct{})
+	mockGenerations.EXPECT().FetchSyncGeneration(gomock.Any()).DoAndReturn(
+		func(context.Context) (int64, error) {
+			<-checked
+			return int64(1), nil
+		})
+	mockFetcher.EXPECT().FetchPhysicalAsset(gomock.Any(), "10.0.0.1").Return(domain.PhysicalAsset{}, nil).Times(2)
+
+	// the lookup does not wait for the check it started, and starts no other until it is done
+	_, err := cache.FetchPhysicalAsset(context.Background(), "10.0.0.1")
+	require.Nil(t, err)
+	_, err = cache.FetchPhysicalAsset(context.Background(), "10.0.0.1")
+	require.Nil(t, err)
+	close(checked)
+
+	for {
+		cache.lock.Lock()
+		checking := cache.checking
+		cache.lock.Unlock()
+		if !checking {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	// the generation was unknown, so the cached lookups were discarded
+	_, err = cache.FetchPhysicalAsset(context.Background(), "10.0.0.1")
+	require.Nil(t, err)
+}
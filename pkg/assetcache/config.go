@@ -0,0 +1,52 @@
+package assetcache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+)
+
+// Config contains configuration settings for a PhysicalAssetFetcher cache
+type Config struct {
+	Size                    int           `description:"The number of lookups by IP address to cache, including those that found no asset. The cache is disabled when 0."`
+	GenerationCheckInterval time.Duration `description:"How often the cache checks whether a sync by another instance has stored a new dataset, discarding the cached lookups if so."`
+}
+
+// Name is used by the settings library to replace the default naming convention.
+func (*Config) Name() string {
+	return "Cache"
+}
+
+// NewComponent generates a new, unititialized Component
+func NewComponent() *Component {
+	return &Component{}
+}
+
+// Component satisfies the settings library Component API,
+// and may be used by the settings.NewComponent function.
+type Component struct{}
+
+// Settings generates a config with default values applied.
+func (*Component) Settings() *Config {
+	return &Config{
+		GenerationCheckInterval: 5 * time.Second,
+	}
+}
+
+// New constructs a PhysicalAssetFetcher cache from a config. The fetcher it wraps, and the
+// store of the sync generation if there is one, are to be set by the caller.
+func (*Component) New(_ context.Context, conf *Config) (*PhysicalAssetFetcher, error) {
+	if conf.Size < 0 {
+		return nil, fmt.Errorf("the cache size must not be negative, got %d", conf.Size)
+	}
+	if conf.GenerationCheckInterval <= 0 {
+		return nil, fmt.Errorf("the cache generation check interval must be positive, got %s", conf.GenerationCheckInterval)
+	}
+	return &PhysicalAssetFetcher{
+		Size:          conf.Size,
+		CheckInterval: conf.GenerationCheckInterval,
+		StatFn:        domain.StatFromContext,
+	}, nil
+}
@@ -0,0 +1,59 @@
+package assetcache
+
+import (
+	"container/list"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+)
+
+// lru is a map of lookups by IP address that holds at most size entries, evicting the least
+// recently used entry to make room for another.
+type lru struct {
+	size    int
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+// lruEntry is the result of a lookup: the asset that was found, or the AssetNotFound error.
+type lruEntry struct {
+	key   string
+	asset domain.PhysicalAsset
+	err   error
+}
+
+func newLRU(size int) *lru {
+	return &lru{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[string]*list.Element, size),
+	}
+}
+
+// get returns the entry for a key, marking it as the most recently used.
+func (l *lru) get(key string) (*lruEntry, bool) {
+	element, ok := l.entries[key]
+	if !ok {
+		return nil, false
+	}
+	l.order.MoveToFront(element)
+	return element.Value.(*lruEntry), true
+}
+
+// add sets the entry for a key, marking it as the most recently used.
+func (l *lru) add(entry *lruEntry) {
+	if element, ok := l.entries[entry.key]; ok {
+		element.Value = entry
+		l.order.MoveToFront(element)
+		return
+	}
+	l.entries[entry.key] = l.order.PushFront(entry)
+	for l.order.Len() > l.size {
+		oldest := l.order.Back()
+		l.order.Remove(oldest)
+		delete(l.entries, oldest.Value.(*lruEntry).key)
+	}
+}
+
+func (l *lru) len() int {
+	return l.order.Len()
+}
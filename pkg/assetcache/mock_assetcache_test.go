@@ -0,0 +1,49 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/asecurityteam/ipam-facade/pkg/domain (interfaces: SyncGenerationStore)
+
+// Package assetcache is a generated GoMock package.
+package assetcache
+
+import (
+	context "context"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockSyncGenerationStore is a mock of SyncGenerationStore interface
+type MockSyncGenerationStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockSyncGenerationStoreMockRecorder
+}
+
+// MockSyncGenerationStoreMockRecorder is the mock recorder for MockSyncGenerationStore
+type MockSyncGenerationStoreMockRecorder struct {
+	mock *MockSyncGenerationStore
+}
+
+// NewMockSyncGenerationStore creates a new mock instance
+func NewMockSyncGenerationStore(ctrl *gomock.Controller) *MockSyncGenerationStore {
+	mock := &MockSyncGenerationStore{ctrl: ctrl}
+	mock.recorder = &MockSyncGenerationStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockSyncGenerationStore) EXPECT() *MockSyncGenerationStoreMockRecorder {
+	return m.recorder
+}
+
+// FetchSyncGeneration mocks base method
+func (m *MockSyncGenerationStore) FetchSyncGeneration(arg0 context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchSyncGeneration", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchSyncGeneration indicates an expected call of FetchSyncGeneration
+func (mr *MockSyncGenerationStoreMockRecorder) FetchSyncGeneration(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchSyncGeneration", reflect.TypeOf((*MockSyncGenerationStore)(nil).FetchSyncGeneration), arg0)
+}
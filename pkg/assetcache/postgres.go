@@ -0,0 +1,25 @@
+package assetcache
+
+import (
+	"context"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+)
+
+const fetchSyncGenerationQuery = `SELECT generation FROM sync_generation;`
+
+// PostgresSyncGenerationStore fetches the generation of the stored physical assets from a
+// PostgreSQL database, where the PhysicalAssetStorers increment it as they store assets. The
+// generation is fetched with the read connection of the DB, the same one used for lookups, so
+// that an instance reading a replica only sees a new generation once the replica also has
+// the dataset that came with it.
+type PostgresSyncGenerationStore struct {
+	DB domain.SQLDB
+}
+
+// FetchSyncGeneration returns the current generation.
+func (s *PostgresSyncGenerationStore) FetchSyncGeneration(ctx context.Context) (int64, error) {
+	var generation int64
+	err := s.DB.ReadConn().QueryRowContext(ctx, fetchSyncGenerationQuery).Scan(&generation)
+	return generation, err
+}
@@ -0,0 +1,48 @@
+package assetcache
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchSyncGeneration(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockSQLDB := NewMockSQLDB(ctrl)
+
+	mockdb, mock, err := sqlmock.New()
+	require.Nil(t, err)
+	defer mockdb.Close()
+	mockSQLDB.EXPECT().ReadConn().Return(mockdb)
+
+	mock.ExpectQuery("SELECT generation FROM sync_generation").WillReturnRows(sqlmock.NewRows([]string{"generation"}).AddRow(3))
+
+	store := PostgresSyncGenerationStore{DB: mockSQLDB}
+	generation, err := store.FetchSyncGeneration(context.Background())
+	require.Nil(t, err)
+	require.Equal(t, int64(3), generation)
+	require.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestFetchSyncGenerationError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockSQLDB := NewMockSQLDB(ctrl)
+
+	mockdb, mock, err := sqlmock.New()
+	require.Nil(t, err)
+	defer mockdb.Close()
+	mockSQLDB.EXPECT().ReadConn().Return(mockdb)
+
+	mock.ExpectQuery("SELECT generation FROM sync_generation").WillReturnError(errors.New("relation does not exist"))
+
+	store := PostgresSyncGenerationStore{DB: mockSQLDB}
+	_, err = store.FetchSyncGeneration(context.Background())
+	require.Error(t, err)
+	require.Nil(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,171 @@
+package assetfetcher
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+	"github.com/lib/pq"
+)
+
+const exportSnapshotQuery = `SELECT generation, synced_at FROM sync_generation;`
+
+// exportCustomersQuery returns a row for every contact of every customer, and a single row
+// without a contact for a customer that has none, so that the contacts of each customer are
+// read along with it in a single pass.
+const exportCustomersQuery = `SELECT c.id, c.resource_owner, c.owner_rule, c.business_unit,
+							cc.id, cc.type, cc.name, cc.email, cc.phone
+						FROM customers c
+						LEFT OUTER JOIN customer_contacts cc ON cc.customer_id = c.id
+						ORDER BY c.id, cc.id;`
+
+const exportSubnetsQuery = `SELECT s.id, text(s.network), s.location, c.id, c.resource_owner, c.business_unit
+						FROM subnets s
+						LEFT OUTER JOIN customers c ON s.customer_id = c.id
+						ORDER BY s.id;`
+
+const exportIPsQuery = `SELECT host(i.ip), i.device_id, s.id, text(s.network), s.location,
+							c.resource_owner, c.business_unit
+						FROM ips i
+						INNER JOIN subnets s ON i.subnet_id = s.id
+						LEFT OUTER JOIN customers c ON s.customer_id = c.id
+						ORDER BY i.id;`
+
+// PostgresAssetExporter exports the physical assets stored in a PostgreSQL database. Each
+// export reads the sync generation and the records in a single read only transaction with
+// repeatable read isolation, so that a sync committed during the export is not seen by it.
+// Exports use the read connection of the DB, so that they may be served by a replica.
+type PostgresAssetExporter struct {
+	DB domain.SQLDB
+}
+
+// ExportAssets streams every stored record of a kind to the writer, as the rows are read.
+func (e *PostgresAssetExporter) ExportAssets(ctx context.Context, kind domain.AssetExportKind, writer domain.AssetExportWriter) error {
+	var export func(context.Context, *sql.Tx, domain.AssetExportWriter) error
+	switch kind {
+	case domain.ExportCustomers:
+		export = exportCustomers
+	case domain.ExportSubnets:
+		export = exportSubnets
+	case domain.ExportIPs:
+		export = exportIPs
+	default:
+		return domain.UnknownExportKind{Kind: string(kind)}
+	}
+
+	tx, err := e.DB.ReadConn().BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	// the transaction only reads, so it is always rolled back
+	defer func() { _ = tx.Rollback() }()
+
+	var snapshot domain.AssetSnapshot
+	var syncedAt pq.NullTime
+	if err := tx.QueryRowContext(ctx, exportSnapshotQuery).Scan(&snapshot.Generation, &syncedAt); err != nil {
+		return err
+	}
+	if syncedAt.Valid {
+		snapshot.SyncedAt = syncedAt.Time
+	}
+	if err := writer.WriteSnapshot(snapshot); err != nil {
+		return err
+	}
+	return export(ctx, tx, writer)
+}
+
+func exportCustomers(ctx context.Context, tx *sql.Tx, writer domain.AssetExportWriter) error {
+	rows, err := tx.QueryContext(ctx, exportCustomersQuery)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var customer *domain.ExportedCustomer
+	for rows.Next() {
+		var id int64
+		var resourceOwner, ownerRule, businessUnit string
+		var contactID sql.NullInt64
+		var contactType, name, email, phone sql.NullString
+		if err := rows.Scan(&id, &resourceOwner, &ownerRule, &businessUnit, &contactID, &contactType, &name, &email, &phone); err != nil {
+			return err
+		}
+		if customer == nil || customer.ID != id {
+			if customer != nil {
+				if err := writer.WriteCustomer(*customer); err != nil {
+					return err
+				}
+			}
+			customer = &domain.ExportedCustomer{
+				ID:                id,
+				ResourceOwner:     resourceOwner,
+				ResourceOwnerRule: ownerRule,
+				BusinessUnit:      businessUnit,
+				Contacts:          []domain.Contact{},
+			}
+		}
+		if contactID.Valid {
+			customer.Contacts = append(customer.Contacts, domain.Contact{
+				Type:  contactType.String,
+				Name:  name.String,
+				Email: email.String,
+				Phone: phone.String,
+			})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if customer != nil {
+		return writer.WriteCustomer(*customer)
+	}
+	return nil
+}
+
+func exportSubnets(ctx context.Context, tx *sql.Tx, writer domain.AssetExportWriter) error {
+	rows, err := tx.QueryContext(ctx, exportSubnetsQuery)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var subnet domain.ExportedSubnet
+		var customerID sql.NullInt64
+		var resourceOwner, businessUnit sql.NullString
+		if err := rows.Scan(&subnet.ID, &subnet.Network, &subnet.Location, &customerID, &resourceOwner, &businessUnit); err != nil {
+			return err
+		}
+		subnet.CustomerID = customerID.Int64
+		subnet.ResourceOwner = resourceOwner.String
+		subnet.BusinessUnit = businessUnit.String
+		if err := writer.WriteSubnet(subnet); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func exportIPs(ctx context.Context, tx *sql.Tx, writer domain.AssetExportWriter) error {
+	rows, err := tx.QueryContext(ctx, exportIPsQuery)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var ip domain.ExportedIP
+		var deviceID sql.NullInt64
+		var resourceOwner, businessUnit sql.NullString
+		if err := rows.Scan(&ip.IP, &deviceID, &ip.SubnetID, &ip.Network, &ip.Location, &resourceOwner, &businessUnit); err != nil {
+			return err
+		}
+		ip.DeviceID = deviceID.Int64
+		ip.ResourceOwner = resourceOwner.String
+		ip.BusinessUnit = businessUnit.String
+		if err := writer.WriteIP(ip); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
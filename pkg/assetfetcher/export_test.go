@@ -0,0 +1,154 @@
+package assetfetcher
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+)
+
+type exportRecorder struct {
+	snapshot  domain.AssetSnapshot
+	customers []domain.ExportedCustomer
+	subnets   []domain.ExportedSubnet
+	ips       []domain.ExportedIP
+	err       error
+}
+
+func (r *exportRecorder) WriteSnapshot(snapshot domain.AssetSnapshot) error {
+	r.snapshot = snapshot
+	return nil
+}
+
+func (r *exportRecorder) WriteCustomer(customer domain.ExportedCustomer) error {
+	r.customers = append(r.customers, customer)
+	return r.err
+}
+
+func (r *exportRecorder) WriteSubnet(subnet domain.ExportedSubnet) error {
+	r.subnets = append(r.subnets, subnet)
+	return r.err
+}
+
+func (r *exportRecorder) WriteIP(ip domain.ExportedIP) error {
+	r.ips = append(r.ips, ip)
+	return r.err
+}
+
+func newExporter(t *testing.T) (*PostgresAssetExporter, sqlmock.Sqlmock, func()) {
+	mockdb, mock, err := sqlmock.New()
+	require.Nil(t, err, "an error '%s' was not expected when opening a stub database connection", err)
+	ctrl := gomock.NewController(t)
+	mocksqldb := NewMockSQLDB(ctrl)
+	mocksqldb.EXPECT().ReadConn().Return(mockdb).AnyTimes()
+	return &PostgresAssetExporter{DB: mocksqldb}, mock, func() {
+		ctrl.Finish()
+		mockdb.Close()
+	}
+}
+
+func expectSnapshot(mock sqlmock.Sqlmock, syncedAt interface{}) {
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT generation, synced_at FROM sync_generation").
+		WillReturnRows(sqlmock.NewRows([]string{"generation", "synced_at"}).AddRow(7, syncedAt))
+}
+
+func TestExportCustomers(t *testing.T) {
+	exporter, mock, done := newExporter(t)
+	defer done()
+	syncedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	expectSnapshot(mock, syncedAt)
+	mock.ExpectQuery(`FROM customers c\s+LEFT OUTER JOIN customer_contacts cc ON cc.customer_id = c.id\s+ORDER BY c.id, cc.id;`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "resource_owner", "owner_rule", "business_unit", "id", "type", "name", "email", "phone"}).
+			AddRow(1, "alice", "contact-type:SRE", "Acme", 10, "SRE", "Alice", "alice@example.com", "555-0100").
+			AddRow(1, "alice", "contact-type:SRE", "Acme", 11, "Technical", "Carol", "carol@example.com", "555-0101").
+			AddRow(2, "bob", "contact-info", "Acme", nil, nil, nil, nil, nil)).
+		RowsWillBeClosed()
+	mock.ExpectRollback()
+
+	recorder := &exportRecorder{}
+	require.NoError(t, exporter.ExportAssets(context.Background(), domain.ExportCustomers, recorder))
+	require.Equal(t, domain.AssetSnapshot{Generation: 7, SyncedAt: syncedAt}, recorder.snapshot)
+	require.Equal(t, []domain.ExportedCustomer{
+		{ID: 1, ResourceOwner: "alice", ResourceOwnerRule: "contact-type:SRE", BusinessUnit: "Acme", Contacts: []domain.Contact{
+			{Type: "SRE", Name: "Alice", Email: "alice@example.com", Phone: "555-0100"},
+			{Type: "Technical", Name: "Carol", Email: "carol@example.com", Phone: "555-0101"},
+		}},
+		{ID: 2, ResourceOwner: "bob", ResourceOwnerRule: "contact-info", BusinessUnit: "Acme", Contacts: []domain.Contact{}},
+	}, recorder.customers)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestExportSubnets(t *testing.T) {
+	exporter, mock, done := newExporter(t)
+	defer done()
+	expectSnapshot(mock, nil)
+	mock.ExpectQuery(`FROM subnets s\s+LEFT OUTER JOIN customers c ON s.customer_id = c.id\s+ORDER BY s.id;`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "network", "location", "id", "resource_owner", "business_unit"}).
+			AddRow(1, "10.0.0.0/8", "DC1", 2, "alice", "Acme").
+			AddRow(3, "10.1.0.0/16", "DC2", nil, nil, nil)).
+		RowsWillBeClosed()
+	mock.ExpectRollback()
+
+	recorder := &exportRecorder{}
+	require.NoError(t, exporter.ExportAssets(context.Background(), domain.ExportSubnets, recorder))
+	require.Equal(t, domain.AssetSnapshot{Generation: 7}, recorder.snapshot)
+	require.Equal(t, []domain.ExportedSubnet{
+		{ID: 1, Network: "10.0.0.0/8", Location: "DC1", CustomerID: 2, ResourceOwner: "alice", BusinessUnit: "Acme"},
+		{ID: 3, Network: "10.1.0.0/16", Location: "DC2"},
+	}, recorder.subnets)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestExportIPs(t *testing.T) {
+	exporter, mock, done := newExporter(t)
+	defer done()
+	expectSnapshot(mock, nil)
+	mock.ExpectQuery(`FROM ips i\s+INNER JOIN subnets s ON i.subnet_id = s.id\s+LEFT OUTER JOIN customers c ON s.customer_id = c.id\s+ORDER BY i.id;`).
+		WillReturnRows(sqlmock.NewRows([]string{"ip", "device_id", "id", "network", "location", "resource_owner", "business_unit"}).
+			AddRow("10.0.0.1", 100, 1, "10.0.0.0/8", "DC1", "alice", "Acme").
+			AddRow("10.1.0.1", nil, 3, "10.1.0.0/16", "DC2", nil, nil)).
+		RowsWillBeClosed()
+	mock.ExpectRollback()
+
+	recorder := &exportRecorder{}
+	require.NoError(t, exporter.ExportAssets(context.Background(), domain.ExportIPs, recorder))
+	require.Equal(t, []domain.ExportedIP{
+		{IP: "10.0.0.1", DeviceID: 100, SubnetID: 1, Network: "10.0.0.0/8", Location: "DC1", ResourceOwner: "alice", BusinessUnit: "Acme"},
+		{IP: "10.1.0.1", SubnetID: 3, Network: "10.1.0.0/16", Location: "DC2"},
+	}, recorder.ips)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestExportWriterError(t *testing.T) {
+	exporter, mock, done := newExporter(t)
+	defer done()
+	expectSnapshot(mock, nil)
+	mock.ExpectQuery(`FROM subnets s`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "network", "location", "id", "resource_owner", "business_unit"}).
+			AddRow(1, "10.0.0.0/8", "DC1", nil, nil, nil).
+			AddRow(3, "10.1.0.0/16", "DC2", nil, nil, nil)).
+		RowsWillBeClosed()
+	mock.ExpectRollback()
+
+	recorder := &exportRecorder{err: errors.New("connection reset")}
+	err := exporter.ExportAssets(context.Background(), domain.ExportSubnets, recorder)
+	require.Equal(t, recorder.err, err)
+	require.Len(t, recorder.subnets, 1)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestExportUnknownKind(t *testing.T) {
+	exporter, mock, done := newExporter(t)
+	defer done()
+
+	err := exporter.ExportAssets(context.Background(), domain.AssetExportKind("devices"), &exportRecorder{})
+	require.Equal(t, domain.UnknownExportKind{Kind: "devices"}, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
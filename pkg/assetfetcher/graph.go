@@ -0,0 +1,250 @@
+package assetfetcher
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+	"github.com/lib/pq"
+)
+
+const queryCustomersStatement = `SELECT id, resource_owner, owner_rule, business_unit
+						FROM customers
+						%s
+						ORDER BY id
+						%s;`
+
+const queryContactsStatement = `SELECT customer_id, type, name, email, phone
+						FROM customer_contacts
+						WHERE customer_id = ANY($1)
+						ORDER BY id;`
+
+const querySubnetsStatement = `SELECT id, text(network), location, customer_id
+						FROM subnets
+						%s
+						ORDER BY id
+						%s;`
+
+// queryCustomerSubnetsStatement numbers the subnets of each customer, so that a limit may
+// apply to the subnets of each customer rather than to all of them. Numbering reads every
+// selected subnet before any is returned, so it is only used when such a limit applies.
+const queryCustomerSubnetsStatement = `SELECT id, network, location, customer_id
+						FROM (
+							SELECT id, text(network) AS network, location, customer_id,
+								row_number() OVER (PARTITION BY customer_id ORDER BY id) AS position
+							FROM subnets
+							%s
+						) s
+						%s
+						ORDER BY id;`
+
+const queryIPsStatement = `SELECT id, host(ip), device_id, subnet_id
+						FROM ips
+						%s
+						ORDER BY id
+						%s;`
+
+// querySubnetIPsStatement numbers the IP addresses of each subnet, so that a limit may apply
+// to the addresses of each subnet rather than to all of them. Numbering reads every selected
+// address before any is returned, so it is only used when such a limit applies.
+const querySubnetIPsStatement = `SELECT id, ip, device_id, subnet_id
+						FROM (
+							SELECT id, host(ip) AS ip, device_id, subnet_id,
+								row_number() OVER (PARTITION BY subnet_id ORDER BY id) AS position
+							FROM ips
+							%s
+						) i
+						%s
+						ORDER BY id;`
+
+// containingCondition selects the subnets that contain any of several networks through a
+// join, so that the GiST index on subnets.network serves each of them.
+const containingCondition = `id IN (
+								SELECT s.id
+								FROM unnest($%d::cidr[]) AS n(network)
+								INNER JOIN subnets s ON s.network >>= n.network
+							)`
+
+// PostgresAssetGraphFetcher fetches the customers, subnets, and IP addresses stored in a
+// PostgreSQL database along the relationships between them. Queries use the read
+// connection of the DB, so that they may be served by a replica.
+type PostgresAssetGraphFetcher struct {
+	DB domain.SQLDB
+}
+
+// QueryCustomers fetches the customers selected by a query.
+func (f *PostgresAssetGraphFetcher) QueryCustomers(ctx context.Context, query domain.CustomerQuery) ([]domain.GraphCustomer, error) {
+	var where conditions
+	if query.IDs != nil {
+		where.add("id = ANY($%d)", pq.Int64Array(query.IDs))
+	}
+	if query.ResourceOwner != "" {
+		where.add("resource_owner = $%d", query.ResourceOwner)
+	}
+	if query.BusinessUnit != "" {
+		where.add("business_unit = $%d", query.BusinessUnit)
+	}
+	if query.After > 0 {
+		where.add("id > $%d", query.After)
+	}
+	limit := where.limit(query.Limit)
+
+	rows, err := f.DB.ReadConn().QueryContext(ctx, fmt.Sprintf(queryCustomersStatement, where.clause(), limit), where.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	customers := make([]domain.GraphCustomer, 0)
+	for rows.Next() {
+		var customer domain.GraphCustomer
+		if err := rows.Scan(&customer.ID, &customer.ResourceOwner, &customer.ResourceOwnerRule, &customer.BusinessUnit); err != nil {
+			return nil, err
+		}
+		customers = append(customers, customer)
+	}
+	return customers, rows.Err()
+}
+
+// QueryContacts fetches the contacts of several customers.
+func (f *PostgresAssetGraphFetcher) QueryContacts(ctx context.Context, customerIDs []int64) ([]domain.GraphContact, error) {
+	rows, err := f.DB.ReadConn().QueryContext(ctx, queryContactsStatement, pq.Int64Array(customerIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	contacts := make([]domain.GraphContact, 0)
+	for rows.Next() {
+		var contact domain.GraphContact
+		if err := rows.Scan(&contact.CustomerID, &contact.Type, &contact.Name, &contact.Email, &contact.Phone); err != nil {
+			return nil, err
+		}
+		contacts = append(contacts, contact)
+	}
+	return contacts, rows.Err()
+}
+
+// QuerySubnets fetches the subnets selected by a query.
+func (f *PostgresAssetGraphFetcher) QuerySubnets(ctx context.Context, query domain.SubnetQuery) ([]domain.GraphSubnet, error) {
+	var where conditions
+	if query.IDs != nil {
+		where.add("id = ANY($%d)", pq.Int64Array(query.IDs))
+	}
+	if query.CustomerIDs != nil {
+		where.add("customer_id = ANY($%d)", pq.Int64Array(query.CustomerIDs))
+	}
+	if query.Containing != nil {
+		where.add(containingCondition, pq.StringArray(query.Containing))
+	}
+	if query.Within != "" {
+		where.add("network <<= $%d::cidr", query.Within)
+	}
+	if query.Location != "" {
+		where.add("location = $%d", query.Location)
+	}
+	if query.After > 0 {
+		where.add("id > $%d", query.After)
+	}
+	var statement string
+	if query.CustomerIDs != nil && query.Limit > 0 {
+		statement = fmt.Sprintf(queryCustomerSubnetsStatement, where.clause(), where.limitPerParent(query.Limit))
+	} else {
+		statement = fmt.Sprintf(querySubnetsStatement, where.clause(), where.limit(query.Limit))
+	}
+
+	rows, err := f.DB.ReadConn().QueryContext(ctx, statement, where.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	subnets := make([]domain.GraphSubnet, 0)
+	for rows.Next() {
+		var subnet domain.GraphSubnet
+		var customerID sql.NullInt64
+		if err := rows.Scan(&subnet.ID, &subnet.Network, &subnet.Location, &customerID); err != nil {
+			return nil, err
+		}
+		subnet.CustomerID = customerID.Int64
+		subnets = append(subnets, subnet)
+	}
+	return subnets, rows.Err()
+}
+
+// QueryIPs fetches the IP addresses selected by a query.
+func (f *PostgresAssetGraphFetcher) QueryIPs(ctx context.Context, query domain.IPQuery) ([]domain.GraphIP, error) {
+	var where conditions
+	if query.SubnetIDs != nil {
+		where.add("subnet_id = ANY($%d)", pq.Int64Array(query.SubnetIDs))
+	}
+	if query.Address != "" {
+		where.add("ip = $%d::inet", query.Address)
+	}
+	if query.DeviceID > 0 {
+		where.add("device_id = $%d", query.DeviceID)
+	}
+	if query.Within != "" {
+		where.add("ip <<= $%d::cidr", query.Within)
+	}
+	if query.After > 0 {
+		where.add("id > $%d", query.After)
+	}
+	var statement string
+	if query.SubnetIDs != nil && query.Limit > 0 {
+		statement = fmt.Sprintf(querySubnetIPsStatement, where.clause(), where.limitPerParent(query.Limit))
+	} else {
+		statement = fmt.Sprintf(queryIPsStatement, where.clause(), where.limit(query.Limit))
+	}
+
+	rows, err := f.DB.ReadConn().QueryContext(ctx, statement, where.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	ips := make([]domain.GraphIP, 0)
+	for rows.Next() {
+		var ip domain.GraphIP
+		var deviceID sql.NullInt64
+		if err := rows.Scan(&ip.ID, &ip.IP, &deviceID, &ip.SubnetID); err != nil {
+			return nil, err
+		}
+		ip.DeviceID = deviceID.Int64
+		ips = append(ips, ip)
+	}
+	return ips, rows.Err()
+}
+
+// conditions builds the WHERE clause of a query from the conditions that apply, numbering
+// their arguments in the order they are added. Each condition has a %d verb for the number
+// of its argument.
+type conditions struct {
+	clauses []string
+	args    []interface{}
+}
+
+func (c *conditions) add(condition string, arg interface{}) {
+	c.args = append(c.args, arg)
+	c.clauses = append(c.clauses, fmt.Sprintf(condition, len(c.args)))
+}
+
+func (c *conditions) clause() string {
+	if len(c.clauses) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(c.clauses, " AND ")
+}
+
+// limit returns the LIMIT clause of a query, which is empty when the limit is 0.
+func (c *conditions) limit(limit int) string {
+	if limit <= 0 {
+		return ""
+	}
+	c.args = append(c.args, limit)
+	return fmt.Sprintf("LIMIT $%d", len(c.args))
+}
+
+// limitPerParent returns the condition that limits the numbered rows of each parent.
+func (c *conditions) limitPerParent(limit int) string {
+	c.args = append(c.args, limit)
+	return fmt.Sprintf("WHERE position <= $%d", len(c.args))
+}
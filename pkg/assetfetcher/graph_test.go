@@ -0,0 +1,202 @@
+package assetfetcher
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+)
+
+func newGraphFetcher(t *testing.T) (*PostgresAssetGraphFetcher, sqlmock.Sqlmock, func()) {
+	mockdb, mock, err := sqlmock.New()
+	require.Nil(t, err, "an error '%s' was not expected when opening a stub database connection", err)
+	ctrl := gomock.NewController(t)
+	mocksqldb := NewMockSQLDB(ctrl)
+	mocksqldb.EXPECT().ReadConn().Return(mockdb).AnyTimes()
+	return &PostgresAssetGraphFetcher{DB: mocksqldb}, mock, func() {
+		ctrl.Finish()
+		mockdb.Close()
+	}
+}
+
+func TestQueryCustomers(t *testing.T) {
+	tc := []struct {
+		name  string
+		query domain.CustomerQuery
+		sql   string
+		args  []driver.Value
+	}{
+		{
+			name:  "all",
+			query: domain.CustomerQuery{},
+			sql:   `FROM customers\s+ORDER BY id\s+;`,
+		},
+		{
+			name:  "filtered",
+			query: domain.CustomerQuery{IDs: []int64{1, 2}, ResourceOwner: "alice", BusinessUnit: "Acme", After: 1, Limit: 10},
+			sql:   `WHERE id = ANY\(\$1\) AND resource_owner = \$2 AND business_unit = \$3 AND id > \$4\s+ORDER BY id\s+LIMIT \$5;`,
+			args:  []driver.Value{pq.Int64Array{1, 2}, "alice", "Acme", int64(1), 10},
+		},
+	}
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			fetcher, mock, done := newGraphFetcher(t)
+			defer done()
+			rows := sqlmock.NewRows([]string{"id", "resource_owner", "owner_rule", "business_unit"}).
+				AddRow(2, "alice", "contact-type:Technical", "Acme")
+			expectation := mock.ExpectQuery(tt.sql)
+			if tt.args != nil {
+				expectation.WithArgs(tt.args...)
+			}
+			expectation.WillReturnRows(rows).RowsWillBeClosed()
+
+			customers, err := fetcher.QueryCustomers(context.Background(), tt.query)
+			require.NoError(t, err)
+			require.Equal(t, []domain.GraphCustomer{
+				{ID: 2, ResourceOwner: "alice", ResourceOwnerRule: "contact-type:Technical", BusinessUnit: "Acme"},
+			}, customers)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestQueryContacts(t *testing.T) {
+	fetcher, mock, done := newGraphFetcher(t)
+	defer done()
+	rows := sqlmock.NewRows([]string{"customer_id", "type", "name", "email", "phone"}).
+		AddRow(1, "Technical", "Alice", "alice@example.com", "555-0100").
+		AddRow(2, "Escalation", "Bob", "bob@example.com", "555-0199")
+	mock.ExpectQuery("FROM customer_contacts").WithArgs(pq.Int64Array{1, 2}).WillReturnRows(rows).RowsWillBeClosed()
+
+	contacts, err := fetcher.QueryContacts(context.Background(), []int64{1, 2})
+	require.NoError(t, err)
+	require.Equal(t, []domain.GraphContact{
+		{CustomerID: 1, Contact: domain.Contact{Type: "Technical", Name: "Alice", Email: "alice@example.com", Phone: "555-0100"}},
+		{CustomerID: 2, Contact: domain.Contact{Type: "Escalation", Name: "Bob", Email: "bob@example.com", Phone: "555-0199"}},
+	}, contacts)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestQuerySubnets(t *testing.T) {
+	tc := []struct {
+		name  string
+		query domain.SubnetQuery
+		sql   string
+		args  []driver.Value
+	}{
+		{
+			name:  "limited overall",
+			query: domain.SubnetQuery{Within: "10.0.0.0/8", Location: "DC", Limit: 5},
+			sql:   `^SELECT id, text\(network\), location, customer_id\s+FROM subnets\s+WHERE network <<= \$1::cidr AND location = \$2\s+ORDER BY id\s+LIMIT \$3;`,
+			args:  []driver.Value{"10.0.0.0/8", "DC", 5},
+		},
+		{
+			name:  "limited per customer",
+			query: domain.SubnetQuery{CustomerIDs: []int64{1}, After: 3, Limit: 5},
+			sql:   `row_number\(\) OVER \(PARTITION BY customer_id ORDER BY id\).*WHERE customer_id = ANY\(\$1\) AND id > \$2\s+\) s\s+WHERE position <= \$3\s+ORDER BY id;`,
+			args:  []driver.Value{pq.Int64Array{1}, int64(3), 5},
+		},
+		{
+			name:  "unlimited per customer",
+			query: domain.SubnetQuery{CustomerIDs: []int64{1, 2}},
+			sql:   `^SELECT id, text\(network\), location, customer_id\s+FROM subnets\s+WHERE customer_id = ANY\(\$1\)\s+ORDER BY id\s+;`,
+			args:  []driver.Value{pq.Int64Array{1, 2}},
+		},
+		{
+			name:  "containing",
+			query: domain.SubnetQuery{IDs: []int64{4}, Containing: []string{"10.0.0.1/32"}},
+			sql:   `WHERE id = ANY\(\$1\) AND id IN \(\s+SELECT s.id\s+FROM unnest\(\$2::cidr\[\]\)`,
+			args:  []driver.Value{pq.Int64Array{4}, pq.StringArray{"10.0.0.1/32"}},
+		},
+	}
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			fetcher, mock, done := newGraphFetcher(t)
+			defer done()
+			rows := sqlmock.NewRows([]string{"id", "network", "location", "customer_id"}).
+				AddRow(4, "10.0.0.0/24", "DC", 1).
+				AddRow(5, "10.0.1.0/24", "DC", nil)
+			mock.ExpectQuery(tt.sql).WithArgs(tt.args...).WillReturnRows(rows).RowsWillBeClosed()
+
+			subnets, err := fetcher.QuerySubnets(context.Background(), tt.query)
+			require.NoError(t, err)
+			require.Equal(t, []domain.GraphSubnet{
+				{ID: 4, Network: "10.0.0.0/24", Location: "DC", CustomerID: 1},
+				{ID: 5, Network: "10.0.1.0/24", Location: "DC"},
+			}, subnets)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestQueryIPs(t *testing.T) {
+	tc := []struct {
+		name  string
+		query domain.IPQuery
+		sql   string
+		args  []driver.Value
+	}{
+		{
+			name:  "limited overall",
+			query: domain.IPQuery{Address: "10.0.0.1", DeviceID: 7, Within: "10.0.0.0/24", Limit: 2},
+			sql:   `^SELECT id, host\(ip\), device_id, subnet_id\s+FROM ips\s+WHERE ip = \$1::inet AND device_id = \$2 AND ip <<= \$3::cidr\s+ORDER BY id\s+LIMIT \$4;`,
+			args:  []driver.Value{"10.0.0.1", int64(7), "10.0.0.0/24", 2},
+		},
+		{
+			name:  "limited per subnet",
+			query: domain.IPQuery{SubnetIDs: []int64{4, 5}, After: 1, Limit: 2},
+			sql:   `row_number\(\) OVER \(PARTITION BY subnet_id ORDER BY id\).*WHERE subnet_id = ANY\(\$1\) AND id > \$2\s+\) i\s+WHERE position <= \$3\s+ORDER BY id;`,
+			args:  []driver.Value{pq.Int64Array{4, 5}, int64(1), 2},
+		},
+		{
+			name:  "unlimited per subnet",
+			query: domain.IPQuery{SubnetIDs: []int64{4}},
+			sql:   `^SELECT id, host\(ip\), device_id, subnet_id\s+FROM ips\s+WHERE subnet_id = ANY\(\$1\)\s+ORDER BY id\s+;`,
+			args:  []driver.Value{pq.Int64Array{4}},
+		},
+	}
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			fetcher, mock, done := newGraphFetcher(t)
+			defer done()
+			rows := sqlmock.NewRows([]string{"id", "ip", "device_id", "subnet_id"}).
+				AddRow(2, "10.0.0.1", 7, 4).
+				AddRow(3, "10.0.0.2", nil, 4)
+			mock.ExpectQuery(tt.sql).WithArgs(tt.args...).WillReturnRows(rows).RowsWillBeClosed()
+
+			ips, err := fetcher.QueryIPs(context.Background(), tt.query)
+			require.NoError(t, err)
+			require.Equal(t, []domain.GraphIP{
+				{ID: 2, IP: "10.0.0.1", DeviceID: 7, SubnetID: 4},
+				{ID: 3, IP: "10.0.0.2", SubnetID: 4},
+			}, ips)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestQueryGraphError(t *testing.T) {
+	fetcher, mock, done := newGraphFetcher(t)
+	defer done()
+	mock.ExpectQuery("FROM customers").WillReturnError(errors.New("boom"))
+	mock.ExpectQuery("FROM customer_contacts").WillReturnError(errors.New("boom"))
+	mock.ExpectQuery("FROM subnets").WillReturnError(errors.New("boom"))
+	mock.ExpectQuery("FROM ips").WillReturnError(errors.New("boom"))
+
+	_, err := fetcher.QueryCustomers(context.Background(), domain.CustomerQuery{})
+	require.Error(t, err)
+	_, err = fetcher.QueryContacts(context.Background(), []int64{1})
+	require.Error(t, err)
+	_, err = fetcher.QuerySubnets(context.Background(), domain.SubnetQuery{})
+	require.Error(t, err)
+	_, err = fetcher.QueryIPs(context.Background(), domain.IPQuery{})
+	require.Error(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -7,19 +7,37 @@ import (
 	"github.com/asecurityteam/ipam-facade/pkg/domain"
 )
 
+// fetchByIPQuery finds the most specific subnet that contains an address, preferring one in
+// which the address is a known device. The address is cast to inet so that the containment
+// condition is served by the GiST index on subnets.network, and the device by the index on
+// ips.ip, rather than by scanning every subnet.
 const fetchByIPQuery = `SELECT host(i.ip) as ip, c.resource_owner as resource_owner,
 							c.business_unit as business_unit, text(s.network) as network,
-							s.location as location, device_id, s.id as subnet_id,
-							c.id as customer_id
-			            FROM ips i
-					  	RIGHT OUTER JOIN subnets s ON
-					  		i.subnet_id = s.id
-						AND i.ip = $1
+							s.location as location, i.device_id as device_id, s.id as subnet_id,
+							c.id as customer_id, c.owner_rule as resource_owner_rule
+						FROM subnets s
+						LEFT OUTER JOIN ips i ON
+							i.subnet_id = s.id
+						AND i.ip = $1::inet
 						LEFT OUTER JOIN customers c ON s.customer_id = c.id
-						WHERE s.network >>= $1
+						WHERE s.network >>= $1::inet
 						ORDER BY i.device_id IS NOT NULL DESC, masklen(s.network) DESC
 						LIMIT 1;`
 
+const fetchContactsQuery = `SELECT type, name, email, phone
+						FROM customer_contacts
+						WHERE customer_id = $1
+						ORDER BY id;`
+
+const fetchInheritedOwnerQuery = `SELECT s.id as subnet_id, c.id as customer_id,
+							c.resource_owner as resource_owner, c.business_unit as business_unit,
+							c.owner_rule as resource_owner_rule
+						FROM subnets s
+						INNER JOIN customers c ON s.customer_id = c.id
+						WHERE s.network >> $1::cidr
+						ORDER BY masklen(s.network) DESC
+						LIMIT 1;`
+
 const fetchSubnetsQuery = `SELECT network, location, resource_owner, business_unit
 						FROM subnets
 						LEFT JOIN customers ON
@@ -34,9 +52,21 @@ const fetchIPsQuery = `SELECT ip, network, location, resource_owner, business_un
 							subnets.customer_id=customers.id
 						LIMIT $1 OFFSET $2;`
 
+// Names of the PhysicalAsset fields that may be inherited from an ancestor subnet.
+const (
+	inheritedResourceOwner = "resourceOwner"
+	inheritedBusinessUnit  = "businessUnit"
+	inheritedCustomerID    = "customerID"
+	inheritedContacts      = "contacts"
+)
+
 // PostgresPhysicalAssetFetcher physical assets from a PostgreSQL database by IP address.
+// When InheritOwnership is set, an asset whose subnet has no customer inherits the
+// ownership of the nearest ancestor subnet that has one. Queries use the read connection
+// of the DB, so that they may be served by a replica.
 type PostgresPhysicalAssetFetcher struct {
-	DB domain.SQLDB
+	DB               domain.SQLDB
+	InheritOwnership bool
 }
 
 // FetchPhysicalAsset queries the SQL DB for a physical asset by the given IP address.
@@ -47,13 +77,16 @@ func (f *PostgresPhysicalAssetFetcher) FetchPhysicalAsset(ctx context.Context, i
 	var assetResourceOwner sql.NullString
 	var assetBusinessUnit sql.NullString
 	var assetCustomerID sql.NullInt64
-	err := f.DB.Conn().QueryRowContext(ctx, fetchByIPQuery, ipAddress).Scan(
+	var assetResourceOwnerRule sql.NullString
+	conn := f.DB.ReadConn()
+	err := conn.QueryRowContext(ctx, fetchByIPQuery, ipAddress).Scan(
 		&ip, &assetResourceOwner, &assetBusinessUnit, &asset.Network,
-		&asset.Location, &deviceID, &asset.SubnetID, &assetCustomerID)
+		&asset.Location, &deviceID, &asset.SubnetID, &assetCustomerID, &assetResourceOwnerRule)
 	if assetCustomerID.Valid {
 		// if we have a customerID from our query, we'll surely have the rest too:
 		asset.CustomerID = assetCustomerID.Int64
 		asset.ResourceOwner = assetResourceOwner.String
+		asset.ResourceOwnerRule = assetResourceOwnerRule.String
 		asset.BusinessUnit = assetBusinessUnit.String
 	}
 	switch {
@@ -71,12 +104,78 @@ func (f *PostgresPhysicalAssetFetcher) FetchPhysicalAsset(ctx context.Context, i
 		asset.DeviceID = 0
 	}
 
+	if !assetCustomerID.Valid && f.InheritOwnership {
+		inherited, err := fetchInheritedOwner(ctx, conn, &asset)
+		if err != nil {
+			return domain.PhysicalAsset{}, err
+		}
+		assetCustomerID.Valid = inherited
+	}
+
+	if assetCustomerID.Valid {
+		contacts, err := fetchContacts(ctx, conn, asset.CustomerID)
+		if err != nil {
+			return domain.PhysicalAsset{}, err
+		}
+		asset.Contacts = contacts
+	}
+
 	return asset, nil
 }
 
+// fetchInheritedOwner fills in the ownership of the asset from the nearest subnet that
+// contains the asset's subnet and has a customer. It returns false if there is no such subnet.
+func fetchInheritedOwner(ctx context.Context, conn *sql.DB, asset *domain.PhysicalAsset) (bool, error) {
+	var subnetID int64
+	var customerID int64
+	var resourceOwner string
+	var businessUnit string
+	var resourceOwnerRule string
+	err := conn.QueryRowContext(ctx, fetchInheritedOwnerQuery, asset.Network).Scan(
+		&subnetID, &customerID, &resourceOwner, &businessUnit, &resourceOwnerRule)
+	switch {
+	case err == sql.ErrNoRows:
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+
+	asset.CustomerID = customerID
+	asset.ResourceOwner = resourceOwner
+	asset.ResourceOwnerRule = resourceOwnerRule
+	asset.BusinessUnit = businessUnit
+	asset.InheritedFromSubnetID = subnetID
+	asset.InheritedFields = []string{
+		inheritedResourceOwner, inheritedBusinessUnit, inheritedCustomerID, inheritedContacts}
+	return true, nil
+}
+
+// fetchContacts queries all of the contacts registered for the given customer ID.
+func fetchContacts(ctx context.Context, conn *sql.DB, customerID int64) ([]domain.Contact, error) {
+	rows, err := conn.QueryContext(ctx, fetchContactsQuery, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	contacts := make([]domain.Contact, 0)
+	for rows.Next() {
+		var contact domain.Contact
+		if err := rows.Scan(&contact.Type, &contact.Name, &contact.Email, &contact.Phone); err != nil {
+			_ = rows.Close()
+			return nil, err
+		}
+		contacts = append(contacts, contact)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+
+	return contacts, nil
+}
+
 // FetchSubnets fetches a single page of subnets from the data store
 func (f *PostgresPhysicalAssetFetcher) FetchSubnets(ctx context.Context, limit, offset int) ([]domain.AssetSubnet, error) {
-	rows, err := f.DB.Conn().QueryContext(ctx, fetchSubnetsQuery, limit, offset)
+	rows, err := f.DB.ReadConn().QueryContext(ctx, fetchSubnetsQuery, limit, offset)
 	if err != nil {
 		return nil, err
 	}
@@ -116,7 +215,7 @@ func (f *PostgresPhysicalAssetFetcher) FetchSubnets(ctx context.Context, limit,
 
 // FetchIPs fetches a single page of IP addresses from the data store
 func (f *PostgresPhysicalAssetFetcher) FetchIPs(ctx context.Context, limit, offset int) ([]domain.AssetIP, error) {
-	rows, err := f.DB.Conn().QueryContext(ctx, fetchIPsQuery, limit, offset)
+	rows, err := f.DB.ReadConn().QueryContext(ctx, fetchIPsQuery, limit, offset)
 	if err != nil {
 		return nil, err
 	}
@@ -20,23 +20,32 @@ func TestFetchPhysicalAssetDeviceFound(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	mocksqldb := NewMockSQLDB(ctrl)
-	mocksqldb.EXPECT().Conn().Return(mockdb)
+	mocksqldb.EXPECT().ReadConn().Return(mockdb)
 	rows := sqlmock.NewRows([]string{
 		"ip", "resource_owner", "business_unit", "network", "location",
-		"device_id", "subnet_id", "customer_id"}).AddRow(
-		"127.0.0.1", "alice@example.com", "Acme", "127.0.0.1/32", "Home", 1, 1, 1)
+		"device_id", "subnet_id", "customer_id", "resource_owner_rule"}).AddRow(
+		"127.0.0.1", "alice@example.com", "Acme", "127.0.0.1/32", "Home", 1, 1, 1, "contact-type:Technical")
 	mock.ExpectQuery("SELECT").WillReturnRows(rows).RowsWillBeClosed()
+	contactRows := sqlmock.NewRows([]string{"type", "name", "email", "phone"}).
+		AddRow("Technical", "Alice", "alice@example.com", "555-0100").
+		AddRow("Escalation", "Bob", "bob@example.com", "555-0199")
+	mock.ExpectQuery("SELECT (.+) FROM customer_contacts").WithArgs(1).WillReturnRows(contactRows).RowsWillBeClosed()
 	fetcher := PostgresPhysicalAssetFetcher{DB: mocksqldb}
 
 	expectedAsset := domain.PhysicalAsset{
-		IP:            "127.0.0.1",
-		ResourceOwner: "alice@example.com",
-		BusinessUnit:  "Acme",
-		Network:       "127.0.0.1/32",
-		Location:      "Home",
-		DeviceID:      1,
-		SubnetID:      1,
-		CustomerID:    1,
+		IP:                "127.0.0.1",
+		ResourceOwner:     "alice@example.com",
+		ResourceOwnerRule: "contact-type:Technical",
+		BusinessUnit:      "Acme",
+		Network:           "127.0.0.1/32",
+		Location:          "Home",
+		DeviceID:          1,
+		SubnetID:          1,
+		CustomerID:        1,
+		Contacts: []domain.Contact{
+			{Type: "Technical", Name: "Alice", Email: "alice@example.com", Phone: "555-0100"},
+			{Type: "Escalation", Name: "Bob", Email: "bob@example.com", Phone: "555-0199"},
+		},
 	}
 
 	asset, err := fetcher.FetchPhysicalAsset(context.Background(), "127.0.0.1")
@@ -55,23 +64,27 @@ func TestFetchPhysicalAssetSubnetFound(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	mocksqldb := NewMockSQLDB(ctrl)
-	mocksqldb.EXPECT().Conn().Return(mockdb)
+	mocksqldb.EXPECT().ReadConn().Return(mockdb)
 	rows := sqlmock.NewRows([]string{
 		"ip", "resource_owner", "business_unit", "network", "location",
-		"device_id", "subnet_id", "customer_id"}).AddRow(
-		nil, "alice@example.com", "Acme", "127.0.0.1/32", "Home", nil, 1, 1)
+		"device_id", "subnet_id", "customer_id", "resource_owner_rule"}).AddRow(
+		nil, "alice@example.com", "Acme", "127.0.0.1/32", "Home", nil, 1, 1, "contact-info")
 	mock.ExpectQuery("SELECT").WillReturnRows(rows).RowsWillBeClosed()
+	mock.ExpectQuery("SELECT (.+) FROM customer_contacts").WithArgs(1).WillReturnRows(
+		sqlmock.NewRows([]string{"type", "name", "email", "phone"})).RowsWillBeClosed()
 	fetcher := PostgresPhysicalAssetFetcher{DB: mocksqldb}
 
 	expectedAsset := domain.PhysicalAsset{
-		IP:            "127.0.0.1",
-		ResourceOwner: "alice@example.com",
-		BusinessUnit:  "Acme",
-		Network:       "127.0.0.1/32",
-		Location:      "Home",
-		DeviceID:      0,
-		SubnetID:      1,
-		CustomerID:    1,
+		IP:                "127.0.0.1",
+		ResourceOwner:     "alice@example.com",
+		ResourceOwnerRule: "contact-info",
+		BusinessUnit:      "Acme",
+		Network:           "127.0.0.1/32",
+		Location:          "Home",
+		DeviceID:          0,
+		SubnetID:          1,
+		CustomerID:        1,
+		Contacts:          []domain.Contact{},
 	}
 
 	asset, err := fetcher.FetchPhysicalAsset(context.Background(), "127.0.0.1")
@@ -90,11 +103,11 @@ func TestFetchPhysicalAssetSubnetFoundNoJoinedCustomer(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	mocksqldb := NewMockSQLDB(ctrl)
-	mocksqldb.EXPECT().Conn().Return(mockdb)
+	mocksqldb.EXPECT().ReadConn().Return(mockdb)
 	rows := sqlmock.NewRows([]string{
 		"ip", "resource_owner", "business_unit", "network", "location",
-		"device_id", "subnet_id", "customer_id"}).AddRow(
-		nil, "alice@example.com", "Acme", "127.0.0.1/32", "Home", nil, 1, nil)
+		"device_id", "subnet_id", "customer_id", "resource_owner_rule"}).AddRow(
+		nil, "alice@example.com", "Acme", "127.0.0.1/32", "Home", nil, 1, nil, nil)
 	mock.ExpectQuery("SELECT").WillReturnRows(rows).RowsWillBeClosed()
 	fetcher := PostgresPhysicalAssetFetcher{DB: mocksqldb}
 
@@ -119,6 +132,135 @@ func TestFetchPhysicalAssetSubnetFoundNoJoinedCustomer(t *testing.T) {
 	}
 }
 
+func TestFetchPhysicalAssetInheritedOwner(t *testing.T) {
+	mockdb, mock, err := sqlmock.New()
+	require.Nil(t, err, "an error '%s' was not expected when opening a stub database connection", err)
+	defer mockdb.Close()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mocksqldb := NewMockSQLDB(ctrl)
+	mocksqldb.EXPECT().ReadConn().Return(mockdb)
+	rows := sqlmock.NewRows([]string{
+		"ip", "resource_owner", "business_unit", "network", "location",
+		"device_id", "subnet_id", "customer_id", "resource_owner_rule"}).AddRow(
+		nil, nil, nil, "127.0.0.0/30", "Home", nil, 2, nil, nil)
+	mock.ExpectQuery("SELECT").WillReturnRows(rows).RowsWillBeClosed()
+	inheritedRows := sqlmock.NewRows([]string{
+		"subnet_id", "customer_id", "resource_owner", "business_unit", "resource_owner_rule"}).AddRow(
+		1, 1, "alice@example.com", "Acme", "contact-info")
+	mock.ExpectQuery("SELECT (.+) WHERE s.network >> ").WithArgs("127.0.0.0/30").WillReturnRows(inheritedRows).RowsWillBeClosed()
+	contactRows := sqlmock.NewRows([]string{"type", "name", "email", "phone"}).
+		AddRow("Technical", "Alice", "alice@example.com", "555-0100")
+	mock.ExpectQuery("SELECT (.+) FROM customer_contacts").WithArgs(1).WillReturnRows(contactRows).RowsWillBeClosed()
+	fetcher := PostgresPhysicalAssetFetcher{DB: mocksqldb, InheritOwnership: true}
+
+	expectedAsset := domain.PhysicalAsset{
+		IP:                    "127.0.0.1",
+		ResourceOwner:         "alice@example.com",
+		ResourceOwnerRule:     "contact-info",
+		BusinessUnit:          "Acme",
+		Network:               "127.0.0.0/30",
+		Location:              "Home",
+		DeviceID:              0,
+		SubnetID:              2,
+		CustomerID:            1,
+		Contacts:              []domain.Contact{{Type: "Technical", Name: "Alice", Email: "alice@example.com", Phone: "555-0100"}},
+		InheritedFields:       []string{"resourceOwner", "businessUnit", "customerID", "contacts"},
+		InheritedFromSubnetID: 1,
+	}
+
+	asset, err := fetcher.FetchPhysicalAsset(context.Background(), "127.0.0.1")
+	require.Nil(t, err)
+	require.Equal(t, expectedAsset, asset)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestFetchPhysicalAssetNoInheritedOwner(t *testing.T) {
+	mockdb, mock, err := sqlmock.New()
+	require.Nil(t, err, "an error '%s' was not expected when opening a stub database connection", err)
+	defer mockdb.Close()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mocksqldb := NewMockSQLDB(ctrl)
+	mocksqldb.EXPECT().ReadConn().Return(mockdb)
+	rows := sqlmock.NewRows([]string{
+		"ip", "resource_owner", "business_unit", "network", "location",
+		"device_id", "subnet_id", "customer_id", "resource_owner_rule"}).AddRow(
+		nil, nil, nil, "127.0.0.0/30", "Home", nil, 2, nil, nil)
+	mock.ExpectQuery("SELECT").WillReturnRows(rows).RowsWillBeClosed()
+	mock.ExpectQuery("SELECT (.+) WHERE s.network >> ").WithArgs("127.0.0.0/30").WillReturnError(sql.ErrNoRows)
+	fetcher := PostgresPhysicalAssetFetcher{DB: mocksqldb, InheritOwnership: true}
+
+	expectedAsset := domain.PhysicalAsset{
+		IP:       "127.0.0.1",
+		Network:  "127.0.0.0/30",
+		Location: "Home",
+		DeviceID: 0,
+		SubnetID: 2,
+	}
+
+	asset, err := fetcher.FetchPhysicalAsset(context.Background(), "127.0.0.1")
+	require.Nil(t, err)
+	require.Equal(t, expectedAsset, asset)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestFetchPhysicalAssetInheritedOwnerQueryError(t *testing.T) {
+	mockdb, mock, err := sqlmock.New()
+	require.Nil(t, err, "an error '%s' was not expected when opening a stub database connection", err)
+	defer mockdb.Close()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mocksqldb := NewMockSQLDB(ctrl)
+	mocksqldb.EXPECT().ReadConn().Return(mockdb)
+	rows := sqlmock.NewRows([]string{
+		"ip", "resource_owner", "business_unit", "network", "location",
+		"device_id", "subnet_id", "customer_id", "resource_owner_rule"}).AddRow(
+		nil, nil, nil, "127.0.0.0/30", "Home", nil, 2, nil, nil)
+	mock.ExpectQuery("SELECT").WillReturnRows(rows).RowsWillBeClosed()
+	dberr := errors.New("unexpected error")
+	mock.ExpectQuery("SELECT (.+) WHERE s.network >> ").WillReturnError(dberr)
+	fetcher := PostgresPhysicalAssetFetcher{DB: mocksqldb, InheritOwnership: true}
+
+	_, err = fetcher.FetchPhysicalAsset(context.Background(), "127.0.0.1")
+	require.Equal(t, dberr, err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestFetchPhysicalAssetContactsQueryError(t *testing.T) {
+	mockdb, mock, err := sqlmock.New()
+	require.Nil(t, err, "an error '%s' was not expected when opening a stub database connection", err)
+	defer mockdb.Close()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mocksqldb := NewMockSQLDB(ctrl)
+	mocksqldb.EXPECT().ReadConn().Return(mockdb)
+	rows := sqlmock.NewRows([]string{
+		"ip", "resource_owner", "business_unit", "network", "location",
+		"device_id", "subnet_id", "customer_id", "resource_owner_rule"}).AddRow(
+		nil, "alice@example.com", "Acme", "127.0.0.1/32", "Home", nil, 1, 1, "contact-info")
+	mock.ExpectQuery("SELECT").WillReturnRows(rows).RowsWillBeClosed()
+	dberr := errors.New("unexpected error")
+	mock.ExpectQuery("SELECT (.+) FROM customer_contacts").WillReturnError(dberr)
+	fetcher := PostgresPhysicalAssetFetcher{DB: mocksqldb}
+
+	_, err = fetcher.FetchPhysicalAsset(context.Background(), "127.0.0.1")
+	require.Equal(t, dberr, err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
 func TestFetchPhysicalAssetNoResults(t *testing.T) {
 	mockdb, mock, err := sqlmock.New()
 	require.Nil(t, err, "an error '%s' was not expected when opening a stub database connection", err)
@@ -126,7 +268,7 @@ func TestFetchPhysicalAssetNoResults(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	mocksqldb := NewMockSQLDB(ctrl)
-	mocksqldb.EXPECT().Conn().Return(mockdb)
+	mocksqldb.EXPECT().ReadConn().Return(mockdb)
 	mock.ExpectQuery("SELECT").WillReturnError(sql.ErrNoRows)
 	fetcher := PostgresPhysicalAssetFetcher{DB: mocksqldb}
 
@@ -145,7 +287,7 @@ func TestFetchPhysicalAssetUnexpectedError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	mocksqldb := NewMockSQLDB(ctrl)
-	mocksqldb.EXPECT().Conn().Return(mockdb)
+	mocksqldb.EXPECT().ReadConn().Return(mockdb)
 	dberr := errors.New("unexpected error")
 	mock.ExpectQuery("SELECT").WillReturnError(dberr)
 	fetcher := PostgresPhysicalAssetFetcher{DB: mocksqldb}
@@ -165,7 +307,7 @@ func TestFetchSubnets(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	mocksqldb := NewMockSQLDB(ctrl)
-	mocksqldb.EXPECT().Conn().Return(mockdb)
+	mocksqldb.EXPECT().ReadConn().Return(mockdb)
 	rows := sqlmock.NewRows([]string{
 		"network", "location", "resource_owner", "business_unit"}).
 		AddRow("127.0.0.1/32", "Home", "alice@example.com", "Acme").
@@ -204,7 +346,7 @@ func TestFetchSubnetsQueryError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	mocksqldb := NewMockSQLDB(ctrl)
-	mocksqldb.EXPECT().Conn().Return(mockdb)
+	mocksqldb.EXPECT().ReadConn().Return(mockdb)
 	mock.ExpectQuery("SELECT").WillReturnError(errors.New(""))
 	fetcher := PostgresPhysicalAssetFetcher{DB: mocksqldb}
 
@@ -223,7 +365,7 @@ func TestFetchSubnetsScanError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	mocksqldb := NewMockSQLDB(ctrl)
-	mocksqldb.EXPECT().Conn().Return(mockdb)
+	mocksqldb.EXPECT().ReadConn().Return(mockdb)
 	rows := sqlmock.NewRows([]string{
 		"network", "location", "resource_owner", "business_unit"}).
 		AddRow(nil, "Home", "alice@example.com", "Acme")
@@ -245,7 +387,7 @@ func TestFetchIPs(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	mocksqldb := NewMockSQLDB(ctrl)
-	mocksqldb.EXPECT().Conn().Return(mockdb)
+	mocksqldb.EXPECT().ReadConn().Return(mockdb)
 	rows := sqlmock.NewRows([]string{
 		"ip", "network", "location", "resource_owner", "business_unit"}).
 		AddRow("127.0.0.1", "127.0.0.1/32", "Home", "alice@example.com", "Acme").
@@ -286,7 +428,7 @@ func TestFetchIPsQueryError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	mocksqldb := NewMockSQLDB(ctrl)
-	mocksqldb.EXPECT().Conn().Return(mockdb)
+	mocksqldb.EXPECT().ReadConn().Return(mockdb)
 	mock.ExpectQuery("SELECT").WillReturnError(errors.New(""))
 	fetcher := PostgresPhysicalAssetFetcher{DB: mocksqldb}
 
@@ -305,7 +447,7 @@ func TestFetchIPsScanError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	mocksqldb := NewMockSQLDB(ctrl)
-	mocksqldb.EXPECT().Conn().Return(mockdb)
+	mocksqldb.EXPECT().ReadConn().Return(mockdb)
 	rows := sqlmock.NewRows([]string{
 		"ip", "network", "location", "resource_owner", "business_unit"}).
 		AddRow(nil, "127.0.0.1/32", "Home", "alice@example.com", "Acme")
@@ -0,0 +1,55 @@
+package assetgraph
+
+import (
+	"context"
+	"fmt"
+)
+
+// Config contains configuration settings for the GraphQL queries
+type Config struct {
+	MaxPageSize   int `description:"The most records a single page of a GraphQL connection may request."`
+	MaxDepth      int `description:"The deepest a GraphQL query may nest its fields."`
+	MaxComplexity int `description:"The highest complexity of a GraphQL query, counting every field it selects as many times as the pages of its connections may repeat it."`
+}
+
+// Name is used by the settings library to replace the default naming convention.
+func (*Config) Name() string {
+	return "GraphQL"
+}
+
+// NewComponent generates a new, unititialized Component
+func NewComponent() *Component {
+	return &Component{}
+}
+
+// Component satisfies the settings library Component API,
+// and may be used by the settings.NewComponent function.
+type Component struct{}
+
+// Settings generates a config with default values applied.
+func (*Component) Settings() *Config {
+	return &Config{
+		MaxPageSize:   1000,
+		MaxDepth:      12,
+		MaxComplexity: 100000,
+	}
+}
+
+// New constructs a Querier from a config. The fetcher, logger, and default page size are to
+// be set by the caller.
+func (*Component) New(_ context.Context, conf *Config) (*Querier, error) {
+	if conf.MaxPageSize <= 0 {
+		return nil, fmt.Errorf("the GraphQL max page size must be positive, got %d", conf.MaxPageSize)
+	}
+	if conf.MaxDepth <= 0 {
+		return nil, fmt.Errorf("the GraphQL max depth must be positive, got %d", conf.MaxDepth)
+	}
+	if conf.MaxComplexity <= 0 {
+		return nil, fmt.Errorf("the GraphQL max complexity must be positive, got %d", conf.MaxComplexity)
+	}
+	return &Querier{
+		MaxPageSize:   conf.MaxPageSize,
+		MaxDepth:      conf.MaxDepth,
+		MaxComplexity: conf.MaxComplexity,
+	}, nil
+}
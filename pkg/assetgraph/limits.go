@@ -0,0 +1,153 @@
+package assetgraph
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// connectionFields are the names of the fields that return connections, whose selections
+// are repeated for every record of a page.
+var connectionFields = map[string]bool{
+	"customers": true,
+	"subnets":   true,
+	"ips":       true,
+}
+
+// checkLimits rejects the operation of a validated query when it nests fields deeper than
+// MaxDepth, or when its complexity exceeds MaxComplexity. Fields of the introspection schema
+// are not counted.
+func (q *Querier) checkLimits(document *ast.Document, operationName string, variables map[string]interface{}) error {
+	var operation *ast.OperationDefinition
+	fragments := make(map[string]*ast.FragmentDefinition)
+	for _, definition := range document.Definitions {
+		switch definition := definition.(type) {
+		case *ast.OperationDefinition:
+			name := ""
+			if definition.Name != nil {
+				name = definition.Name.Value
+			}
+			if operation == nil && (operationName == "" || operationName == name) {
+				operation = definition
+			}
+		case *ast.FragmentDefinition:
+			fragments[definition.Name.Value] = definition
+		}
+	}
+	if operation == nil {
+		// The executor reports the missing operation.
+		return nil
+	}
+
+	m := &measure{querier: q, fragments: fragments, variables: variables}
+	depth, complexity := m.selections(operation.SelectionSet, 1)
+	if depth > q.MaxDepth {
+		return fmt.Errorf("the query nests fields %d deep, more than the maximum of %d", depth, q.MaxDepth)
+	}
+	if complexity > q.MaxComplexity {
+		return fmt.Errorf("the query has a complexity of more than the maximum of %d", q.MaxComplexity)
+	}
+	return nil
+}
+
+// measure measures the depth and complexity of the selections of an operation.
+type measure struct {
+	querier   *Querier
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]interface{}
+}
+
+// selections returns the depth of the deepest field of a selection set whose fields are at
+// the given depth, and the complexity of the selection set. The complexity counts each field
+// once, plus the complexity of its selections, which is multiplied by the page size of a
+// connection. Complexities are capped just above the maximum, so that they cannot overflow.
+func (m *measure) selections(selectionSet *ast.SelectionSet, depth int) (int, int) {
+	if selectionSet == nil {
+		return depth - 1, 0
+	}
+	maxDepth, complexity := depth-1, 0
+	for _, selection := range selectionSet.Selections {
+		var selectionDepth, selectionComplexity int
+		switch selection := selection.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(selection.Name.Value, "__") {
+				continue
+			}
+			childDepth, childComplexity := m.selections(selection.SelectionSet, depth+1)
+			if connectionFields[selection.Name.Value] {
+				childComplexity = m.cap(childComplexity * m.pageSize(selection))
+			}
+			selectionDepth, selectionComplexity = maxInt(depth, childDepth), 1+childComplexity
+		case *ast.FragmentSpread:
+			if fragment, ok := m.fragments[selection.Name.Value]; ok {
+				selectionDepth, selectionComplexity = m.selections(fragment.SelectionSet, depth)
+			}
+		case *ast.InlineFragment:
+			selectionDepth, selectionComplexity = m.selections(selection.SelectionSet, depth)
+		}
+		maxDepth = maxInt(maxDepth, selectionDepth)
+		complexity = m.cap(complexity + selectionComplexity)
+	}
+	return maxDepth, complexity
+}
+
+// pageSize returns the page size that the first argument of a connection requests, or the
+// default page size. Page sizes outside the allowed range are rejected when the field is
+// resolved, so they are counted as the maximum.
+func (m *measure) pageSize(field *ast.Field) int {
+	for _, argument := range field.Arguments {
+		if argument.Name.Value != "first" {
+			continue
+		}
+		var first int
+		switch value := argument.Value.(type) {
+		case *ast.IntValue:
+			parsed, err := strconv.Atoi(value.Value)
+			if err != nil {
+				return m.querier.MaxPageSize
+			}
+			first = parsed
+		case *ast.Variable:
+			switch variable := m.variables[value.Name.Value].(type) {
+			case nil:
+				return m.querier.DefaultPageSize
+			case int:
+				first = variable
+			case float64:
+				first = int(variable)
+			case json.Number:
+				parsed, err := variable.Int64()
+				if err != nil {
+					return m.querier.MaxPageSize
+				}
+				first = int(parsed)
+			default:
+				return m.querier.MaxPageSize
+			}
+		default:
+			return m.querier.MaxPageSize
+		}
+		if first < 1 || first > m.querier.MaxPageSize {
+			return m.querier.MaxPageSize
+		}
+		return first
+	}
+	return m.querier.DefaultPageSize
+}
+
+func (m *measure) cap(complexity int) int {
+	if complexity > m.querier.MaxComplexity {
+		return m.querier.MaxComplexity + 1
+	}
+	return complexity
+}
+
+func maxInt(a int, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,101 @@
+// Package assetgraph executes GraphQL queries over the stored customers, subnets, and IP
+// addresses, following the relationships from customers to their subnets and from subnets to
+// their IP addresses. The records at each level of a query are fetched together, with a
+// single call to the domain.AssetGraphFetcher for each relationship, rather than once for
+// every parent record.
+package assetgraph
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+	"github.com/asecurityteam/ipam-facade/pkg/logs"
+)
+
+// errFetch is the error of a field whose records could not be fetched. The cause is logged
+// rather than returned, so that storage errors are not exposed to clients.
+var errFetch = errors.New("failed to fetch the asset graph")
+
+// Querier executes GraphQL queries with the records fetched by its Fetcher. Connections
+// return DefaultPageSize records unless their first argument asks for another number, up to
+// MaxPageSize. Queries that nest fields deeper than MaxDepth, or whose complexity exceeds
+// MaxComplexity, are rejected before they are executed. The complexity of a query counts
+// every field it selects as many times as the pages of its connections may repeat it.
+type Querier struct {
+	Fetcher         domain.AssetGraphFetcher
+	LogFn           domain.LogFn
+	DefaultPageSize int
+	MaxPageSize     int
+	MaxDepth        int
+	MaxComplexity   int
+
+	once      sync.Once
+	schema    graphql.Schema
+	schemaErr error
+}
+
+// QueryAssetGraph executes a GraphQL query.
+func (q *Querier) QueryAssetGraph(ctx context.Context, request domain.AssetGraphRequest) domain.AssetGraphResult {
+	q.once.Do(func() {
+		q.schema, q.schemaErr = q.newSchema()
+	})
+	if q.schemaErr != nil {
+		return rejected(q.schemaErr)
+	}
+
+	document, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{
+		Body: []byte(request.Query),
+		Name: "GraphQL request",
+	})})
+	if err != nil {
+		return resultFromGraphQL(&graphql.Result{Errors: gqlerrors.FormatErrors(err)})
+	}
+	validation := graphql.ValidateDocument(&q.schema, document, nil)
+	if !validation.IsValid {
+		return resultFromGraphQL(&graphql.Result{Errors: validation.Errors})
+	}
+	if err := q.checkLimits(document, request.OperationName, request.Variables); err != nil {
+		q.LogFn(ctx).Info(logs.AssetGraphQueryRejected{Reason: err.Error()})
+		return rejected(err)
+	}
+
+	return resultFromGraphQL(graphql.Execute(graphql.ExecuteParams{
+		Schema:        q.schema,
+		AST:           document,
+		OperationName: request.OperationName,
+		Args:          request.Variables,
+		Context:       newRequestContext(ctx),
+	}))
+}
+
+// fetchFailed logs the error of a fetch, and returns the error of the field to the client.
+func (q *Querier) fetchFailed(ctx context.Context, err error) error {
+	q.LogFn(ctx).Error(logs.AssetGraphFetchFailure{Reason: err.Error()})
+	return errFetch
+}
+
+func rejected(err error) domain.AssetGraphResult {
+	return domain.AssetGraphResult{Errors: []domain.AssetGraphError{{Message: err.Error()}}}
+}
+
+func resultFromGraphQL(result *graphql.Result) domain.AssetGraphResult {
+	converted := domain.AssetGraphResult{Data: result.Data}
+	for _, err := range result.Errors {
+		graphError := domain.AssetGraphError{Message: err.Message, Path: err.Path}
+		for _, location := range err.Locations {
+			graphError.Locations = append(graphError.Locations, domain.AssetGraphLocation{
+				Line:   location.Line,
+				Column: location.Column,
+			})
+		}
+		converted.Errors = append(converted.Errors, graphError)
+	}
+	return converted
+}
@@ -0,0 +1,300 @@
+package assetgraph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	"github.com/asecurityteam/logevent"
+	gomock "github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+	"github.com/asecurityteam/ipam-facade/pkg/memstore"
+)
+
+func discardLogger(context.Context) domain.Logger {
+	return logevent.New(logevent.Config{Output: ioutil.Discard})
+}
+
+// countingFetcher counts the calls made to the fetcher it wraps.
+type countingFetcher struct {
+	domain.AssetGraphFetcher
+	calls map[string]int
+}
+
+func (f *countingFetcher) QueryCustomers(ctx context.Context, query domain.CustomerQuery) ([]domain.GraphCustomer, error) {
+	f.calls["customers"]++
+	return f.AssetGraphFetcher.QueryCustomers(ctx, query)
+}
+
+func (f *countingFetcher) QueryContacts(ctx context.Context, customerIDs []int64) ([]domain.GraphContact, error) {
+	f.calls["contacts"]++
+	return f.AssetGraphFetcher.QueryContacts(ctx, customerIDs)
+}
+
+func (f *countingFetcher) QuerySubnets(ctx context.Context, query domain.SubnetQuery) ([]domain.GraphSubnet, error) {
+	f.calls["subnets"]++
+	return f.AssetGraphFetcher.QuerySubnets(ctx, query)
+}
+
+func (f *countingFetcher) QueryIPs(ctx context.Context, query domain.IPQuery) ([]domain.GraphIP, error) {
+	f.calls["ips"]++
+	return f.AssetGraphFetcher.QueryIPs(ctx, query)
+}
+
+// newTestQuerier returns a Querier of a dataset stored in memory, along with the fetcher that
+// counts its calls.
+func newTestQuerier(t *testing.T) (*Querier, *countingFetcher) {
+	store := &memstore.Store{}
+	require.NoError(t, store.StorePhysicalAssets(context.Background(), domain.IPAMData{
+		Customers: []domain.Customer{
+			{ID: "1", ResourceOwner: "alice@example.com", ResourceOwnerRule: "contact-type:SRE", BusinessUnit: "Team A",
+				Contacts: []domain.Contact{{Type: "SRE", Name: "Alice", Email: "alice@example.com", Phone: "555-0100"}}},
+			{ID: "2", ResourceOwner: "bob@example.com", ResourceOwnerRule: "contact-info", BusinessUnit: "Team B"},
+		},
+		Subnets: []domain.Subnet{
+			{ID: "1", Network: "10.1.0.0", MaskBits: 16, Location: "DC1", CustomerID: "1"},
+			{ID: "2", Network: "10.2.0.0", MaskBits: 16, Location: "DC2", CustomerID: "2"},
+			{ID: "3", Network: "10.1.1.0", MaskBits: 24, Location: "DC1", CustomerID: "2"},
+			{ID: "4", Network: "10.3.0.0", MaskBits: 24, Location: "DC3"},
+		},
+		Devices: []domain.Device{
+			{ID: "100", IP: "10.1.0.10", SubnetID: "1"},
+			{IP: "10.1.0.11", SubnetID: "1"},
+			{ID: "300", IP: "10.1.1.5", SubnetID: "3"},
+		},
+	}))
+	fetcher := &countingFetcher{AssetGraphFetcher: store, calls: make(map[string]int)}
+	component := NewComponent()
+	querier, err := component.New(context.Background(), component.Settings())
+	require.NoError(t, err)
+	querier.Fetcher = fetcher
+	querier.LogFn = discardLogger
+	querier.DefaultPageSize = 10
+	return querier, fetcher
+}
+
+func requireData(t *testing.T, expected string, result domain.AssetGraphResult) {
+	require.Empty(t, result.Errors)
+	data, err := json.Marshal(result.Data)
+	require.NoError(t, err)
+	require.JSONEq(t, expected, string(data))
+}
+
+func TestQueryAssetGraph(t *testing.T) {
+	tc := []struct {
+		name      string
+		query     string
+		variables map[string]interface{}
+		expected  string
+	}{
+		{
+			name:  "customers",
+			query: `{ customers { edges { node { id resourceOwner resourceOwnerRule businessUnit contacts { type name email phone } } } } }`,
+			expected: `{"customers": {"edges": [
+				{"node": {"id": "1", "resourceOwner": "alice@example.com", "resourceOwnerRule": "contact-type:SRE", "businessUnit": "Team A",
+					"contacts": [{"type": "SRE", "name": "Alice", "email": "alice@example.com", "phone": "555-0100"}]}},
+				{"node": {"id": "2", "resourceOwner": "bob@example.com", "resourceOwnerRule": "contact-info", "businessUnit": "Team B", "contacts": []}}
+			]}}`,
+		},
+		{
+			name:  "customer subnets and IPs",
+			query: `{ customers(businessUnit: "Team B") { edges { node { id subnets { edges { node { network ips { edges { node { ip deviceID } } } } } } } } } }`,
+			expected: `{"customers": {"edges": [{"node": {"id": "2", "subnets": {"edges": [
+				{"node": {"network": "10.2.0.0/16", "ips": {"edges": []}}},
+				{"node": {"network": "10.1.1.0/24", "ips": {"edges": [{"node": {"ip": "10.1.1.5", "deviceID": "300"}}]}}}
+			]}}}]}}`,
+		},
+		{
+			name:  "subnets containing an address",
+			query: `query($ip: String!) { subnets(containing: [$ip]) { edges { node { id network location customer { resourceOwner } } } } }`,
+			variables: map[string]interface{}{
+				"ip": "10.1.1.5",
+			},
+			expected: `{"subnets": {"edges": [
+				{"node": {"id": "1", "network": "10.1.0.0/16", "location": "DC1", "customer": {"resourceOwner": "alice@example.com"}}},
+				{"node": {"id": "3", "network": "10.1.1.0/24", "location": "DC1", "customer": {"resourceOwner": "bob@example.com"}}}
+			]}}`,
+		},
+		{
+			name:     "subnets without a customer",
+			query:    `{ subnets(location: "DC3") { edges { node { network customer { id } } } } }`,
+			expected: `{"subnets": {"edges": [{"node": {"network": "10.3.0.0/24", "customer": null}}]}}`,
+		},
+		{
+			name:  "IPs within a network",
+			query: `{ ips(within: "10.1.0.0/24") { edges { node { ip deviceID subnet { network } } } } }`,
+			expected: `{"ips": {"edges": [
+				{"node": {"ip": "10.1.0.10", "deviceID": "100", "subnet": {"network": "10.1.0.0/16"}}},
+				{"node": {"ip": "10.1.0.11", "deviceID": null, "subnet": {"network": "10.1.0.0/16"}}}
+			]}}`,
+		},
+		{
+			name:     "IP by device",
+			query:    `{ ips(deviceID: 300) { edges { node { ip } } } }`,
+			expected: `{"ips": {"edges": [{"node": {"ip": "10.1.1.5"}}]}}`,
+		},
+	}
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			querier, _ := newTestQuerier(t)
+			result := querier.QueryAssetGraph(context.Background(), domain.AssetGraphRequest{Query: tt.query, Variables: tt.variables})
+			requireData(t, tt.expected, result)
+		})
+	}
+}
+
+func TestQueryAssetGraphBatching(t *testing.T) {
+	querier, fetcher := newTestQuerier(t)
+	result := querier.QueryAssetGraph(context.Background(), domain.AssetGraphRequest{Query: `{
+		customers {
+			edges { node { contacts { name } subnets(first: 1) { edges { node { customer { id } ips { edges { node { ip subnet { id } } } } } } } } }
+		}
+	}`})
+	require.Empty(t, result.Errors)
+	require.Equal(t, map[string]int{"customers": 2, "contacts": 1, "subnets": 2, "ips": 1}, fetcher.calls)
+
+	requireData(t, `{"customers": {"edges": [
+		{"node": {"contacts": [{"name": "Alice"}], "subnets": {"edges": [{"node": {"customer": {"id": "1"}, "ips": {"edges": [
+			{"node": {"ip": "10.1.0.10", "subnet": {"id": "1"}}},
+			{"node": {"ip": "10.1.0.11", "subnet": {"id": "1"}}}
+		]}}}]}}},
+		{"node": {"contacts": [], "subnets": {"edges": [{"node": {"customer": {"id": "2"}, "ips": {"edges": []}}}]}}}
+	]}}`, result)
+}
+
+func TestQueryAssetGraphPagination(t *testing.T) {
+	querier, _ := newTestQuerier(t)
+	query := `query($after: String) { subnets(first: 3, after: $after) { edges { node { id } } pageInfo { endCursor hasNextPage } } }`
+
+	result := querier.QueryAssetGraph(context.Background(), domain.AssetGraphRequest{Query: query})
+	require.Empty(t, result.Errors)
+	connection := result.Data.(map[string]interface{})["subnets"].(map[string]interface{})
+	require.Len(t, connection["edges"], 3)
+	pageInfo := connection["pageInfo"].(map[string]interface{})
+	require.Equal(t, true, pageInfo["hasNextPage"])
+
+	result = querier.QueryAssetGraph(context.Background(), domain.AssetGraphRequest{
+		Query:     query,
+		Variables: map[string]interface{}{"after": pageInfo["endCursor"]},
+	})
+	requireData(t, `{"subnets": {"edges": [{"node": {"id": "4"}}], "pageInfo": {"endCursor": "`+encodeCursor(subnetKind, 4)+`", "hasNextPage": false}}}`, result)
+
+	result = querier.QueryAssetGraph(context.Background(), domain.AssetGraphRequest{
+		Query: `{ customers(after: "` + encodeCursor(customerKind, 2) + `") { edges { node { id } } pageInfo { endCursor hasNextPage } } }`,
+	})
+	requireData(t, `{"customers": {"edges": [], "pageInfo": {"endCursor": null, "hasNextPage": false}}}`, result)
+}
+
+func TestQueryAssetGraphRejected(t *testing.T) {
+	tc := []struct {
+		name      string
+		query     string
+		variables map[string]interface{}
+	}{
+		{
+			name:  "syntax error",
+			query: `{ customers {`,
+		},
+		{
+			name:  "unknown field",
+			query: `{ devices { id } }`,
+		},
+		{
+			name:  "too deep",
+			query: `{ ips { edges { node { subnet { customer { subnets { edges { node { ips { edges { node { subnet { id } } } } } } } } } } } } } }`,
+		},
+		{
+			name:  "too deep through a fragment",
+			query: `{ ips { edges { node { subnet { customer { subnets { edges { node { ...ips } } } } } } } } } fragment ips on Subnet { ips { edges { node { subnet { id } } } } }`,
+		},
+		{
+			name:  "too complex",
+			query: `{ customers(first: 1000) { edges { node { subnets(first: 1000) { edges { node { id } } } } } } }`,
+		},
+		{
+			name:      "too complex through a variable",
+			query:     `query($first: Int) { customers(first: $first) { edges { node { subnets(first: $first) { edges { node { id } } } } } } }`,
+			variables: map[string]interface{}{"first": float64(500)},
+		},
+	}
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			querier, fetcher := newTestQuerier(t)
+			result := querier.QueryAssetGraph(context.Background(), domain.AssetGraphRequest{Query: tt.query, Variables: tt.variables})
+			require.Nil(t, result.Data)
+			require.Len(t, result.Errors, 1)
+			require.Empty(t, fetcher.calls)
+		})
+	}
+}
+
+func TestQueryAssetGraphInvalidArguments(t *testing.T) {
+	tc := []struct {
+		name    string
+		query   string
+		message string
+	}{
+		{
+			name:    "page too large",
+			query:   `{ customers(first: 1001) { edges { node { id } } } }`,
+			message: "first must be between 1 and 1000, got 1001",
+		},
+		{
+			name:    "empty page",
+			query:   `{ ips(first: 0) { edges { node { ip } } } }`,
+			message: "first must be between 1 and 1000, got 0",
+		},
+		{
+			name:    "cursor of another kind",
+			query:   `{ subnets(after: "` + encodeCursor(customerKind, 1) + `") { edges { node { id } } } }`,
+			message: `invalid subnet cursor "` + encodeCursor(customerKind, 1) + `"`,
+		},
+		{
+			name:    "invalid network",
+			query:   `{ subnets(within: "10.1.0.1/16") { edges { node { id } } } }`,
+			message: `network "10.1.0.1/16" has bits set after the mask`,
+		},
+		{
+			name:    "invalid address",
+			query:   `{ ips(address: "10.1.0.0/16") { edges { node { ip } } } }`,
+			message: `invalid IP address "10.1.0.0/16"`,
+		},
+		{
+			name:    "invalid ID",
+			query:   `{ customers(ids: ["one"]) { edges { node { id } } } }`,
+			message: `invalid ID "one"`,
+		},
+	}
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			querier, _ := newTestQuerier(t)
+			result := querier.QueryAssetGraph(context.Background(), domain.AssetGraphRequest{Query: tt.query})
+			require.Len(t, result.Errors, 1)
+			require.Equal(t, tt.message, result.Errors[0].Message)
+			require.NotEmpty(t, result.Errors[0].Locations)
+			require.NotEmpty(t, result.Errors[0].Path)
+		})
+	}
+}
+
+func TestQueryAssetGraphFetchError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFetcher := NewMockAssetGraphFetcher(ctrl)
+	mockFetcher.EXPECT().QueryCustomers(gomock.Any(), domain.CustomerQuery{Limit: 11}).Return([]domain.GraphCustomer{{ID: 1}, {ID: 2}}, nil)
+	mockFetcher.EXPECT().QueryContacts(gomock.Any(), []int64{1, 2}).Return(nil, errors.New("connection refused"))
+	querier := &Querier{Fetcher: mockFetcher, LogFn: discardLogger, DefaultPageSize: 10, MaxPageSize: 100, MaxDepth: 10, MaxComplexity: 1000}
+
+	result := querier.QueryAssetGraph(context.Background(), domain.AssetGraphRequest{
+		Query: `{ customers { edges { node { id contacts { name } } } } }`,
+	})
+	require.NotEmpty(t, result.Errors)
+	for _, err := range result.Errors {
+		require.Equal(t, errFetch.Error(), err.Message)
+	}
+}
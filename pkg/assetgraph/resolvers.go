@@ -0,0 +1,308 @@
+package assetgraph
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+)
+
+type requestKey struct{}
+
+// request holds the batches of a single query. The executor resolves fields one at a time, so
+// the batches are not locked.
+type request struct {
+	batches map[string]*batch
+}
+
+func newRequestContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, requestKey{}, &request{batches: make(map[string]*batch)})
+}
+
+// batchOf returns the batch of a relationship, creating it with its load function if needed.
+// Fields of the same relationship with different arguments have different keys.
+func batchOf(ctx context.Context, key string, load func(parentIDs []int64) (map[int64]interface{}, error)) *batch {
+	r := ctx.Value(requestKey{}).(*request)
+	b, ok := r.batches[key]
+	if !ok {
+		b = &batch{load: load, queued: make(map[int64]bool), results: make(map[int64]interface{})}
+		r.batches[key] = b
+	}
+	return b
+}
+
+// batch loads the records related to several parents with a single fetch. The executor
+// resolves the fields of every parent at a level of the query before it calls the thunks that
+// return their results, so the first thunk called loads the records of every parent added.
+type batch struct {
+	load    func(parentIDs []int64) (map[int64]interface{}, error)
+	pending []int64
+	queued  map[int64]bool
+	results map[int64]interface{}
+	err     error
+}
+
+// add adds a parent to the batch, returning the thunk of its result.
+func (b *batch) add(parentID int64) func() (interface{}, error) {
+	if !b.queued[parentID] {
+		b.queued[parentID] = true
+		b.pending = append(b.pending, parentID)
+	}
+	return func() (interface{}, error) {
+		if len(b.pending) > 0 {
+			pending := b.pending
+			b.pending = nil
+			results, err := b.load(pending)
+			if err != nil {
+				b.err = err
+			}
+			for parentID, result := range results {
+				b.results[parentID] = result
+			}
+		}
+		if b.err != nil {
+			return nil, b.err
+		}
+		return b.results[parentID], nil
+	}
+}
+
+func (q *Querier) resolveCustomers(p graphql.ResolveParams) (interface{}, error) {
+	requested, err := q.page(p.Args, customerKind)
+	if err != nil {
+		return nil, err
+	}
+	query := domain.CustomerQuery{
+		ResourceOwner: stringArg(p.Args, "resourceOwner"),
+		BusinessUnit:  stringArg(p.Args, "businessUnit"),
+		After:         requested.after,
+		Limit:         requested.first + 1,
+	}
+	if query.IDs, err = idsArg(p.Args, "ids"); err != nil {
+		return nil, err
+	}
+	customers, err := q.Fetcher.QueryCustomers(p.Context, query)
+	if err != nil {
+		return nil, q.fetchFailed(p.Context, err)
+	}
+	return newConnection(customerKind, requested.first, len(customers), func(i int) (int64, interface{}) {
+		return customers[i].ID, customers[i]
+	}), nil
+}
+
+func (q *Querier) resolveSubnets(p graphql.ResolveParams) (interface{}, error) {
+	requested, err := q.page(p.Args, subnetKind)
+	if err != nil {
+		return nil, err
+	}
+	query, err := subnetQuery(p.Args, requested)
+	if err != nil {
+		return nil, err
+	}
+	if query.IDs, err = idsArg(p.Args, "ids"); err != nil {
+		return nil, err
+	}
+	subnets, err := q.Fetcher.QuerySubnets(p.Context, query)
+	if err != nil {
+		return nil, q.fetchFailed(p.Context, err)
+	}
+	return newConnection(subnetKind, requested.first, len(subnets), func(i int) (int64, interface{}) {
+		return subnets[i].ID, subnets[i]
+	}), nil
+}
+
+func (q *Querier) resolveIPs(p graphql.ResolveParams) (interface{}, error) {
+	requested, err := q.page(p.Args, ipKind)
+	if err != nil {
+		return nil, err
+	}
+	query, err := ipQuery(p.Args, requested)
+	if err != nil {
+		return nil, err
+	}
+	ips, err := q.Fetcher.QueryIPs(p.Context, query)
+	if err != nil {
+		return nil, q.fetchFailed(p.Context, err)
+	}
+	return newConnection(ipKind, requested.first, len(ips), func(i int) (int64, interface{}) {
+		return ips[i].ID, ips[i]
+	}), nil
+}
+
+func (q *Querier) resolveContacts(p graphql.ResolveParams) (interface{}, error) {
+	customer := p.Source.(domain.GraphCustomer)
+	b := batchOf(p.Context, "contacts", func(customerIDs []int64) (map[int64]interface{}, error) {
+		contacts, err := q.Fetcher.QueryContacts(p.Context, customerIDs)
+		if err != nil {
+			return nil, q.fetchFailed(p.Context, err)
+		}
+		grouped := make(map[int64][]domain.Contact, len(customerIDs))
+		for _, contact := range contacts {
+			grouped[contact.CustomerID] = append(grouped[contact.CustomerID], contact.Contact)
+		}
+		results := make(map[int64]interface{}, len(customerIDs))
+		for _, customerID := range customerIDs {
+			results[customerID] = append(make([]domain.Contact, 0, len(grouped[customerID])), grouped[customerID]...)
+		}
+		return results, nil
+	})
+	return b.add(customer.ID), nil
+}
+
+func (q *Querier) resolveCustomerSubnets(p graphql.ResolveParams) (interface{}, error) {
+	customer := p.Source.(domain.GraphCustomer)
+	requested, err := q.page(p.Args, subnetKind)
+	if err != nil {
+		return nil, err
+	}
+	query, err := subnetQuery(p.Args, requested)
+	if err != nil {
+		return nil, err
+	}
+	b := batchOf(p.Context, fmt.Sprintf("customer subnets %+v", query), func(customerIDs []int64) (map[int64]interface{}, error) {
+		query.CustomerIDs = customerIDs
+		subnets, err := q.Fetcher.QuerySubnets(p.Context, query)
+		if err != nil {
+			return nil, q.fetchFailed(p.Context, err)
+		}
+		grouped := make(map[int64][]domain.GraphSubnet, len(customerIDs))
+		for _, subnet := range subnets {
+			grouped[subnet.CustomerID] = append(grouped[subnet.CustomerID], subnet)
+		}
+		results := make(map[int64]interface{}, len(customerIDs))
+		for _, customerID := range customerIDs {
+			page := grouped[customerID]
+			results[customerID] = newConnection(subnetKind, requested.first, len(page), func(i int) (int64, interface{}) {
+				return page[i].ID, page[i]
+			})
+		}
+		return results, nil
+	})
+	return b.add(customer.ID), nil
+}
+
+func (q *Querier) resolveSubnetIPs(p graphql.ResolveParams) (interface{}, error) {
+	subnet := p.Source.(domain.GraphSubnet)
+	requested, err := q.page(p.Args, ipKind)
+	if err != nil {
+		return nil, err
+	}
+	query, err := ipQuery(p.Args, requested)
+	if err != nil {
+		return nil, err
+	}
+	b := batchOf(p.Context, fmt.Sprintf("subnet ips %+v", query), func(subnetIDs []int64) (map[int64]interface{}, error) {
+		query.SubnetIDs = subnetIDs
+		ips, err := q.Fetcher.QueryIPs(p.Context, query)
+		if err != nil {
+			return nil, q.fetchFailed(p.Context, err)
+		}
+		grouped := make(map[int64][]domain.GraphIP, len(subnetIDs))
+		for _, ip := range ips {
+			grouped[ip.SubnetID] = append(grouped[ip.SubnetID], ip)
+		}
+		results := make(map[int64]interface{}, len(subnetIDs))
+		for _, subnetID := range subnetIDs {
+			page := grouped[subnetID]
+			results[subnetID] = newConnection(ipKind, requested.first, len(page), func(i int) (int64, interface{}) {
+				return page[i].ID, page[i]
+			})
+		}
+		return results, nil
+	})
+	return b.add(subnet.ID), nil
+}
+
+func (q *Querier) resolveSubnetCustomer(p graphql.ResolveParams) (interface{}, error) {
+	subnet := p.Source.(domain.GraphSubnet)
+	if subnet.CustomerID == 0 {
+		return nil, nil
+	}
+	b := batchOf(p.Context, "subnet customer", func(customerIDs []int64) (map[int64]interface{}, error) {
+		customers, err := q.Fetcher.QueryCustomers(p.Context, domain.CustomerQuery{IDs: customerIDs})
+		if err != nil {
+			return nil, q.fetchFailed(p.Context, err)
+		}
+		results := make(map[int64]interface{}, len(customers))
+		for _, customer := range customers {
+			results[customer.ID] = customer
+		}
+		return results, nil
+	})
+	return b.add(subnet.CustomerID), nil
+}
+
+func (q *Querier) resolveIPSubnet(p graphql.ResolveParams) (interface{}, error) {
+	ip := p.Source.(domain.GraphIP)
+	b := batchOf(p.Context, "ip subnet", func(subnetIDs []int64) (map[int64]interface{}, error) {
+		subnets, err := q.Fetcher.QuerySubnets(p.Context, domain.SubnetQuery{IDs: subnetIDs})
+		if err != nil {
+			return nil, q.fetchFailed(p.Context, err)
+		}
+		results := make(map[int64]interface{}, len(subnets))
+		for _, subnet := range subnets {
+			results[subnet.ID] = subnet
+		}
+		return results, nil
+	})
+	return b.add(ip.SubnetID), nil
+}
+
+// subnetQuery builds the query of a page of subnets from the filter arguments.
+func subnetQuery(args map[string]interface{}, requested page) (domain.SubnetQuery, error) {
+	query := domain.SubnetQuery{
+		Location: stringArg(args, "location"),
+		After:    requested.after,
+		Limit:    requested.first + 1,
+	}
+	if containing, ok := args["containing"].([]interface{}); ok {
+		query.Containing = make([]string, 0, len(containing))
+		for _, value := range containing {
+			network, err := networkArg(fmt.Sprint(value))
+			if err != nil {
+				return domain.SubnetQuery{}, err
+			}
+			query.Containing = append(query.Containing, network)
+		}
+	}
+	if within := stringArg(args, "within"); within != "" {
+		network, err := networkArg(within)
+		if err != nil {
+			return domain.SubnetQuery{}, err
+		}
+		query.Within = network
+	}
+	return query, nil
+}
+
+// ipQuery builds the query of a page of IP addresses from the filter arguments.
+func ipQuery(args map[string]interface{}, requested page) (domain.IPQuery, error) {
+	query := domain.IPQuery{
+		After: requested.after,
+		Limit: requested.first + 1,
+	}
+	if address := stringArg(args, "address"); address != "" {
+		if net.ParseIP(address) == nil {
+			return domain.IPQuery{}, fmt.Errorf("invalid IP address %q", address)
+		}
+		query.Address = address
+	}
+	if deviceID, ok := args["deviceID"]; ok && deviceID != nil {
+		id, err := idArg(deviceID)
+		if err != nil {
+			return domain.IPQuery{}, err
+		}
+		query.DeviceID = id
+	}
+	if within := stringArg(args, "within"); within != "" {
+		network, err := networkArg(within)
+		if err != nil {
+			return domain.IPQuery{}, err
+		}
+		query.Within = network
+	}
+	return query, nil
+}
@@ -0,0 +1,307 @@
+package assetgraph
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+)
+
+// Kinds of the records that cursors point to.
+const (
+	customerKind = "customer"
+	subnetKind   = "subnet"
+	ipKind       = "ip"
+)
+
+// connection is a page of the records of a connection field, along with the cursors that
+// point to them.
+type connection struct {
+	Edges    []edge
+	PageInfo pageInfo
+}
+
+type edge struct {
+	Cursor string
+	Node   interface{}
+}
+
+type pageInfo struct {
+	EndCursor   *string
+	HasNextPage bool
+}
+
+// newSchema builds the GraphQL schema:
+//
+//	type Query {
+//	  customers(first: Int, after: String, ids: [ID!], resourceOwner: String, businessUnit: String): CustomerConnection!
+//	  subnets(first: Int, after: String, ids: [ID!], containing: [String!], within: String, location: String): SubnetConnection!
+//	  ips(first: Int, after: String, address: String, deviceID: ID, within: String): IPConnection!
+//	}
+//	type Customer {
+//	  id: ID!, resourceOwner: String!, resourceOwnerRule: String!, businessUnit: String!, contacts: [Contact!]!
+//	  subnets(first: Int, after: String, containing: [String!], within: String, location: String): SubnetConnection!
+//	}
+//	type Subnet {
+//	  id: ID!, network: String!, location: String!, customer: Customer
+//	  ips(first: Int, after: String, address: String, deviceID: ID, within: String): IPConnection!
+//	}
+//	type IP { ip: String!, deviceID: ID, subnet: Subnet! }
+//	type Contact { type: String!, name: String!, email: String!, phone: String! }
+//
+// Every connection has edges, with the cursor and node of each record, and a pageInfo with
+// the endCursor of the page and whether it hasNextPage.
+func (q *Querier) newSchema() (graphql.Schema, error) {
+	pageInfoType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "PageInfo",
+		Fields: graphql.Fields{
+			"endCursor":   &graphql.Field{Type: graphql.String},
+			"hasNextPage": &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean)},
+		},
+	})
+	contactType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Contact",
+		Fields: graphql.Fields{
+			"type":  &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"name":  &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"email": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"phone": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		},
+	})
+
+	var customerType, subnetType, ipType *graphql.Object
+	var customerConnection, subnetConnection, ipConnection graphql.Output
+	customerType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Customer",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":                &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+				"resourceOwner":     &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"resourceOwnerRule": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"businessUnit":      &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"contacts": &graphql.Field{
+					Type:    graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(contactType))),
+					Resolve: q.resolveContacts,
+				},
+				"subnets": &graphql.Field{
+					Type:    subnetConnection,
+					Args:    pageArgs(subnetFilterArgs()),
+					Resolve: q.resolveCustomerSubnets,
+				},
+			}
+		}),
+	})
+	subnetType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Subnet",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":       &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+				"network":  &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"location": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"customer": &graphql.Field{
+					Type:    customerType,
+					Resolve: q.resolveSubnetCustomer,
+				},
+				"ips": &graphql.Field{
+					Type:    ipConnection,
+					Args:    pageArgs(ipFilterArgs()),
+					Resolve: q.resolveSubnetIPs,
+				},
+			}
+		}),
+	})
+	ipType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "IP",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"ip": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"deviceID": &graphql.Field{
+					Type: graphql.ID,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						if ip := p.Source.(domain.GraphIP); ip.DeviceID != 0 {
+							return ip.DeviceID, nil
+						}
+						return nil, nil
+					},
+				},
+				"subnet": &graphql.Field{
+					Type:    graphql.NewNonNull(subnetType),
+					Resolve: q.resolveIPSubnet,
+				},
+			}
+		}),
+	})
+	customerConnection = connectionType("Customer", customerType, pageInfoType)
+	subnetConnection = connectionType("Subnet", subnetType, pageInfoType)
+	ipConnection = connectionType("IP", ipType, pageInfoType)
+
+	customerArgs := pageArgs(graphql.FieldConfigArgument{
+		"ids":           &graphql.ArgumentConfig{Type: graphql.NewList(graphql.NewNonNull(graphql.ID))},
+		"resourceOwner": &graphql.ArgumentConfig{Type: graphql.String},
+		"businessUnit":  &graphql.ArgumentConfig{Type: graphql.String},
+	})
+	subnetArgs := pageArgs(subnetFilterArgs())
+	subnetArgs["ids"] = &graphql.ArgumentConfig{Type: graphql.NewList(graphql.NewNonNull(graphql.ID))}
+
+	return graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"customers": &graphql.Field{Type: customerConnection, Args: customerArgs, Resolve: q.resolveCustomers},
+				"subnets":   &graphql.Field{Type: subnetConnection, Args: subnetArgs, Resolve: q.resolveSubnets},
+				"ips":       &graphql.Field{Type: ipConnection, Args: pageArgs(ipFilterArgs()), Resolve: q.resolveIPs},
+			},
+		}),
+	})
+}
+
+// connectionType builds the connection and edge types of a node type.
+func connectionType(name string, nodeType *graphql.Object, pageInfoType *graphql.Object) graphql.Output {
+	edgeType := graphql.NewObject(graphql.ObjectConfig{
+		Name: name + "Edge",
+		Fields: graphql.Fields{
+			"cursor": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"node":   &graphql.Field{Type: graphql.NewNonNull(nodeType)},
+		},
+	})
+	return graphql.NewNonNull(graphql.NewObject(graphql.ObjectConfig{
+		Name: name + "Connection",
+		Fields: graphql.Fields{
+			"edges":    &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(edgeType)))},
+			"pageInfo": &graphql.Field{Type: graphql.NewNonNull(pageInfoType)},
+		},
+	}))
+}
+
+// pageArgs adds the pagination arguments of a connection to its filters.
+func pageArgs(args graphql.FieldConfigArgument) graphql.FieldConfigArgument {
+	args["first"] = &graphql.ArgumentConfig{Type: graphql.Int}
+	args["after"] = &graphql.ArgumentConfig{Type: graphql.String}
+	return args
+}
+
+func subnetFilterArgs() graphql.FieldConfigArgument {
+	return graphql.FieldConfigArgument{
+		"containing": &graphql.ArgumentConfig{Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+		"within":     &graphql.ArgumentConfig{Type: graphql.String},
+		"location":   &graphql.ArgumentConfig{Type: graphql.String},
+	}
+}
+
+func ipFilterArgs() graphql.FieldConfigArgument {
+	return graphql.FieldConfigArgument{
+		"address":  &graphql.ArgumentConfig{Type: graphql.String},
+		"deviceID": &graphql.ArgumentConfig{Type: graphql.ID},
+		"within":   &graphql.ArgumentConfig{Type: graphql.String},
+	}
+}
+
+// page is the page of a connection requested by its arguments.
+type page struct {
+	first int
+	after int64
+}
+
+// page parses the pagination arguments of a connection to records of a kind.
+func (q *Querier) page(args map[string]interface{}, kind string) (page, error) {
+	requested := page{first: q.DefaultPageSize}
+	if first, ok := args["first"].(int); ok {
+		if first < 1 || first > q.MaxPageSize {
+			return page{}, fmt.Errorf("first must be between 1 and %d, got %d", q.MaxPageSize, first)
+		}
+		requested.first = first
+	}
+	if after, ok := args["after"].(string); ok {
+		id, err := decodeCursor(kind, after)
+		if err != nil {
+			return page{}, err
+		}
+		requested.after = id
+	}
+	return requested, nil
+}
+
+// newConnection builds a connection from a page of records, fetched with a limit of one
+// more than the page so that the next page is known to exist.
+func newConnection(kind string, first int, count int, record func(i int) (int64, interface{})) *connection {
+	result := &connection{Edges: make([]edge, 0, first)}
+	for i := 0; i < count && i < first; i++ {
+		id, node := record(i)
+		result.Edges = append(result.Edges, edge{Cursor: encodeCursor(kind, id), Node: node})
+	}
+	if len(result.Edges) > 0 {
+		result.PageInfo.EndCursor = &result.Edges[len(result.Edges)-1].Cursor
+	}
+	result.PageInfo.HasNextPage = count > first
+	return result
+}
+
+// encodeCursor returns the opaque cursor of a record.
+func encodeCursor(kind string, id int64) string {
+	return base64.StdEncoding.EncodeToString([]byte(kind + ":" + strconv.FormatInt(id, 10)))
+}
+
+// decodeCursor returns the ID of the record of a kind that a cursor points to.
+func decodeCursor(kind string, cursor string) (int64, error) {
+	decoded, err := base64.StdEncoding.DecodeString(cursor)
+	if err == nil && strings.HasPrefix(string(decoded), kind+":") {
+		if id, err := strconv.ParseInt(strings.TrimPrefix(string(decoded), kind+":"), 10, 64); err == nil {
+			return id, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid %s cursor %q", kind, cursor)
+}
+
+// idsArg parses a list of IDs, which is nil when the argument is not given.
+func idsArg(args map[string]interface{}, name string) ([]int64, error) {
+	values, ok := args[name].([]interface{})
+	if !ok {
+		return nil, nil
+	}
+	ids := make([]int64, 0, len(values))
+	for _, value := range values {
+		id, err := idArg(value)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func idArg(value interface{}) (int64, error) {
+	id, err := strconv.ParseInt(fmt.Sprint(value), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid ID %q", fmt.Sprint(value))
+	}
+	return id, nil
+}
+
+// networkArg checks that an argument is an IP address, or a network without bits set after
+// its mask, so that the backends are only queried with networks they accept.
+func networkArg(network string) (string, error) {
+	if !strings.Contains(network, "/") {
+		if net.ParseIP(network) == nil {
+			return "", fmt.Errorf("invalid IP address or network %q", network)
+		}
+		return network, nil
+	}
+	ip, ipNet, err := net.ParseCIDR(network)
+	if err != nil {
+		return "", fmt.Errorf("invalid IP address or network %q", network)
+	}
+	if !ip.Equal(ipNet.IP) {
+		return "", fmt.Errorf("network %q has bits set after the mask", network)
+	}
+	return network, nil
+}
+
+func stringArg(args map[string]interface{}, name string) string {
+	value, _ := args[name].(string)
+	return value
+}
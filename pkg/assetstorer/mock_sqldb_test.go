@@ -62,6 +62,20 @@ func (mr *MockSQLDBMockRecorder) Init(arg0, arg1, arg2, arg3, arg4, arg5 interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Init", reflect.TypeOf((*MockSQLDB)(nil).Init), arg0, arg1, arg2, arg3, arg4, arg5)
 }
 
+// ReadConn mocks base method
+func (m *MockSQLDB) ReadConn() *sql.DB {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadConn")
+	ret0, _ := ret[0].(*sql.DB)
+	return ret0
+}
+
+// ReadConn indicates an expected call of ReadConn
+func (mr *MockSQLDBMockRecorder) ReadConn() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadConn", reflect.TypeOf((*MockSQLDB)(nil).ReadConn))
+}
+
 // RunScript mocks base method
 func (m *MockSQLDB) RunScript(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -10,20 +10,47 @@ import (
 )
 
 const (
-	insertCustomerStatement = `INSERT INTO customers VALUES ($1, $2, $3)`
-	insertSubnetStatement   = `INSERT INTO subnets VALUES ($1, $2, $3, $4)`
-	insertIPStatement       = `INSERT INTO ips VALUES (DEFAULT, $1, $2, $3)`
+	insertCustomerStatement = `INSERT INTO %s (id, resource_owner, business_unit, owner_rule, source) VALUES ($1, $2, $3, $4, $5)`
+	insertSubnetStatement   = `INSERT INTO %s (id, network, location, customer_id, source) VALUES ($1, $2, $3, $4, $5)`
+	insertIPStatement       = `INSERT INTO %s (ip, subnet_id, device_id, source, record_id) VALUES ($1, $2, $3, $4, $5)`
+	insertContactStatement  = `INSERT INTO %s (customer_id, type, name, email, phone, source) VALUES ($1, $2, $3, $4, $5, $6)`
 	clearCustomerStatement  = `DELETE FROM customers`
 	clearSubnetStatement    = `DELETE FROM subnets`
 	clearIPStatement        = `DELETE FROM ips`
+
+	// incrementSyncGenerationStatement starts a new sync generation, recording the time it
+	// started. It runs in the transaction that changes the live tables, so that the generation
+	// changes if and only if the stored assets do.
+	incrementSyncGenerationStatement = `UPDATE sync_generation SET generation = generation + 1, synced_at = now()`
 )
 
+// assetTables names the tables that physical assets are stored in.
+type assetTables struct {
+	customers string
+	contacts  string
+	subnets   string
+	ips       string
+}
+
+func (t assetTables) names() []string {
+	return []string{t.customers, t.contacts, t.subnets, t.ips}
+}
+
+// liveTables are the tables that physical assets are fetched from.
+var liveTables = assetTables{
+	customers: "customers",
+	contacts:  "customer_contacts",
+	subnets:   "subnets",
+	ips:       "ips",
+}
+
 // PostgresPhysicalAssetStorer stores physical assets in a PostgreSQL database.
 type PostgresPhysicalAssetStorer struct {
 	DB domain.SQLDB
 }
 
-// StorePhysicalAssets stores physical asset device, subnet, and customer data in a a PostgreSQL database.
+// StorePhysicalAssets stores physical asset device, subnet, and customer data in a a PostgreSQL
+// database, and starts a new sync generation in the same transaction.
 func (s *PostgresPhysicalAssetStorer) StorePhysicalAssets(ctx context.Context, ipamData domain.IPAMData) error {
 	tx, err := s.DB.Conn().BeginTx(ctx, nil)
 	if err != nil {
@@ -38,7 +65,10 @@ func (s *PostgresPhysicalAssetStorer) StorePhysicalAssets(ctx context.Context, i
 		return err
 	}
 
-	err = s.savePhysicalAssets(ctx, ipamData, tx)
+	err = savePhysicalAssets(ctx, ipamData, tx, liveTables)
+	if err == nil {
+		_, err = tx.ExecContext(ctx, incrementSyncGenerationStatement)
+	}
 	if err != nil {
 		if rollbackErr := tx.Rollback(); rollbackErr != nil {
 			return errors.Wrap(rollbackErr, err.Error())
@@ -48,21 +78,22 @@ func (s *PostgresPhysicalAssetStorer) StorePhysicalAssets(ctx context.Context, i
 	return tx.Commit()
 }
 
-func (s *PostgresPhysicalAssetStorer) savePhysicalAssets(ctx context.Context, ipamData domain.IPAMData, tx *sql.Tx) error {
+// savePhysicalAssets inserts physical asset device, subnet, and customer data into a set of tables.
+func savePhysicalAssets(ctx context.Context, ipamData domain.IPAMData, tx *sql.Tx, tables assetTables) error {
 	for _, customer := range ipamData.Customers {
-		if err := s.storeCustomer(ctx, customer, tx); err != nil {
+		if err := storeCustomer(ctx, customer, tx, tables); err != nil {
 			return err
 		}
 	}
 
 	for _, subnet := range ipamData.Subnets {
-		if err := s.storeSubnet(ctx, subnet, tx); err != nil {
+		if err := storeSubnet(ctx, subnet, tx, tables); err != nil {
 			return err
 		}
 	}
 
 	for _, device := range ipamData.Devices {
-		if err := s.storeIP(ctx, device, tx); err != nil {
+		if err := storeIP(ctx, device, tx, tables); err != nil {
 			return err
 		}
 	}
@@ -70,28 +101,36 @@ func (s *PostgresPhysicalAssetStorer) savePhysicalAssets(ctx context.Context, ip
 	return nil
 }
 
-func (s *PostgresPhysicalAssetStorer) storeCustomer(ctx context.Context, customer domain.Customer, tx *sql.Tx) error {
-	if _, err := tx.ExecContext(ctx, insertCustomerStatement, customer.ID, customer.ResourceOwner, customer.BusinessUnit); err != nil {
+func storeCustomer(ctx context.Context, customer domain.Customer, tx *sql.Tx, tables assetTables) error {
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(insertCustomerStatement, tables.customers), customer.ID, customer.ResourceOwner, customer.BusinessUnit, customer.ResourceOwnerRule, customer.Source); err != nil {
 		return err
 	}
 
+	// contacts are removed along with their customer by the ON DELETE CASCADE
+	// foreign key, so clearing the customers table is enough to clear them as well
+	for _, contact := range customer.Contacts {
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf(insertContactStatement, tables.contacts), customer.ID, contact.Type, contact.Name, contact.Email, contact.Phone, contact.Source); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
-func (s *PostgresPhysicalAssetStorer) storeSubnet(ctx context.Context, subnet domain.Subnet, tx *sql.Tx) error {
-	if _, err := tx.ExecContext(ctx, insertSubnetStatement, subnet.ID, fmt.Sprintf("%s/%d", subnet.Network, subnet.MaskBits), subnet.Location, newNullString(subnet.CustomerID)); err != nil {
+func storeSubnet(ctx context.Context, subnet domain.Subnet, tx *sql.Tx, tables assetTables) error {
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(insertSubnetStatement, tables.subnets), subnet.ID, fmt.Sprintf("%s/%d", subnet.Network, subnet.MaskBits), subnet.Location, newNullString(subnet.CustomerID), subnet.Source); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (s *PostgresPhysicalAssetStorer) storeIP(ctx context.Context, device domain.Device, tx *sql.Tx) error {
+func storeIP(ctx context.Context, device domain.Device, tx *sql.Tx, tables assetTables) error {
 	var deviceID *string
 	if device.ID != "" {
 		deviceID = &device.ID
 	}
-	if _, err := tx.ExecContext(ctx, insertIPStatement, device.IP, device.SubnetID, deviceID); err != nil {
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(insertIPStatement, tables.ips), device.IP, device.SubnetID, deviceID, device.Source, device.RecordID); err != nil {
 		return err
 	}
 
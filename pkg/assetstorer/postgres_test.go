@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"regexp"
 	"testing"
 
 	sqlmock "github.com/DATA-DOG/go-sqlmock"
@@ -26,6 +27,8 @@ func TestPostgresPhysicalAssetStorer_StorePhysicalAssets_Success(t *testing.T) {
 		ID:       "1",
 		IP:       "127.0.0.1",
 		SubnetID: "2",
+		RecordID: "7",
+		Source:   "device42",
 	}
 	subnet := domain.Subnet{
 		ID:         "1",
@@ -33,16 +36,58 @@ func TestPostgresPhysicalAssetStorer_StorePhysicalAssets_Success(t *testing.T) {
 		MaskBits:   1,
 		Location:   "",
 		CustomerID: "1",
+		Source:     "device42,netbox",
 	}
+	customer := domain.Customer{
+		ID:                "1",
+		ResourceOwner:     "alice@example.com",
+		ResourceOwnerRule: "contact-info",
+		BusinessUnit:      "Security",
+		Source:            "file",
+	}
+
+	ipamData := domain.IPAMData{
+		Devices:   []domain.Device{device},
+		Subnets:   []domain.Subnet{subnet},
+		Customers: []domain.Customer{customer},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM customers").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM subnets").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM ips").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO customers (id, resource_owner, business_unit, owner_rule, source)")).WithArgs(customer.ID, customer.ResourceOwner, customer.BusinessUnit, customer.ResourceOwnerRule, customer.Source).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO subnets (id, network, location, customer_id, source)")).WithArgs(subnet.ID, fmt.Sprintf("%s/%d", subnet.Network, subnet.MaskBits), subnet.Location, subnet.CustomerID, subnet.Source).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO ips (ip, subnet_id, device_id, source, record_id)")).WithArgs(device.IP, device.SubnetID, device.ID, device.Source, device.RecordID).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE sync_generation").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	storer := PostgresPhysicalAssetStorer{DB: mockSQLDB}
+	e := storer.StorePhysicalAssets(context.Background(), ipamData)
+	require.Nil(t, e)
+	require.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresPhysicalAssetStorer_StorePhysicalAssetsWithContacts_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockSQLDB := NewMockSQLDB(ctrl)
+
+	mockdb, mock, err := sqlmock.New()
+	require.Nil(t, err)
+	defer mockdb.Close()
+	mockSQLDB.EXPECT().Conn().Return(mockdb)
+
+	technical := domain.Contact{Type: "Technical", Name: "Alice", Email: "alice@example.com", Phone: "555-0100", Source: "device42"}
+	escalation := domain.Contact{Type: "Escalation", Name: "Bob", Email: "bob@example.com", Phone: "555-0199", Source: "device42"}
 	customer := domain.Customer{
 		ID:            "1",
 		ResourceOwner: "alice@example.com",
 		BusinessUnit:  "Security",
+		Contacts:      []domain.Contact{technical, escalation},
 	}
 
 	ipamData := domain.IPAMData{
-		Devices:   []domain.Device{device},
-		Subnets:   []domain.Subnet{subnet},
 		Customers: []domain.Customer{customer},
 	}
 
@@ -50,9 +95,10 @@ func TestPostgresPhysicalAssetStorer_StorePhysicalAssets_Success(t *testing.T) {
 	mock.ExpectExec("DELETE FROM customers").WillReturnResult(sqlmock.NewResult(0, 0))
 	mock.ExpectExec("DELETE FROM subnets").WillReturnResult(sqlmock.NewResult(0, 0))
 	mock.ExpectExec("DELETE FROM ips").WillReturnResult(sqlmock.NewResult(0, 0))
-	mock.ExpectExec("INSERT INTO customers").WithArgs(customer.ID, customer.ResourceOwner, customer.BusinessUnit).WillReturnResult(sqlmock.NewResult(1, 1))
-	mock.ExpectExec("INSERT INTO subnets").WithArgs(subnet.ID, fmt.Sprintf("%s/%d", subnet.Network, subnet.MaskBits), subnet.Location, subnet.CustomerID).WillReturnResult(sqlmock.NewResult(1, 1))
-	mock.ExpectExec("INSERT INTO ips").WithArgs(device.IP, device.SubnetID, device.ID).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO customers").WithArgs(customer.ID, customer.ResourceOwner, customer.BusinessUnit, customer.ResourceOwnerRule, customer.Source).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO customer_contacts (customer_id, type, name, email, phone, source)")).WithArgs(customer.ID, technical.Type, technical.Name, technical.Email, technical.Phone, technical.Source).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO customer_contacts (customer_id, type, name, email, phone, source)")).WithArgs(customer.ID, escalation.Type, escalation.Name, escalation.Email, escalation.Phone, escalation.Source).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE sync_generation").WillReturnResult(sqlmock.NewResult(0, 1))
 	mock.ExpectCommit()
 
 	storer := PostgresPhysicalAssetStorer{DB: mockSQLDB}
@@ -86,9 +132,10 @@ func TestPostgresPhysicalAssetStorer_StorePhysicalAssetsNoDeviceID_Success(t *te
 		CustomerID: "1",
 	}
 	customer := domain.Customer{
-		ID:            "1",
-		ResourceOwner: "alice@example.com",
-		BusinessUnit:  "Security",
+		ID:                "1",
+		ResourceOwner:     "alice@example.com",
+		ResourceOwnerRule: "contact-info",
+		BusinessUnit:      "Security",
 	}
 
 	ipamData := domain.IPAMData{
@@ -101,9 +148,10 @@ func TestPostgresPhysicalAssetStorer_StorePhysicalAssetsNoDeviceID_Success(t *te
 	mock.ExpectExec("DELETE FROM customers").WillReturnResult(sqlmock.NewResult(0, 0))
 	mock.ExpectExec("DELETE FROM subnets").WillReturnResult(sqlmock.NewResult(0, 0))
 	mock.ExpectExec("DELETE FROM ips").WillReturnResult(sqlmock.NewResult(0, 0))
-	mock.ExpectExec("INSERT INTO customers").WithArgs(customer.ID, customer.ResourceOwner, customer.BusinessUnit).WillReturnResult(sqlmock.NewResult(1, 1))
-	mock.ExpectExec("INSERT INTO subnets").WithArgs(subnet.ID, fmt.Sprintf("%s/%d", subnet.Network, subnet.MaskBits), subnet.Location, subnet.CustomerID).WillReturnResult(sqlmock.NewResult(1, 1))
-	mock.ExpectExec("INSERT INTO ips").WithArgs(device.IP, device.SubnetID, nil).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO customers").WithArgs(customer.ID, customer.ResourceOwner, customer.BusinessUnit, customer.ResourceOwnerRule, customer.Source).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO subnets").WithArgs(subnet.ID, fmt.Sprintf("%s/%d", subnet.Network, subnet.MaskBits), subnet.Location, subnet.CustomerID, subnet.Source).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO ips").WithArgs(device.IP, device.SubnetID, nil, device.Source, device.RecordID).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE sync_generation").WillReturnResult(sqlmock.NewResult(0, 1))
 	mock.ExpectCommit()
 
 	storer := PostgresPhysicalAssetStorer{DB: mockSQLDB}
@@ -138,7 +186,8 @@ func TestPostgresPhysicalAssetStorer_StorePhysicalAssetsNoCustomerID_Success(t *
 	mock.ExpectExec("DELETE FROM customers").WillReturnResult(sqlmock.NewResult(0, 0))
 	mock.ExpectExec("DELETE FROM subnets").WillReturnResult(sqlmock.NewResult(0, 0))
 	mock.ExpectExec("DELETE FROM ips").WillReturnResult(sqlmock.NewResult(0, 0))
-	mock.ExpectExec("INSERT INTO subnets").WithArgs(subnet.ID, fmt.Sprintf("%s/%d", subnet.Network, subnet.MaskBits), subnet.Location, sql.NullString{}).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO subnets").WithArgs(subnet.ID, fmt.Sprintf("%s/%d", subnet.Network, subnet.MaskBits), subnet.Location, sql.NullString{}, subnet.Source).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE sync_generation").WillReturnResult(sqlmock.NewResult(0, 1))
 	mock.ExpectCommit()
 
 	storer := PostgresPhysicalAssetStorer{DB: mockSQLDB}
@@ -170,9 +219,10 @@ func TestPostgresPhysicalAssetStorer_StorePhysicalAssets_RollbackError(t *testin
 		CustomerID: "1",
 	}
 	customer := domain.Customer{
-		ID:            "1",
-		ResourceOwner: "alice@example.com",
-		BusinessUnit:  "Security",
+		ID:                "1",
+		ResourceOwner:     "alice@example.com",
+		ResourceOwnerRule: "contact-info",
+		BusinessUnit:      "Security",
 	}
 
 	ipamData := domain.IPAMData{
@@ -185,9 +235,9 @@ func TestPostgresPhysicalAssetStorer_StorePhysicalAssets_RollbackError(t *testin
 	mock.ExpectExec("DELETE FROM customers").WillReturnResult(sqlmock.NewResult(0, 0))
 	mock.ExpectExec("DELETE FROM subnets").WillReturnResult(sqlmock.NewResult(0, 0))
 	mock.ExpectExec("DELETE FROM ips").WillReturnResult(sqlmock.NewResult(0, 0))
-	mock.ExpectExec("INSERT INTO customers").WithArgs(customer.ID, customer.ResourceOwner, customer.BusinessUnit).WillReturnResult(sqlmock.NewResult(1, 1))
-	mock.ExpectExec("INSERT INTO subnets").WithArgs(subnet.ID, fmt.Sprintf("%s/%d", subnet.Network, subnet.MaskBits), subnet.Location, subnet.CustomerID).WillReturnResult(sqlmock.NewResult(1, 1))
-	mock.ExpectExec("INSERT INTO ips").WithArgs(device.IP, device.SubnetID, device.ID).WillReturnError(fmt.Errorf("some error"))
+	mock.ExpectExec("INSERT INTO customers").WithArgs(customer.ID, customer.ResourceOwner, customer.BusinessUnit, customer.ResourceOwnerRule, customer.Source).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO subnets").WithArgs(subnet.ID, fmt.Sprintf("%s/%d", subnet.Network, subnet.MaskBits), subnet.Location, subnet.CustomerID, subnet.Source).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO ips").WithArgs(device.IP, device.SubnetID, device.ID, device.Source, device.RecordID).WillReturnError(fmt.Errorf("some error"))
 	mock.ExpectRollback().WillReturnError(fmt.Errorf("rollback error"))
 
 	storer := PostgresPhysicalAssetStorer{DB: mockSQLDB}
@@ -196,6 +246,30 @@ func TestPostgresPhysicalAssetStorer_StorePhysicalAssets_RollbackError(t *testin
 	require.Nil(t, mock.ExpectationsWereMet())
 }
 
+func TestPostgresPhysicalAssetStorer_StorePhysicalAssets_SyncGenerationError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockSQLDB := NewMockSQLDB(ctrl)
+
+	mockdb, mock, err := sqlmock.New()
+	require.Nil(t, err)
+	defer mockdb.Close()
+	mockSQLDB.EXPECT().Conn().Return(mockdb)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM customers").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM subnets").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM ips").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("UPDATE sync_generation").WillReturnError(fmt.Errorf("some error"))
+	// the assets are not replaced when the generation cannot be incremented along with them
+	mock.ExpectRollback()
+
+	storer := PostgresPhysicalAssetStorer{DB: mockSQLDB}
+	e := storer.StorePhysicalAssets(context.Background(), domain.IPAMData{})
+	require.Error(t, e)
+	require.Nil(t, mock.ExpectationsWereMet())
+}
+
 func TestPostgresPhysicalAssetStorer_StorePhysicalAssets_TxBeginError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -238,7 +312,7 @@ func TestPostgresPhysicalAssetStorer_storeSubnet_Error(t *testing.T) {
 	mock.ExpectExec("DELETE FROM customers").WillReturnResult(sqlmock.NewResult(0, 0))
 	mock.ExpectExec("DELETE FROM subnets").WillReturnResult(sqlmock.NewResult(0, 0))
 	mock.ExpectExec("DELETE FROM ips").WillReturnResult(sqlmock.NewResult(0, 0))
-	mock.ExpectExec("INSERT INTO subnets").WithArgs(subnet.ID, fmt.Sprintf("%s/%d", subnet.Network, subnet.MaskBits), subnet.Location, subnet.CustomerID).WillReturnError(fmt.Errorf("some error"))
+	mock.ExpectExec("INSERT INTO subnets").WithArgs(subnet.ID, fmt.Sprintf("%s/%d", subnet.Network, subnet.MaskBits), subnet.Location, subnet.CustomerID, subnet.Source).WillReturnError(fmt.Errorf("some error"))
 	mock.ExpectRollback()
 
 	storer := PostgresPhysicalAssetStorer{DB: mockSQLDB}
@@ -258,9 +332,10 @@ func TestPostgresPhysicalAssetStorer_storeCustomer_Error(t *testing.T) {
 	mockSQLDB.EXPECT().Conn().Return(mockdb)
 
 	customer := domain.Customer{
-		ID:            "1",
-		ResourceOwner: "alice@example.com",
-		BusinessUnit:  "Security",
+		ID:                "1",
+		ResourceOwner:     "alice@example.com",
+		ResourceOwnerRule: "contact-info",
+		BusinessUnit:      "Security",
 	}
 
 	ipamData := domain.IPAMData{
@@ -271,7 +346,7 @@ func TestPostgresPhysicalAssetStorer_storeCustomer_Error(t *testing.T) {
 	mock.ExpectExec("DELETE FROM customers").WillReturnResult(sqlmock.NewResult(0, 0))
 	mock.ExpectExec("DELETE FROM subnets").WillReturnResult(sqlmock.NewResult(0, 0))
 	mock.ExpectExec("DELETE FROM ips").WillReturnResult(sqlmock.NewResult(0, 0))
-	mock.ExpectExec("INSERT INTO customers").WithArgs(customer.ID, customer.ResourceOwner, customer.BusinessUnit).WillReturnError(fmt.Errorf("some error"))
+	mock.ExpectExec("INSERT INTO customers").WithArgs(customer.ID, customer.ResourceOwner, customer.BusinessUnit, customer.ResourceOwnerRule, customer.Source).WillReturnError(fmt.Errorf("some error"))
 	mock.ExpectRollback()
 
 	storer := PostgresPhysicalAssetStorer{DB: mockSQLDB}
@@ -303,7 +378,43 @@ func TestPostgresPhysicalAssetStorer_storeIP_Error(t *testing.T) {
 	mock.ExpectExec("DELETE FROM customers").WillReturnResult(sqlmock.NewResult(0, 0))
 	mock.ExpectExec("DELETE FROM subnets").WillReturnResult(sqlmock.NewResult(0, 0))
 	mock.ExpectExec("DELETE FROM ips").WillReturnResult(sqlmock.NewResult(0, 0))
-	mock.ExpectExec("INSERT INTO ips").WithArgs(device.IP, device.SubnetID, device.ID).WillReturnError(fmt.Errorf("some error"))
+	mock.ExpectExec("INSERT INTO ips").WithArgs(device.IP, device.SubnetID, device.ID, device.Source, device.RecordID).WillReturnError(fmt.Errorf("some error"))
+	mock.ExpectRollback()
+
+	storer := PostgresPhysicalAssetStorer{DB: mockSQLDB}
+	e := storer.StorePhysicalAssets(context.Background(), ipamData)
+	require.Error(t, e)
+	require.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresPhysicalAssetStorer_storeContact_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockSQLDB := NewMockSQLDB(ctrl)
+
+	mockdb, mock, err := sqlmock.New()
+	require.Nil(t, err)
+	defer mockdb.Close()
+	mockSQLDB.EXPECT().Conn().Return(mockdb)
+
+	contact := domain.Contact{Type: "Technical", Name: "Alice", Email: "alice@example.com", Phone: "555-0100"}
+	customer := domain.Customer{
+		ID:            "1",
+		ResourceOwner: "alice@example.com",
+		BusinessUnit:  "Security",
+		Contacts:      []domain.Contact{contact},
+	}
+
+	ipamData := domain.IPAMData{
+		Customers: []domain.Customer{customer},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM customers").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM subnets").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM ips").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO customers").WithArgs(customer.ID, customer.ResourceOwner, customer.BusinessUnit, customer.ResourceOwnerRule, customer.Source).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO customer_contacts").WithArgs(customer.ID, contact.Type, contact.Name, contact.Email, contact.Phone, contact.Source).WillReturnError(fmt.Errorf("some error"))
 	mock.ExpectRollback()
 
 	storer := PostgresPhysicalAssetStorer{DB: mockSQLDB}
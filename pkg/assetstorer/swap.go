@@ -0,0 +1,200 @@
+package assetstorer
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+	"github.com/pkg/errors"
+)
+
+const (
+	// createStagingTablesStatement replaces any staging tables left by a failed sync with empty
+	// copies of the live tables. The copies have the columns, defaults, constraints and indexes
+	// of the live tables, but their own sequences and foreign keys, which LIKE does not copy.
+	createStagingTablesStatement = `DROP TABLE IF EXISTS ips_staging, subnets_staging, customer_contacts_staging, customers_staging;
+CREATE TABLE customers_staging (LIKE customers INCLUDING ALL);
+CREATE TABLE customer_contacts_staging (LIKE customer_contacts INCLUDING ALL);
+CREATE TABLE subnets_staging (LIKE subnets INCLUDING ALL);
+CREATE TABLE ips_staging (LIKE ips INCLUDING ALL);
+CREATE SEQUENCE customer_contacts_staging_id_seq OWNED BY customer_contacts_staging.id;
+ALTER TABLE customer_contacts_staging ALTER COLUMN id SET DEFAULT nextval('customer_contacts_staging_id_seq');
+CREATE SEQUENCE ips_staging_id_seq OWNED BY ips_staging.id;
+ALTER TABLE ips_staging ALTER COLUMN id SET DEFAULT nextval('ips_staging_id_seq');
+ALTER TABLE customer_contacts_staging ADD FOREIGN KEY (customer_id) REFERENCES customers_staging (id) ON DELETE CASCADE;
+ALTER TABLE subnets_staging ADD FOREIGN KEY (customer_id) REFERENCES customers_staging (id) ON DELETE CASCADE;
+ALTER TABLE ips_staging ADD FOREIGN KEY (subnet_id) REFERENCES subnets_staging (id) ON DELETE CASCADE;`
+	analyzeStagingTablesStatement = `ANALYZE customers_staging;
+ANALYZE customer_contacts_staging;
+ANALYZE subnets_staging;
+ANALYZE ips_staging;`
+	dropPreviousTablesStatement = `DROP TABLE IF EXISTS ips_previous, subnets_previous, customer_contacts_previous, customers_previous;`
+	// resetIncrementalSyncStateStatement forgets the state of incremental syncs, which was
+	// built on the generation a rollback replaces, so that the next sync is a full one.
+	resetIncrementalSyncStateStatement = `UPDATE sync_generation SET incremental_watermark = NULL, incremental_last_full = NULL;`
+	lockTimeoutStatement               = `SET LOCAL lock_timeout = %d`
+)
+
+const countStagingRowsQuery = `SELECT
+							(SELECT count(*) FROM customers_staging),
+							(SELECT count(*) FROM customer_contacts_staging),
+							(SELECT count(*) FROM subnets_staging),
+							(SELECT count(*) FROM ips_staging);`
+
+const countPreviousTablesQuery = `SELECT count(*)
+							FROM pg_catalog.pg_tables
+							WHERE schemaname = current_schema()
+							AND tablename IN ('customers_previous', 'customer_contacts_previous', 'subnets_previous', 'ips_previous');`
+
+// ErrNoPreviousGeneration is returned when rolling back before any sync has swapped tables.
+var ErrNoPreviousGeneration = errors.New("there is no previous generation of physical assets to roll back to")
+
+var (
+	stagingTables  = generation("_staging")
+	previousTables = generation("_previous")
+	rollbackTables = generation("_rollback")
+)
+
+// generation returns the names of the live tables with a suffix.
+func generation(suffix string) assetTables {
+	return assetTables{
+		customers: liveTables.customers + suffix,
+		contacts:  liveTables.contacts + suffix,
+		subnets:   liveTables.subnets + suffix,
+		ips:       liveTables.ips + suffix,
+	}
+}
+
+// PostgresSwapPhysicalAssetStorer stores physical assets in a PostgreSQL database without
+// locking the tables that are read by lookups while loading. The assets are loaded into
+// staging tables, which are validated, then swapped in for the live tables by renaming them
+// in a short transaction. The replaced tables are kept as the previous generation, until the
+// next sync, so that Rollback can restore them instantly. Syncs and rollbacks share the staging
+// and previous tables, so callers serialize them by holding the sync lock of domain.SyncLocker.
+type PostgresSwapPhysicalAssetStorer struct {
+	DB domain.SQLDB
+	// LockTimeout, when positive, limits how long the swap waits for lookups that are still
+	// reading the live tables. Lookups started while it waits are queued behind it, so a
+	// swap that times out fails rather than holding them up.
+	LockTimeout time.Duration
+}
+
+// StorePhysicalAssets stores physical asset device, subnet, and customer data in a PostgreSQL
+// database, replacing the live tables. Lookups keep reading the previous data until the swap.
+func (s *PostgresSwapPhysicalAssetStorer) StorePhysicalAssets(ctx context.Context, ipamData domain.IPAMData) error {
+	db := s.DB.Conn()
+	if err := s.load(ctx, db, ipamData); err != nil {
+		return err
+	}
+	return s.swap(ctx, db, dropPreviousTablesStatement+"\n"+
+		renameGeneration(liveTables, previousTables)+
+		renameGeneration(stagingTables, liveTables))
+}
+
+// Rollback restores the previous generation of physical assets. The generation it replaces
+// becomes the previous one, so rolling back a second time undoes the first. The state of
+// incremental syncs is reset along with the swap, as the changes they fetch next would
+// otherwise be merged into the restored generation, and the next sync is a full one. Applying
+// a schema migration drops the previous generation, which lacks its changes, so there is none
+// to roll back to until the next sync.
+func (s *PostgresSwapPhysicalAssetStorer) Rollback(ctx context.Context) error {
+	db := s.DB.Conn()
+	var count int
+	if err := db.QueryRowContext(ctx, countPreviousTablesQuery).Scan(&count); err != nil {
+		return err
+	}
+	if count != len(previousTables.names()) {
+		return ErrNoPreviousGeneration
+	}
+	return s.swap(ctx, db, renameGeneration(liveTables, rollbackTables)+
+		renameGeneration(previousTables, liveTables)+
+		renameGeneration(rollbackTables, previousTables)+
+		resetIncrementalSyncStateStatement)
+}
+
+// load creates the staging tables and inserts the physical assets into them, in a transaction
+// that only locks the staging tables. The staging tables are then validated against the data,
+// and analyzed so that lookups are planned well as soon as they are swapped in.
+func (s *PostgresSwapPhysicalAssetStorer) load(ctx context.Context, db *sql.DB, ipamData domain.IPAMData) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	err = loadStagingTables(ctx, tx, ipamData)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			return errors.Wrap(rollbackErr, err.Error())
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+func loadStagingTables(ctx context.Context, tx *sql.Tx, ipamData domain.IPAMData) error {
+	if _, err := tx.ExecContext(ctx, createStagingTablesStatement); err != nil {
+		return err
+	}
+	if err := savePhysicalAssets(ctx, ipamData, tx, stagingTables); err != nil {
+		return err
+	}
+
+	expected := [4]int{len(ipamData.Customers), 0, len(ipamData.Subnets), len(ipamData.Devices)}
+	for _, customer := range ipamData.Customers {
+		expected[1] += len(customer.Contacts)
+	}
+	var actual [4]int
+	if err := tx.QueryRowContext(ctx, countStagingRowsQuery).Scan(&actual[0], &actual[1], &actual[2], &actual[3]); err != nil {
+		return err
+	}
+	if actual != expected {
+		return fmt.Errorf("staging tables hold %d customers, %d contacts, %d subnets and %d IPs, but %d, %d, %d and %d were stored",
+			actual[0], actual[1], actual[2], actual[3], expected[0], expected[1], expected[2], expected[3])
+	}
+
+	_, err := tx.ExecContext(ctx, analyzeStagingTablesStatement)
+	return err
+}
+
+// swap renames tables in a transaction, which waits for no longer than the LockTimeout for
+// the lookups reading them, and starts a new sync generation in the same transaction.
+func (s *PostgresSwapPhysicalAssetStorer) swap(ctx context.Context, db *sql.DB, renames string) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	err = func() error {
+		if s.LockTimeout > 0 {
+			if _, err := tx.ExecContext(ctx, fmt.Sprintf(lockTimeoutStatement, s.LockTimeout/time.Millisecond)); err != nil {
+				return err
+			}
+		}
+		if _, err := tx.ExecContext(ctx, renames); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, incrementSyncGenerationStatement)
+		return err
+	}()
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			return errors.Wrap(rollbackErr, err.Error())
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+// renameGeneration returns the statements that rename the tables of one generation, and the
+// sequences of their serial columns, to those of another.
+func renameGeneration(from, to assetTables) string {
+	var b strings.Builder
+	toNames := to.names()
+	for i, name := range from.names() {
+		fmt.Fprintf(&b, "ALTER TABLE %s RENAME TO %s;\n", name, toNames[i])
+	}
+	fmt.Fprintf(&b, "ALTER SEQUENCE %s_id_seq RENAME TO %s_id_seq;\n", from.contacts, to.contacts)
+	fmt.Fprintf(&b, "ALTER SEQUENCE %s_id_seq RENAME TO %s_id_seq;\n", from.ips, to.ips)
+	return b.String()
+}
@@ -0,0 +1,231 @@
+package assetstorer
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func swapTestData() domain.IPAMData {
+	return domain.IPAMData{
+		Devices: []domain.Device{{ID: "1", IP: "127.0.0.1", SubnetID: "1", RecordID: "7", Source: "device42"}},
+		Subnets: []domain.Subnet{{ID: "1", Network: "127.0.0.0", MaskBits: 31, CustomerID: "1", Source: "device42"}},
+		Customers: []domain.Customer{{
+			ID:            "1",
+			ResourceOwner: "alice@example.com",
+			BusinessUnit:  "Security",
+			Source:        "file",
+			Contacts:      []domain.Contact{{Type: "Technical", Name: "Alice", Email: "alice@example.com", Phone: "555-0100"}},
+		}},
+	}
+}
+
+func expectLoad(mock sqlmock.Sqlmock, ipamData domain.IPAMData) {
+	customer := ipamData.Customers[0]
+	contact := customer.Contacts[0]
+	subnet := ipamData.Subnets[0]
+	device := ipamData.Devices[0]
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(createStagingTablesStatement)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO customers_staging").WithArgs(customer.ID, customer.ResourceOwner, customer.BusinessUnit, customer.ResourceOwnerRule, customer.Source).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO customer_contacts_staging").WithArgs(customer.ID, contact.Type, contact.Name, contact.Email, contact.Phone, contact.Source).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO subnets_staging").WithArgs(subnet.ID, "127.0.0.0/31", subnet.Location, subnet.CustomerID, subnet.Source).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO ips_staging").WithArgs(device.IP, device.SubnetID, device.ID, device.Source, device.RecordID).WillReturnResult(sqlmock.NewResult(1, 1))
+}
+
+func TestPostgresSwapPhysicalAssetStorer_StorePhysicalAssets_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockSQLDB := NewMockSQLDB(ctrl)
+
+	mockdb, mock, err := sqlmock.New()
+	require.Nil(t, err)
+	defer mockdb.Close()
+	mockSQLDB.EXPECT().Conn().Return(mockdb)
+
+	ipamData := swapTestData()
+	expectLoad(mock, ipamData)
+	mock.ExpectQuery(regexp.QuoteMeta(countStagingRowsQuery)).WillReturnRows(sqlmock.NewRows([]string{"customers", "contacts", "subnets", "ips"}).AddRow(1, 1, 1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(analyzeStagingTablesStatement)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("SET LOCAL lock_timeout = 5000")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(dropPreviousTablesStatement) + `\s+` +
+		regexp.QuoteMeta("ALTER TABLE customers RENAME TO customers_previous;") + `.*` +
+		regexp.QuoteMeta("ALTER SEQUENCE ips_id_seq RENAME TO ips_previous_id_seq;") + `\s+` +
+		regexp.QuoteMeta("ALTER TABLE customers_staging RENAME TO customers;") + `.*` +
+		regexp.QuoteMeta("ALTER SEQUENCE ips_staging_id_seq RENAME TO ips_id_seq;")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(incrementSyncGenerationStatement)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	storer := PostgresSwapPhysicalAssetStorer{DB: mockSQLDB, LockTimeout: 5 * time.Second}
+	e := storer.StorePhysicalAssets(context.Background(), ipamData)
+	require.Nil(t, e)
+	require.Nil(t, mock.ExpectationsWereMet(), "there were unfulfilled expectations: %s", mock.ExpectationsWereMet())
+}
+
+func TestPostgresSwapPhysicalAssetStorer_StorePhysicalAssets_LoadError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockSQLDB := NewMockSQLDB(ctrl)
+
+	mockdb, mock, err := sqlmock.New()
+	require.Nil(t, err)
+	defer mockdb.Close()
+	mockSQLDB.EXPECT().Conn().Return(mockdb)
+
+	ipamData := swapTestData()
+	customer := ipamData.Customers[0]
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(createStagingTablesStatement)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO customers_staging").WithArgs(customer.ID, customer.ResourceOwner, customer.BusinessUnit, customer.ResourceOwnerRule, customer.Source).WillReturnError(fmt.Errorf("some error"))
+	mock.ExpectRollback()
+
+	storer := PostgresSwapPhysicalAssetStorer{DB: mockSQLDB}
+	e := storer.StorePhysicalAssets(context.Background(), ipamData)
+	require.Error(t, e)
+	require.Nil(t, mock.ExpectationsWereMet(), "there were unfulfilled expectations: %s", mock.ExpectationsWereMet())
+}
+
+func TestPostgresSwapPhysicalAssetStorer_StorePhysicalAssets_ValidationError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockSQLDB := NewMockSQLDB(ctrl)
+
+	mockdb, mock, err := sqlmock.New()
+	require.Nil(t, err)
+	defer mockdb.Close()
+	mockSQLDB.EXPECT().Conn().Return(mockdb)
+
+	ipamData := swapTestData()
+	expectLoad(mock, ipamData)
+	mock.ExpectQuery(regexp.QuoteMeta(countStagingRowsQuery)).WillReturnRows(sqlmock.NewRows([]string{"customers", "contacts", "subnets", "ips"}).AddRow(1, 1, 1, 0))
+	mock.ExpectRollback()
+
+	storer := PostgresSwapPhysicalAssetStorer{DB: mockSQLDB}
+	e := storer.StorePhysicalAssets(context.Background(), ipamData)
+	require.Error(t, e)
+	require.Nil(t, mock.ExpectationsWereMet(), "there were unfulfilled expectations: %s", mock.ExpectationsWereMet())
+}
+
+func TestPostgresSwapPhysicalAssetStorer_StorePhysicalAssets_SwapError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockSQLDB := NewMockSQLDB(ctrl)
+
+	mockdb, mock, err := sqlmock.New()
+	require.Nil(t, err)
+	defer mockdb.Close()
+	mockSQLDB.EXPECT().Conn().Return(mockdb)
+
+	ipamData := swapTestData()
+	expectLoad(mock, ipamData)
+	mock.ExpectQuery(regexp.QuoteMeta(countStagingRowsQuery)).WillReturnRows(sqlmock.NewRows([]string{"customers", "contacts", "subnets", "ips"}).AddRow(1, 1, 1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(analyzeStagingTablesStatement)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(dropPreviousTablesStatement)).WillReturnError(fmt.Errorf("canceling statement due to lock timeout"))
+	mock.ExpectRollback()
+
+	storer := PostgresSwapPhysicalAssetStorer{DB: mockSQLDB}
+	e := storer.StorePhysicalAssets(context.Background(), ipamData)
+	require.Error(t, e)
+	require.Nil(t, mock.ExpectationsWereMet(), "there were unfulfilled expectations: %s", mock.ExpectationsWereMet())
+}
+
+func TestPostgresSwapPhysicalAssetStorer_StorePhysicalAssets_SyncGenerationError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockSQLDB := NewMockSQLDB(ctrl)
+
+	mockdb, mock, err := sqlmock.New()
+	require.Nil(t, err)
+	defer mockdb.Close()
+	mockSQLDB.EXPECT().Conn().Return(mockdb)
+
+	ipamData := swapTestData()
+	expectLoad(mock, ipamData)
+	mock.ExpectQuery(regexp.QuoteMeta(countStagingRowsQuery)).WillReturnRows(sqlmock.NewRows([]string{"customers", "contacts", "subnets", "ips"}).AddRow(1, 1, 1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(analyzeStagingTablesStatement)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(dropPreviousTablesStatement)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(incrementSyncGenerationStatement)).WillReturnError(fmt.Errorf("relation \"sync_generation\" does not exist"))
+	// the tables are not swapped in when the generation cannot be incremented along with them
+	mock.ExpectRollback()
+
+	storer := PostgresSwapPhysicalAssetStorer{DB: mockSQLDB}
+	e := storer.StorePhysicalAssets(context.Background(), ipamData)
+	require.Error(t, e)
+	require.Nil(t, mock.ExpectationsWereMet(), "there were unfulfilled expectations: %s", mock.ExpectationsWereMet())
+}
+
+func TestPostgresSwapPhysicalAssetStorer_Rollback(t *testing.T) {
+	tc := []struct {
+		name          string
+		previous      int
+		expectedError error
+	}{
+		{
+			name:     "previous generation",
+			previous: 4,
+		},
+		{
+			name:          "no previous generation",
+			previous:      0,
+			expectedError: ErrNoPreviousGeneration,
+		},
+		{
+			name:          "partial previous generation",
+			previous:      2,
+			expectedError: ErrNoPreviousGeneration,
+		},
+	}
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockSQLDB := NewMockSQLDB(ctrl)
+
+			mockdb, mock, err := sqlmock.New()
+			require.Nil(t, err)
+			defer mockdb.Close()
+			mockSQLDB.EXPECT().Conn().Return(mockdb)
+
+			mock.ExpectQuery(regexp.QuoteMeta(countPreviousTablesQuery)).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(tt.previous))
+			if tt.expectedError == nil {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("ALTER TABLE customers RENAME TO customers_rollback;") + `.*` +
+					regexp.QuoteMeta("ALTER TABLE customers_previous RENAME TO customers;") + `.*` +
+					regexp.QuoteMeta("ALTER TABLE customers_rollback RENAME TO customers_previous;") + `.*` +
+					regexp.QuoteMeta("ALTER SEQUENCE ips_rollback_id_seq RENAME TO ips_previous_id_seq;") + `\s+` +
+					regexp.QuoteMeta(resetIncrementalSyncStateStatement)).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(regexp.QuoteMeta(incrementSyncGenerationStatement)).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			}
+
+			storer := PostgresSwapPhysicalAssetStorer{DB: mockSQLDB}
+			e := storer.Rollback(context.Background())
+			require.Equal(t, tt.expectedError, e)
+			require.Nil(t, mock.ExpectationsWereMet(), "there were unfulfilled expectations: %s", mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRenameGeneration(t *testing.T) {
+	expected := `ALTER TABLE customers_staging RENAME TO customers;
+ALTER TABLE customer_contacts_staging RENAME TO customer_contacts;
+ALTER TABLE subnets_staging RENAME TO subnets;
+ALTER TABLE ips_staging RENAME TO ips;
+ALTER SEQUENCE customer_contacts_staging_id_seq RENAME TO customer_contacts_id_seq;
+ALTER SEQUENCE ips_staging_id_seq RENAME TO ips_id_seq;
+`
+	require.Equal(t, expected, renameGeneration(stagingTables, liveTables))
+}
@@ -0,0 +1,538 @@
+// Package assettest provides the contract tests that every implementation of
+// domain.PhysicalAssetStorer, domain.Fetcher, domain.AssetGraphFetcher, and
+// domain.AssetExporter must pass, so that the storage backends can be used interchangeably.
+package assettest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Backend is a storage backend under test. The Fetcher, Graph, and Exporter must read the
+// assets stored by the Storer. The Graph and Exporter cases are skipped when they are nil.
+type Backend struct {
+	Storer   domain.PhysicalAssetStorer
+	Fetcher  domain.Fetcher
+	Graph    domain.AssetGraphFetcher
+	Exporter domain.AssetExporter
+}
+
+// NewBackend returns a Backend whose Fetcher inherits the ownership of ancestor subnets
+// when inheritOwnership is set. Every case stores a complete dataset before fetching, so
+// backends may share their storage between calls.
+type NewBackend func(t *testing.T, inheritOwnership bool) Backend
+
+var inheritedFields = []string{"resourceOwner", "businessUnit", "customerID", "contacts"}
+
+// Run runs the contract tests against the backends returned by newBackend.
+func Run(t *testing.T, newBackend NewBackend) {
+	tc := []struct {
+		name             string
+		ipamData         domain.IPAMData
+		inheritOwnership bool
+		ip               string
+		expected         domain.PhysicalAsset
+		notFound         bool
+	}{
+		{
+			name:     "no data",
+			ip:       "10.0.0.1",
+			notFound: true,
+		},
+		{
+			name:     "outside every subnet",
+			ipamData: dataset(),
+			ip:       "192.168.0.1",
+			notFound: true,
+		},
+		{
+			name:     "subnet only",
+			ipamData: dataset(),
+			ip:       "10.2.0.5",
+			expected: domain.PhysicalAsset{
+				IP: "10.2.0.5", Network: "10.2.0.0/16", Location: "DC2", SubnetID: 2,
+				CustomerID: 2, ResourceOwner: "bob@example.com", ResourceOwnerRule: "contact-info",
+				BusinessUnit: "Team B", Contacts: []domain.Contact{},
+			},
+		},
+		{
+			name:     "device and subnet",
+			ipamData: dataset(),
+			ip:       "10.1.0.10",
+			expected: domain.PhysicalAsset{
+				IP: "10.1.0.10", Network: "10.1.0.0/16", Location: "DC1", SubnetID: 1, DeviceID: 100,
+				CustomerID: 1, ResourceOwner: "alice@example.com", ResourceOwnerRule: "contact-type:SRE",
+				BusinessUnit: "Team A", Contacts: contacts(),
+			},
+		},
+		{
+			name:     "IP without a device ID",
+			ipamData: dataset(),
+			ip:       "10.1.0.11",
+			expected: domain.PhysicalAsset{
+				IP: "10.1.0.11", Network: "10.1.0.0/16", Location: "DC1", SubnetID: 1,
+				CustomerID: 1, ResourceOwner: "alice@example.com", ResourceOwnerRule: "contact-type:SRE",
+				BusinessUnit: "Team A", Contacts: contacts(),
+			},
+		},
+		{
+			name:     "most specific subnet",
+			ipamData: dataset(),
+			ip:       "10.1.1.5",
+			expected: domain.PhysicalAsset{
+				IP: "10.1.1.5", Network: "10.1.1.0/24", Location: "DC1", SubnetID: 3,
+				CustomerID: 2, ResourceOwner: "bob@example.com", ResourceOwnerRule: "contact-info",
+				BusinessUnit: "Team B", Contacts: []domain.Contact{},
+			},
+		},
+		{
+			name:     "device in a less specific subnet",
+			ipamData: dataset(),
+			ip:       "10.1.1.20",
+			expected: domain.PhysicalAsset{
+				IP: "10.1.1.20", Network: "10.1.0.0/16", Location: "DC1", SubnetID: 1, DeviceID: 120,
+				CustomerID: 1, ResourceOwner: "alice@example.com", ResourceOwnerRule: "contact-type:SRE",
+				BusinessUnit: "Team A", Contacts: contacts(),
+			},
+		},
+		{
+			name:     "subnet without a customer",
+			ipamData: dataset(),
+			ip:       "10.1.2.5",
+			expected: domain.PhysicalAsset{
+				IP: "10.1.2.5", Network: "10.1.2.0/24", Location: "DC3", SubnetID: 4,
+			},
+		},
+		{
+			name:             "inherited ownership",
+			ipamData:         dataset(),
+			inheritOwnership: true,
+			ip:               "10.1.2.5",
+			expected: domain.PhysicalAsset{
+				IP: "10.1.2.5", Network: "10.1.2.0/24", Location: "DC3", SubnetID: 4,
+				CustomerID: 1, ResourceOwner: "alice@example.com", ResourceOwnerRule: "contact-type:SRE",
+				BusinessUnit: "Team A", Contacts: contacts(),
+				InheritedFields: inheritedFields, InheritedFromSubnetID: 1,
+			},
+		},
+		{
+			name:             "no ancestor to inherit from",
+			ipamData:         dataset(),
+			inheritOwnership: true,
+			ip:               "172.16.0.5",
+			expected: domain.PhysicalAsset{
+				IP: "172.16.0.5", Network: "172.16.0.0/24", Location: "DC3", SubnetID: 5,
+			},
+		},
+		{
+			name:     "IPv6 device",
+			ipamData: dataset(),
+			ip:       "2001:db8:1::5",
+			expected: domain.PhysicalAsset{
+				IP: "2001:db8:1::5", Network: "2001:db8:1::/48", Location: "DC4", SubnetID: 7, DeviceID: 700,
+				CustomerID: 2, ResourceOwner: "bob@example.com", ResourceOwnerRule: "contact-info",
+				BusinessUnit: "Team B", Contacts: []domain.Contact{},
+			},
+		},
+		{
+			name:     "IPv6 subnet only",
+			ipamData: dataset(),
+			ip:       "2001:db8:2::5",
+			expected: domain.PhysicalAsset{
+				IP: "2001:db8:2::5", Network: "2001:db8::/32", Location: "DC4", SubnetID: 6,
+			},
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			backend := newBackend(t, tt.inheritOwnership)
+			require.NoError(t, backend.Storer.StorePhysicalAssets(context.Background(), tt.ipamData))
+
+			asset, err := backend.Fetcher.FetchPhysicalAsset(context.Background(), tt.ip)
+			if tt.notFound {
+				require.IsType(t, domain.AssetNotFound{}, err)
+				assert.Equal(t, tt.ip, err.(domain.AssetNotFound).IP)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, asset)
+		})
+	}
+
+	t.Run("store replaces previous data", func(t *testing.T) {
+		backend := newBackend(t, false)
+		require.NoError(t, backend.Storer.StorePhysicalAssets(context.Background(), dataset()))
+		require.NoError(t, backend.Storer.StorePhysicalAssets(context.Background(), domain.IPAMData{
+			Subnets: []domain.Subnet{{ID: "9", Network: "192.168.0.0", MaskBits: 24, Location: "DC9"}},
+		}))
+
+		_, err := backend.Fetcher.FetchPhysicalAsset(context.Background(), "10.1.0.10")
+		require.IsType(t, domain.AssetNotFound{}, err)
+		asset, err := backend.Fetcher.FetchPhysicalAsset(context.Background(), "192.168.0.1")
+		require.NoError(t, err)
+		assert.Equal(t, int64(9), asset.SubnetID)
+	})
+
+	t.Run("invalid data keeps previous data", func(t *testing.T) {
+		invalid := []struct {
+			name     string
+			ipamData domain.IPAMData
+		}{
+			{
+				name: "missing customer",
+				ipamData: domain.IPAMData{
+					Subnets: []domain.Subnet{{ID: "1", Network: "10.0.0.0", MaskBits: 8, CustomerID: "99"}},
+				},
+			},
+			{
+				name: "missing subnet",
+				ipamData: domain.IPAMData{
+					Subnets: []domain.Subnet{{ID: "1", Network: "10.0.0.0", MaskBits: 8}},
+					Devices: []domain.Device{{ID: "1", IP: "10.0.0.1", SubnetID: "2"}},
+				},
+			},
+			{
+				name: "duplicate subnet",
+				ipamData: domain.IPAMData{
+					Subnets: []domain.Subnet{
+						{ID: "1", Network: "10.0.0.0", MaskBits: 8},
+						{ID: "1", Network: "11.0.0.0", MaskBits: 8},
+					},
+				},
+			},
+		}
+		for _, tt := range invalid {
+			t.Run(tt.name, func(t *testing.T) {
+				backend := newBackend(t, false)
+				require.NoError(t, backend.Storer.StorePhysicalAssets(context.Background(), dataset()))
+				assert.Error(t, backend.Storer.StorePhysicalAssets(context.Background(), tt.ipamData))
+
+				asset, err := backend.Fetcher.FetchPhysicalAsset(context.Background(), "10.1.0.10")
+				require.NoError(t, err)
+				assert.Equal(t, int64(100), asset.DeviceID)
+			})
+		}
+	})
+
+	t.Run("fetch subnets", func(t *testing.T) {
+		backend := newBackend(t, false)
+		require.NoError(t, backend.Storer.StorePhysicalAssets(context.Background(), dataset()))
+
+		subnets, err := backend.Fetcher.FetchSubnets(context.Background(), 3, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []domain.AssetSubnet{
+			{Network: "10.1.0.0/16", Location: "DC1", ResourceOwner: "alice@example.com", BusinessUnit: "Team A"},
+			{Network: "10.2.0.0/16", Location: "DC2", ResourceOwner: "bob@example.com", BusinessUnit: "Team B"},
+			{Network: "10.1.1.0/24", Location: "DC1", ResourceOwner: "bob@example.com", BusinessUnit: "Team B"},
+		}, subnets)
+
+		subnets, err = backend.Fetcher.FetchSubnets(context.Background(), 3, 6)
+		require.NoError(t, err)
+		assert.Equal(t, []domain.AssetSubnet{
+			{Network: "2001:db8:1::/48", Location: "DC4", ResourceOwner: "bob@example.com", BusinessUnit: "Team B"},
+		}, subnets)
+
+		subnets, err = backend.Fetcher.FetchSubnets(context.Background(), 3, 9)
+		require.NoError(t, err)
+		assert.Empty(t, subnets)
+	})
+
+	t.Run("fetch IPs", func(t *testing.T) {
+		backend := newBackend(t, false)
+		require.NoError(t, backend.Storer.StorePhysicalAssets(context.Background(), dataset()))
+
+		ips, err := backend.Fetcher.FetchIPs(context.Background(), 2, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []domain.AssetIP{
+			{IP: "10.1.1.20", Network: "10.1.0.0/16", Location: "DC1", ResourceOwner: "alice@example.com", BusinessUnit: "Team A"},
+			{IP: "10.1.1.20", Network: "10.1.1.0/24", Location: "DC1", ResourceOwner: "bob@example.com", BusinessUnit: "Team B"},
+		}, ips)
+
+		ips, err = backend.Fetcher.FetchIPs(context.Background(), 2, 4)
+		require.NoError(t, err)
+		assert.Equal(t, []domain.AssetIP{
+			{IP: "2001:db8:1::5", Network: "2001:db8:1::/48", Location: "DC4", ResourceOwner: "bob@example.com", BusinessUnit: "Team B"},
+		}, ips)
+	})
+
+	t.Run("query asset graph", func(t *testing.T) {
+		backend := newBackend(t, false)
+		if backend.Graph == nil {
+			t.Skip("the backend does not fetch the asset graph")
+		}
+		require.NoError(t, backend.Storer.StorePhysicalAssets(context.Background(), dataset()))
+		runGraph(t, backend.Graph)
+	})
+
+	t.Run("export assets", func(t *testing.T) {
+		backend := newBackend(t, false)
+		if backend.Exporter == nil {
+			t.Skip("the backend does not export assets")
+		}
+		require.NoError(t, backend.Storer.StorePhysicalAssets(context.Background(), dataset()))
+		runExport(t, backend.Exporter)
+	})
+}
+
+// runGraph runs the contract tests of a domain.AssetGraphFetcher against the stored dataset.
+// The IDs of IP addresses are assigned by the backends, so only their order is compared.
+func runGraph(t *testing.T, graph domain.AssetGraphFetcher) {
+	ctx := context.Background()
+	alice := domain.GraphCustomer{ID: 1, ResourceOwner: "alice@example.com", ResourceOwnerRule: "contact-type:SRE", BusinessUnit: "Team A"}
+	bob := domain.GraphCustomer{ID: 2, ResourceOwner: "bob@example.com", ResourceOwnerRule: "contact-info", BusinessUnit: "Team B"}
+
+	customers := []struct {
+		name     string
+		query    domain.CustomerQuery
+		expected []domain.GraphCustomer
+	}{
+		{name: "all", expected: []domain.GraphCustomer{alice, bob}},
+		{name: "by ID", query: domain.CustomerQuery{IDs: []int64{2, 3}}, expected: []domain.GraphCustomer{bob}},
+		{name: "by business unit", query: domain.CustomerQuery{BusinessUnit: "Team A"}, expected: []domain.GraphCustomer{alice}},
+		{name: "by resource owner", query: domain.CustomerQuery{ResourceOwner: "nobody@example.com"}, expected: []domain.GraphCustomer{}},
+		{name: "page", query: domain.CustomerQuery{After: 1, Limit: 1}, expected: []domain.GraphCustomer{bob}},
+	}
+	for _, tt := range customers {
+		t.Run("customers "+tt.name, func(t *testing.T) {
+			result, err := graph.QueryCustomers(ctx, tt.query)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+
+	t.Run("contacts", func(t *testing.T) {
+		result, err := graph.QueryContacts(ctx, []int64{1, 2})
+		require.NoError(t, err)
+		expected := make([]domain.GraphContact, 0)
+		for _, contact := range contacts() {
+			expected = append(expected, domain.GraphContact{CustomerID: 1, Contact: contact})
+		}
+		assert.Equal(t, expected, result)
+	})
+
+	subnets := []struct {
+		name     string
+		query    domain.SubnetQuery
+		expected []int64
+	}{
+		{name: "all", expected: []int64{1, 2, 3, 4, 5, 6, 7}},
+		{name: "by ID", query: domain.SubnetQuery{IDs: []int64{3, 5}}, expected: []int64{3, 5}},
+		{name: "limited per customer", query: domain.SubnetQuery{CustomerIDs: []int64{1, 2}, Limit: 2}, expected: []int64{1, 2, 3}},
+		{name: "customer page", query: domain.SubnetQuery{CustomerIDs: []int64{2}, After: 2, Limit: 1}, expected: []int64{3}},
+		{name: "containing an address", query: domain.SubnetQuery{Containing: []string{"10.1.1.20"}}, expected: []int64{1, 3}},
+		{name: "containing networks", query: domain.SubnetQuery{Containing: []string{"10.1.0.0/16", "2001:db8:1::/64"}}, expected: []int64{1, 6, 7}},
+		{name: "within", query: domain.SubnetQuery{Within: "10.1.0.0/16"}, expected: []int64{1, 3, 4}},
+		{name: "within IPv6", query: domain.SubnetQuery{Within: "2001:db8::/32"}, expected: []int64{6, 7}},
+		{name: "by location", query: domain.SubnetQuery{Location: "DC3"}, expected: []int64{4, 5}},
+		{name: "limited", query: domain.SubnetQuery{After: 2, Limit: 3}, expected: []int64{3, 4, 5}},
+	}
+	for _, tt := range subnets {
+		t.Run("subnets "+tt.name, func(t *testing.T) {
+			result, err := graph.QuerySubnets(ctx, tt.query)
+			require.NoError(t, err)
+			ids := make([]int64, 0, len(result))
+			for _, subnet := range result {
+				ids = append(ids, subnet.ID)
+			}
+			assert.Equal(t, tt.expected, ids)
+		})
+	}
+
+	t.Run("subnet fields", func(t *testing.T) {
+		result, err := graph.QuerySubnets(ctx, domain.SubnetQuery{IDs: []int64{3, 4, 7}})
+		require.NoError(t, err)
+		assert.Equal(t, []domain.GraphSubnet{
+			{ID: 3, Network: "10.1.1.0/24", Location: "DC1", CustomerID: 2},
+			{ID: 4, Network: "10.1.2.0/24", Location: "DC3"},
+			{ID: 7, Network: "2001:db8:1::/48", Location: "DC4", CustomerID: 2},
+		}, result)
+	})
+
+	all, err := graph.QueryIPs(ctx, domain.IPQuery{})
+	require.NoError(t, err)
+	require.Len(t, all, 5)
+	ips := []struct {
+		name     string
+		query    domain.IPQuery
+		expected []domain.GraphIP
+	}{
+		{
+			name: "all",
+			expected: []domain.GraphIP{
+				{IP: "10.1.0.10", DeviceID: 100, SubnetID: 1},
+				{IP: "10.1.0.11", SubnetID: 1},
+				{IP: "10.1.1.20", DeviceID: 120, SubnetID: 1},
+				{IP: "10.1.1.20", SubnetID: 3},
+				{IP: "2001:db8:1::5", DeviceID: 700, SubnetID: 7},
+			},
+		},
+		{
+			name:  "limited per subnet",
+			query: domain.IPQuery{SubnetIDs: []int64{1, 3}, Limit: 2},
+			expected: []domain.GraphIP{
+				{IP: "10.1.0.10", DeviceID: 100, SubnetID: 1},
+				{IP: "10.1.0.11", SubnetID: 1},
+				{IP: "10.1.1.20", SubnetID: 3},
+			},
+		},
+		{
+			name:  "by address",
+			query: domain.IPQuery{Address: "10.1.1.20"},
+			expected: []domain.GraphIP{
+				{IP: "10.1.1.20", DeviceID: 120, SubnetID: 1},
+				{IP: "10.1.1.20", SubnetID: 3},
+			},
+		},
+		{
+			name:     "by device",
+			query:    domain.IPQuery{DeviceID: 700},
+			expected: []domain.GraphIP{{IP: "2001:db8:1::5", DeviceID: 700, SubnetID: 7}},
+		},
+		{
+			name:  "within",
+			query: domain.IPQuery{Within: "10.1.0.0/24"},
+			expected: []domain.GraphIP{
+				{IP: "10.1.0.10", DeviceID: 100, SubnetID: 1},
+				{IP: "10.1.0.11", SubnetID: 1},
+			},
+		},
+		{
+			name:     "page",
+			query:    domain.IPQuery{After: all[1].ID, Limit: 1},
+			expected: []domain.GraphIP{{IP: "10.1.1.20", DeviceID: 120, SubnetID: 1}},
+		},
+	}
+	for _, tt := range ips {
+		t.Run("IPs "+tt.name, func(t *testing.T) {
+			result, err := graph.QueryIPs(ctx, tt.query)
+			require.NoError(t, err)
+			for i := range result {
+				if i > 0 {
+					assert.True(t, result[i-1].ID < result[i].ID, "IP addresses are out of order")
+				}
+				result[i].ID = 0
+			}
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+// exportRecorder records the records of an export.
+type exportRecorder struct {
+	snapshots int
+	customers []domain.ExportedCustomer
+	subnets   []domain.ExportedSubnet
+	ips       []domain.ExportedIP
+}
+
+func (r *exportRecorder) WriteSnapshot(domain.AssetSnapshot) error {
+	r.snapshots++
+	return nil
+}
+
+func (r *exportRecorder) WriteCustomer(customer domain.ExportedCustomer) error {
+	r.customers = append(r.customers, customer)
+	return nil
+}
+
+func (r *exportRecorder) WriteSubnet(subnet domain.ExportedSubnet) error {
+	r.subnets = append(r.subnets, subnet)
+	return nil
+}
+
+func (r *exportRecorder) WriteIP(ip domain.ExportedIP) error {
+	r.ips = append(r.ips, ip)
+	return nil
+}
+
+// runExport runs the contract tests of a domain.AssetExporter against the stored dataset.
+// The sync generation depends on how the backend tracks it, so only the records are compared.
+func runExport(t *testing.T, exporter domain.AssetExporter) {
+	ctx := context.Background()
+
+	t.Run("customers", func(t *testing.T) {
+		recorder := &exportRecorder{}
+		require.NoError(t, exporter.ExportAssets(ctx, domain.ExportCustomers, recorder))
+		assert.Equal(t, 1, recorder.snapshots)
+		assert.Equal(t, []domain.ExportedCustomer{
+			{ID: 1, ResourceOwner: "alice@example.com", ResourceOwnerRule: "contact-type:SRE", BusinessUnit: "Team A", Contacts: contacts()},
+			{ID: 2, ResourceOwner: "bob@example.com", ResourceOwnerRule: "contact-info", BusinessUnit: "Team B", Contacts: []domain.Contact{}},
+		}, recorder.customers)
+		assert.Empty(t, recorder.subnets)
+		assert.Empty(t, recorder.ips)
+	})
+
+	t.Run("subnets", func(t *testing.T) {
+		recorder := &exportRecorder{}
+		require.NoError(t, exporter.ExportAssets(ctx, domain.ExportSubnets, recorder))
+		assert.Equal(t, 1, recorder.snapshots)
+		assert.Equal(t, []domain.ExportedSubnet{
+			{ID: 1, Network: "10.1.0.0/16", Location: "DC1", CustomerID: 1, ResourceOwner: "alice@example.com", BusinessUnit: "Team A"},
+			{ID: 2, Network: "10.2.0.0/16", Location: "DC2", CustomerID: 2, ResourceOwner: "bob@example.com", BusinessUnit: "Team B"},
+			{ID: 3, Network: "10.1.1.0/24", Location: "DC1", CustomerID: 2, ResourceOwner: "bob@example.com", BusinessUnit: "Team B"},
+			{ID: 4, Network: "10.1.2.0/24", Location: "DC3"},
+			{ID: 5, Network: "172.16.0.0/24", Location: "DC3"},
+			{ID: 6, Network: "2001:db8::/32", Location: "DC4"},
+			{ID: 7, Network: "2001:db8:1::/48", Location: "DC4", CustomerID: 2, ResourceOwner: "bob@example.com", BusinessUnit: "Team B"},
+		}, recorder.subnets)
+	})
+
+	t.Run("IPs", func(t *testing.T) {
+		recorder := &exportRecorder{}
+		require.NoError(t, exporter.ExportAssets(ctx, domain.ExportIPs, recorder))
+		assert.Equal(t, 1, recorder.snapshots)
+		assert.Equal(t, []domain.ExportedIP{
+			{IP: "10.1.0.10", DeviceID: 100, SubnetID: 1, Network: "10.1.0.0/16", Location: "DC1", ResourceOwner: "alice@example.com", BusinessUnit: "Team A"},
+			{IP: "10.1.0.11", SubnetID: 1, Network: "10.1.0.0/16", Location: "DC1", ResourceOwner: "alice@example.com", BusinessUnit: "Team A"},
+			{IP: "10.1.1.20", DeviceID: 120, SubnetID: 1, Network: "10.1.0.0/16", Location: "DC1", ResourceOwner: "alice@example.com", BusinessUnit: "Team A"},
+			{IP: "10.1.1.20", SubnetID: 3, Network: "10.1.1.0/24", Location: "DC1", ResourceOwner: "bob@example.com", BusinessUnit: "Team B"},
+			{IP: "2001:db8:1::5", DeviceID: 700, SubnetID: 7, Network: "2001:db8:1::/48", Location: "DC4", ResourceOwner: "bob@example.com", BusinessUnit: "Team B"},
+		}, recorder.ips)
+	})
+
+	t.Run("unknown kind", func(t *testing.T) {
+		recorder := &exportRecorder{}
+		err := exporter.ExportAssets(ctx, domain.AssetExportKind("devices"), recorder)
+		require.IsType(t, domain.UnknownExportKind{}, err)
+		assert.Equal(t, 0, recorder.snapshots)
+	})
+}
+
+func contacts() []domain.Contact {
+	return []domain.Contact{
+		{Type: "SRE", Name: "Alice", Email: "alice@example.com", Phone: "555-0100"},
+		{Type: "Technical", Name: "Carol", Email: "carol@example.com", Phone: "555-0101"},
+	}
+}
+
+// dataset returns the assets that the contract tests store. Subnet 1 contains subnets 3 and 4,
+// and 10.1.1.20 is a device in subnet 1 and an address without a device in subnet 3.
+func dataset() domain.IPAMData {
+	return domain.IPAMData{
+		Customers: []domain.Customer{
+			{ID: "1", ResourceOwner: "alice@example.com", ResourceOwnerRule: "contact-type:SRE", BusinessUnit: "Team A", Contacts: contacts()},
+			{ID: "2", ResourceOwner: "bob@example.com", ResourceOwnerRule: "contact-info", BusinessUnit: "Team B"},
+		},
+		Subnets: []domain.Subnet{
+			{ID: "1", Network: "10.1.0.0", MaskBits: 16, Location: "DC1", CustomerID: "1"},
+			{ID: "2", Network: "10.2.0.0", MaskBits: 16, Location: "DC2", CustomerID: "2"},
+			{ID: "3", Network: "10.1.1.0", MaskBits: 24, Location: "DC1", CustomerID: "2"},
+			{ID: "4", Network: "10.1.2.0", MaskBits: 24, Location: "DC3"},
+			{ID: "5", Network: "172.16.0.0", MaskBits: 24, Location: "DC3", CustomerID: "0"},
+			{ID: "6", Network: "2001:db8::", MaskBits: 32, Location: "DC4"},
+			{ID: "7", Network: "2001:db8:1::", MaskBits: 48, Location: "DC4", CustomerID: "2"},
+		},
+		Devices: []domain.Device{
+			{ID: "100", IP: "10.1.0.10", SubnetID: "1"},
+			{IP: "10.1.0.11", SubnetID: "1"},
+			{ID: "120", IP: "10.1.1.20", SubnetID: "1"},
+			{IP: "10.1.1.20", SubnetID: "3"},
+			{ID: "700", IP: "2001:db8:1::5", SubnetID: "7"},
+		},
+	}
+}
@@ -0,0 +1,16 @@
+package domain
+
+import "context"
+
+// AssetCacheInvalidator discards the cached lookups of physical assets once a sync has
+// stored a new dataset, in this instance and in every other instance sharing the storage.
+type AssetCacheInvalidator interface {
+	InvalidateAssetCache(ctx context.Context) error
+}
+
+// SyncGenerationStore fetches the generation of the stored physical assets, a number that
+// the PhysicalAssetStorer changes along with the stored dataset, so that instances can tell
+// when their cached lookups are out of date.
+type SyncGenerationStore interface {
+	FetchSyncGeneration(ctx context.Context) (int64, error)
+}
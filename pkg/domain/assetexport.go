@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// AssetExportKind names the records of an export.
+type AssetExportKind string
+
+// The kinds of records that may be exported.
+const (
+	ExportCustomers AssetExportKind = "customers"
+	ExportSubnets   AssetExportKind = "subnets"
+	ExportIPs       AssetExportKind = "ips"
+)
+
+// AssetExporter streams every stored record of a kind to an AssetExportWriter, reading them
+// all from a single consistent snapshot of the stored dataset. Customers and subnets are
+// exported in the order of their IDs, and IP addresses in the order they were stored.
+type AssetExporter interface {
+	ExportAssets(ctx context.Context, kind AssetExportKind, writer AssetExportWriter) error
+}
+
+// AssetExportWriter receives an export. WriteSnapshot is called once, before any record, and
+// then the write method of the exported kind once for every record. An error returned by the
+// writer stops the export.
+type AssetExportWriter interface {
+	WriteSnapshot(snapshot AssetSnapshot) error
+	WriteCustomer(customer ExportedCustomer) error
+	WriteSubnet(subnet ExportedSubnet) error
+	WriteIP(ip ExportedIP) error
+}
+
+// AssetSnapshot identifies the stored dataset that an export reads: the sync generation,
+// which changes whenever a sync stores a new dataset, and the time it last changed. SyncedAt
+// is zero when no sync has stored a dataset yet.
+type AssetSnapshot struct {
+	Generation int64
+	SyncedAt   time.Time
+}
+
+// ExportedCustomer is a stored customer, along with its contacts.
+type ExportedCustomer struct {
+	ID                int64
+	ResourceOwner     string
+	ResourceOwnerRule string
+	BusinessUnit      string
+	Contacts          []Contact
+}
+
+// ExportedSubnet is a stored subnet, along with the ownership of its customer. The CustomerID
+// is 0 when the subnet has no customer.
+type ExportedSubnet struct {
+	ID            int64
+	Network       string
+	Location      string
+	CustomerID    int64
+	ResourceOwner string
+	BusinessUnit  string
+}
+
+// ExportedIP is a stored IP address, along with its subnet and the ownership of the subnet's
+// customer. The DeviceID is 0 when the address has no device.
+type ExportedIP struct {
+	IP            string
+	DeviceID      int64
+	SubnetID      int64
+	Network       string
+	Location      string
+	ResourceOwner string
+	BusinessUnit  string
+}
+
+// UnknownExportKind is used to indicate that an export of an unknown kind of records was
+// requested.
+type UnknownExportKind struct {
+	Kind string
+}
+
+func (e UnknownExportKind) Error() string {
+	return fmt.Sprintf("unknown export %q, expected customers, subnets, or ips", e.Kind)
+}
@@ -6,15 +6,21 @@ import (
 )
 
 // PhysicalAsset represents a non-cloud device with a network interface.
+// InheritedFields names the ownership fields that were inherited from the ancestor
+// subnet identified by InheritedFromSubnetID, because the asset's own subnet has no customer.
 type PhysicalAsset struct {
-	IP            string
-	ResourceOwner string
-	BusinessUnit  string
-	Network       string
-	Location      string
-	DeviceID      int64
-	SubnetID      int64
-	CustomerID    int64
+	IP                    string
+	ResourceOwner         string
+	ResourceOwnerRule     string
+	BusinessUnit          string
+	Network               string
+	Location              string
+	DeviceID              int64
+	SubnetID              int64
+	CustomerID            int64
+	Contacts              []Contact
+	InheritedFields       []string
+	InheritedFromSubnetID int64
 }
 
 // AssetSubnet represents a network subnet to which assets are allocated
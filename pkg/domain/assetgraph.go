@@ -0,0 +1,118 @@
+package domain
+
+import "context"
+
+// AssetGraphFetcher fetches the stored customers, subnets, and IP addresses along the
+// relationships between them. Records are returned in the order of their IDs. The queries
+// select the records related to several others at once, so that the records at each level
+// of a nested query are fetched together rather than one parent at a time.
+type AssetGraphFetcher interface {
+	QueryCustomers(ctx context.Context, query CustomerQuery) ([]GraphCustomer, error)
+	QueryContacts(ctx context.Context, customerIDs []int64) ([]GraphContact, error)
+	QuerySubnets(ctx context.Context, query SubnetQuery) ([]GraphSubnet, error)
+	QueryIPs(ctx context.Context, query IPQuery) ([]GraphIP, error)
+}
+
+// CustomerQuery selects the customers that match every field set. Customers are returned
+// after the After ID, at most Limit of them unless it is 0.
+type CustomerQuery struct {
+	IDs           []int64
+	ResourceOwner string
+	BusinessUnit  string
+	After         int64
+	Limit         int
+}
+
+// SubnetQuery selects the subnets that match every field set. Containing selects the
+// subnets whose network contains, or is, any of the given networks or addresses, and Within
+// those whose network is inside, or is, the given network. Subnets are returned after the
+// After ID, at most Limit of them unless it is 0. When CustomerIDs is set, the Limit applies
+// to the subnets of each customer.
+type SubnetQuery struct {
+	IDs         []int64
+	CustomerIDs []int64
+	Containing  []string
+	Within      string
+	Location    string
+	After       int64
+	Limit       int
+}
+
+// IPQuery selects the IP addresses that match every field set. Within selects the addresses
+// inside the given network. IP addresses are returned after the After ID, at most Limit of
+// them unless it is 0. When SubnetIDs is set, the Limit applies to the IP addresses of each
+// subnet.
+type IPQuery struct {
+	SubnetIDs []int64
+	Address   string
+	DeviceID  int64
+	Within    string
+	After     int64
+	Limit     int
+}
+
+// GraphCustomer is a stored customer.
+type GraphCustomer struct {
+	ID                int64
+	ResourceOwner     string
+	ResourceOwnerRule string
+	BusinessUnit      string
+}
+
+// GraphContact is a contact of a stored customer.
+type GraphContact struct {
+	CustomerID int64
+	Contact
+}
+
+// GraphSubnet is a stored subnet. The CustomerID is 0 when the subnet has no customer.
+type GraphSubnet struct {
+	ID         int64
+	Network    string
+	Location   string
+	CustomerID int64
+}
+
+// GraphIP is a stored IP address. The ID is assigned when the address is stored, so it
+// changes with every sync. The DeviceID is 0 when the address has no device.
+type GraphIP struct {
+	ID       int64
+	IP       string
+	DeviceID int64
+	SubnetID int64
+}
+
+// AssetGraphQuerier executes GraphQL queries over the stored customers, subnets, and IP
+// addresses.
+type AssetGraphQuerier interface {
+	QueryAssetGraph(ctx context.Context, request AssetGraphRequest) AssetGraphResult
+}
+
+// AssetGraphRequest is a GraphQL query, along with the name of the operation to execute when
+// it has several, and the values of its variables.
+type AssetGraphRequest struct {
+	Query         string
+	OperationName string
+	Variables     map[string]interface{}
+}
+
+// AssetGraphResult is the result of a GraphQL query. The Data is nil when the query was
+// rejected before it was executed.
+type AssetGraphResult struct {
+	Data   interface{}
+	Errors []AssetGraphError
+}
+
+// AssetGraphError is an error of a GraphQL query, with the locations in the query and the
+// path in the result where it occurred.
+type AssetGraphError struct {
+	Message   string
+	Locations []AssetGraphLocation
+	Path      []interface{}
+}
+
+// AssetGraphLocation is a line and column of a GraphQL query.
+type AssetGraphLocation struct {
+	Line   int
+	Column int
+}
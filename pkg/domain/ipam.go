@@ -3,33 +3,66 @@ package domain
 import "context"
 
 // Device represents a physical device with a network interface on the stored IP address.
+// RecordID identifies the IP address record in its source, when the source has one, so that
+// an address that moves to another subnet or is renumbered can be matched with its previous
+// version.
 type Device struct {
 	ID       string
 	IP       string
 	SubnetID string
+	RecordID string
+	Source   string
 }
 
 // Subnet represents a block of IP addresses allocated to a ResourceOwner.
 type Subnet struct {
 	ID         string
 	Network    string
-	MaskBits   int8
+	MaskBits   int
 	Location   string
 	CustomerID string
+	Source     string
 }
 
 // Customer represents a person and team most directly responsible for a Subnet.
+// ResourceOwnerRule records which rule was used to choose the ResourceOwner.
 type Customer struct {
-	ID            string
-	ResourceOwner string
-	BusinessUnit  string
+	ID                string
+	ResourceOwner     string
+	ResourceOwnerRule string
+	BusinessUnit      string
+	Contacts          []Contact
+	Source            string
 }
 
-// IPAMData represents the full collection of IPAM data stored by the IPAM Facade.
+// Contact represents one of the typed contacts, such as a technical owner or an
+// escalation point, registered for a Customer.
+type Contact struct {
+	Type   string
+	Name   string
+	Email  string
+	Phone  string
+	Source string
+}
+
+// SourceConflict records that the IPAM data sources merged for a sync disagree about a Field
+// of the Subnet with the given ID and Network. Values lists the value of each source, and Kept
+// is the value that was stored.
+type SourceConflict struct {
+	SubnetID string
+	Network  string
+	Field    string
+	Values   string
+	Kept     string
+}
+
+// IPAMData represents the full collection of IPAM data stored by the IPAM Facade. Conflicts
+// lists the disagreements between the sources the data was merged from, if any.
 type IPAMData struct {
 	Devices   []Device
 	Subnets   []Subnet
 	Customers []Customer
+	Conflicts []SourceConflict
 }
 
 // SubnetFetcher is an interface to fetch Subnet information
@@ -1,8 +1,40 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // IPAMDataFetcher fetches IPAM data from a CMDB like Device42 for local storage.
 type IPAMDataFetcher interface {
 	FetchIPAMData(context.Context) (IPAMData, error)
 }
+
+// IPAMDataCommitter is implemented by IPAMDataFetchers that keep state between fetches. It is
+// called once the data of the last fetch has been stored, so that the state only moves past
+// data that was stored, and data that could not be stored is fetched again by the next sync.
+type IPAMDataCommitter interface {
+	CommitIPAMData(context.Context) error
+}
+
+// IncrementalSyncState is the state an incremental IPAMDataFetcher keeps between syncs: the
+// high-water mark of its last fetch and the time of its last full fetch. A zero LastFull means
+// that there is no state yet, and that the next fetch must be a full one.
+type IncrementalSyncState struct {
+	Watermark time.Time
+	LastFull  time.Time
+}
+
+// IncrementalSyncStateStore persists the IncrementalSyncState, so that an incremental
+// IPAMDataFetcher can carry on from the last stored sync after a restart, or from another
+// instance sharing the storage.
+type IncrementalSyncStateStore interface {
+	FetchIncrementalSyncState(context.Context) (IncrementalSyncState, error)
+	StoreIncrementalSyncState(context.Context, IncrementalSyncState) error
+}
+
+// StoredSourceFetcher fetches the stored subnets and IPs of a single IPAM data source, which
+// an incremental IPAMDataFetcher merges the changes it fetches into.
+type StoredSourceFetcher interface {
+	FetchStoredSource(ctx context.Context, source string) (IPAMData, error)
+}
@@ -0,0 +1,18 @@
+package domain
+
+import "context"
+
+// QuarantinedRecord is a record fetched from a CMDB that failed validation and was
+// left out of local storage. RecordType is one of the QualityRecord types, and RecordID
+// is the CMDB ID of the record, or the IP address for records of type "ip".
+type QuarantinedRecord struct {
+	RecordType string
+	RecordID   string
+	Reason     string
+}
+
+// IPAMDataValidator separates the valid records of IPAM data fetched from a CMDB from
+// those that cannot be stored, so that a sync can proceed with the valid data.
+type IPAMDataValidator interface {
+	ValidateIPAMData(context.Context, IPAMData) (IPAMData, []QuarantinedRecord)
+}
@@ -0,0 +1,19 @@
+package domain
+
+import "context"
+
+// PrefixFilter selects the subnets whose customer has the BusinessUnit and ResourceOwner, and
+// that are in the Location and inside, or are, the Within network, for every field set.
+type PrefixFilter struct {
+	BusinessUnit  string
+	ResourceOwner string
+	Location      string
+	Within        string
+}
+
+// PrefixFetcher fetches the networks of the subnets selected by a filter as the smallest set
+// of prefixes that covers them, with nested and adjacent networks aggregated. The IPv4
+// prefixes are returned before the IPv6 ones, each in address order.
+type PrefixFetcher interface {
+	FetchPrefixes(ctx context.Context, filter PrefixFilter) ([]string, error)
+}
@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// RetainedQualityReports is the number of the most recent data quality reports that are kept,
+// so that the outcome of a sync job can still be read once later syncs have run.
+const RetainedQualityReports = 100
+
+// Categories of data quality findings.
+const (
+	// QualitySubnetWithoutCustomer is a Subnet that is not associated with a Customer.
+	QualitySubnetWithoutCustomer = "subnet-without-customer"
+	// QualityCustomerEmptyOwner is a Customer with no resource owner.
+	QualityCustomerEmptyOwner = "customer-empty-owner"
+	// QualityIPUnknownSubnet is a Device IP address whose subnet ID does not match any Subnet.
+	QualityIPUnknownSubnet = "ip-unknown-subnet"
+	// QualityOverlappingSubnets is a Subnet that overlaps another Subnet with a different Customer.
+	QualityOverlappingSubnets = "overlapping-subnets"
+	// QualityInvalidSubnet is a Subnet whose network and mask bits do not form a valid CIDR.
+	QualityInvalidSubnet = "invalid-subnet"
+	// QualitySourceConflict is a Subnet whose merged sources disagree about its resource owner.
+	QualitySourceConflict = "source-conflict"
+)
+
+// Types of the records that data quality findings refer to.
+const (
+	QualityRecordCustomer = "customer"
+	QualityRecordSubnet   = "subnet"
+	QualityRecordIP       = "ip"
+)
+
+// QualityFinding is a single data quality problem found in the IPAM data. RecordID is the
+// Device42 ID of the offending record, or the IP address for records of type "ip".
+type QualityFinding struct {
+	Category   string
+	RecordType string
+	RecordID   string
+	Detail     string
+}
+
+// QualityReport is the set of data quality findings from an IPAM data sync, along with the ID
+// of its job, if it ran as one, and the records it quarantined.
+type QualityReport struct {
+	GeneratedAt time.Time
+	JobID       string
+	Findings    []QualityFinding
+	Quarantined []QuarantinedRecord
+}
+
+// QualityAnalyzer inspects IPAM data fetched from a CMDB for data quality problems.
+type QualityAnalyzer interface {
+	AnalyzeIPAMData(context.Context, IPAMData) []QualityFinding
+}
+
+// QualityReportStorer stores the data quality report of a sync as the most recent one. The
+// time the report is generated at is set as it is stored.
+type QualityReportStorer interface {
+	StoreQualityReport(context.Context, QualityReport) error
+}
+
+// QualityReportFetcher fetches the most recent data quality report, optionally filtered
+// to a single category.
+type QualityReportFetcher interface {
+	FetchQualityReport(ctx context.Context, category string) (QualityReport, error)
+}
+
+// SyncJobReportFetcher fetches the data quality report of the sync that ran as a job,
+// returning SyncJobNotFound when there is none.
+type SyncJobReportFetcher interface {
+	FetchSyncJobReport(ctx context.Context, jobID string) (QualityReport, error)
+}
+
+// SyncJobNotFound is used to indicate that no report of a sync job is stored, as the job has
+// not run yet, failed to fetch its data, or ran before the retained reports.
+type SyncJobNotFound struct {
+	JobID string
+}
+
+func (e SyncJobNotFound) Error() string {
+	return fmt.Sprintf("no report of sync job %s found in storage", e.JobID)
+}
@@ -14,6 +14,9 @@ type SQLDB interface {
 	RunScript(ctx context.Context, name string) error
 	// Conn returns an existing, initialized database connection, or nil if one does not exist
 	Conn() *sql.DB
+	// ReadConn returns a database connection for queries that may be served by a read replica,
+	// which is the same as Conn when there is no healthy replica
+	ReadConn() *sql.DB
 	// Use closes any existing database connection and opens a new one using the given connection string
 	Use(ctx context.Context, psqlInfo string) error
 }
@@ -0,0 +1,18 @@
+package domain
+
+import "context"
+
+// SyncLocker ensures that a single sync stores physical assets at a time, across every
+// instance sharing the storage. LockSync returns a function that releases the lock, or
+// SyncInProgress when another sync holds it.
+type SyncLocker interface {
+	LockSync(ctx context.Context) (func(), error)
+}
+
+// SyncInProgress is used to indicate that a sync was not started because another sync is
+// still running.
+type SyncInProgress struct{}
+
+func (e SyncInProgress) Error() string {
+	return "another sync of the IPAM data is in progress"
+}
@@ -0,0 +1,38 @@
+package filesource
+
+import (
+	"context"
+	"errors"
+)
+
+// Config contains configuration settings for a file based IPAMDataFetcher
+type Config struct {
+	Path string `description:"Path of a YAML, JSON, or CSV file of IPAM data, or of a directory of such files."`
+}
+
+// Name is used by the settings library to replace the default naming convention.
+func (*Config) Name() string {
+	return "File"
+}
+
+// NewComponent generates a new, unititialized Component
+func NewComponent() *Component {
+	return &Component{}
+}
+
+// Component satisfies the settings library Component API,
+// and may be used by the settings.NewComponent function.
+type Component struct{}
+
+// Settings generates a config with default values applied.
+func (*Component) Settings() *Config {
+	return &Config{}
+}
+
+// New constructs an IPAMDataFetcher from a config.
+func (*Component) New(_ context.Context, conf *Config) (*IPAMDataFetcher, error) {
+	if conf.Path == "" {
+		return nil, errors.New("a file or directory path is required for the file IPAM data source")
+	}
+	return &IPAMDataFetcher{Path: conf.Path}, nil
+}
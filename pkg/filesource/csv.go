@@ -0,0 +1,68 @@
+package filesource
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+)
+
+// parseCSV reads a CSV file holding a single record type, named by the file, such as
+// subnets.csv. The header row names the fields of each column. Customer contacts cannot
+// be expressed in CSV. Line numbers assume that no quoted value spans multiple lines.
+func parseCSV(path string, recordType string, data []byte) (records, SchemaErrors) {
+	result := make(records)
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err == io.EOF {
+		return result, nil
+	}
+	if err != nil {
+		return nil, SchemaErrors{{Path: path, Reason: err.Error()}}
+	}
+
+	var errs SchemaErrors
+	columns := make([]string, len(header))
+	seen := make(map[string]bool)
+	for i, name := range header {
+		field, ok := canonicalField(schemas[recordType].Fields, name)
+		switch {
+		case !ok || field == "contacts":
+			errs = append(errs, SchemaError{Path: path, Line: 1, Reason: fmt.Sprintf("unknown %s column %q", recordType, name)})
+		case seen[field]:
+			errs = append(errs, SchemaError{Path: path, Line: 1, Reason: fmt.Sprintf("%s column %q listed more than once", recordType, name)})
+		default:
+			columns[i] = field
+			seen[field] = true
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	for line := 2; ; line++ {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// the csv reader reports its own line numbers, and cannot continue after most errors
+			errs = append(errs, SchemaError{Path: path, Reason: err.Error()})
+			if parseErr, ok := err.(*csv.ParseError); ok && parseErr.Err == csv.ErrFieldCount {
+				continue
+			}
+			break
+		}
+		r := record{Line: line, Fields: make(map[string]string)}
+		for i, value := range row {
+			r.Fields[columns[i]] = value
+		}
+		recordErrs := r.validate(path, recordType)
+		errs = append(errs, recordErrs...)
+		if len(recordErrs) == 0 {
+			result[recordType] = append(result[recordType], r)
+		}
+	}
+	return result, errs
+}
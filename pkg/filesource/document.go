@@ -0,0 +1,125 @@
+package filesource
+
+import (
+	"fmt"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+	"gopkg.in/yaml.v3"
+)
+
+// parseDocument reads a YAML or JSON document with top level "customers", "subnets", and "ips"
+// lists of records. JSON is a subset of YAML, so both are parsed into YAML nodes, which keep
+// the line numbers used in SchemaErrors.
+func parseDocument(path string, data []byte) (records, SchemaErrors) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, SchemaErrors{{Path: path, Reason: err.Error()}}
+	}
+	result := make(records)
+	if len(root.Content) == 0 {
+		return result, nil
+	}
+	document := root.Content[0]
+	if document.Kind != yaml.MappingNode {
+		return nil, SchemaErrors{{Path: path, Line: document.Line, Reason: `expected a mapping of "customers", "subnets", and "ips"`}}
+	}
+
+	var errs SchemaErrors
+	seen := make(map[string]bool)
+	for i := 0; i+1 < len(document.Content); i += 2 {
+		key, value := document.Content[i], document.Content[i+1]
+		recordType := key.Value
+		if _, ok := schemas[recordType]; !ok {
+			errs = append(errs, SchemaError{Path: path, Line: key.Line, Reason: fmt.Sprintf("unknown record type %q", recordType)})
+			continue
+		}
+		if seen[recordType] {
+			errs = append(errs, SchemaError{Path: path, Line: key.Line, Reason: fmt.Sprintf("%s listed more than once", recordType)})
+			continue
+		}
+		seen[recordType] = true
+		if isNull(value) {
+			continue
+		}
+		if value.Kind != yaml.SequenceNode {
+			errs = append(errs, SchemaError{Path: path, Line: value.Line, Reason: fmt.Sprintf("%s must be a list", recordType)})
+			continue
+		}
+		for _, item := range value.Content {
+			r, recordErrs := parseRecord(path, recordType, item)
+			errs = append(errs, recordErrs...)
+			if len(recordErrs) == 0 {
+				result[recordType] = append(result[recordType], r)
+			}
+		}
+	}
+	return result, errs
+}
+
+func parseRecord(path string, recordType string, node *yaml.Node) (record, SchemaErrors) {
+	r := record{Line: node.Line, Fields: make(map[string]string)}
+	if node.Kind != yaml.MappingNode {
+		return r, SchemaErrors{{Path: path, Line: node.Line, Reason: fmt.Sprintf("each of the %s must be a mapping of fields", recordType)}}
+	}
+	var errs SchemaErrors
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		field, ok := canonicalField(schemas[recordType].Fields, key.Value)
+		switch {
+		case !ok:
+			errs = append(errs, SchemaError{Path: path, Line: key.Line, Reason: fmt.Sprintf("unknown %s field %q", recordType, key.Value)})
+		case field == "contacts":
+			contacts, contactErrs := parseContacts(path, value)
+			r.Contacts = contacts
+			errs = append(errs, contactErrs...)
+		case isNull(value):
+		case value.Kind != yaml.ScalarNode:
+			errs = append(errs, SchemaError{Path: path, Line: value.Line, Reason: fmt.Sprintf("%s field %q must be a single value", recordType, field)})
+		default:
+			r.Fields[field] = value.Value
+		}
+	}
+	return r, append(errs, r.validate(path, recordType)...)
+}
+
+func parseContacts(path string, node *yaml.Node) ([]domain.Contact, SchemaErrors) {
+	if isNull(node) {
+		return nil, nil
+	}
+	if node.Kind != yaml.SequenceNode {
+		return nil, SchemaErrors{{Path: path, Line: node.Line, Reason: "contacts must be a list"}}
+	}
+	var errs SchemaErrors
+	contacts := make([]domain.Contact, 0, len(node.Content))
+	for _, item := range node.Content {
+		if item.Kind != yaml.MappingNode {
+			errs = append(errs, SchemaError{Path: path, Line: item.Line, Reason: "each contact must be a mapping of fields"})
+			continue
+		}
+		fields := make(map[string]string)
+		for i := 0; i+1 < len(item.Content); i += 2 {
+			key, value := item.Content[i], item.Content[i+1]
+			field, ok := canonicalField(contactFields, key.Value)
+			switch {
+			case !ok:
+				errs = append(errs, SchemaError{Path: path, Line: key.Line, Reason: fmt.Sprintf("unknown contact field %q", key.Value)})
+			case isNull(value):
+			case value.Kind != yaml.ScalarNode:
+				errs = append(errs, SchemaError{Path: path, Line: value.Line, Reason: fmt.Sprintf("contact field %q must be a single value", field)})
+			default:
+				fields[field] = value.Value
+			}
+		}
+		contacts = append(contacts, domain.Contact{
+			Type:  fields["type"],
+			Name:  fields["name"],
+			Email: fields["email"],
+			Phone: fields["phone"],
+		})
+	}
+	return contacts, errs
+}
+
+func isNull(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode && node.Tag == "!!null"
+}
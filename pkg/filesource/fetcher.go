@@ -0,0 +1,125 @@
+package filesource
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+	"github.com/asecurityteam/ipam-facade/pkg/subnetassign"
+)
+
+// IPAMDataFetcher implements the IPAMDataFetcher interface to read IPAM data from YAML,
+// JSON, or CSV files, for sites without a CMDB and for local development.
+//
+// Path may name a single file or a directory, in which case every .yaml, .yml, .json, and
+// .csv file directly inside of it is read in name order and the records are combined.
+// Files that do not match the schema fail the fetch with SchemaErrors, while records that
+// match the schema but hold invalid values are left to the sync validation to quarantine.
+// IPs without a subnetID are assigned to the most specific subnet that contains them.
+type IPAMDataFetcher struct {
+	Path string
+}
+
+// FetchIPAMData reads and validates the IPAM data files.
+func (f *IPAMDataFetcher) FetchIPAMData(ctx context.Context) (domain.IPAMData, error) {
+	paths, err := f.files()
+	if err != nil {
+		return domain.IPAMData{}, err
+	}
+
+	ipamData := domain.IPAMData{
+		Customers: make([]domain.Customer, 0),
+		Subnets:   make([]domain.Subnet, 0),
+		Devices:   make([]domain.Device, 0),
+	}
+	var errs SchemaErrors
+	for _, path := range paths {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return domain.IPAMData{}, err
+		}
+		fileRecords, fileErrs := parseFile(path, data)
+		errs = append(errs, fileErrs...)
+		for _, r := range fileRecords[customerRecords] {
+			ipamData.Customers = append(ipamData.Customers, r.toCustomer())
+		}
+		for _, r := range fileRecords[subnetRecords] {
+			ipamData.Subnets = append(ipamData.Subnets, r.toSubnet())
+		}
+		for _, r := range fileRecords[ipRecords] {
+			ipamData.Devices = append(ipamData.Devices, r.toDevice())
+		}
+	}
+	if len(errs) > 0 {
+		return domain.IPAMData{}, errs
+	}
+
+	unassigned := make([]domain.Device, 0)
+	positions := make([]int, 0)
+	for i, device := range ipamData.Devices {
+		if device.SubnetID == "" {
+			unassigned = append(unassigned, device)
+			positions = append(positions, i)
+		}
+	}
+	subnetassign.AssignSubnets(unassigned, ipamData.Subnets)
+	for i, position := range positions {
+		ipamData.Devices[position] = unassigned[i]
+	}
+	return ipamData, nil
+}
+
+// CheckDependencies verifies that there are IPAM data files to read.
+func (f *IPAMDataFetcher) CheckDependencies(ctx context.Context) error {
+	_, err := f.files()
+	return err
+}
+
+// files lists the IPAM data files to read from the configured path.
+func (f *IPAMDataFetcher) files() ([]string, error) {
+	info, err := os.Stat(f.Path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{f.Path}, nil
+	}
+	entries, err := ioutil.ReadDir(f.Path)
+	if err != nil {
+		return nil, err
+	}
+	paths := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".yaml", ".yml", ".json", ".csv":
+			if !entry.IsDir() {
+				paths = append(paths, filepath.Join(f.Path, entry.Name()))
+			}
+		}
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no IPAM data files found in %s", f.Path)
+	}
+	return paths, nil
+}
+
+// parseFile parses a single IPAM data file according to its extension.
+func parseFile(path string, data []byte) (records, SchemaErrors) {
+	ext := strings.ToLower(filepath.Ext(path))
+	switch ext {
+	case ".yaml", ".yml", ".json":
+		return parseDocument(path, data)
+	case ".csv":
+		recordType := strings.ToLower(strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)))
+		if _, ok := schemas[recordType]; !ok {
+			return nil, SchemaErrors{{Path: path, Reason: "CSV files must be named customers.csv, subnets.csv, or ips.csv"}}
+		}
+		return parseCSV(path, recordType, data)
+	default:
+		return nil, SchemaErrors{{Path: path, Reason: fmt.Sprintf("unsupported file type %q", ext)}}
+	}
+}
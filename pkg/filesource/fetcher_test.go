@@ -0,0 +1,199 @@
+package filesource
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, dir string, name string, content string) string {
+	path := filepath.Join(dir, name)
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func tempDir(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "filesource")
+	require.NoError(t, err)
+	return dir, func() { _ = os.RemoveAll(dir) }
+}
+
+func TestFetchIPAMDataDirectory(t *testing.T) {
+	fetcher := &IPAMDataFetcher{Path: filepath.Join("testdata", "lab")}
+	ipamData, err := fetcher.FetchIPAMData(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, []domain.Customer{
+		{
+			ID:                "1",
+			ResourceOwner:     "payments@example.com",
+			ResourceOwnerRule: "file",
+			BusinessUnit:      "Payments",
+			Contacts:          []domain.Contact{{Type: "Technical", Name: "Alice", Email: "alice@example.com", Phone: "555-0100"}},
+		},
+		{ID: "2", BusinessUnit: "Security"},
+	}, ipamData.Customers)
+	assert.Equal(t, []domain.Subnet{
+		{ID: "1", Network: "10.0.0.0", MaskBits: 16, Location: "DC1", CustomerID: "1"},
+		{ID: "2", Network: "10.0.1.0", MaskBits: 24, Location: "DC2", CustomerID: "2"},
+		{ID: "3", Network: "2001:db8::", MaskBits: 32},
+	}, ipamData.Subnets)
+	assert.Equal(t, []domain.Device{
+		{ID: "70", IP: "10.0.0.5", SubnetID: "1"},
+		{IP: "10.0.1.5", SubnetID: "2"},
+		{IP: "2001:db8::1", SubnetID: "1"},
+		{IP: "192.168.0.1"},
+	}, ipamData.Devices)
+}
+
+func TestFetchIPAMDataSingleFile(t *testing.T) {
+	fetcher := &IPAMDataFetcher{Path: filepath.Join("testdata", "lab", "subnets.csv")}
+	ipamData, err := fetcher.FetchIPAMData(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, ipamData.Customers)
+	assert.Len(t, ipamData.Subnets, 2)
+	assert.Empty(t, ipamData.Devices)
+}
+
+func TestFetchIPAMDataSchemaErrors(t *testing.T) {
+	tc := []struct {
+		Name     string
+		File     string
+		Content  string
+		Expected SchemaErrors
+	}{
+		{
+			Name:    "unknown record type",
+			File:    "ipam.yaml",
+			Content: "customers: []\ndevices:\n  - ip: 10.0.0.1\n",
+			Expected: SchemaErrors{
+				{Line: 2, Reason: `unknown record type "devices"`},
+			},
+		},
+		{
+			Name:    "missing and malformed fields",
+			File:    "ipam.yaml",
+			Content: "subnets:\n  - id: 1\n    network: 10.0.0.0\n  - network: 10.0.1.0\n    maskBits: big\n    vlan: 7\n",
+			Expected: SchemaErrors{
+				{Line: 2, Reason: `subnets need a "maskBits" field or a "network" in CIDR notation`},
+				{Line: 6, Reason: `unknown subnets field "vlan"`},
+				{Line: 4, Reason: `subnets is missing required field "id"`},
+				{Line: 4, Reason: `subnets field "maskBits" must be an integer, got "big"`},
+			},
+		},
+		{
+			Name:    "wrong shapes",
+			File:    "ipam.json",
+			Content: "{\n  \"ips\": [\n    \"10.0.0.1\",\n    {\"ip\": [\"10.0.0.2\"]}\n  ],\n  \"customers\": {\"id\": 1}\n}\n",
+			Expected: SchemaErrors{
+				{Line: 3, Reason: "each of the ips must be a mapping of fields"},
+				{Line: 4, Reason: `ips field "ip" must be a single value`},
+				{Line: 4, Reason: `ips is missing required field "ip"`},
+				{Line: 6, Reason: "customers must be a list"},
+			},
+		},
+		{
+			Name:    "bad contacts",
+			File:    "ipam.yml",
+			Content: "customers:\n  - id: 1\n    contacts:\n      - email: a@example.com\n        pager: 1\n      - b@example.com\n",
+			Expected: SchemaErrors{
+				{Line: 5, Reason: `unknown contact field "pager"`},
+				{Line: 6, Reason: "each contact must be a mapping of fields"},
+			},
+		},
+		{
+			Name:     "not a mapping",
+			File:     "ipam.yaml",
+			Content:  "- id: 1\n",
+			Expected: SchemaErrors{{Line: 1, Reason: `expected a mapping of "customers", "subnets", and "ips"`}},
+		},
+		{
+			Name:    "csv rows",
+			File:    "ips.csv",
+			Content: "IP,SubnetID\n10.0.0.1,1\n,2\n10.0.0.3\n",
+			Expected: SchemaErrors{
+				{Line: 3, Reason: `ips is missing required field "ip"`},
+				{Reason: "record on line 4: wrong number of fields"},
+			},
+		},
+		{
+			Name:     "csv header",
+			File:     "customers.csv",
+			Content:  "id,contacts,owner\n1,,\n",
+			Expected: SchemaErrors{{Line: 1, Reason: `unknown customers column "contacts"`}, {Line: 1, Reason: `unknown customers column "owner"`}},
+		},
+		{
+			Name:     "csv name",
+			File:     "devices.csv",
+			Content:  "ip\n10.0.0.1\n",
+			Expected: SchemaErrors{{Reason: "CSV files must be named customers.csv, subnets.csv, or ips.csv"}},
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.Name, func(t *testing.T) {
+			dir, cleanup := tempDir(t)
+			defer cleanup()
+			path := writeFile(t, dir, tt.File, tt.Content)
+			for i := range tt.Expected {
+				tt.Expected[i].Path = path
+			}
+
+			_, err := (&IPAMDataFetcher{Path: path}).FetchIPAMData(context.Background())
+			require.Error(t, err)
+			assert.Equal(t, tt.Expected, err)
+		})
+	}
+}
+
+func TestFetchIPAMDataSyntaxError(t *testing.T) {
+	dir, cleanup := tempDir(t)
+	defer cleanup()
+	path := writeFile(t, dir, "ipam.yaml", "subnets:\n  - id: [1\n")
+
+	_, err := (&IPAMDataFetcher{Path: path}).FetchIPAMData(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), path+": yaml: line")
+}
+
+func TestFetchIPAMDataEmptyFiles(t *testing.T) {
+	dir, cleanup := tempDir(t)
+	defer cleanup()
+	writeFile(t, dir, "ipam.yaml", "")
+	writeFile(t, dir, "subnets.csv", "")
+	writeFile(t, dir, "customers.yaml", "customers:\n")
+
+	ipamData, err := (&IPAMDataFetcher{Path: dir}).FetchIPAMData(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, domain.IPAMData{Customers: []domain.Customer{}, Subnets: []domain.Subnet{}, Devices: []domain.Device{}}, ipamData)
+}
+
+func TestCheckDependencies(t *testing.T) {
+	dir, cleanup := tempDir(t)
+	defer cleanup()
+	writeFile(t, dir, "notes.txt", "")
+
+	assert.NoError(t, (&IPAMDataFetcher{Path: filepath.Join("testdata", "lab")}).CheckDependencies(context.Background()))
+	assert.Error(t, (&IPAMDataFetcher{Path: dir}).CheckDependencies(context.Background()))
+	assert.Error(t, (&IPAMDataFetcher{Path: filepath.Join(dir, "missing.yaml")}).CheckDependencies(context.Background()))
+}
+
+func TestConfig(t *testing.T) {
+	assert.Equal(t, "File", (&Config{}).Name())
+
+	component := NewComponent()
+	conf := component.Settings()
+	_, err := component.New(context.Background(), conf)
+	assert.Error(t, err)
+
+	conf.Path = "ipam.yaml"
+	fetcher, err := component.New(context.Background(), conf)
+	require.NoError(t, err)
+	assert.Equal(t, "ipam.yaml", fetcher.Path)
+}
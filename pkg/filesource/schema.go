@@ -0,0 +1,152 @@
+package filesource
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+)
+
+const (
+	customerRecords = "customers"
+	subnetRecords   = "subnets"
+	ipRecords       = "ips"
+)
+
+// SchemaError describes a part of an IPAM data file that does not match the expected schema.
+// Line is zero when the error does not apply to a particular line.
+type SchemaError struct {
+	Path   string
+	Line   int
+	Reason string
+}
+
+func (e SchemaError) Error() string {
+	if e.Line == 0 {
+		return fmt.Sprintf("%s: %s", e.Path, e.Reason)
+	}
+	return fmt.Sprintf("%s:%d: %s", e.Path, e.Line, e.Reason)
+}
+
+// SchemaErrors collects every SchemaError found in the IPAM data files, so that all of the
+// problems in a file can be fixed at once.
+type SchemaErrors []SchemaError
+
+func (e SchemaErrors) Error() string {
+	reasons := make([]string, 0, len(e))
+	for _, err := range e {
+		reasons = append(reasons, err.Error())
+	}
+	return fmt.Sprintf("invalid IPAM data files: %s", strings.Join(reasons, "; "))
+}
+
+// recordSchema lists the fields of a record type. Field names are matched case-insensitively.
+type recordSchema struct {
+	Fields   []string
+	Required []string
+	Integers []string
+}
+
+var schemas = map[string]recordSchema{
+	customerRecords: {
+		Fields:   []string{"id", "resourceOwner", "businessUnit", "contacts"},
+		Required: []string{"id"},
+	},
+	subnetRecords: {
+		Fields:   []string{"id", "network", "maskBits", "location", "customerID"},
+		Required: []string{"id", "network"},
+		Integers: []string{"maskBits"},
+	},
+	ipRecords: {
+		Fields:   []string{"ip", "id", "subnetID"},
+		Required: []string{"ip"},
+	},
+}
+
+var contactFields = []string{"type", "name", "email", "phone"}
+
+// canonicalField returns the schema spelling of a field name, or false if the field is unknown.
+func canonicalField(fields []string, name string) (string, bool) {
+	for _, field := range fields {
+		if strings.EqualFold(field, name) {
+			return field, true
+		}
+	}
+	return "", false
+}
+
+// record is a single customer, subnet, or IP read from a file, before conversion to the domain types.
+type record struct {
+	Line     int
+	Fields   map[string]string
+	Contacts []domain.Contact
+}
+
+// records holds the records of each type read from a single file.
+type records map[string][]record
+
+// validate checks the required and integer fields of a record against the schema of its type.
+func (r record) validate(path string, recordType string) SchemaErrors {
+	var errs SchemaErrors
+	schema := schemas[recordType]
+	for _, field := range schema.Required {
+		if r.Fields[field] == "" {
+			errs = append(errs, SchemaError{Path: path, Line: r.Line, Reason: fmt.Sprintf("%s is missing required field %q", recordType, field)})
+		}
+	}
+	for _, field := range schema.Integers {
+		if value := r.Fields[field]; value != "" {
+			if _, err := strconv.ParseInt(value, 10, 8); err != nil {
+				errs = append(errs, SchemaError{Path: path, Line: r.Line, Reason: fmt.Sprintf("%s field %q must be an integer, got %q", recordType, field, value)})
+			}
+		}
+	}
+	if recordType == subnetRecords && r.Fields["network"] != "" && r.Fields["maskBits"] == "" && !strings.Contains(r.Fields["network"], "/") {
+		errs = append(errs, SchemaError{Path: path, Line: r.Line, Reason: `subnets need a "maskBits" field or a "network" in CIDR notation`})
+	}
+	return errs
+}
+
+func (r record) toCustomer() domain.Customer {
+	customer := domain.Customer{
+		ID:            r.Fields["id"],
+		ResourceOwner: r.Fields["resourceOwner"],
+		BusinessUnit:  r.Fields["businessUnit"],
+		Contacts:      r.Contacts,
+	}
+	if customer.ResourceOwner != "" {
+		customer.ResourceOwnerRule = "file"
+	}
+	return customer
+}
+
+func (r record) toSubnet() domain.Subnet {
+	subnet := domain.Subnet{
+		ID:         r.Fields["id"],
+		Network:    r.Fields["network"],
+		Location:   r.Fields["location"],
+		CustomerID: r.Fields["customerID"],
+	}
+	maskBits := r.Fields["maskBits"]
+	if slash := strings.LastIndex(subnet.Network, "/"); slash >= 0 {
+		if maskBits == "" {
+			maskBits = subnet.Network[slash+1:]
+		}
+		subnet.Network = subnet.Network[:slash]
+	}
+	// a malformed CIDR prefix length is passed along as -1 so that the sync validation quarantines it
+	subnet.MaskBits = -1
+	if bits, err := strconv.Atoi(maskBits); err == nil {
+		subnet.MaskBits = bits
+	}
+	return subnet
+}
+
+func (r record) toDevice() domain.Device {
+	return domain.Device{
+		ID:       r.Fields["id"],
+		IP:       r.Fields["ip"],
+		SubnetID: r.Fields["subnetID"],
+	}
+}
@@ -0,0 +1,104 @@
+package grpcserver
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+)
+
+// errNoClientAuthentication is returned when the listener is enabled without mutual TLS, which
+// is how the gRPC API authenticates its clients, and without opting in to serving insecurely.
+var errNoClientAuthentication = errors.New("the gRPC listener requires a TLS certificate, key, and client CA " +
+	"certificate to authenticate its clients; set IPAMFACADE_GRPC_INSECURE to serve without them")
+
+// errPartialTLS is returned when only some of the TLS files of the listener are set.
+var errPartialTLS = errors.New("the gRPC TLS certificate, key, and client CA certificate must be set together")
+
+// Config contains configuration settings for the gRPC listener
+type Config struct {
+	Address      string `description:"The address of the gRPC listener, such as :9090. The listener is disabled when empty."`
+	MaxBatchSize int    `description:"The most IP addresses a single batch lookup may request."`
+	TLSCert      string `description:"Path of the certificate file the gRPC listener serves TLS with."`
+	TLSKey       string `description:"Path of the private key file of the TLS certificate."`
+	TLSClientCA  string `description:"Path of the CA certificate file that client certificates must be signed by. Clients without one are refused."`
+	Insecure     bool   `description:"Serve the gRPC listener without TLS or client authentication, for local development only."`
+}
+
+// Name is used by the settings library to replace the default naming convention.
+func (*Config) Name() string {
+	return "GRPC"
+}
+
+// NewComponent generates a new, unititialized Component
+func NewComponent() *Component {
+	return &Component{}
+}
+
+// Component satisfies the settings library Component API,
+// and may be used by the settings.NewComponent function.
+type Component struct{}
+
+// Settings generates a config with default values applied.
+func (*Component) Settings() *Config {
+	return &Config{
+		MaxBatchSize: 1000,
+	}
+}
+
+// New constructs a Server from a config. The handlers, and the logger and stat client of the
+// runtime, are to be set by the caller. The gRPC API is not behind the gateway that
+// authenticates HTTP requests, so an enabled listener requires mutual TLS unless it is
+// explicitly configured to be insecure.
+func (*Component) New(_ context.Context, conf *Config) (*Server, error) {
+	if conf.MaxBatchSize <= 0 {
+		return nil, fmt.Errorf("the gRPC max batch size must be positive, got %d", conf.MaxBatchSize)
+	}
+	server := &Server{
+		Address:      conf.Address,
+		MaxBatchSize: conf.MaxBatchSize,
+	}
+	if conf.Address == "" {
+		return server, nil
+	}
+	if conf.TLSCert == "" && conf.TLSKey == "" && conf.TLSClientCA == "" {
+		if conf.Insecure {
+			return server, nil
+		}
+		return nil, errNoClientAuthentication
+	}
+	if conf.TLSCert == "" || conf.TLSKey == "" || conf.TLSClientCA == "" {
+		return nil, errPartialTLS
+	}
+	tlsConfig, err := newTLSConfig(conf.TLSCert, conf.TLSKey, conf.TLSClientCA)
+	if err != nil {
+		return nil, err
+	}
+	server.TLSConfig = tlsConfig
+	return server, nil
+}
+
+// newTLSConfig returns a TLS configuration that serves a certificate, and requires clients to
+// present a certificate signed by the client CA.
+func newTLSConfig(certFile string, keyFile string, clientCAFile string) (*tls.Config, error) {
+	certificate, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the gRPC TLS certificate: %v", err)
+	}
+	content, err := ioutil.ReadFile(clientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the gRPC client CA certificate: %v", err)
+	}
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(content) {
+		return nil, fmt.Errorf("no certificates found in the gRPC client CA certificate file %s", clientCAFile)
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		ClientCAs:    clientCAs,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
@@ -0,0 +1,207 @@
+package grpcserver
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	gomock "github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+	"github.com/asecurityteam/ipam-facade/pkg/grpcserver/ipamfacadepb"
+	v1 "github.com/asecurityteam/ipam-facade/pkg/handlers/v1"
+)
+
+// testPKI is a CA along with a server and a client certificate it signed, written to files.
+type testPKI struct {
+	certFile string
+	keyFile  string
+	caFile   string
+	client   tls.Certificate
+	roots    *x509.CertPool
+}
+
+// newTestPKI generates a testPKI in a temporary directory, along with a function that removes
+// it.
+func newTestPKI(t *testing.T) (testPKI, func()) {
+	dir, err := ioutil.TempDir("", "grpcserver")
+	require.NoError(t, err)
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	ca, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+
+	issue := func(serial int64, usage x509.ExtKeyUsage) ([]byte, *ecdsa.PrivateKey) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: "localhost"},
+			DNSNames:     []string{"localhost"},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+		require.NoError(t, err)
+		return der, key
+	}
+	encodeKey := func(key *ecdsa.PrivateKey) []byte {
+		der, err := x509.MarshalECPrivateKey(key)
+		require.NoError(t, err)
+		return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})
+	}
+
+	pki := testPKI{
+		certFile: filepath.Join(dir, "server.crt"),
+		keyFile:  filepath.Join(dir, "server.key"),
+		caFile:   filepath.Join(dir, "ca.crt"),
+		roots:    x509.NewCertPool(),
+	}
+	pki.roots.AddCert(ca)
+	serverDER, serverKey := issue(2, x509.ExtKeyUsageServerAuth)
+	require.NoError(t, ioutil.WriteFile(pki.certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: serverDER}), 0600))
+	require.NoError(t, ioutil.WriteFile(pki.keyFile, encodeKey(serverKey), 0600))
+	require.NoError(t, ioutil.WriteFile(pki.caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER}), 0600))
+	clientDER, clientKey := issue(3, x509.ExtKeyUsageClientAuth)
+	pki.client, err = tls.X509KeyPair(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: clientDER}), encodeKey(clientKey))
+	require.NoError(t, err)
+	return pki, func() { _ = os.RemoveAll(dir) }
+}
+
+func TestComponent(t *testing.T) {
+	pki, removePKI := newTestPKI(t)
+	defer removePKI()
+	tc := []struct {
+		name        string
+		conf        Config
+		expectedErr bool
+		expectedTLS bool
+	}{
+		{
+			name: "disabled",
+			conf: Config{MaxBatchSize: 1000},
+		},
+		{
+			name:        "no client authentication",
+			conf:        Config{Address: ":9090", MaxBatchSize: 1000},
+			expectedErr: true,
+		},
+		{
+			name: "insecure",
+			conf: Config{Address: ":9090", MaxBatchSize: 1000, Insecure: true},
+		},
+		{
+			name:        "mutual TLS",
+			conf:        Config{Address: ":9090", MaxBatchSize: 1000, TLSCert: pki.certFile, TLSKey: pki.keyFile, TLSClientCA: pki.caFile},
+			expectedTLS: true,
+		},
+		{
+			name:        "no client CA",
+			conf:        Config{Address: ":9090", MaxBatchSize: 1000, TLSCert: pki.certFile, TLSKey: pki.keyFile, Insecure: true},
+			expectedErr: true,
+		},
+		{
+			name:        "missing certificate file",
+			conf:        Config{Address: ":9090", MaxBatchSize: 1000, TLSCert: pki.certFile + ".missing", TLSKey: pki.keyFile, TLSClientCA: pki.caFile},
+			expectedErr: true,
+		},
+		{
+			name:        "client CA without certificates",
+			conf:        Config{Address: ":9090", MaxBatchSize: 1000, TLSCert: pki.certFile, TLSKey: pki.keyFile, TLSClientCA: pki.keyFile},
+			expectedErr: true,
+		},
+		{
+			name:        "invalid max batch size",
+			conf:        Config{Address: ":9090", Insecure: true},
+			expectedErr: true,
+		},
+	}
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := tt.conf
+			server, err := NewComponent().New(context.Background(), &conf)
+			if tt.expectedErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.conf.Address, server.Address)
+			require.Equal(t, tt.expectedTLS, server.TLSConfig != nil)
+		})
+	}
+}
+
+func TestMutualTLS(t *testing.T) {
+	pki, removePKI := newTestPKI(t)
+	defer removePKI()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUUIDGenerator := NewMockUUIDGenerator(ctrl)
+	mockProducer := NewMockProducer(ctrl)
+	conf := &Config{Address: ":9090", MaxBatchSize: 1000, TLSCert: pki.certFile, TLSKey: pki.keyFile, TLSClientCA: pki.caFile}
+	server, err := NewComponent().New(context.Background(), conf)
+	require.NoError(t, err)
+	server.EnqueueHandler = &v1.EnqueueHandler{Producer: mockProducer, UUIDGenerator: mockUUIDGenerator, LogFn: domain.LoggerFromContext}
+
+	listener := bufconn.Listen(1024 * 1024)
+	grpcServer := server.newGRPCServer()
+	go func() {
+		_ = grpcServer.Serve(listener)
+	}()
+	defer grpcServer.Stop()
+	dial := func(certificates []tls.Certificate) *grpc.ClientConn {
+		creds := credentials.NewTLS(&tls.Config{ServerName: "localhost", RootCAs: pki.roots, Certificates: certificates})
+		conn, err := grpc.DialContext(context.Background(), "bufconn",
+			grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+			grpc.WithTransportCredentials(creds))
+		require.NoError(t, err)
+		return conn
+	}
+
+	// a client without a certificate is refused before its call is handled
+	anonymous := dial(nil)
+	defer anonymous.Close()
+	_, err = ipamfacadepb.NewIPAMFacadeClient(anonymous).EnqueueSync(context.Background(), &ipamfacadepb.EnqueueSyncRequest{})
+	require.Equal(t, codes.Unavailable, status.Code(err))
+
+	authenticated := dial([]tls.Certificate{pki.client})
+	defer authenticated.Close()
+	mockUUIDGenerator.EXPECT().NewUUIDString().Return("foo-bar-baz-quux", nil)
+	mockProducer.EXPECT().Produce(gomock.Any(), v1.JobMetadata{JobID: "foo-bar-baz-quux"}).Return(nil, nil)
+	job, err := ipamfacadepb.NewIPAMFacadeClient(authenticated).EnqueueSync(context.Background(), &ipamfacadepb.EnqueueSyncRequest{})
+	require.NoError(t, err)
+	require.Equal(t, "foo-bar-baz-quux", job.JobId)
+}
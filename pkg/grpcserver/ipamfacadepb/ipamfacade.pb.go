@@ -0,0 +1,1051 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.27.1
+// 	protoc        (unknown)
+// source: ipamfacade/v1/ipamfacade.proto
+
+package ipamfacadepb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type FetchByIPRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	IpAddress string `protobuf:"bytes,1,opt,name=ip_address,json=ipAddress,proto3" json:"ip_address,omitempty"`
+}
+
+func (x *FetchByIPRequest) Reset() {
+	*x = FetchByIPRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ipamfacade_v1_ipamfacade_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FetchByIPRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FetchByIPRequest) ProtoMessage() {}
+
+func (x *FetchByIPRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ipamfacade_v1_ipamfacade_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FetchByIPRequest.ProtoReflect.Descriptor instead.
+func (*FetchByIPRequest) Descriptor() ([]byte, []int) {
+	return file_ipamfacade_v1_ipamfacade_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *FetchByIPRequest) GetIpAddress() string {
+	if x != nil {
+		return x.IpAddress
+	}
+	return ""
+}
+
+// PhysicalAsset has the fields of the PhysicalAsset returned by the HTTP API.
+type PhysicalAsset struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Ip            string     `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`
+	ResourceOwner string     `protobuf:"bytes,2,opt,name=resource_owner,json=resourceOwner,proto3" json:"resource_owner,omitempty"`
+	BusinessUnit  string     `protobuf:"bytes,3,opt,name=business_unit,json=businessUnit,proto3" json:"business_unit,omitempty"`
+	Contacts      []*Contact `protobuf:"bytes,4,rep,name=contacts,proto3" json:"contacts,omitempty"`
+	// The fields inherited from the ancestor subnet identified by the
+	// inherited_from_subnet_id tag.
+	InheritedFields []string `protobuf:"bytes,5,rep,name=inherited_fields,json=inheritedFields,proto3" json:"inherited_fields,omitempty"`
+	Tags            *Tags    `protobuf:"bytes,6,opt,name=tags,proto3" json:"tags,omitempty"`
+}
+
+func (x *PhysicalAsset) Reset() {
+	*x = PhysicalAsset{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ipamfacade_v1_ipamfacade_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PhysicalAsset) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PhysicalAsset) ProtoMessage() {}
+
+func (x *PhysicalAsset) ProtoReflect() protoreflect.Message {
+	mi := &file_ipamfacade_v1_ipamfacade_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PhysicalAsset.ProtoReflect.Descriptor instead.
+func (*PhysicalAsset) Descriptor() ([]byte, []int) {
+	return file_ipamfacade_v1_ipamfacade_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PhysicalAsset) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *PhysicalAsset) GetResourceOwner() string {
+	if x != nil {
+		return x.ResourceOwner
+	}
+	return ""
+}
+
+func (x *PhysicalAsset) GetBusinessUnit() string {
+	if x != nil {
+		return x.BusinessUnit
+	}
+	return ""
+}
+
+func (x *PhysicalAsset) GetContacts() []*Contact {
+	if x != nil {
+		return x.Contacts
+	}
+	return nil
+}
+
+func (x *PhysicalAsset) GetInheritedFields() []string {
+	if x != nil {
+		return x.InheritedFields
+	}
+	return nil
+}
+
+func (x *PhysicalAsset) GetTags() *Tags {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+type Contact struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type  string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Name  string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Email string `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
+	Phone string `protobuf:"bytes,4,opt,name=phone,proto3" json:"phone,omitempty"`
+}
+
+func (x *Contact) Reset() {
+	*x = Contact{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ipamfacade_v1_ipamfacade_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Contact) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Contact) ProtoMessage() {}
+
+func (x *Contact) ProtoReflect() protoreflect.Message {
+	mi := &file_ipamfacade_v1_ipamfacade_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Contact.ProtoReflect.Descriptor instead.
+func (*Contact) Descriptor() ([]byte, []int) {
+	return file_ipamfacade_v1_ipamfacade_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Contact) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Contact) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Contact) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *Contact) GetPhone() string {
+	if x != nil {
+		return x.Phone
+	}
+	return ""
+}
+
+type Tags struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Network               string `protobuf:"bytes,1,opt,name=network,proto3" json:"network,omitempty"`
+	Location              string `protobuf:"bytes,2,opt,name=location,proto3" json:"location,omitempty"`
+	DeviceId              string `protobuf:"bytes,3,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	SubnetId              string `protobuf:"bytes,4,opt,name=subnet_id,json=subnetId,proto3" json:"subnet_id,omitempty"`
+	CustomerId            string `protobuf:"bytes,5,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`
+	ResourceOwnerRule     string `protobuf:"bytes,6,opt,name=resource_owner_rule,json=resourceOwnerRule,proto3" json:"resource_owner_rule,omitempty"`
+	InheritedFromSubnetId string `protobuf:"bytes,7,opt,name=inherited_from_subnet_id,json=inheritedFromSubnetId,proto3" json:"inherited_from_subnet_id,omitempty"`
+}
+
+func (x *Tags) Reset() {
+	*x = Tags{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ipamfacade_v1_ipamfacade_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Tags) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Tags) ProtoMessage() {}
+
+func (x *Tags) ProtoReflect() protoreflect.Message {
+	mi := &file_ipamfacade_v1_ipamfacade_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Tags.ProtoReflect.Descriptor instead.
+func (*Tags) Descriptor() ([]byte, []int) {
+	return file_ipamfacade_v1_ipamfacade_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Tags) GetNetwork() string {
+	if x != nil {
+		return x.Network
+	}
+	return ""
+}
+
+func (x *Tags) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *Tags) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *Tags) GetSubnetId() string {
+	if x != nil {
+		return x.SubnetId
+	}
+	return ""
+}
+
+func (x *Tags) GetCustomerId() string {
+	if x != nil {
+		return x.CustomerId
+	}
+	return ""
+}
+
+func (x *Tags) GetResourceOwnerRule() string {
+	if x != nil {
+		return x.ResourceOwnerRule
+	}
+	return ""
+}
+
+func (x *Tags) GetInheritedFromSubnetId() string {
+	if x != nil {
+		return x.InheritedFromSubnetId
+	}
+	return ""
+}
+
+type BatchFetchByIPRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	IpAddresses []string `protobuf:"bytes,1,rep,name=ip_addresses,json=ipAddresses,proto3" json:"ip_addresses,omitempty"`
+}
+
+func (x *BatchFetchByIPRequest) Reset() {
+	*x = BatchFetchByIPRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ipamfacade_v1_ipamfacade_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchFetchByIPRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchFetchByIPRequest) ProtoMessage() {}
+
+func (x *BatchFetchByIPRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ipamfacade_v1_ipamfacade_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchFetchByIPRequest.ProtoReflect.Descriptor instead.
+func (*BatchFetchByIPRequest) Descriptor() ([]byte, []int) {
+	return file_ipamfacade_v1_ipamfacade_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *BatchFetchByIPRequest) GetIpAddresses() []string {
+	if x != nil {
+		return x.IpAddresses
+	}
+	return nil
+}
+
+// BatchFetchByIPResult is the lookup of one IP address in a batch. The asset is set when
+// the lookup succeeds; otherwise code is the gRPC status code that FetchByIP would have
+// failed with, and message describes the failure.
+type BatchFetchByIPResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	IpAddress string         `protobuf:"bytes,1,opt,name=ip_address,json=ipAddress,proto3" json:"ip_address,omitempty"`
+	Asset     *PhysicalAsset `protobuf:"bytes,2,opt,name=asset,proto3" json:"asset,omitempty"`
+	Code      int32          `protobuf:"varint,3,opt,name=code,proto3" json:"code,omitempty"`
+	Message   string         `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (x *BatchFetchByIPResult) Reset() {
+	*x = BatchFetchByIPResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ipamfacade_v1_ipamfacade_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchFetchByIPResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchFetchByIPResult) ProtoMessage() {}
+
+func (x *BatchFetchByIPResult) ProtoReflect() protoreflect.Message {
+	mi := &file_ipamfacade_v1_ipamfacade_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchFetchByIPResult.ProtoReflect.Descriptor instead.
+func (*BatchFetchByIPResult) Descriptor() ([]byte, []int) {
+	return file_ipamfacade_v1_ipamfacade_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *BatchFetchByIPResult) GetIpAddress() string {
+	if x != nil {
+		return x.IpAddress
+	}
+	return ""
+}
+
+func (x *BatchFetchByIPResult) GetAsset() *PhysicalAsset {
+	if x != nil {
+		return x.Asset
+	}
+	return nil
+}
+
+func (x *BatchFetchByIPResult) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *BatchFetchByIPResult) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// ListRequest sets the number of records fetched from storage at a time, or uses the
+// default page size when 0.
+type ListRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PageSize int32 `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+}
+
+func (x *ListRequest) Reset() {
+	*x = ListRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ipamfacade_v1_ipamfacade_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRequest) ProtoMessage() {}
+
+func (x *ListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ipamfacade_v1_ipamfacade_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRequest.ProtoReflect.Descriptor instead.
+func (*ListRequest) Descriptor() ([]byte, []int) {
+	return file_ipamfacade_v1_ipamfacade_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+type Subnet struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Network       string `protobuf:"bytes,1,opt,name=network,proto3" json:"network,omitempty"`
+	ResourceOwner string `protobuf:"bytes,2,opt,name=resource_owner,json=resourceOwner,proto3" json:"resource_owner,omitempty"`
+	BusinessUnit  string `protobuf:"bytes,3,opt,name=business_unit,json=businessUnit,proto3" json:"business_unit,omitempty"`
+	Location      string `protobuf:"bytes,4,opt,name=location,proto3" json:"location,omitempty"`
+}
+
+func (x *Subnet) Reset() {
+	*x = Subnet{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ipamfacade_v1_ipamfacade_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Subnet) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Subnet) ProtoMessage() {}
+
+func (x *Subnet) ProtoReflect() protoreflect.Message {
+	mi := &file_ipamfacade_v1_ipamfacade_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Subnet.ProtoReflect.Descriptor instead.
+func (*Subnet) Descriptor() ([]byte, []int) {
+	return file_ipamfacade_v1_ipamfacade_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Subnet) GetNetwork() string {
+	if x != nil {
+		return x.Network
+	}
+	return ""
+}
+
+func (x *Subnet) GetResourceOwner() string {
+	if x != nil {
+		return x.ResourceOwner
+	}
+	return ""
+}
+
+func (x *Subnet) GetBusinessUnit() string {
+	if x != nil {
+		return x.BusinessUnit
+	}
+	return ""
+}
+
+func (x *Subnet) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+type IP struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Ip            string `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`
+	Network       string `protobuf:"bytes,2,opt,name=network,proto3" json:"network,omitempty"`
+	ResourceOwner string `protobuf:"bytes,3,opt,name=resource_owner,json=resourceOwner,proto3" json:"resource_owner,omitempty"`
+	BusinessUnit  string `protobuf:"bytes,4,opt,name=business_unit,json=businessUnit,proto3" json:"business_unit,omitempty"`
+	Location      string `protobuf:"bytes,5,opt,name=location,proto3" json:"location,omitempty"`
+}
+
+func (x *IP) Reset() {
+	*x = IP{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ipamfacade_v1_ipamfacade_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IP) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IP) ProtoMessage() {}
+
+func (x *IP) ProtoReflect() protoreflect.Message {
+	mi := &file_ipamfacade_v1_ipamfacade_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IP.ProtoReflect.Descriptor instead.
+func (*IP) Descriptor() ([]byte, []int) {
+	return file_ipamfacade_v1_ipamfacade_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *IP) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *IP) GetNetwork() string {
+	if x != nil {
+		return x.Network
+	}
+	return ""
+}
+
+func (x *IP) GetResourceOwner() string {
+	if x != nil {
+		return x.ResourceOwner
+	}
+	return ""
+}
+
+func (x *IP) GetBusinessUnit() string {
+	if x != nil {
+		return x.BusinessUnit
+	}
+	return ""
+}
+
+func (x *IP) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+type EnqueueSyncRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *EnqueueSyncRequest) Reset() {
+	*x = EnqueueSyncRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ipamfacade_v1_ipamfacade_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EnqueueSyncRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnqueueSyncRequest) ProtoMessage() {}
+
+func (x *EnqueueSyncRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ipamfacade_v1_ipamfacade_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnqueueSyncRequest.ProtoReflect.Descriptor instead.
+func (*EnqueueSyncRequest) Descriptor() ([]byte, []int) {
+	return file_ipamfacade_v1_ipamfacade_proto_rawDescGZIP(), []int{9}
+}
+
+type SyncJob struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	JobId string `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+}
+
+func (x *SyncJob) Reset() {
+	*x = SyncJob{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ipamfacade_v1_ipamfacade_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SyncJob) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncJob) ProtoMessage() {}
+
+func (x *SyncJob) ProtoReflect() protoreflect.Message {
+	mi := &file_ipamfacade_v1_ipamfacade_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncJob.ProtoReflect.Descriptor instead.
+func (*SyncJob) Descriptor() ([]byte, []int) {
+	return file_ipamfacade_v1_ipamfacade_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *SyncJob) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+var File_ipamfacade_v1_ipamfacade_proto protoreflect.FileDescriptor
+
+var file_ipamfacade_v1_ipamfacade_proto_rawDesc = []byte{
+	0x0a, 0x1e, 0x69, 0x70, 0x61, 0x6d, 0x66, 0x61, 0x63, 0x61, 0x64, 0x65, 0x2f, 0x76, 0x31, 0x2f,
+	0x69, 0x70, 0x61, 0x6d, 0x66, 0x61, 0x63, 0x61, 0x64, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x12, 0x0d, 0x69, 0x70, 0x61, 0x6d, 0x66, 0x61, 0x63, 0x61, 0x64, 0x65, 0x2e, 0x76, 0x31, 0x22,
+	0x31, 0x0a, 0x10, 0x46, 0x65, 0x74, 0x63, 0x68, 0x42, 0x79, 0x49, 0x50, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x69, 0x70, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73,
+	0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x69, 0x70, 0x41, 0x64, 0x64, 0x72, 0x65,
+	0x73, 0x73, 0x22, 0xf3, 0x01, 0x0a, 0x0d, 0x50, 0x68, 0x79, 0x73, 0x69, 0x63, 0x61, 0x6c, 0x41,
+	0x73, 0x73, 0x65, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x02, 0x69, 0x70, 0x12, 0x25, 0x0a, 0x0e, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65,
+	0x5f, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x72, 0x65,
+	0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x12, 0x23, 0x0a, 0x0d, 0x62,
+	0x75, 0x73, 0x69, 0x6e, 0x65, 0x73, 0x73, 0x5f, 0x75, 0x6e, 0x69, 0x74, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0c, 0x62, 0x75, 0x73, 0x69, 0x6e, 0x65, 0x73, 0x73, 0x55, 0x6e, 0x69, 0x74,
+	0x12, 0x32, 0x0a, 0x08, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x73, 0x18, 0x04, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x16, 0x2e, 0x69, 0x70, 0x61, 0x6d, 0x66, 0x61, 0x63, 0x61, 0x64, 0x65, 0x2e,
+	0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x52, 0x08, 0x63, 0x6f, 0x6e, 0x74,
+	0x61, 0x63, 0x74, 0x73, 0x12, 0x29, 0x0a, 0x10, 0x69, 0x6e, 0x68, 0x65, 0x72, 0x69, 0x74, 0x65,
+	0x64, 0x5f, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0f,
+	0x69, 0x6e, 0x68, 0x65, 0x72, 0x69, 0x74, 0x65, 0x64, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x12,
+	0x27, 0x0a, 0x04, 0x74, 0x61, 0x67, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e,
+	0x69, 0x70, 0x61, 0x6d, 0x66, 0x61, 0x63, 0x61, 0x64, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x61,
+	0x67, 0x73, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x22, 0x5d, 0x0a, 0x07, 0x43, 0x6f, 0x6e, 0x74,
+	0x61, 0x63, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65,
+	0x6d, 0x61, 0x69, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x6d, 0x61, 0x69,
+	0x6c, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x68, 0x6f, 0x6e, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x70, 0x68, 0x6f, 0x6e, 0x65, 0x22, 0x80, 0x02, 0x0a, 0x04, 0x54, 0x61, 0x67, 0x73,
+	0x12, 0x18, 0x0a, 0x07, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x6f,
+	0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6c, 0x6f,
+	0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1b, 0x0a, 0x09, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65,
+	0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x64, 0x65, 0x76, 0x69, 0x63,
+	0x65, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x5f, 0x69, 0x64,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x49, 0x64,
+	0x12, 0x1f, 0x0a, 0x0b, 0x63, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x65, 0x72, 0x49,
+	0x64, 0x12, 0x2e, 0x0a, 0x13, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x6f, 0x77,
+	0x6e, 0x65, 0x72, 0x5f, 0x72, 0x75, 0x6c, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x11,
+	0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x52, 0x75, 0x6c,
+	0x65, 0x12, 0x37, 0x0a, 0x18, 0x69, 0x6e, 0x68, 0x65, 0x72, 0x69, 0x74, 0x65, 0x64, 0x5f, 0x66,
+	0x72, 0x6f, 0x6d, 0x5f, 0x73, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x15, 0x69, 0x6e, 0x68, 0x65, 0x72, 0x69, 0x74, 0x65, 0x64, 0x46, 0x72,
+	0x6f, 0x6d, 0x53, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x49, 0x64, 0x22, 0x3a, 0x0a, 0x15, 0x42, 0x61,
+	0x74, 0x63, 0x68, 0x46, 0x65, 0x74, 0x63, 0x68, 0x42, 0x79, 0x49, 0x50, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x69, 0x70, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73,
+	0x73, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x69, 0x70, 0x41, 0x64, 0x64,
+	0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x22, 0x97, 0x01, 0x0a, 0x14, 0x42, 0x61, 0x74, 0x63, 0x68,
+	0x46, 0x65, 0x74, 0x63, 0x68, 0x42, 0x79, 0x49, 0x50, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12,
+	0x1d, 0x0a, 0x0a, 0x69, 0x70, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x09, 0x69, 0x70, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x32,
+	0x0a, 0x05, 0x61, 0x73, 0x73, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e,
+	0x69, 0x70, 0x61, 0x6d, 0x66, 0x61, 0x63, 0x61, 0x64, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x68,
+	0x79, 0x73, 0x69, 0x63, 0x61, 0x6c, 0x41, 0x73, 0x73, 0x65, 0x74, 0x52, 0x05, 0x61, 0x73, 0x73,
+	0x65, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x22, 0x2a, 0x0a, 0x0b, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x1b, 0x0a, 0x09, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x08, 0x70, 0x61, 0x67, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x22, 0x8a, 0x01, 0x0a,
+	0x06, 0x53, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x6e, 0x65, 0x74, 0x77, 0x6f,
+	0x72, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72,
+	0x6b, 0x12, 0x25, 0x0a, 0x0e, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x6f, 0x77,
+	0x6e, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x72, 0x65, 0x73, 0x6f, 0x75,
+	0x72, 0x63, 0x65, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x12, 0x23, 0x0a, 0x0d, 0x62, 0x75, 0x73, 0x69,
+	0x6e, 0x65, 0x73, 0x73, 0x5f, 0x75, 0x6e, 0x69, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0c, 0x62, 0x75, 0x73, 0x69, 0x6e, 0x65, 0x73, 0x73, 0x55, 0x6e, 0x69, 0x74, 0x12, 0x1a, 0x0a,
+	0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x96, 0x01, 0x0a, 0x02, 0x49, 0x50,
+	0x12, 0x0e, 0x0a, 0x02, 0x69, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x70,
+	0x12, 0x18, 0x0a, 0x07, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x12, 0x25, 0x0a, 0x0e, 0x72, 0x65,
+	0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0d, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x4f, 0x77, 0x6e, 0x65,
+	0x72, 0x12, 0x23, 0x0a, 0x0d, 0x62, 0x75, 0x73, 0x69, 0x6e, 0x65, 0x73, 0x73, 0x5f, 0x75, 0x6e,
+	0x69, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x62, 0x75, 0x73, 0x69, 0x6e, 0x65,
+	0x73, 0x73, 0x55, 0x6e, 0x69, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x22, 0x14, 0x0a, 0x12, 0x45, 0x6e, 0x71, 0x75, 0x65, 0x75, 0x65, 0x53, 0x79, 0x6e,
+	0x63, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x20, 0x0a, 0x07, 0x53, 0x79, 0x6e, 0x63,
+	0x4a, 0x6f, 0x62, 0x12, 0x15, 0x0a, 0x06, 0x6a, 0x6f, 0x62, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x6a, 0x6f, 0x62, 0x49, 0x64, 0x32, 0x81, 0x03, 0x0a, 0x0a, 0x49,
+	0x50, 0x41, 0x4d, 0x46, 0x61, 0x63, 0x61, 0x64, 0x65, 0x12, 0x4a, 0x0a, 0x09, 0x46, 0x65, 0x74,
+	0x63, 0x68, 0x42, 0x79, 0x49, 0x50, 0x12, 0x1f, 0x2e, 0x69, 0x70, 0x61, 0x6d, 0x66, 0x61, 0x63,
+	0x61, 0x64, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x65, 0x74, 0x63, 0x68, 0x42, 0x79, 0x49, 0x50,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x69, 0x70, 0x61, 0x6d, 0x66, 0x61,
+	0x63, 0x61, 0x64, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x68, 0x79, 0x73, 0x69, 0x63, 0x61, 0x6c,
+	0x41, 0x73, 0x73, 0x65, 0x74, 0x12, 0x5d, 0x0a, 0x0e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x46, 0x65,
+	0x74, 0x63, 0x68, 0x42, 0x79, 0x49, 0x50, 0x12, 0x24, 0x2e, 0x69, 0x70, 0x61, 0x6d, 0x66, 0x61,
+	0x63, 0x61, 0x64, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x46, 0x65, 0x74,
+	0x63, 0x68, 0x42, 0x79, 0x49, 0x50, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x23, 0x2e,
+	0x69, 0x70, 0x61, 0x6d, 0x66, 0x61, 0x63, 0x61, 0x64, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x61,
+	0x74, 0x63, 0x68, 0x46, 0x65, 0x74, 0x63, 0x68, 0x42, 0x79, 0x49, 0x50, 0x52, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x30, 0x01, 0x12, 0x42, 0x0a, 0x0b, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x75, 0x62, 0x6e,
+	0x65, 0x74, 0x73, 0x12, 0x1a, 0x2e, 0x69, 0x70, 0x61, 0x6d, 0x66, 0x61, 0x63, 0x61, 0x64, 0x65,
+	0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x15, 0x2e, 0x69, 0x70, 0x61, 0x6d, 0x66, 0x61, 0x63, 0x61, 0x64, 0x65, 0x2e, 0x76, 0x31, 0x2e,
+	0x53, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x30, 0x01, 0x12, 0x3a, 0x0a, 0x07, 0x4c, 0x69, 0x73, 0x74,
+	0x49, 0x50, 0x73, 0x12, 0x1a, 0x2e, 0x69, 0x70, 0x61, 0x6d, 0x66, 0x61, 0x63, 0x61, 0x64, 0x65,
+	0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x11, 0x2e, 0x69, 0x70, 0x61, 0x6d, 0x66, 0x61, 0x63, 0x61, 0x64, 0x65, 0x2e, 0x76, 0x31, 0x2e,
+	0x49, 0x50, 0x30, 0x01, 0x12, 0x48, 0x0a, 0x0b, 0x45, 0x6e, 0x71, 0x75, 0x65, 0x75, 0x65, 0x53,
+	0x79, 0x6e, 0x63, 0x12, 0x21, 0x2e, 0x69, 0x70, 0x61, 0x6d, 0x66, 0x61, 0x63, 0x61, 0x64, 0x65,
+	0x2e, 0x76, 0x31, 0x2e, 0x45, 0x6e, 0x71, 0x75, 0x65, 0x75, 0x65, 0x53, 0x79, 0x6e, 0x63, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x69, 0x70, 0x61, 0x6d, 0x66, 0x61, 0x63,
+	0x61, 0x64, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x79, 0x6e, 0x63, 0x4a, 0x6f, 0x62, 0x42, 0x42,
+	0x5a, 0x40, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x73, 0x65,
+	0x63, 0x75, 0x72, 0x69, 0x74, 0x79, 0x74, 0x65, 0x61, 0x6d, 0x2f, 0x69, 0x70, 0x61, 0x6d, 0x2d,
+	0x66, 0x61, 0x63, 0x61, 0x64, 0x65, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x67, 0x72, 0x70, 0x63, 0x73,
+	0x65, 0x72, 0x76, 0x65, 0x72, 0x2f, 0x69, 0x70, 0x61, 0x6d, 0x66, 0x61, 0x63, 0x61, 0x64, 0x65,
+	0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_ipamfacade_v1_ipamfacade_proto_rawDescOnce sync.Once
+	file_ipamfacade_v1_ipamfacade_proto_rawDescData = file_ipamfacade_v1_ipamfacade_proto_rawDesc
+)
+
+func file_ipamfacade_v1_ipamfacade_proto_rawDescGZIP() []byte {
+	file_ipamfacade_v1_ipamfacade_proto_rawDescOnce.Do(func() {
+		file_ipamfacade_v1_ipamfacade_proto_rawDescData = protoimpl.X.CompressGZIP(file_ipamfacade_v1_ipamfacade_proto_rawDescData)
+	})
+	return file_ipamfacade_v1_ipamfacade_proto_rawDescData
+}
+
+var file_ipamfacade_v1_ipamfacade_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_ipamfacade_v1_ipamfacade_proto_goTypes = []interface{}{
+	(*FetchByIPRequest)(nil),      // 0: ipamfacade.v1.FetchByIPRequest
+	(*PhysicalAsset)(nil),         // 1: ipamfacade.v1.PhysicalAsset
+	(*Contact)(nil),               // 2: ipamfacade.v1.Contact
+	(*Tags)(nil),                  // 3: ipamfacade.v1.Tags
+	(*BatchFetchByIPRequest)(nil), // 4: ipamfacade.v1.BatchFetchByIPRequest
+	(*BatchFetchByIPResult)(nil),  // 5: ipamfacade.v1.BatchFetchByIPResult
+	(*ListRequest)(nil),           // 6: ipamfacade.v1.ListRequest
+	(*Subnet)(nil),                // 7: ipamfacade.v1.Subnet
+	(*IP)(nil),                    // 8: ipamfacade.v1.IP
+	(*EnqueueSyncRequest)(nil),    // 9: ipamfacade.v1.EnqueueSyncRequest
+	(*SyncJob)(nil),               // 10: ipamfacade.v1.SyncJob
+}
+var file_ipamfacade_v1_ipamfacade_proto_depIdxs = []int32{
+	2,  // 0: ipamfacade.v1.PhysicalAsset.contacts:type_name -> ipamfacade.v1.Contact
+	3,  // 1: ipamfacade.v1.PhysicalAsset.tags:type_name -> ipamfacade.v1.Tags
+	1,  // 2: ipamfacade.v1.BatchFetchByIPResult.asset:type_name -> ipamfacade.v1.PhysicalAsset
+	0,  // 3: ipamfacade.v1.IPAMFacade.FetchByIP:input_type -> ipamfacade.v1.FetchByIPRequest
+	4,  // 4: ipamfacade.v1.IPAMFacade.BatchFetchByIP:input_type -> ipamfacade.v1.BatchFetchByIPRequest
+	6,  // 5: ipamfacade.v1.IPAMFacade.ListSubnets:input_type -> ipamfacade.v1.ListRequest
+	6,  // 6: ipamfacade.v1.IPAMFacade.ListIPs:input_type -> ipamfacade.v1.ListRequest
+	9,  // 7: ipamfacade.v1.IPAMFacade.EnqueueSync:input_type -> ipamfacade.v1.EnqueueSyncRequest
+	1,  // 8: ipamfacade.v1.IPAMFacade.FetchByIP:output_type -> ipamfacade.v1.PhysicalAsset
+	5,  // 9: ipamfacade.v1.IPAMFacade.BatchFetchByIP:output_type -> ipamfacade.v1.BatchFetchByIPResult
+	7,  // 10: ipamfacade.v1.IPAMFacade.ListSubnets:output_type -> ipamfacade.v1.Subnet
+	8,  // 11: ipamfacade.v1.IPAMFacade.ListIPs:output_type -> ipamfacade.v1.IP
+	10, // 12: ipamfacade.v1.IPAMFacade.EnqueueSync:output_type -> ipamfacade.v1.SyncJob
+	8,  // [8:13] is the sub-list for method output_type
+	3,  // [3:8] is the sub-list for method input_type
+	3,  // [3:3] is the sub-list for extension type_name
+	3,  // [3:3] is the sub-list for extension extendee
+	0,  // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_ipamfacade_v1_ipamfacade_proto_init() }
+func file_ipamfacade_v1_ipamfacade_proto_init() {
+	if File_ipamfacade_v1_ipamfacade_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_ipamfacade_v1_ipamfacade_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FetchByIPRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ipamfacade_v1_ipamfacade_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PhysicalAsset); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ipamfacade_v1_ipamfacade_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Contact); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ipamfacade_v1_ipamfacade_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Tags); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ipamfacade_v1_ipamfacade_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchFetchByIPRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ipamfacade_v1_ipamfacade_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchFetchByIPResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ipamfacade_v1_ipamfacade_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ipamfacade_v1_ipamfacade_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Subnet); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ipamfacade_v1_ipamfacade_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*IP); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ipamfacade_v1_ipamfacade_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EnqueueSyncRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ipamfacade_v1_ipamfacade_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SyncJob); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_ipamfacade_v1_ipamfacade_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   11,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_ipamfacade_v1_ipamfacade_proto_goTypes,
+		DependencyIndexes: file_ipamfacade_v1_ipamfacade_proto_depIdxs,
+		MessageInfos:      file_ipamfacade_v1_ipamfacade_proto_msgTypes,
+	}.Build()
+	File_ipamfacade_v1_ipamfacade_proto = out.File
+	file_ipamfacade_v1_ipamfacade_proto_rawDesc = nil
+	file_ipamfacade_v1_ipamfacade_proto_goTypes = nil
+	file_ipamfacade_v1_ipamfacade_proto_depIdxs = nil
+}
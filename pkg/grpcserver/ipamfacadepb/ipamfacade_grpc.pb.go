@@ -0,0 +1,347 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             (unknown)
+// source: ipamfacade/v1/ipamfacade.proto
+
+package ipamfacadepb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// IPAMFacadeClient is the client API for IPAMFacade service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type IPAMFacadeClient interface {
+	// FetchByIP returns the physical asset with an IP address. It fails with INVALID_ARGUMENT
+	// when the IP address is not valid, and with NOT_FOUND when no subnet contains it.
+	FetchByIP(ctx context.Context, in *FetchByIPRequest, opts ...grpc.CallOption) (*PhysicalAsset, error)
+	// BatchFetchByIP streams the lookups of several IP addresses, one result per address in
+	// the order requested. A failed lookup is reported in its result rather than failing the
+	// stream.
+	BatchFetchByIP(ctx context.Context, in *BatchFetchByIPRequest, opts ...grpc.CallOption) (IPAMFacade_BatchFetchByIPClient, error)
+	// ListSubnets streams every stored subnet, fetched from storage a page at a time.
+	ListSubnets(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (IPAMFacade_ListSubnetsClient, error)
+	// ListIPs streams every stored IP address, fetched from storage a page at a time.
+	ListIPs(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (IPAMFacade_ListIPsClient, error)
+	// EnqueueSync enqueues a job to sync the IPAM data, and returns its ID.
+	EnqueueSync(ctx context.Context, in *EnqueueSyncRequest, opts ...grpc.CallOption) (*SyncJob, error)
+}
+
+type iPAMFacadeClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewIPAMFacadeClient(cc grpc.ClientConnInterface) IPAMFacadeClient {
+	return &iPAMFacadeClient{cc}
+}
+
+func (c *iPAMFacadeClient) FetchByIP(ctx context.Context, in *FetchByIPRequest, opts ...grpc.CallOption) (*PhysicalAsset, error) {
+	out := new(PhysicalAsset)
+	err := c.cc.Invoke(ctx, "/ipamfacade.v1.IPAMFacade/FetchByIP", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *iPAMFacadeClient) BatchFetchByIP(ctx context.Context, in *BatchFetchByIPRequest, opts ...grpc.CallOption) (IPAMFacade_BatchFetchByIPClient, error) {
+	stream, err := c.cc.NewStream(ctx, &IPAMFacade_ServiceDesc.Streams[0], "/ipamfacade.v1.IPAMFacade/BatchFetchByIP", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &iPAMFacadeBatchFetchByIPClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type IPAMFacade_BatchFetchByIPClient interface {
+	Recv() (*BatchFetchByIPResult, error)
+	grpc.ClientStream
+}
+
+type iPAMFacadeBatchFetchByIPClient struct {
+	grpc.ClientStream
+}
+
+func (x *iPAMFacadeBatchFetchByIPClient) Recv() (*BatchFetchByIPResult, error) {
+	m := new(BatchFetchByIPResult)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *iPAMFacadeClient) ListSubnets(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (IPAMFacade_ListSubnetsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &IPAMFacade_ServiceDesc.Streams[1], "/ipamfacade.v1.IPAMFacade/ListSubnets", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &iPAMFacadeListSubnetsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type IPAMFacade_ListSubnetsClient interface {
+	Recv() (*Subnet, error)
+	grpc.ClientStream
+}
+
+type iPAMFacadeListSubnetsClient struct {
+	grpc.ClientStream
+}
+
+func (x *iPAMFacadeListSubnetsClient) Recv() (*Subnet, error) {
+	m := new(Subnet)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *iPAMFacadeClient) ListIPs(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (IPAMFacade_ListIPsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &IPAMFacade_ServiceDesc.Streams[2], "/ipamfacade.v1.IPAMFacade/ListIPs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &iPAMFacadeListIPsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type IPAMFacade_ListIPsClient interface {
+	Recv() (*IP, error)
+	grpc.ClientStream
+}
+
+type iPAMFacadeListIPsClient struct {
+	grpc.ClientStream
+}
+
+func (x *iPAMFacadeListIPsClient) Recv() (*IP, error) {
+	m := new(IP)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *iPAMFacadeClient) EnqueueSync(ctx context.Context, in *EnqueueSyncRequest, opts ...grpc.CallOption) (*SyncJob, error) {
+	out := new(SyncJob)
+	err := c.cc.Invoke(ctx, "/ipamfacade.v1.IPAMFacade/EnqueueSync", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// IPAMFacadeServer is the server API for IPAMFacade service.
+// All implementations must embed UnimplementedIPAMFacadeServer
+// for forward compatibility
+type IPAMFacadeServer interface {
+	// FetchByIP returns the physical asset with an IP address. It fails with INVALID_ARGUMENT
+	// when the IP address is not valid, and with NOT_FOUND when no subnet contains it.
+	FetchByIP(context.Context, *FetchByIPRequest) (*PhysicalAsset, error)
+	// BatchFetchByIP streams the lookups of several IP addresses, one result per address in
+	// the order requested. A failed lookup is reported in its result rather than failing the
+	// stream.
+	BatchFetchByIP(*BatchFetchByIPRequest, IPAMFacade_BatchFetchByIPServer) error
+	// ListSubnets streams every stored subnet, fetched from storage a page at a time.
+	ListSubnets(*ListRequest, IPAMFacade_ListSubnetsServer) error
+	// ListIPs streams every stored IP address, fetched from storage a page at a time.
+	ListIPs(*ListRequest, IPAMFacade_ListIPsServer) error
+	// EnqueueSync enqueues a job to sync the IPAM data, and returns its ID.
+	EnqueueSync(context.Context, *EnqueueSyncRequest) (*SyncJob, error)
+	mustEmbedUnimplementedIPAMFacadeServer()
+}
+
+// UnimplementedIPAMFacadeServer must be embedded to have forward compatible implementations.
+type UnimplementedIPAMFacadeServer struct {
+}
+
+func (UnimplementedIPAMFacadeServer) FetchByIP(context.Context, *FetchByIPRequest) (*PhysicalAsset, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FetchByIP not implemented")
+}
+func (UnimplementedIPAMFacadeServer) BatchFetchByIP(*BatchFetchByIPRequest, IPAMFacade_BatchFetchByIPServer) error {
+	return status.Errorf(codes.Unimplemented, "method BatchFetchByIP not implemented")
+}
+func (UnimplementedIPAMFacadeServer) ListSubnets(*ListRequest, IPAMFacade_ListSubnetsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListSubnets not implemented")
+}
+func (UnimplementedIPAMFacadeServer) ListIPs(*ListRequest, IPAMFacade_ListIPsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListIPs not implemented")
+}
+func (UnimplementedIPAMFacadeServer) EnqueueSync(context.Context, *EnqueueSyncRequest) (*SyncJob, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EnqueueSync not implemented")
+}
+func (UnimplementedIPAMFacadeServer) mustEmbedUnimplementedIPAMFacadeServer() {}
+
+// UnsafeIPAMFacadeServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to IPAMFacadeServer will
+// result in compilation errors.
+type UnsafeIPAMFacadeServer interface {
+	mustEmbedUnimplementedIPAMFacadeServer()
+}
+
+func RegisterIPAMFacadeServer(s grpc.ServiceRegistrar, srv IPAMFacadeServer) {
+	s.RegisterService(&IPAMFacade_ServiceDesc, srv)
+}
+
+func _IPAMFacade_FetchByIP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FetchByIPRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IPAMFacadeServer).FetchByIP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ipamfacade.v1.IPAMFacade/FetchByIP",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IPAMFacadeServer).FetchByIP(ctx, req.(*FetchByIPRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IPAMFacade_BatchFetchByIP_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(BatchFetchByIPRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(IPAMFacadeServer).BatchFetchByIP(m, &iPAMFacadeBatchFetchByIPServer{stream})
+}
+
+type IPAMFacade_BatchFetchByIPServer interface {
+	Send(*BatchFetchByIPResult) error
+	grpc.ServerStream
+}
+
+type iPAMFacadeBatchFetchByIPServer struct {
+	grpc.ServerStream
+}
+
+func (x *iPAMFacadeBatchFetchByIPServer) Send(m *BatchFetchByIPResult) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _IPAMFacade_ListSubnets_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(IPAMFacadeServer).ListSubnets(m, &iPAMFacadeListSubnetsServer{stream})
+}
+
+type IPAMFacade_ListSubnetsServer interface {
+	Send(*Subnet) error
+	grpc.ServerStream
+}
+
+type iPAMFacadeListSubnetsServer struct {
+	grpc.ServerStream
+}
+
+func (x *iPAMFacadeListSubnetsServer) Send(m *Subnet) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _IPAMFacade_ListIPs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(IPAMFacadeServer).ListIPs(m, &iPAMFacadeListIPsServer{stream})
+}
+
+type IPAMFacade_ListIPsServer interface {
+	Send(*IP) error
+	grpc.ServerStream
+}
+
+type iPAMFacadeListIPsServer struct {
+	grpc.ServerStream
+}
+
+func (x *iPAMFacadeListIPsServer) Send(m *IP) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _IPAMFacade_EnqueueSync_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EnqueueSyncRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IPAMFacadeServer).EnqueueSync(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ipamfacade.v1.IPAMFacade/EnqueueSync",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IPAMFacadeServer).EnqueueSync(ctx, req.(*EnqueueSyncRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// IPAMFacade_ServiceDesc is the grpc.ServiceDesc for IPAMFacade service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var IPAMFacade_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "ipamfacade.v1.IPAMFacade",
+	HandlerType: (*IPAMFacadeServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "FetchByIP",
+			Handler:    _IPAMFacade_FetchByIP_Handler,
+		},
+		{
+			MethodName: "EnqueueSync",
+			Handler:    _IPAMFacade_EnqueueSync_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "BatchFetchByIP",
+			Handler:       _IPAMFacade_BatchFetchByIP_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListSubnets",
+			Handler:       _IPAMFacade_ListSubnets_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListIPs",
+			Handler:       _IPAMFacade_ListIPs_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "ipamfacade/v1/ipamfacade.proto",
+}
@@ -0,0 +1,118 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/asecurityteam/ipam-facade/pkg/domain (interfaces: PhysicalAssetFetcher,Fetcher)
+
+// Package grpcserver is a generated GoMock package.
+package grpcserver
+
+import (
+	context "context"
+	domain "github.com/asecurityteam/ipam-facade/pkg/domain"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockPhysicalAssetFetcher is a mock of PhysicalAssetFetcher interface
+type MockPhysicalAssetFetcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockPhysicalAssetFetcherMockRecorder
+}
+
+// MockPhysicalAssetFetcherMockRecorder is the mock recorder for MockPhysicalAssetFetcher
+type MockPhysicalAssetFetcherMockRecorder struct {
+	mock *MockPhysicalAssetFetcher
+}
+
+// NewMockPhysicalAssetFetcher creates a new mock instance
+func NewMockPhysicalAssetFetcher(ctrl *gomock.Controller) *MockPhysicalAssetFetcher {
+	mock := &MockPhysicalAssetFetcher{ctrl: ctrl}
+	mock.recorder = &MockPhysicalAssetFetcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockPhysicalAssetFetcher) EXPECT() *MockPhysicalAssetFetcherMockRecorder {
+	return m.recorder
+}
+
+// FetchPhysicalAsset mocks base method
+func (m *MockPhysicalAssetFetcher) FetchPhysicalAsset(arg0 context.Context, arg1 string) (domain.PhysicalAsset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchPhysicalAsset", arg0, arg1)
+	ret0, _ := ret[0].(domain.PhysicalAsset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchPhysicalAsset indicates an expected call of FetchPhysicalAsset
+func (mr *MockPhysicalAssetFetcherMockRecorder) FetchPhysicalAsset(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchPhysicalAsset", reflect.TypeOf((*MockPhysicalAssetFetcher)(nil).FetchPhysicalAsset), arg0, arg1)
+}
+
+// MockFetcher is a mock of Fetcher interface
+type MockFetcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockFetcherMockRecorder
+}
+
+// MockFetcherMockRecorder is the mock recorder for MockFetcher
+type MockFetcherMockRecorder struct {
+	mock *MockFetcher
+}
+
+// NewMockFetcher creates a new mock instance
+func NewMockFetcher(ctrl *gomock.Controller) *MockFetcher {
+	mock := &MockFetcher{ctrl: ctrl}
+	mock.recorder = &MockFetcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockFetcher) EXPECT() *MockFetcherMockRecorder {
+	return m.recorder
+}
+
+// FetchIPs mocks base method
+func (m *MockFetcher) FetchIPs(arg0 context.Context, arg1, arg2 int) ([]domain.AssetIP, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchIPs", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]domain.AssetIP)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchIPs indicates an expected call of FetchIPs
+func (mr *MockFetcherMockRecorder) FetchIPs(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchIPs", reflect.TypeOf((*MockFetcher)(nil).FetchIPs), arg0, arg1, arg2)
+}
+
+// FetchPhysicalAsset mocks base method
+func (m *MockFetcher) FetchPhysicalAsset(arg0 context.Context, arg1 string) (domain.PhysicalAsset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchPhysicalAsset", arg0, arg1)
+	ret0, _ := ret[0].(domain.PhysicalAsset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchPhysicalAsset indicates an expected call of FetchPhysicalAsset
+func (mr *MockFetcherMockRecorder) FetchPhysicalAsset(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchPhysicalAsset", reflect.TypeOf((*MockFetcher)(nil).FetchPhysicalAsset), arg0, arg1)
+}
+
+// FetchSubnets mocks base method
+func (m *MockFetcher) FetchSubnets(arg0 context.Context, arg1, arg2 int) ([]domain.AssetSubnet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchSubnets", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]domain.AssetSubnet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchSubnets indicates an expected call of FetchSubnets
+func (mr *MockFetcherMockRecorder) FetchSubnets(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchSubnets", reflect.TypeOf((*MockFetcher)(nil).FetchSubnets), arg0, arg1, arg2)
+}
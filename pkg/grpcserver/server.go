@@ -0,0 +1,239 @@
+// Package grpcserver serves the IPAMFacade gRPC service, defined in
+// proto/ipamfacade/v1/ipamfacade.proto, with the handlers of the HTTP API.
+package grpcserver
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+	"github.com/asecurityteam/ipam-facade/pkg/grpcserver/ipamfacadepb"
+	v1 "github.com/asecurityteam/ipam-facade/pkg/handlers/v1"
+	"github.com/asecurityteam/logevent"
+	"github.com/rs/xstats"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+)
+
+// Server serves the IPAMFacade gRPC service on the Address. Lookups are served by the
+// FetchByIPAddressHandler, the subnet and IP streams by the FetchPageHandler, and sync
+// requests by the EnqueueHandler. The Logger and Stat are added to the context of every
+// call, as the HTTP runtime adds them to the context of every request. Calls are served over
+// TLS with the TLSConfig, which authenticates clients by their certificates, and without TLS
+// when it is nil.
+type Server struct {
+	ipamfacadepb.UnimplementedIPAMFacadeServer
+
+	Address                 string
+	MaxBatchSize            int
+	TLSConfig               *tls.Config
+	FetchByIPAddressHandler *v1.FetchByIPAddressHandler
+	FetchPageHandler        *v1.FetchPageHandler
+	EnqueueHandler          *v1.EnqueueHandler
+	Logger                  domain.Logger
+	Stat                    domain.Stat
+}
+
+// Start listens on the Address and serves the gRPC service in the background. It returns a
+// function that stops the server once the calls in progress have finished.
+func (s *Server) Start() (func(), error) {
+	listener, err := net.Listen("tcp", s.Address)
+	if err != nil {
+		return nil, err
+	}
+	server := s.newGRPCServer()
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	return server.GracefulStop, nil
+}
+
+// newGRPCServer constructs a gRPC server for the IPAMFacade service, adding the runtime to
+// the context of every call.
+func (s *Server) newGRPCServer() *grpc.Server {
+	options := []grpc.ServerOption{
+		grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			return handler(s.withRuntime(ctx), req)
+		}),
+		grpc.StreamInterceptor(func(srv interface{}, stream grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			return handler(srv, &runtimeStream{ServerStream: stream, ctx: s.withRuntime(stream.Context())})
+		}),
+	}
+	if s.TLSConfig != nil {
+		options = append(options, grpc.Creds(credentials.NewTLS(s.TLSConfig)))
+	}
+	server := grpc.NewServer(options...)
+	ipamfacadepb.RegisterIPAMFacadeServer(server, s)
+	return server
+}
+
+// FetchByIP returns the physical asset with an IP address.
+func (s *Server) FetchByIP(ctx context.Context, req *ipamfacadepb.FetchByIPRequest) (*ipamfacadepb.PhysicalAsset, error) {
+	asset, err := s.FetchByIPAddressHandler.Handle(ctx, v1.IPAddressQuery{IPAddress: req.IpAddress})
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	return physicalAssetToMessage(asset), nil
+}
+
+// BatchFetchByIP streams the lookups of several IP addresses, in the order requested.
+func (s *Server) BatchFetchByIP(req *ipamfacadepb.BatchFetchByIPRequest, stream ipamfacadepb.IPAMFacade_BatchFetchByIPServer) error {
+	if len(req.IpAddresses) > s.MaxBatchSize {
+		return status.Errorf(codes.InvalidArgument, "a batch may look up at most %d IP addresses, got %d", s.MaxBatchSize, len(req.IpAddresses))
+	}
+	for _, ipAddress := range req.IpAddresses {
+		result := &ipamfacadepb.BatchFetchByIPResult{IpAddress: ipAddress}
+		asset, err := s.FetchByIPAddressHandler.Handle(stream.Context(), v1.IPAddressQuery{IPAddress: ipAddress})
+		if err != nil {
+			lookupStatus := status.Convert(statusFromError(err))
+			result.Code = int32(lookupStatus.Code())
+			result.Message = lookupStatus.Message()
+		} else {
+			result.Asset = physicalAssetToMessage(asset)
+		}
+		if err := stream.Send(result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListSubnets streams every stored subnet, a page at a time.
+func (s *Server) ListSubnets(req *ipamfacadepb.ListRequest, stream ipamfacadepb.IPAMFacade_ListSubnetsServer) error {
+	if req.PageSize < 0 {
+		return status.Errorf(codes.InvalidArgument, "the page size must not be negative, got %d", req.PageSize)
+	}
+	ctx := stream.Context()
+	page, err := s.FetchPageHandler.FetchSubnets(ctx, v1.PaginationRequest{Limit: int(req.PageSize)})
+	for {
+		if err != nil {
+			return statusFromError(err)
+		}
+		for _, subnet := range page.Result.([]v1.Subnet) {
+			if err := stream.Send(&ipamfacadepb.Subnet{
+				Network:       subnet.Network,
+				ResourceOwner: subnet.ResourceOwner,
+				BusinessUnit:  subnet.BusinessUnit,
+				Location:      subnet.Location,
+			}); err != nil {
+				return err
+			}
+		}
+		if page.NextPageToken == "" {
+			return nil
+		}
+		page, err = s.FetchPageHandler.FetchNextSubnets(ctx, v1.NextPageRequest{NextPageToken: page.NextPageToken})
+	}
+}
+
+// ListIPs streams every stored IP address, a page at a time.
+func (s *Server) ListIPs(req *ipamfacadepb.ListRequest, stream ipamfacadepb.IPAMFacade_ListIPsServer) error {
+	if req.PageSize < 0 {
+		return status.Errorf(codes.InvalidArgument, "the page size must not be negative, got %d", req.PageSize)
+	}
+	ctx := stream.Context()
+	page, err := s.FetchPageHandler.FetchIPs(ctx, v1.PaginationRequest{Limit: int(req.PageSize)})
+	for {
+		if err != nil {
+			return statusFromError(err)
+		}
+		for _, ip := range page.Result.([]v1.IP) {
+			if err := stream.Send(&ipamfacadepb.IP{
+				Ip:            ip.IP,
+				Network:       ip.Network,
+				ResourceOwner: ip.ResourceOwner,
+				BusinessUnit:  ip.BusinessUnit,
+				Location:      ip.Location,
+			}); err != nil {
+				return err
+			}
+		}
+		if page.NextPageToken == "" {
+			return nil
+		}
+		page, err = s.FetchPageHandler.FetchNextIPs(ctx, v1.NextPageRequest{NextPageToken: page.NextPageToken})
+	}
+}
+
+// EnqueueSync enqueues a job to sync the IPAM data.
+func (s *Server) EnqueueSync(ctx context.Context, _ *ipamfacadepb.EnqueueSyncRequest) (*ipamfacadepb.SyncJob, error) {
+	job, err := s.EnqueueHandler.Handle(ctx)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	return &ipamfacadepb.SyncJob{JobId: job.JobID}, nil
+}
+
+// withRuntime adds the Logger and Stat to the context of a call.
+func (s *Server) withRuntime(ctx context.Context) context.Context {
+	if s.Logger != nil {
+		ctx = logevent.NewContext(ctx, s.Logger)
+	}
+	if s.Stat != nil {
+		ctx = xstats.NewContext(ctx, s.Stat)
+	}
+	return ctx
+}
+
+// runtimeStream replaces the context of a server stream.
+type runtimeStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *runtimeStream) Context() context.Context {
+	return s.ctx
+}
+
+// statusFromError converts the errors returned by the handlers into gRPC status errors,
+// with the codes matching the HTTP statuses the gateway responds with.
+func statusFromError(err error) error {
+	switch err.(type) {
+	case domain.InvalidInput:
+		return status.Error(codes.InvalidArgument, err.Error())
+	case domain.AssetNotFound:
+		return status.Error(codes.NotFound, err.Error())
+	case domain.AssetFetchError:
+		return status.Error(codes.Internal, err.Error())
+	}
+	switch err {
+	case context.Canceled:
+		return status.Error(codes.Canceled, err.Error())
+	case context.DeadlineExceeded:
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+// physicalAssetToMessage converts the PhysicalAssetDetails response of the HTTP API into a
+// PhysicalAsset message.
+func physicalAssetToMessage(asset v1.PhysicalAssetDetails) *ipamfacadepb.PhysicalAsset {
+	contacts := make([]*ipamfacadepb.Contact, 0, len(asset.Contacts))
+	for _, contact := range asset.Contacts {
+		contacts = append(contacts, &ipamfacadepb.Contact{
+			Type:  contact.Type,
+			Name:  contact.Name,
+			Email: contact.Email,
+			Phone: contact.Phone,
+		})
+	}
+	return &ipamfacadepb.PhysicalAsset{
+		Ip:              asset.IP,
+		ResourceOwner:   asset.ResourceOwner,
+		BusinessUnit:    asset.BusinessUnit,
+		Contacts:        contacts,
+		InheritedFields: asset.InheritedFields,
+		Tags: &ipamfacadepb.Tags{
+			Network:               asset.Tags.Network,
+			Location:              asset.Tags.Location,
+			DeviceId:              asset.Tags.DeviceID,
+			SubnetId:              asset.Tags.SubnetID,
+			CustomerId:            asset.Tags.CustomerID,
+			ResourceOwnerRule:     asset.Tags.ResourceOwnerRule,
+			InheritedFromSubnetId: asset.Tags.InheritedFromSubnetID,
+		},
+	}
+}
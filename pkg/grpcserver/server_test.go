@@ -0,0 +1,292 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"testing"
+
+	gomock "github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+	"github.com/asecurityteam/ipam-facade/pkg/grpcserver/ipamfacadepb"
+	v1 "github.com/asecurityteam/ipam-facade/pkg/handlers/v1"
+	"github.com/asecurityteam/logevent"
+)
+
+// newTestClient serves the server over an in-memory connection, and returns a client of it
+// along with a function that closes both.
+func newTestClient(t *testing.T, server *Server) (ipamfacadepb.IPAMFacadeClient, func()) {
+	server.Logger = logevent.New(logevent.Config{Output: ioutil.Discard})
+	listener := bufconn.Listen(1024 * 1024)
+	grpcServer := server.newGRPCServer()
+	go func() {
+		_ = grpcServer.Serve(listener)
+	}()
+	conn, err := grpc.DialContext(context.Background(), "bufconn",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	return ipamfacadepb.NewIPAMFacadeClient(conn), func() {
+		_ = conn.Close()
+		grpcServer.Stop()
+	}
+}
+
+func TestFetchByIP(t *testing.T) {
+	asset := domain.PhysicalAsset{
+		IP:            "10.0.0.1",
+		Network:       "10.0.0.0/24",
+		Location:      "DC",
+		ResourceOwner: "owner@example.com",
+		BusinessUnit:  "unit",
+		Contacts:      []domain.Contact{{Type: "owner", Name: "Owner", Email: "owner@example.com"}},
+		CustomerID:    3,
+		SubnetID:      2,
+		DeviceID:      1,
+	}
+	tc := []struct {
+		name      string
+		ipAddress string
+		fetch     bool
+		asset     domain.PhysicalAsset
+		err       error
+		expected  *ipamfacadepb.PhysicalAsset
+		code      codes.Code
+	}{
+		{
+			name:      "found",
+			ipAddress: "10.0.0.1",
+			fetch:     true,
+			asset:     asset,
+			expected: &ipamfacadepb.PhysicalAsset{
+				Ip:              "10.0.0.1",
+				ResourceOwner:   "owner@example.com",
+				BusinessUnit:    "unit",
+				Contacts:        []*ipamfacadepb.Contact{{Type: "owner", Name: "Owner", Email: "owner@example.com"}},
+				InheritedFields: []string{},
+				Tags: &ipamfacadepb.Tags{
+					Network:    "10.0.0.0/24",
+					Location:   "DC",
+					DeviceId:   "1",
+					SubnetId:   "2",
+					CustomerId: "3",
+				},
+			},
+			code: codes.OK,
+		},
+		{
+			name:      "invalid input",
+			ipAddress: "not an ip",
+			code:      codes.InvalidArgument,
+		},
+		{
+			name:      "not found",
+			ipAddress: "10.0.0.1",
+			fetch:     true,
+			err:       domain.AssetNotFound{IP: "10.0.0.1"},
+			code:      codes.NotFound,
+		},
+		{
+			name:      "fetch error",
+			ipAddress: "10.0.0.1",
+			fetch:     true,
+			err:       domain.AssetFetchError{IP: "10.0.0.1", Inner: errors.New("boom")},
+			code:      codes.Internal,
+		},
+		{
+			name:      "unexpected error",
+			ipAddress: "10.0.0.1",
+			fetch:     true,
+			err:       errors.New("boom"),
+			code:      codes.Internal,
+		},
+	}
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockFetcher := NewMockPhysicalAssetFetcher(ctrl)
+			if tt.fetch {
+				mockFetcher.EXPECT().FetchPhysicalAsset(gomock.Any(), tt.ipAddress).Return(tt.asset, tt.err)
+			}
+			client, closeClient := newTestClient(t, &Server{
+				FetchByIPAddressHandler: &v1.FetchByIPAddressHandler{PhysicalAssetFetcher: mockFetcher, LogFn: domain.LoggerFromContext},
+			})
+			defer closeClient()
+
+			result, err := client.FetchByIP(context.Background(), &ipamfacadepb.FetchByIPRequest{IpAddress: tt.ipAddress})
+			require.Equal(t, tt.code, status.Code(err))
+			if tt.expected != nil {
+				require.True(t, proto.Equal(tt.expected, result), "expected %v but got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestBatchFetchByIP(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFetcher := NewMockPhysicalAssetFetcher(ctrl)
+	client, closeClient := newTestClient(t, &Server{
+		MaxBatchSize:            3,
+		FetchByIPAddressHandler: &v1.FetchByIPAddressHandler{PhysicalAssetFetcher: mockFetcher, LogFn: domain.LoggerFromContext},
+	})
+	defer closeClient()
+
+	mockFetcher.EXPECT().FetchPhysicalAsset(gomock.Any(), "10.0.0.1").Return(domain.PhysicalAsset{IP: "10.0.0.1", SubnetID: 1}, nil)
+	mockFetcher.EXPECT().FetchPhysicalAsset(gomock.Any(), "192.168.0.1").Return(domain.PhysicalAsset{}, domain.AssetNotFound{IP: "192.168.0.1"})
+	stream, err := client.BatchFetchByIP(context.Background(), &ipamfacadepb.BatchFetchByIPRequest{
+		IpAddresses: []string{"10.0.0.1", "not an ip", "192.168.0.1"},
+	})
+	require.NoError(t, err)
+	var results []*ipamfacadepb.BatchFetchByIPResult
+	for {
+		result, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		results = append(results, result)
+	}
+
+	require.Len(t, results, 3)
+	require.Equal(t, "10.0.0.1", results[0].IpAddress)
+	require.Equal(t, int32(codes.OK), results[0].Code)
+	require.Equal(t, "10.0.0.1", results[0].Asset.Ip)
+	require.Equal(t, "not an ip", results[1].IpAddress)
+	require.Equal(t, int32(codes.InvalidArgument), results[1].Code)
+	require.Nil(t, results[1].Asset)
+	require.Equal(t, "192.168.0.1", results[2].IpAddress)
+	require.Equal(t, int32(codes.NotFound), results[2].Code)
+	require.NotEmpty(t, results[2].Message)
+	require.Nil(t, results[2].Asset)
+
+	stream, err = client.BatchFetchByIP(context.Background(), &ipamfacadepb.BatchFetchByIPRequest{
+		IpAddresses: []string{"10.0.0.1", "10.0.0.2", "10.0.0.3", "10.0.0.4"},
+	})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestListSubnets(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFetcher := NewMockFetcher(ctrl)
+	client, closeClient := newTestClient(t, &Server{
+		FetchPageHandler: &v1.FetchPageHandler{Fetcher: mockFetcher, LogFn: domain.LoggerFromContext, DefaultPageSize: 100},
+	})
+	defer closeClient()
+
+	gomock.InOrder(
+		mockFetcher.EXPECT().FetchSubnets(gomock.Any(), 2, 0).Return([]domain.AssetSubnet{
+			{Network: "10.0.0.0/24", ResourceOwner: "a", BusinessUnit: "ua", Location: "DC"},
+			{Network: "10.0.1.0/24", ResourceOwner: "b", BusinessUnit: "ub", Location: "DC"},
+		}, nil),
+		mockFetcher.EXPECT().FetchSubnets(gomock.Any(), 2, 2).Return([]domain.AssetSubnet{
+			{Network: "10.0.2.0/24", ResourceOwner: "c", BusinessUnit: "uc", Location: "DC"},
+		}, nil),
+	)
+	stream, err := client.ListSubnets(context.Background(), &ipamfacadepb.ListRequest{PageSize: 2})
+	require.NoError(t, err)
+	var networks []string
+	for {
+		subnet, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		networks = append(networks, subnet.Network)
+	}
+	require.Equal(t, []string{"10.0.0.0/24", "10.0.1.0/24", "10.0.2.0/24"}, networks)
+
+	mockFetcher.EXPECT().FetchSubnets(gomock.Any(), 100, 0).Return(nil, errors.New("boom"))
+	stream, err = client.ListSubnets(context.Background(), &ipamfacadepb.ListRequest{})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	require.Equal(t, codes.Internal, status.Code(err))
+
+	stream, err = client.ListSubnets(context.Background(), &ipamfacadepb.ListRequest{PageSize: -1})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestListIPs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFetcher := NewMockFetcher(ctrl)
+	client, closeClient := newTestClient(t, &Server{
+		FetchPageHandler: &v1.FetchPageHandler{Fetcher: mockFetcher, LogFn: domain.LoggerFromContext, DefaultPageSize: 1},
+	})
+	defer closeClient()
+
+	gomock.InOrder(
+		mockFetcher.EXPECT().FetchIPs(gomock.Any(), 1, 0).Return([]domain.AssetIP{
+			{IP: "10.0.0.1", Network: "10.0.0.0/24", ResourceOwner: "a", BusinessUnit: "ua", Location: "DC"},
+		}, nil),
+		mockFetcher.EXPECT().FetchIPs(gomock.Any(), 1, 1).Return([]domain.AssetIP{}, nil),
+	)
+	stream, err := client.ListIPs(context.Background(), &ipamfacadepb.ListRequest{})
+	require.NoError(t, err)
+	ip, err := stream.Recv()
+	require.NoError(t, err)
+	require.True(t, proto.Equal(&ipamfacadepb.IP{
+		Ip: "10.0.0.1", Network: "10.0.0.0/24", ResourceOwner: "a", BusinessUnit: "ua", Location: "DC",
+	}, ip))
+	_, err = stream.Recv()
+	require.Equal(t, io.EOF, err)
+}
+
+func TestEnqueueSync(t *testing.T) {
+	tc := []struct {
+		name       string
+		produceErr error
+		code       codes.Code
+	}{
+		{
+			name: "enqueued",
+			code: codes.OK,
+		},
+		{
+			name:       "producer error",
+			produceErr: errors.New("boom"),
+			code:       codes.Internal,
+		},
+	}
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockUUIDGenerator := NewMockUUIDGenerator(ctrl)
+			mockProducer := NewMockProducer(ctrl)
+			client, closeClient := newTestClient(t, &Server{
+				EnqueueHandler: &v1.EnqueueHandler{Producer: mockProducer, UUIDGenerator: mockUUIDGenerator, LogFn: domain.LoggerFromContext},
+			})
+			defer closeClient()
+
+			mockUUIDGenerator.EXPECT().NewUUIDString().Return("foo-bar-baz-quux", nil)
+			mockProducer.EXPECT().Produce(gomock.Any(), v1.JobMetadata{JobID: "foo-bar-baz-quux"}).Return(nil, tt.produceErr)
+			job, err := client.EnqueueSync(context.Background(), &ipamfacadepb.EnqueueSyncRequest{})
+			require.Equal(t, tt.code, status.Code(err))
+			if tt.produceErr == nil {
+				require.Equal(t, "foo-bar-baz-quux", job.JobId)
+			}
+		})
+	}
+}
@@ -0,0 +1,275 @@
+package v1
+
+import (
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+	"github.com/asecurityteam/ipam-facade/pkg/logs"
+)
+
+// Formats of an export.
+const (
+	ndjsonFormat = "ndjson"
+	csvFormat    = "csv"
+)
+
+// Headers of an export that identify the stored dataset it was read from.
+const (
+	SyncGenerationHeader = "X-Sync-Generation"
+	SyncedAtHeader       = "X-Synced-At"
+)
+
+// ExportCustomer provides the structure of an exported customer.
+type ExportCustomer struct {
+	ID                string    `json:"id"`
+	ResourceOwner     string    `json:"resourceOwner"`
+	ResourceOwnerRule string    `json:"resourceOwnerRule"`
+	BusinessUnit      string    `json:"businessUnit"`
+	Contacts          []Contact `json:"contacts"`
+}
+
+// ExportSubnet provides the structure of an exported subnet. The customerID is empty when the
+// subnet has no customer.
+type ExportSubnet struct {
+	ID            string `json:"id"`
+	Network       string `json:"network"`
+	Location      string `json:"location"`
+	CustomerID    string `json:"customerID"`
+	ResourceOwner string `json:"resourceOwner"`
+	BusinessUnit  string `json:"businessUnit"`
+}
+
+// ExportIP provides the structure of an exported IP address. The deviceID is empty when the
+// address has no device.
+type ExportIP struct {
+	IP            string `json:"ip"`
+	DeviceID      string `json:"deviceID"`
+	SubnetID      string `json:"subnetID"`
+	Network       string `json:"network"`
+	Location      string `json:"location"`
+	ResourceOwner string `json:"resourceOwner"`
+	BusinessUnit  string `json:"businessUnit"`
+}
+
+// csvHeaders are the header rows of the CSV exports, in the order of the fields of the
+// exported records. The contacts of a customer are a JSON array in a single column.
+var csvHeaders = map[domain.AssetExportKind][]string{
+	domain.ExportCustomers: {"id", "resourceOwner", "resourceOwnerRule", "businessUnit", "contacts"},
+	domain.ExportSubnets:   {"id", "network", "location", "customerID", "resourceOwner", "businessUnit"},
+	domain.ExportIPs:       {"ip", "deviceID", "subnetID", "network", "location", "resourceOwner", "businessUnit"},
+}
+
+// ExportHandler uses its AssetExporter implementation to stream every stored customer, subnet,
+// or IP address, named by the last element of the request path, as newline delimited JSON or,
+// with the format=csv query parameter, as CSV. The response is compressed with gzip when the
+// request accepts it. Unlike the other handlers it serves HTTP requests directly, because the
+// functions of the runtime respond with a single JSON document that must fit in memory.
+type ExportHandler struct {
+	AssetExporter domain.AssetExporter
+	LogFn         domain.LogFn
+}
+
+// ServeHTTP streams an export. Errors before the first record is written are returned as a
+// JSON Error response. Once streaming has started, an error aborts the response, so that
+// clients never mistake a partial export for a complete one.
+func (h *ExportHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := h.LogFn(ctx)
+
+	kind := domain.AssetExportKind(path.Base(r.URL.Path))
+	if _, ok := csvHeaders[kind]; !ok {
+		err := domain.UnknownExportKind{Kind: string(kind)}
+		logger.Info(logs.InvalidInput{Reason: err.Error()})
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	format := strings.ToLower(r.URL.Query().Get("format"))
+	if format == "" {
+		format = ndjsonFormat
+	}
+	if format != ndjsonFormat && format != csvFormat {
+		err := domain.InvalidInput{Input: "format " + strconv.Quote(format)}
+		logger.Info(logs.InvalidInput{Reason: err.Error()})
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	writer := &exportWriter{
+		response: w,
+		kind:     kind,
+		format:   format,
+		gzip:     acceptsGzip(r.Header.Get("Accept-Encoding")),
+	}
+	err := h.AssetExporter.ExportAssets(ctx, kind, writer)
+	if err == nil {
+		err = writer.close()
+	}
+	if err == nil {
+		return
+	}
+	logger.Error(logs.AssetExportFailure{Reason: err.Error(), Kind: string(kind)})
+	if !writer.started {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	// the status has been sent, so the response is aborted rather than completed
+	panic(http.ErrAbortHandler)
+}
+
+// exportWriter encodes the records of an export into an HTTP response. The response is
+// started when the snapshot is written, with headers that identify the snapshot.
+type exportWriter struct {
+	response http.ResponseWriter
+	kind     domain.AssetExportKind
+	format   string
+	gzip     bool
+
+	started    bool
+	compressor *gzip.Writer
+	json       *json.Encoder
+	csv        *csv.Writer
+}
+
+func (w *exportWriter) WriteSnapshot(snapshot domain.AssetSnapshot) error {
+	header := w.response.Header()
+	if w.format == csvFormat {
+		header.Set("Content-Type", "text/csv; charset=utf-8")
+	} else {
+		header.Set("Content-Type", "application/x-ndjson")
+	}
+	header.Set(SyncGenerationHeader, strconv.FormatInt(snapshot.Generation, 10))
+	if !snapshot.SyncedAt.IsZero() {
+		header.Set(SyncedAtHeader, snapshot.SyncedAt.UTC().Format(time.RFC3339))
+	}
+	header.Add("Vary", "Accept-Encoding")
+	if w.gzip {
+		header.Set("Content-Encoding", "gzip")
+	}
+	w.response.WriteHeader(http.StatusOK)
+	w.started = true
+
+	var body io.Writer = w.response
+	if w.gzip {
+		w.compressor = gzip.NewWriter(w.response)
+		body = w.compressor
+	}
+	if w.format == csvFormat {
+		w.csv = csv.NewWriter(body)
+		return w.csv.Write(csvHeaders[w.kind])
+	}
+	w.json = json.NewEncoder(body)
+	return nil
+}
+
+func (w *exportWriter) WriteCustomer(customer domain.ExportedCustomer) error {
+	contacts := make([]Contact, 0, len(customer.Contacts))
+	for _, contact := range customer.Contacts {
+		contacts = append(contacts, newContact(contact))
+	}
+	record := ExportCustomer{
+		ID:                formatID(customer.ID),
+		ResourceOwner:     customer.ResourceOwner,
+		ResourceOwnerRule: customer.ResourceOwnerRule,
+		BusinessUnit:      customer.BusinessUnit,
+		Contacts:          contacts,
+	}
+	if w.csv == nil {
+		return w.json.Encode(record)
+	}
+	encodedContacts, err := json.Marshal(record.Contacts)
+	if err != nil {
+		return err
+	}
+	return w.csv.Write([]string{record.ID, record.ResourceOwner, record.ResourceOwnerRule, record.BusinessUnit, string(encodedContacts)})
+}
+
+func (w *exportWriter) WriteSubnet(subnet domain.ExportedSubnet) error {
+	record := ExportSubnet{
+		ID:            formatID(subnet.ID),
+		Network:       subnet.Network,
+		Location:      subnet.Location,
+		CustomerID:    formatID(subnet.CustomerID),
+		ResourceOwner: subnet.ResourceOwner,
+		BusinessUnit:  subnet.BusinessUnit,
+	}
+	if w.csv == nil {
+		return w.json.Encode(record)
+	}
+	return w.csv.Write([]string{record.ID, record.Network, record.Location, record.CustomerID, record.ResourceOwner, record.BusinessUnit})
+}
+
+func (w *exportWriter) WriteIP(ip domain.ExportedIP) error {
+	record := ExportIP{
+		IP:            ip.IP,
+		DeviceID:      formatID(ip.DeviceID),
+		SubnetID:      formatID(ip.SubnetID),
+		Network:       ip.Network,
+		Location:      ip.Location,
+		ResourceOwner: ip.ResourceOwner,
+		BusinessUnit:  ip.BusinessUnit,
+	}
+	if w.csv == nil {
+		return w.json.Encode(record)
+	}
+	return w.csv.Write([]string{record.IP, record.DeviceID, record.SubnetID, record.Network, record.Location, record.ResourceOwner, record.BusinessUnit})
+}
+
+// close writes out the records still buffered and ends the compressed stream.
+func (w *exportWriter) close() error {
+	if w.csv != nil {
+		w.csv.Flush()
+		if err := w.csv.Error(); err != nil {
+			return err
+		}
+	}
+	if w.compressor != nil {
+		return w.compressor.Close()
+	}
+	return nil
+}
+
+// formatID formats an ID, or returns an empty string for the 0 of a missing ID.
+func formatID(id int64) string {
+	if id == 0 {
+		return ""
+	}
+	return strconv.FormatInt(id, 10)
+}
+
+// acceptsGzip returns whether an Accept-Encoding header accepts the gzip encoding.
+func acceptsGzip(acceptEncoding string) bool {
+	for _, encoding := range strings.Split(acceptEncoding, ",") {
+		parts := strings.Split(encoding, ";")
+		if strings.ToLower(strings.TrimSpace(parts[0])) != "gzip" {
+			continue
+		}
+		for _, param := range parts[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				q, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64)
+				return err == nil && q > 0
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// writeError writes an Error response with a status code.
+func writeError(w http.ResponseWriter, code int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"status": http.StatusText(code),
+		"code":   code,
+		"reason": err.Error(),
+	})
+}
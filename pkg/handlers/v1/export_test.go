@@ -0,0 +1,197 @@
+package v1
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+	gomock "github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+var exportSnapshot = domain.AssetSnapshot{Generation: 7, SyncedAt: time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)}
+
+func TestExportHandler(t *testing.T) {
+	tc := []struct {
+		name        string
+		path        string
+		kind        domain.AssetExportKind
+		export      func(domain.AssetExportWriter) error
+		contentType string
+		body        string
+	}{
+		{
+			name: "customers as NDJSON",
+			path: "/v1/export/customers",
+			kind: domain.ExportCustomers,
+			export: func(writer domain.AssetExportWriter) error {
+				require.NoError(t, writer.WriteSnapshot(exportSnapshot))
+				require.NoError(t, writer.WriteCustomer(domain.ExportedCustomer{ID: 1, ResourceOwner: "alice", ResourceOwnerRule: "contact-type:SRE", BusinessUnit: "Acme",
+					Contacts: []domain.Contact{{Type: "SRE", Name: "Alice", Email: "alice@example.com", Phone: "555-0100"}}}))
+				return writer.WriteCustomer(domain.ExportedCustomer{ID: 2, ResourceOwner: "bob", BusinessUnit: "Acme"})
+			},
+			contentType: "application/x-ndjson",
+			body: `{"id":"1","resourceOwner":"alice","resourceOwnerRule":"contact-type:SRE","businessUnit":"Acme","contacts":[{"type":"SRE","name":"Alice","email":"alice@example.com","phone":"555-0100"}]}` + "\n" +
+				`{"id":"2","resourceOwner":"bob","resourceOwnerRule":"","businessUnit":"Acme","contacts":[]}` + "\n",
+		},
+		{
+			name: "customers as CSV",
+			path: "/v1/export/customers?format=csv",
+			kind: domain.ExportCustomers,
+			export: func(writer domain.AssetExportWriter) error {
+				require.NoError(t, writer.WriteSnapshot(exportSnapshot))
+				return writer.WriteCustomer(domain.ExportedCustomer{ID: 1, ResourceOwner: "alice", BusinessUnit: "Acme",
+					Contacts: []domain.Contact{{Type: "SRE", Name: "Alice", Email: "alice@example.com"}}})
+			},
+			contentType: "text/csv; charset=utf-8",
+			body: "id,resourceOwner,resourceOwnerRule,businessUnit,contacts\n" +
+				`1,alice,,Acme,"[{""type"":""SRE"",""name"":""Alice"",""email"":""alice@example.com"",""phone"":""""}]"` + "\n",
+		},
+		{
+			name: "subnets as CSV",
+			path: "/v1/export/subnets?format=CSV",
+			kind: domain.ExportSubnets,
+			export: func(writer domain.AssetExportWriter) error {
+				require.NoError(t, writer.WriteSnapshot(exportSnapshot))
+				require.NoError(t, writer.WriteSubnet(domain.ExportedSubnet{ID: 1, Network: "10.0.0.0/8", Location: "DC1", CustomerID: 2, ResourceOwner: "alice", BusinessUnit: "Acme"}))
+				return writer.WriteSubnet(domain.ExportedSubnet{ID: 3, Network: "10.1.0.0/16", Location: "DC2"})
+			},
+			contentType: "text/csv; charset=utf-8",
+			body: "id,network,location,customerID,resourceOwner,businessUnit\n" +
+				"1,10.0.0.0/8,DC1,2,alice,Acme\n" +
+				"3,10.1.0.0/16,DC2,,,\n",
+		},
+		{
+			name: "IPs as NDJSON",
+			path: "/v1/export/ips?format=ndjson",
+			kind: domain.ExportIPs,
+			export: func(writer domain.AssetExportWriter) error {
+				require.NoError(t, writer.WriteSnapshot(exportSnapshot))
+				return writer.WriteIP(domain.ExportedIP{IP: "10.0.0.1", SubnetID: 1, Network: "10.0.0.0/8", Location: "DC1"})
+			},
+			contentType: "application/x-ndjson",
+			body:        `{"ip":"10.0.0.1","deviceID":"","subnetID":"1","network":"10.0.0.0/8","location":"DC1","resourceOwner":"","businessUnit":""}` + "\n",
+		},
+		{
+			name: "no records",
+			path: "/v1/export/ips?format=csv",
+			kind: domain.ExportIPs,
+			export: func(writer domain.AssetExportWriter) error {
+				return writer.WriteSnapshot(exportSnapshot)
+			},
+			contentType: "text/csv; charset=utf-8",
+			body:        "ip,deviceID,subnetID,network,location,resourceOwner,businessUnit\n",
+		},
+	}
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockExporter := NewMockAssetExporter(ctrl)
+			mockExporter.EXPECT().ExportAssets(gomock.Any(), tt.kind, gomock.Any()).DoAndReturn(
+				func(_ context.Context, _ domain.AssetExportKind, writer domain.AssetExportWriter) error {
+					return tt.export(writer)
+				})
+			handler := &ExportHandler{AssetExporter: mockExporter, LogFn: testLogFn}
+
+			response := httptest.NewRecorder()
+			handler.ServeHTTP(response, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			require.Equal(t, http.StatusOK, response.Code)
+			require.Equal(t, tt.contentType, response.Header().Get("Content-Type"))
+			require.Equal(t, "7", response.Header().Get(SyncGenerationHeader))
+			require.Equal(t, "2026-10-01T12:00:00Z", response.Header().Get(SyncedAtHeader))
+			require.Empty(t, response.Header().Get("Content-Encoding"))
+			require.Equal(t, tt.body, response.Body.String())
+		})
+	}
+}
+
+func TestExportHandlerGzip(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockExporter := NewMockAssetExporter(ctrl)
+	mockExporter.EXPECT().ExportAssets(gomock.Any(), domain.ExportSubnets, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ domain.AssetExportKind, writer domain.AssetExportWriter) error {
+			require.NoError(t, writer.WriteSnapshot(domain.AssetSnapshot{}))
+			return writer.WriteSubnet(domain.ExportedSubnet{ID: 1, Network: "10.0.0.0/8", Location: "DC1"})
+		})
+	handler := &ExportHandler{AssetExporter: mockExporter, LogFn: testLogFn}
+
+	request := httptest.NewRequest(http.MethodGet, "/v1/export/subnets", nil)
+	request.Header.Set("Accept-Encoding", "br;q=1.0, gzip;q=0.8")
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, request)
+	require.Equal(t, http.StatusOK, response.Code)
+	require.Equal(t, "gzip", response.Header().Get("Content-Encoding"))
+	require.Equal(t, "0", response.Header().Get(SyncGenerationHeader))
+	require.Empty(t, response.Header().Get(SyncedAtHeader))
+
+	reader, err := gzip.NewReader(response.Body)
+	require.NoError(t, err)
+	body, err := ioutil.ReadAll(reader)
+	require.NoError(t, err)
+	require.Equal(t, `{"id":"1","network":"10.0.0.0/8","location":"DC1","customerID":"","resourceOwner":"","businessUnit":""}`+"\n", string(body))
+}
+
+func TestExportHandlerInvalidRequest(t *testing.T) {
+	tc := []struct {
+		name string
+		path string
+		code int
+	}{
+		{name: "unknown kind", path: "/v1/export/devices", code: http.StatusNotFound},
+		{name: "unknown format", path: "/v1/export/ips?format=xml", code: http.StatusBadRequest},
+	}
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			handler := &ExportHandler{AssetExporter: NewMockAssetExporter(ctrl), LogFn: testLogFn}
+			response := httptest.NewRecorder()
+			handler.ServeHTTP(response, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			require.Equal(t, tt.code, response.Code)
+			require.Equal(t, "application/json", response.Header().Get("Content-Type"))
+		})
+	}
+}
+
+func TestExportHandlerError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockExporter := NewMockAssetExporter(ctrl)
+	mockExporter.EXPECT().ExportAssets(gomock.Any(), domain.ExportIPs, gomock.Any()).Return(errors.New("connection refused"))
+	handler := &ExportHandler{AssetExporter: mockExporter, LogFn: testLogFn}
+
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/v1/export/ips", nil))
+	require.Equal(t, http.StatusInternalServerError, response.Code)
+	require.Empty(t, response.Header().Get(SyncGenerationHeader))
+}
+
+func TestExportHandlerErrorWhileStreaming(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockExporter := NewMockAssetExporter(ctrl)
+	mockExporter.EXPECT().ExportAssets(gomock.Any(), domain.ExportIPs, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ domain.AssetExportKind, writer domain.AssetExportWriter) error {
+			require.NoError(t, writer.WriteSnapshot(exportSnapshot))
+			return errors.New("connection reset")
+		})
+	handler := &ExportHandler{AssetExporter: mockExporter, LogFn: testLogFn}
+
+	response := httptest.NewRecorder()
+	require.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		handler.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/v1/export/ips", nil))
+	})
+}
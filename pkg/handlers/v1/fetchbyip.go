@@ -15,21 +15,42 @@ type IPAddressQuery struct {
 }
 
 // PhysicalAssetDetails provides the response structure for PhysicalAsset records returned from storage.
+// InheritedFields names the fields that were inherited from the ancestor subnet identified by the
+// inheritedFromSubnetID tag.
 type PhysicalAssetDetails struct {
-	IP            string `json:"ip"`
-	ResourceOwner string `json:"resourceOwner"`
-	BusinessUnit  string `json:"businessUnit"`
-	Tags          tags   `json:"tags"`
+	IP              string    `json:"ip"`
+	ResourceOwner   string    `json:"resourceOwner"`
+	BusinessUnit    string    `json:"businessUnit"`
+	Contacts        []Contact `json:"contacts"`
+	InheritedFields []string  `json:"inheritedFields"`
+	Tags            tags      `json:"tags"`
+}
+
+// Contact provides the response structure for each of the contacts registered for the
+// customer that owns a PhysicalAsset.
+type Contact struct {
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	Phone string `json:"phone"`
+}
+
+// newContact converts a stored contact into its response structure, which leaves out the
+// source the contact was synced from.
+func newContact(contact domain.Contact) Contact {
+	return Contact{Type: contact.Type, Name: contact.Name, Email: contact.Email, Phone: contact.Phone}
 }
 
 // tags is the key-value pair structure that provides less important information than the
 // root keys of the PhysicalAssetDetails response.
 type tags struct {
-	Network    string `json:"network"`
-	Location   string `json:"location"`
-	DeviceID   string `json:"deviceID"`
-	SubnetID   string `json:"subnetID"`
-	CustomerID string `json:"customerID"`
+	Network               string `json:"network"`
+	Location              string `json:"location"`
+	DeviceID              string `json:"deviceID"`
+	SubnetID              string `json:"subnetID"`
+	CustomerID            string `json:"customerID"`
+	ResourceOwnerRule     string `json:"resourceOwnerRule"`
+	InheritedFromSubnetID string `json:"inheritedFromSubnetID"`
 }
 
 // FetchByIPAddressHandler uses its PhysicalAssetFetcher implementation to serve fetch requests for
@@ -68,6 +89,7 @@ func (h *FetchByIPAddressHandler) Handle(ctx context.Context, query IPAddressQue
 func physicalAssetToResponse(asset domain.PhysicalAsset) PhysicalAssetDetails {
 	var deviceID string
 	var customerID string
+	var inheritedFromSubnetID string
 	if asset.DeviceID == 0 {
 		deviceID = ""
 	} else {
@@ -78,16 +100,29 @@ func physicalAssetToResponse(asset domain.PhysicalAsset) PhysicalAssetDetails {
 	} else {
 		customerID = strconv.FormatInt(asset.CustomerID, 10)
 	}
+	if asset.InheritedFromSubnetID != 0 {
+		inheritedFromSubnetID = strconv.FormatInt(asset.InheritedFromSubnetID, 10)
+	}
+	inheritedFields := make([]string, 0, len(asset.InheritedFields))
+	inheritedFields = append(inheritedFields, asset.InheritedFields...)
+	contacts := make([]Contact, 0, len(asset.Contacts))
+	for _, contact := range asset.Contacts {
+		contacts = append(contacts, newContact(contact))
+	}
 	return PhysicalAssetDetails{
-		IP:            asset.IP,
-		ResourceOwner: asset.ResourceOwner,
-		BusinessUnit:  asset.BusinessUnit,
+		IP:              asset.IP,
+		ResourceOwner:   asset.ResourceOwner,
+		BusinessUnit:    asset.BusinessUnit,
+		Contacts:        contacts,
+		InheritedFields: inheritedFields,
 		Tags: tags{
-			Network:    asset.Network,
-			Location:   asset.Location,
-			DeviceID:   deviceID,
-			SubnetID:   strconv.FormatInt(asset.SubnetID, 10),
-			CustomerID: customerID,
+			Network:               asset.Network,
+			Location:              asset.Location,
+			DeviceID:              deviceID,
+			SubnetID:              strconv.FormatInt(asset.SubnetID, 10),
+			CustomerID:            customerID,
+			ResourceOwnerRule:     asset.ResourceOwnerRule,
+			InheritedFromSubnetID: inheritedFromSubnetID,
 		},
 	}
 }
@@ -12,25 +12,36 @@ import (
 
 func TestPhysicalAssetToResponse(t *testing.T) {
 	asset := domain.PhysicalAsset{
-		IP:            "127.0.0.1",
-		ResourceOwner: "alice@example.com",
-		BusinessUnit:  "Security",
-		Network:       "127.0.0.0/31",
-		Location:      "",
-		DeviceID:      1,
-		SubnetID:      1,
-		CustomerID:    1,
+		IP:                "127.0.0.1",
+		ResourceOwner:     "alice@example.com",
+		BusinessUnit:      "Security",
+		Network:           "127.0.0.0/31",
+		Location:          "",
+		DeviceID:          1,
+		SubnetID:          1,
+		CustomerID:        1,
+		ResourceOwnerRule: "contact-type:Technical",
+		Contacts: []domain.Contact{
+			{Type: "Technical", Name: "Alice", Email: "alice@example.com", Phone: "555-0100"},
+			{Type: "Escalation", Name: "Bob", Email: "bob@example.com", Phone: "555-0199"},
+		},
 	}
 	expectedResult := PhysicalAssetDetails{
 		IP:            "127.0.0.1",
 		ResourceOwner: "alice@example.com",
 		BusinessUnit:  "Security",
+		Contacts: []Contact{
+			{Type: "Technical", Name: "Alice", Email: "alice@example.com", Phone: "555-0100"},
+			{Type: "Escalation", Name: "Bob", Email: "bob@example.com", Phone: "555-0199"},
+		},
+		InheritedFields: []string{},
 		Tags: tags{
-			Network:    "127.0.0.0/31",
-			Location:   "",
-			DeviceID:   "1",
-			SubnetID:   "1",
-			CustomerID: "1",
+			Network:           "127.0.0.0/31",
+			Location:          "",
+			DeviceID:          "1",
+			SubnetID:          "1",
+			CustomerID:        "1",
+			ResourceOwnerRule: "contact-type:Technical",
 		},
 	}
 
@@ -50,9 +61,11 @@ func TestPhysicalAssetToResponseZeroValues(t *testing.T) {
 		CustomerID:    0,
 	}
 	expectedResult := PhysicalAssetDetails{
-		IP:            "127.0.0.1",
-		ResourceOwner: "alice@example.com",
-		BusinessUnit:  "Security",
+		IP:              "127.0.0.1",
+		ResourceOwner:   "alice@example.com",
+		BusinessUnit:    "Security",
+		Contacts:        []Contact{},
+		InheritedFields: []string{},
 		Tags: tags{
 			Network:    "127.0.0.0/31",
 			Location:   "",
@@ -66,6 +79,35 @@ func TestPhysicalAssetToResponseZeroValues(t *testing.T) {
 	require.Equal(t, expectedResult, result)
 }
 
+func TestPhysicalAssetToResponseInherited(t *testing.T) {
+	asset := domain.PhysicalAsset{
+		IP:                    "127.0.0.1",
+		ResourceOwner:         "alice@example.com",
+		BusinessUnit:          "Security",
+		Network:               "127.0.0.0/31",
+		SubnetID:              2,
+		CustomerID:            1,
+		InheritedFields:       []string{"resourceOwner", "businessUnit", "customerID", "contacts"},
+		InheritedFromSubnetID: 1,
+	}
+	expectedResult := PhysicalAssetDetails{
+		IP:              "127.0.0.1",
+		ResourceOwner:   "alice@example.com",
+		BusinessUnit:    "Security",
+		Contacts:        []Contact{},
+		InheritedFields: []string{"resourceOwner", "businessUnit", "customerID", "contacts"},
+		Tags: tags{
+			Network:               "127.0.0.0/31",
+			SubnetID:              "2",
+			CustomerID:            "1",
+			InheritedFromSubnetID: "1",
+		},
+	}
+
+	result := physicalAssetToResponse(asset)
+	require.Equal(t, expectedResult, result)
+}
+
 func TestFetchHandlerInvalidInput(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -0,0 +1,71 @@
+package v1
+
+import (
+	"context"
+	"strings"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+	"github.com/asecurityteam/ipam-facade/pkg/logs"
+)
+
+// GraphQLRequest contains a GraphQL query over customers, subnets, and IP addresses, along
+// with the name of the operation to execute when it has several, and the values of its variables.
+type GraphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// GraphQLResponse provides the response structure for a GraphQL query. Data is null when the
+// query was rejected before it was executed, and may be partial when Errors is not empty.
+type GraphQLResponse struct {
+	Data   interface{}    `json:"data"`
+	Errors []GraphQLError `json:"errors,omitempty"`
+}
+
+// GraphQLError provides the response structure for an error of a GraphQL query, with the
+// locations in the query and the path in the data where it occurred.
+type GraphQLError struct {
+	Message   string            `json:"message"`
+	Locations []GraphQLLocation `json:"locations,omitempty"`
+	Path      []interface{}     `json:"path,omitempty"`
+}
+
+// GraphQLLocation provides the response structure for a line and column of a GraphQL query.
+type GraphQLLocation struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// GraphQLHandler uses its AssetGraphQuerier implementation to serve GraphQL queries.
+type GraphQLHandler struct {
+	AssetGraphQuerier domain.AssetGraphQuerier
+	LogFn             domain.LogFn
+}
+
+// Handle processes an incoming GraphQLRequest and returns a GraphQLResponse, which holds the
+// errors of the query, or an error when there is no query.
+func (h *GraphQLHandler) Handle(ctx context.Context, request GraphQLRequest) (GraphQLResponse, error) {
+	logger := h.LogFn(ctx)
+
+	if strings.TrimSpace(request.Query) == "" {
+		err := domain.InvalidInput{Input: "an empty query"}
+		logger.Info(logs.InvalidInput{Reason: err.Error()})
+		return GraphQLResponse{}, err
+	}
+
+	result := h.AssetGraphQuerier.QueryAssetGraph(ctx, domain.AssetGraphRequest{
+		Query:         request.Query,
+		OperationName: request.OperationName,
+		Variables:     request.Variables,
+	})
+	response := GraphQLResponse{Data: result.Data}
+	for _, err := range result.Errors {
+		graphQLError := GraphQLError{Message: err.Message, Path: err.Path}
+		for _, location := range err.Locations {
+			graphQLError.Locations = append(graphQLError.Locations, GraphQLLocation(location))
+		}
+		response.Errors = append(response.Errors, graphQLError)
+	}
+	return response, nil
+}
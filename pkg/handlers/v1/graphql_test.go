@@ -0,0 +1,63 @@
+package v1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+	gomock "github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGraphQLHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	request := GraphQLRequest{
+		Query:         `query Owners($id: ID!) { customers(ids: [$id]) { edges { node { resourceOwner } } } }`,
+		OperationName: "Owners",
+		Variables:     map[string]interface{}{"id": "1"},
+	}
+	data := map[string]interface{}{"customers": map[string]interface{}{"edges": []interface{}{}}}
+	mockQuerier := NewMockAssetGraphQuerier(ctrl)
+	mockQuerier.EXPECT().QueryAssetGraph(gomock.Any(), domain.AssetGraphRequest{
+		Query:         request.Query,
+		OperationName: request.OperationName,
+		Variables:     request.Variables,
+	}).Return(domain.AssetGraphResult{
+		Data: data,
+		Errors: []domain.AssetGraphError{{
+			Message:   "failed to fetch the asset graph",
+			Locations: []domain.AssetGraphLocation{{Line: 1, Column: 30}},
+			Path:      []interface{}{"customers"},
+		}},
+	})
+	handler := GraphQLHandler{
+		AssetGraphQuerier: mockQuerier,
+		LogFn:             testLogFn,
+	}
+
+	response, err := handler.Handle(context.Background(), request)
+	require.NoError(t, err)
+	require.Equal(t, GraphQLResponse{
+		Data: data,
+		Errors: []GraphQLError{{
+			Message:   "failed to fetch the asset graph",
+			Locations: []GraphQLLocation{{Line: 1, Column: 30}},
+			Path:      []interface{}{"customers"},
+		}},
+	}, response)
+}
+
+func TestGraphQLHandlerEmptyQuery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := GraphQLHandler{
+		AssetGraphQuerier: NewMockAssetGraphQuerier(ctrl),
+		LogFn:             testLogFn,
+	}
+
+	_, err := handler.Handle(context.Background(), GraphQLRequest{Query: " \n"})
+	require.IsType(t, domain.InvalidInput{}, err)
+}
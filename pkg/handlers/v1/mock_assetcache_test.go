@@ -0,0 +1,46 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/asecurityteam/ipam-facade/pkg/domain (interfaces: AssetCacheInvalidator)
+
+// Package v1 is a generated GoMock package.
+package v1
+
+import (
+	context "context"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockAssetCacheInvalidator is a mock of AssetCacheInvalidator interface
+type MockAssetCacheInvalidator struct {
+	ctrl     *gomock.Controller
+	recorder *MockAssetCacheInvalidatorMockRecorder
+}
+
+// MockAssetCacheInvalidatorMockRecorder is the mock recorder for MockAssetCacheInvalidator
+type MockAssetCacheInvalidatorMockRecorder struct {
+	mock *MockAssetCacheInvalidator
+}
+
+// NewMockAssetCacheInvalidator creates a new mock instance
+func NewMockAssetCacheInvalidator(ctrl *gomock.Controller) *MockAssetCacheInvalidator {
+	mock := &MockAssetCacheInvalidator{ctrl: ctrl}
+	mock.recorder = &MockAssetCacheInvalidatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockAssetCacheInvalidator) EXPECT() *MockAssetCacheInvalidatorMockRecorder {
+	return m.recorder
+}
+
+// InvalidateAssetCache mocks base method
+func (m *MockAssetCacheInvalidator) InvalidateAssetCache(arg0 context.Context) error {
+	ret := m.ctrl.Call(m, "InvalidateAssetCache", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InvalidateAssetCache indicates an expected call of InvalidateAssetCache
+func (mr *MockAssetCacheInvalidatorMockRecorder) InvalidateAssetCache(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvalidateAssetCache", reflect.TypeOf((*MockAssetCacheInvalidator)(nil).InvalidateAssetCache), arg0)
+}
@@ -0,0 +1,47 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/asecurityteam/ipam-facade/pkg/domain (interfaces: AssetExporter)
+
+// Package v1 is a generated GoMock package.
+package v1
+
+import (
+	context "context"
+	domain "github.com/asecurityteam/ipam-facade/pkg/domain"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockAssetExporter is a mock of AssetExporter interface
+type MockAssetExporter struct {
+	ctrl     *gomock.Controller
+	recorder *MockAssetExporterMockRecorder
+}
+
+// MockAssetExporterMockRecorder is the mock recorder for MockAssetExporter
+type MockAssetExporterMockRecorder struct {
+	mock *MockAssetExporter
+}
+
+// NewMockAssetExporter creates a new mock instance
+func NewMockAssetExporter(ctrl *gomock.Controller) *MockAssetExporter {
+	mock := &MockAssetExporter{ctrl: ctrl}
+	mock.recorder = &MockAssetExporterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockAssetExporter) EXPECT() *MockAssetExporterMockRecorder {
+	return m.recorder
+}
+
+// ExportAssets mocks base method
+func (m *MockAssetExporter) ExportAssets(arg0 context.Context, arg1 domain.AssetExportKind, arg2 domain.AssetExportWriter) error {
+	ret := m.ctrl.Call(m, "ExportAssets", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportAssets indicates an expected call of ExportAssets
+func (mr *MockAssetExporterMockRecorder) ExportAssets(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportAssets", reflect.TypeOf((*MockAssetExporter)(nil).ExportAssets), arg0, arg1, arg2)
+}
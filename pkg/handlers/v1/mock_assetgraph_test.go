@@ -0,0 +1,47 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/asecurityteam/ipam-facade/pkg/domain (interfaces: AssetGraphQuerier)
+
+// Package v1 is a generated GoMock package.
+package v1
+
+import (
+	context "context"
+	domain "github.com/asecurityteam/ipam-facade/pkg/domain"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockAssetGraphQuerier is a mock of AssetGraphQuerier interface
+type MockAssetGraphQuerier struct {
+	ctrl     *gomock.Controller
+	recorder *MockAssetGraphQuerierMockRecorder
+}
+
+// MockAssetGraphQuerierMockRecorder is the mock recorder for MockAssetGraphQuerier
+type MockAssetGraphQuerierMockRecorder struct {
+	mock *MockAssetGraphQuerier
+}
+
+// NewMockAssetGraphQuerier creates a new mock instance
+func NewMockAssetGraphQuerier(ctrl *gomock.Controller) *MockAssetGraphQuerier {
+	mock := &MockAssetGraphQuerier{ctrl: ctrl}
+	mock.recorder = &MockAssetGraphQuerierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockAssetGraphQuerier) EXPECT() *MockAssetGraphQuerierMockRecorder {
+	return m.recorder
+}
+
+// QueryAssetGraph mocks base method
+func (m *MockAssetGraphQuerier) QueryAssetGraph(arg0 context.Context, arg1 domain.AssetGraphRequest) domain.AssetGraphResult {
+	ret := m.ctrl.Call(m, "QueryAssetGraph", arg0, arg1)
+	ret0, _ := ret[0].(domain.AssetGraphResult)
+	return ret0
+}
+
+// QueryAssetGraph indicates an expected call of QueryAssetGraph
+func (mr *MockAssetGraphQuerierMockRecorder) QueryAssetGraph(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryAssetGraph", reflect.TypeOf((*MockAssetGraphQuerier)(nil).QueryAssetGraph), arg0, arg1)
+}
@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/asecurityteam/ipam-facade/pkg/domain (interfaces: IPAMDataFetcher)
+// Source: github.com/asecurityteam/ipam-facade/pkg/domain (interfaces: IPAMDataFetcher,IPAMDataCommitter)
 
 // Package v1 is a generated GoMock package.
 package v1
@@ -46,3 +46,38 @@ func (m *MockIPAMDataFetcher) FetchIPAMData(arg0 context.Context) (domain.IPAMDa
 func (mr *MockIPAMDataFetcherMockRecorder) FetchIPAMData(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchIPAMData", reflect.TypeOf((*MockIPAMDataFetcher)(nil).FetchIPAMData), arg0)
 }
+
+// MockIPAMDataCommitter is a mock of IPAMDataCommitter interface
+type MockIPAMDataCommitter struct {
+	ctrl     *gomock.Controller
+	recorder *MockIPAMDataCommitterMockRecorder
+}
+
+// MockIPAMDataCommitterMockRecorder is the mock recorder for MockIPAMDataCommitter
+type MockIPAMDataCommitterMockRecorder struct {
+	mock *MockIPAMDataCommitter
+}
+
+// NewMockIPAMDataCommitter creates a new mock instance
+func NewMockIPAMDataCommitter(ctrl *gomock.Controller) *MockIPAMDataCommitter {
+	mock := &MockIPAMDataCommitter{ctrl: ctrl}
+	mock.recorder = &MockIPAMDataCommitterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockIPAMDataCommitter) EXPECT() *MockIPAMDataCommitterMockRecorder {
+	return m.recorder
+}
+
+// CommitIPAMData mocks base method
+func (m *MockIPAMDataCommitter) CommitIPAMData(arg0 context.Context) error {
+	ret := m.ctrl.Call(m, "CommitIPAMData", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CommitIPAMData indicates an expected call of CommitIPAMData
+func (mr *MockIPAMDataCommitterMockRecorder) CommitIPAMData(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CommitIPAMData", reflect.TypeOf((*MockIPAMDataCommitter)(nil).CommitIPAMData), arg0)
+}
@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/asecurityteam/ipam-facade/pkg/domain (interfaces: IPAMDataValidator)
+
+// Package v1 is a generated GoMock package.
+package v1
+
+import (
+	context "context"
+	domain "github.com/asecurityteam/ipam-facade/pkg/domain"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockIPAMDataValidator is a mock of IPAMDataValidator interface
+type MockIPAMDataValidator struct {
+	ctrl     *gomock.Controller
+	recorder *MockIPAMDataValidatorMockRecorder
+}
+
+// MockIPAMDataValidatorMockRecorder is the mock recorder for MockIPAMDataValidator
+type MockIPAMDataValidatorMockRecorder struct {
+	mock *MockIPAMDataValidator
+}
+
+// NewMockIPAMDataValidator creates a new mock instance
+func NewMockIPAMDataValidator(ctrl *gomock.Controller) *MockIPAMDataValidator {
+	mock := &MockIPAMDataValidator{ctrl: ctrl}
+	mock.recorder = &MockIPAMDataValidatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockIPAMDataValidator) EXPECT() *MockIPAMDataValidatorMockRecorder {
+	return m.recorder
+}
+
+// ValidateIPAMData mocks base method
+func (m *MockIPAMDataValidator) ValidateIPAMData(arg0 context.Context, arg1 domain.IPAMData) (domain.IPAMData, []domain.QuarantinedRecord) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateIPAMData", arg0, arg1)
+	ret0, _ := ret[0].(domain.IPAMData)
+	ret1, _ := ret[1].([]domain.QuarantinedRecord)
+	return ret0, ret1
+}
+
+// ValidateIPAMData indicates an expected call of ValidateIPAMData
+func (mr *MockIPAMDataValidatorMockRecorder) ValidateIPAMData(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateIPAMData", reflect.TypeOf((*MockIPAMDataValidator)(nil).ValidateIPAMData), arg0, arg1)
+}
@@ -0,0 +1,48 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/asecurityteam/ipam-facade/pkg/domain (interfaces: PrefixFetcher)
+
+// Package v1 is a generated GoMock package.
+package v1
+
+import (
+	context "context"
+	domain "github.com/asecurityteam/ipam-facade/pkg/domain"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockPrefixFetcher is a mock of PrefixFetcher interface
+type MockPrefixFetcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockPrefixFetcherMockRecorder
+}
+
+// MockPrefixFetcherMockRecorder is the mock recorder for MockPrefixFetcher
+type MockPrefixFetcherMockRecorder struct {
+	mock *MockPrefixFetcher
+}
+
+// NewMockPrefixFetcher creates a new mock instance
+func NewMockPrefixFetcher(ctrl *gomock.Controller) *MockPrefixFetcher {
+	mock := &MockPrefixFetcher{ctrl: ctrl}
+	mock.recorder = &MockPrefixFetcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockPrefixFetcher) EXPECT() *MockPrefixFetcherMockRecorder {
+	return m.recorder
+}
+
+// FetchPrefixes mocks base method
+func (m *MockPrefixFetcher) FetchPrefixes(arg0 context.Context, arg1 domain.PrefixFilter) ([]string, error) {
+	ret := m.ctrl.Call(m, "FetchPrefixes", arg0, arg1)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchPrefixes indicates an expected call of FetchPrefixes
+func (mr *MockPrefixFetcherMockRecorder) FetchPrefixes(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchPrefixes", reflect.TypeOf((*MockPrefixFetcher)(nil).FetchPrefixes), arg0, arg1)
+}
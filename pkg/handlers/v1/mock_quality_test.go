@@ -0,0 +1,154 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/asecurityteam/ipam-facade/pkg/domain (interfaces: QualityAnalyzer,QualityReportStorer,QualityReportFetcher,SyncJobReportFetcher)
+
+// Package v1 is a generated GoMock package.
+package v1
+
+import (
+	context "context"
+	domain "github.com/asecurityteam/ipam-facade/pkg/domain"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockQualityAnalyzer is a mock of QualityAnalyzer interface
+type MockQualityAnalyzer struct {
+	ctrl     *gomock.Controller
+	recorder *MockQualityAnalyzerMockRecorder
+}
+
+// MockQualityAnalyzerMockRecorder is the mock recorder for MockQualityAnalyzer
+type MockQualityAnalyzerMockRecorder struct {
+	mock *MockQualityAnalyzer
+}
+
+// NewMockQualityAnalyzer creates a new mock instance
+func NewMockQualityAnalyzer(ctrl *gomock.Controller) *MockQualityAnalyzer {
+	mock := &MockQualityAnalyzer{ctrl: ctrl}
+	mock.recorder = &MockQualityAnalyzerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockQualityAnalyzer) EXPECT() *MockQualityAnalyzerMockRecorder {
+	return m.recorder
+}
+
+// AnalyzeIPAMData mocks base method
+func (m *MockQualityAnalyzer) AnalyzeIPAMData(arg0 context.Context, arg1 domain.IPAMData) []domain.QualityFinding {
+	ret := m.ctrl.Call(m, "AnalyzeIPAMData", arg0, arg1)
+	ret0, _ := ret[0].([]domain.QualityFinding)
+	return ret0
+}
+
+// AnalyzeIPAMData indicates an expected call of AnalyzeIPAMData
+func (mr *MockQualityAnalyzerMockRecorder) AnalyzeIPAMData(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnalyzeIPAMData", reflect.TypeOf((*MockQualityAnalyzer)(nil).AnalyzeIPAMData), arg0, arg1)
+}
+
+// MockQualityReportStorer is a mock of QualityReportStorer interface
+type MockQualityReportStorer struct {
+	ctrl     *gomock.Controller
+	recorder *MockQualityReportStorerMockRecorder
+}
+
+// MockQualityReportStorerMockRecorder is the mock recorder for MockQualityReportStorer
+type MockQualityReportStorerMockRecorder struct {
+	mock *MockQualityReportStorer
+}
+
+// NewMockQualityReportStorer creates a new mock instance
+func NewMockQualityReportStorer(ctrl *gomock.Controller) *MockQualityReportStorer {
+	mock := &MockQualityReportStorer{ctrl: ctrl}
+	mock.recorder = &MockQualityReportStorerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockQualityReportStorer) EXPECT() *MockQualityReportStorerMockRecorder {
+	return m.recorder
+}
+
+// StoreQualityReport mocks base method
+func (m *MockQualityReportStorer) StoreQualityReport(arg0 context.Context, arg1 domain.QualityReport) error {
+	ret := m.ctrl.Call(m, "StoreQualityReport", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StoreQualityReport indicates an expected call of StoreQualityReport
+func (mr *MockQualityReportStorerMockRecorder) StoreQualityReport(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreQualityReport", reflect.TypeOf((*MockQualityReportStorer)(nil).StoreQualityReport), arg0, arg1)
+}
+
+// MockQualityReportFetcher is a mock of QualityReportFetcher interface
+type MockQualityReportFetcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockQualityReportFetcherMockRecorder
+}
+
+// MockQualityReportFetcherMockRecorder is the mock recorder for MockQualityReportFetcher
+type MockQualityReportFetcherMockRecorder struct {
+	mock *MockQualityReportFetcher
+}
+
+// NewMockQualityReportFetcher creates a new mock instance
+func NewMockQualityReportFetcher(ctrl *gomock.Controller) *MockQualityReportFetcher {
+	mock := &MockQualityReportFetcher{ctrl: ctrl}
+	mock.recorder = &MockQualityReportFetcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockQualityReportFetcher) EXPECT() *MockQualityReportFetcherMockRecorder {
+	return m.recorder
+}
+
+// FetchQualityReport mocks base method
+func (m *MockQualityReportFetcher) FetchQualityReport(arg0 context.Context, arg1 string) (domain.QualityReport, error) {
+	ret := m.ctrl.Call(m, "FetchQualityReport", arg0, arg1)
+	ret0, _ := ret[0].(domain.QualityReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchQualityReport indicates an expected call of FetchQualityReport
+func (mr *MockQualityReportFetcherMockRecorder) FetchQualityReport(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchQualityReport", reflect.TypeOf((*MockQualityReportFetcher)(nil).FetchQualityReport), arg0, arg1)
+}
+
+// MockSyncJobReportFetcher is a mock of SyncJobReportFetcher interface
+type MockSyncJobReportFetcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockSyncJobReportFetcherMockRecorder
+}
+
+// MockSyncJobReportFetcherMockRecorder is the mock recorder for MockSyncJobReportFetcher
+type MockSyncJobReportFetcherMockRecorder struct {
+	mock *MockSyncJobReportFetcher
+}
+
+// NewMockSyncJobReportFetcher creates a new mock instance
+func NewMockSyncJobReportFetcher(ctrl *gomock.Controller) *MockSyncJobReportFetcher {
+	mock := &MockSyncJobReportFetcher{ctrl: ctrl}
+	mock.recorder = &MockSyncJobReportFetcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockSyncJobReportFetcher) EXPECT() *MockSyncJobReportFetcherMockRecorder {
+	return m.recorder
+}
+
+// FetchSyncJobReport mocks base method
+func (m *MockSyncJobReportFetcher) FetchSyncJobReport(arg0 context.Context, arg1 string) (domain.QualityReport, error) {
+	ret := m.ctrl.Call(m, "FetchSyncJobReport", arg0, arg1)
+	ret0, _ := ret[0].(domain.QualityReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchSyncJobReport indicates an expected call of FetchSyncJobReport
+func (mr *MockSyncJobReportFetcherMockRecorder) FetchSyncJobReport(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchSyncJobReport", reflect.TypeOf((*MockSyncJobReportFetcher)(nil).FetchSyncJobReport), arg0, arg1)
+}
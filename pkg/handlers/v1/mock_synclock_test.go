@@ -0,0 +1,47 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/asecurityteam/ipam-facade/pkg/domain (interfaces: SyncLocker)
+
+// Package v1 is a generated GoMock package.
+package v1
+
+import (
+	context "context"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockSyncLocker is a mock of SyncLocker interface
+type MockSyncLocker struct {
+	ctrl     *gomock.Controller
+	recorder *MockSyncLockerMockRecorder
+}
+
+// MockSyncLockerMockRecorder is the mock recorder for MockSyncLocker
+type MockSyncLockerMockRecorder struct {
+	mock *MockSyncLocker
+}
+
+// NewMockSyncLocker creates a new mock instance
+func NewMockSyncLocker(ctrl *gomock.Controller) *MockSyncLocker {
+	mock := &MockSyncLocker{ctrl: ctrl}
+	mock.recorder = &MockSyncLockerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockSyncLocker) EXPECT() *MockSyncLockerMockRecorder {
+	return m.recorder
+}
+
+// LockSync mocks base method
+func (m *MockSyncLocker) LockSync(arg0 context.Context) (func(), error) {
+	ret := m.ctrl.Call(m, "LockSync", arg0)
+	ret0, _ := ret[0].(func())
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockSync indicates an expected call of LockSync
+func (mr *MockSyncLockerMockRecorder) LockSync(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockSync", reflect.TypeOf((*MockSyncLocker)(nil).LockSync), arg0)
+}
@@ -0,0 +1,291 @@
+package v1
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+	"github.com/asecurityteam/ipam-facade/pkg/logs"
+)
+
+const defaultPrefixListName = "ipamfacade"
+
+// prefixListName is the form of a prefix list name that every format accepts. The IPv4 and
+// IPv6 sets of the ipset and nftables formats add a suffix of 3 characters, and ipset names
+// are limited to 31 characters.
+var prefixListName = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]{0,27}$`)
+
+// invalidNameCharacters are the runs of characters replaced when a prefix list is named after
+// a business unit.
+var invalidNameCharacters = regexp.MustCompile(`[^a-z0-9]+`)
+
+// prefixListFormat renders a prefix list named name.
+type prefixListFormat struct {
+	contentType string
+	render      func(w io.Writer, name string, prefixes []string, now time.Time) error
+}
+
+var prefixListFormats = map[string]prefixListFormat{
+	"cisco":    {contentType: "text/plain; charset=utf-8", render: renderCisco},
+	"juniper":  {contentType: "text/plain; charset=utf-8", render: renderJuniper},
+	"ipset":    {contentType: "text/plain; charset=utf-8", render: renderIPSet},
+	"nftables": {contentType: "text/plain; charset=utf-8", render: renderNFTables},
+	"aws":      {contentType: "application/json", render: renderAWS},
+	"gcp":      {contentType: "application/json", render: renderGCP},
+}
+
+// PrefixListHandler uses its PrefixFetcher implementation to render the aggregated networks
+// of the subnets selected by the businessUnit, resourceOwner, location, and within query
+// parameters in the format named by the last element of the request path: Cisco or Juniper
+// prefix lists, an ipset restore file, an nftables set, or an AWS or GCP style JSON list. The
+// list is named by the name query parameter, or after the business unit. Like the
+// ExportHandler, it serves HTTP requests directly, because its responses are not JSON.
+type PrefixListHandler struct {
+	PrefixFetcher domain.PrefixFetcher
+	LogFn         domain.LogFn
+
+	now func() time.Time
+}
+
+// ServeHTTP renders a prefix list, or returns a JSON Error response.
+func (h *PrefixListHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := h.LogFn(ctx)
+
+	formatName := path.Base(r.URL.Path)
+	format, ok := prefixListFormats[formatName]
+	if !ok {
+		err := fmt.Errorf("unknown prefix list format %q, expected cisco, juniper, ipset, nftables, aws, or gcp", formatName)
+		logger.Info(logs.InvalidInput{Reason: err.Error()})
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	query := r.URL.Query()
+	filter := domain.PrefixFilter{
+		BusinessUnit:  query.Get("businessUnit"),
+		ResourceOwner: query.Get("resourceOwner"),
+		Location:      query.Get("location"),
+		Within:        query.Get("within"),
+	}
+	name := query.Get("name")
+	if name == "" {
+		name = nameAfter(filter.BusinessUnit)
+	}
+	if !prefixListName.MatchString(name) {
+		err := domain.InvalidInput{Input: "prefix list name " + strconv.Quote(name)}
+		logger.Info(logs.InvalidInput{Reason: err.Error()})
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	prefixes, err := h.PrefixFetcher.FetchPrefixes(ctx, filter)
+	switch err.(type) {
+	case nil:
+	case domain.InvalidInput:
+		logger.Info(logs.InvalidInput{Reason: err.Error()})
+		writeError(w, http.StatusBadRequest, err)
+		return
+	default:
+		logger.Error(logs.AssetExportFailure{Reason: err.Error(), Kind: "prefixes"})
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	var body bytes.Buffer
+	if err := format.render(&body, name, prefixes, h.currentTime()); err != nil {
+		logger.Error(logs.AssetExportFailure{Reason: err.Error(), Kind: "prefixes"})
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Content-Type", format.contentType)
+	_, _ = body.WriteTo(w)
+}
+
+func (h *PrefixListHandler) currentTime() time.Time {
+	if h.now == nil {
+		return time.Now()
+	}
+	return h.now()
+}
+
+// nameAfter names a prefix list after a business unit, such as payments_platform for
+// "Payments Platform".
+func nameAfter(businessUnit string) string {
+	name := strings.Trim(invalidNameCharacters.ReplaceAllString(strings.ToLower(businessUnit), "_"), "_")
+	if name == "" {
+		return defaultPrefixListName
+	}
+	if name[0] < 'a' || name[0] > 'z' {
+		name = "bu_" + name
+	}
+	if len(name) > 28 {
+		name = strings.TrimRight(name[:28], "_")
+	}
+	return name
+}
+
+// splitFamilies returns the IPv4 and the IPv6 prefixes.
+func splitFamilies(prefixes []string) ([]string, []string) {
+	v4 := make([]string, 0, len(prefixes))
+	v6 := make([]string, 0)
+	for _, prefix := range prefixes {
+		if strings.Contains(prefix, ":") {
+			v6 = append(v6, prefix)
+		} else {
+			v4 = append(v4, prefix)
+		}
+	}
+	return v4, v6
+}
+
+// renderCisco renders IOS prefix lists, ip prefix-list for IPv4 and ipv6 prefix-list for IPv6.
+func renderCisco(w io.Writer, name string, prefixes []string, _ time.Time) error {
+	v4, v6 := splitFamilies(prefixes)
+	for _, list := range []struct {
+		command  string
+		prefixes []string
+	}{{"ip", v4}, {"ipv6", v6}} {
+		for i, prefix := range list.prefixes {
+			if _, err := fmt.Fprintf(w, "%s prefix-list %s seq %d permit %s\n", list.command, name, 5*(i+1), prefix); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// renderJuniper renders a Junos policy-options prefix list as set commands.
+func renderJuniper(w io.Writer, name string, prefixes []string, _ time.Time) error {
+	if len(prefixes) == 0 {
+		_, err := fmt.Fprintf(w, "set policy-options prefix-list %s\n", name)
+		return err
+	}
+	for _, prefix := range prefixes {
+		if _, err := fmt.Fprintf(w, "set policy-options prefix-list %s %s\n", name, prefix); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderIPSet renders an ipset restore file with a hash:net set for each family, named with
+// the _v4 and _v6 suffixes. Both sets are created even when empty, so that firewall rules
+// may refer to them, and flushed before the prefixes are added, so that restoring the file
+// over a previous one drops the prefixes that are no longer listed.
+func renderIPSet(w io.Writer, name string, prefixes []string, _ time.Time) error {
+	v4, v6 := splitFamilies(prefixes)
+	for _, set := range []struct {
+		suffix   string
+		family   string
+		prefixes []string
+	}{{"_v4", "inet", v4}, {"_v6", "inet6", v6}} {
+		if _, err := fmt.Fprintf(w, "create %s%s hash:net family %s -exist\n", name, set.suffix, set.family); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "flush %s%s\n", name, set.suffix); err != nil {
+			return err
+		}
+		for _, prefix := range set.prefixes {
+			if _, err := fmt.Fprintf(w, "add %s%s %s -exist\n", name, set.suffix, prefix); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// renderNFTables renders a named interval set for each family, with the _v4 and _v6 suffixes,
+// in the inet filter table, to be loaded with nft -f. Elements declared in a set are added to
+// those it already has, so the sets are declared empty and flushed before their elements are
+// added, and loading the file over a previous one drops the prefixes that are no longer listed.
+// nft -f applies the file in a single transaction, so the sets are never seen empty.
+func renderNFTables(w io.Writer, name string, prefixes []string, _ time.Time) error {
+	v4, v6 := splitFamilies(prefixes)
+	sets := []struct {
+		suffix   string
+		addrType string
+		prefixes []string
+	}{{"_v4", "ipv4_addr", v4}, {"_v6", "ipv6_addr", v6}}
+	var b strings.Builder
+	b.WriteString("table inet filter {\n")
+	for _, set := range sets {
+		fmt.Fprintf(&b, "\tset %s%s {\n\t\ttype %s\n\t\tflags interval\n\t}\n", name, set.suffix, set.addrType)
+	}
+	b.WriteString("}\n")
+	for _, set := range sets {
+		fmt.Fprintf(&b, "flush set inet filter %s%s\n", name, set.suffix)
+	}
+	for _, set := range sets {
+		// nftables rejects an empty list of elements
+		if len(set.prefixes) > 0 {
+			fmt.Fprintf(&b, "add element inet filter %s%s { %s }\n", name, set.suffix, strings.Join(set.prefixes, ", "))
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+type awsPrefix struct {
+	IPPrefix string `json:"ip_prefix"`
+}
+
+type awsIPv6Prefix struct {
+	IPv6Prefix string `json:"ipv6_prefix"`
+}
+
+// renderAWS renders a list in the form of the AWS ip-ranges.json file.
+func renderAWS(w io.Writer, _ string, prefixes []string, now time.Time) error {
+	v4, v6 := splitFamilies(prefixes)
+	list := struct {
+		SyncToken    string          `json:"syncToken"`
+		CreateDate   string          `json:"createDate"`
+		Prefixes     []awsPrefix     `json:"prefixes"`
+		IPv6Prefixes []awsIPv6Prefix `json:"ipv6_prefixes"`
+	}{
+		SyncToken:    strconv.FormatInt(now.Unix(), 10),
+		CreateDate:   now.UTC().Format("2006-01-02-15-04-05"),
+		Prefixes:     make([]awsPrefix, 0, len(v4)),
+		IPv6Prefixes: make([]awsIPv6Prefix, 0, len(v6)),
+	}
+	for _, prefix := range v4 {
+		list.Prefixes = append(list.Prefixes, awsPrefix{IPPrefix: prefix})
+	}
+	for _, prefix := range v6 {
+		list.IPv6Prefixes = append(list.IPv6Prefixes, awsIPv6Prefix{IPv6Prefix: prefix})
+	}
+	return json.NewEncoder(w).Encode(list)
+}
+
+type gcpPrefix struct {
+	IPv4Prefix string `json:"ipv4Prefix,omitempty"`
+	IPv6Prefix string `json:"ipv6Prefix,omitempty"`
+}
+
+// renderGCP renders a list in the form of the Google Cloud cloud.json file.
+func renderGCP(w io.Writer, _ string, prefixes []string, now time.Time) error {
+	v4, v6 := splitFamilies(prefixes)
+	list := struct {
+		SyncToken    string      `json:"syncToken"`
+		CreationTime string      `json:"creationTime"`
+		Prefixes     []gcpPrefix `json:"prefixes"`
+	}{
+		SyncToken:    strconv.FormatInt(now.UnixNano()/int64(time.Millisecond), 10),
+		CreationTime: now.UTC().Format("2006-01-02T15:04:05.000000"),
+		Prefixes:     make([]gcpPrefix, 0, len(prefixes)),
+	}
+	for _, prefix := range v4 {
+		list.Prefixes = append(list.Prefixes, gcpPrefix{IPv4Prefix: prefix})
+	}
+	for _, prefix := range v6 {
+		list.Prefixes = append(list.Prefixes, gcpPrefix{IPv6Prefix: prefix})
+	}
+	return json.NewEncoder(w).Encode(list)
+}
@@ -0,0 +1,213 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+	gomock "github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+var testPrefixes = []string{"10.0.0.0/23", "10.0.2.0/24", "2001:db8::/48"}
+
+func TestPrefixListHandler(t *testing.T) {
+	tc := []struct {
+		name        string
+		path        string
+		filter      domain.PrefixFilter
+		prefixes    []string
+		contentType string
+		body        string
+	}{
+		{
+			name:        "cisco",
+			path:        "/v1/export/prefixes/cisco?businessUnit=Payments",
+			filter:      domain.PrefixFilter{BusinessUnit: "Payments"},
+			prefixes:    testPrefixes,
+			contentType: "text/plain; charset=utf-8",
+			body: "ip prefix-list payments seq 5 permit 10.0.0.0/23\n" +
+				"ip prefix-list payments seq 10 permit 10.0.2.0/24\n" +
+				"ipv6 prefix-list payments seq 5 permit 2001:db8::/48\n",
+		},
+		{
+			name:        "juniper",
+			path:        "/v1/export/prefixes/juniper?businessUnit=Payments+Platform&location=DC1",
+			filter:      domain.PrefixFilter{BusinessUnit: "Payments Platform", Location: "DC1"},
+			prefixes:    testPrefixes,
+			contentType: "text/plain; charset=utf-8",
+			body: "set policy-options prefix-list payments_platform 10.0.0.0/23\n" +
+				"set policy-options prefix-list payments_platform 10.0.2.0/24\n" +
+				"set policy-options prefix-list payments_platform 2001:db8::/48\n",
+		},
+		{
+			name:        "juniper without prefixes",
+			path:        "/v1/export/prefixes/juniper?resourceOwner=alice%40example.com&name=Alice",
+			filter:      domain.PrefixFilter{ResourceOwner: "alice@example.com"},
+			prefixes:    []string{},
+			contentType: "text/plain; charset=utf-8",
+			body:        "set policy-options prefix-list Alice\n",
+		},
+		{
+			name:        "ipset",
+			path:        "/v1/export/prefixes/ipset?within=10.0.0.0%2F8",
+			filter:      domain.PrefixFilter{Within: "10.0.0.0/8"},
+			prefixes:    testPrefixes[:2],
+			contentType: "text/plain; charset=utf-8",
+			body: "create ipamfacade_v4 hash:net family inet -exist\n" +
+				"flush ipamfacade_v4\n" +
+				"add ipamfacade_v4 10.0.0.0/23 -exist\n" +
+				"add ipamfacade_v4 10.0.2.0/24 -exist\n" +
+				"create ipamfacade_v6 hash:net family inet6 -exist\n" +
+				"flush ipamfacade_v6\n",
+		},
+		{
+			name:        "nftables",
+			path:        "/v1/export/prefixes/nftables?businessUnit=2nd+Line",
+			filter:      domain.PrefixFilter{BusinessUnit: "2nd Line"},
+			prefixes:    testPrefixes,
+			contentType: "text/plain; charset=utf-8",
+			body: "table inet filter {\n" +
+				"\tset bu_2nd_line_v4 {\n\t\ttype ipv4_addr\n\t\tflags interval\n\t}\n" +
+				"\tset bu_2nd_line_v6 {\n\t\ttype ipv6_addr\n\t\tflags interval\n\t}\n" +
+				"}\n" +
+				"flush set inet filter bu_2nd_line_v4\n" +
+				"flush set inet filter bu_2nd_line_v6\n" +
+				"add element inet filter bu_2nd_line_v4 { 10.0.0.0/23, 10.0.2.0/24 }\n" +
+				"add element inet filter bu_2nd_line_v6 { 2001:db8::/48 }\n",
+		},
+		{
+			name:        "nftables without prefixes",
+			path:        "/v1/export/prefixes/nftables?name=empty",
+			prefixes:    []string{},
+			contentType: "text/plain; charset=utf-8",
+			body: "table inet filter {\n" +
+				"\tset empty_v4 {\n\t\ttype ipv4_addr\n\t\tflags interval\n\t}\n" +
+				"\tset empty_v6 {\n\t\ttype ipv6_addr\n\t\tflags interval\n\t}\n" +
+				"}\n" +
+				"flush set inet filter empty_v4\n" +
+				"flush set inet filter empty_v6\n",
+		},
+		{
+			name:        "aws",
+			path:        "/v1/export/prefixes/aws?businessUnit=Payments",
+			filter:      domain.PrefixFilter{BusinessUnit: "Payments"},
+			prefixes:    testPrefixes,
+			contentType: "application/json",
+			body: `{"syncToken":"1790856000","createDate":"2026-10-01-12-00-00",` +
+				`"prefixes":[{"ip_prefix":"10.0.0.0/23"},{"ip_prefix":"10.0.2.0/24"}],` +
+				`"ipv6_prefixes":[{"ipv6_prefix":"2001:db8::/48"}]}` + "\n",
+		},
+		{
+			name:        "aws without prefixes",
+			path:        "/v1/export/prefixes/aws",
+			prefixes:    []string{},
+			contentType: "application/json",
+			body:        `{"syncToken":"1790856000","createDate":"2026-10-01-12-00-00","prefixes":[],"ipv6_prefixes":[]}` + "\n",
+		},
+		{
+			name:        "gcp",
+			path:        "/v1/export/prefixes/gcp?businessUnit=Payments",
+			filter:      domain.PrefixFilter{BusinessUnit: "Payments"},
+			prefixes:    testPrefixes,
+			contentType: "application/json",
+			body: `{"syncToken":"1790856000000","creationTime":"2026-10-01T12:00:00.000000",` +
+				`"prefixes":[{"ipv4Prefix":"10.0.0.0/23"},{"ipv4Prefix":"10.0.2.0/24"},{"ipv6Prefix":"2001:db8::/48"}]}` + "\n",
+		},
+	}
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockFetcher := NewMockPrefixFetcher(ctrl)
+			mockFetcher.EXPECT().FetchPrefixes(gomock.Any(), tt.filter).Return(tt.prefixes, nil)
+			handler := &PrefixListHandler{
+				PrefixFetcher: mockFetcher,
+				LogFn:         testLogFn,
+				now:           func() time.Time { return time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC) },
+			}
+
+			response := httptest.NewRecorder()
+			handler.ServeHTTP(response, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			require.Equal(t, http.StatusOK, response.Code)
+			require.Equal(t, tt.contentType, response.Header().Get("Content-Type"))
+			require.Equal(t, tt.body, response.Body.String())
+		})
+	}
+}
+
+func TestPrefixListHandlerInvalidRequest(t *testing.T) {
+	tc := []struct {
+		name string
+		path string
+		code int
+	}{
+		{name: "unknown format", path: "/v1/export/prefixes/mikrotik", code: http.StatusNotFound},
+		{name: "name with spaces", path: "/v1/export/prefixes/cisco?name=a+b", code: http.StatusBadRequest},
+		{name: "name starting with a digit", path: "/v1/export/prefixes/cisco?name=1st", code: http.StatusBadRequest},
+		{name: "name too long", path: "/v1/export/prefixes/ipset?name=abcdefghijklmnopqrstuvwxyz012", code: http.StatusBadRequest},
+	}
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			handler := &PrefixListHandler{PrefixFetcher: NewMockPrefixFetcher(ctrl), LogFn: testLogFn}
+			response := httptest.NewRecorder()
+			handler.ServeHTTP(response, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			require.Equal(t, tt.code, response.Code)
+			require.Equal(t, "application/json", response.Header().Get("Content-Type"))
+		})
+	}
+}
+
+func TestPrefixListHandlerFetchError(t *testing.T) {
+	tc := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{name: "invalid input", err: domain.InvalidInput{Input: "network 10.0.0.1/8"}, code: http.StatusBadRequest},
+		{name: "storage error", err: errors.New("connection refused"), code: http.StatusInternalServerError},
+	}
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockFetcher := NewMockPrefixFetcher(ctrl)
+			mockFetcher.EXPECT().FetchPrefixes(gomock.Any(), gomock.Any()).Return(nil, tt.err)
+			handler := &PrefixListHandler{PrefixFetcher: mockFetcher, LogFn: testLogFn}
+
+			response := httptest.NewRecorder()
+			handler.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/v1/export/prefixes/cisco", nil))
+			require.Equal(t, tt.code, response.Code)
+		})
+	}
+}
+
+func TestNameAfter(t *testing.T) {
+	tc := []struct {
+		businessUnit string
+		expected     string
+	}{
+		{businessUnit: "", expected: "ipamfacade"},
+		{businessUnit: "Payments", expected: "payments"},
+		{businessUnit: " Payments & Billing ", expected: "payments_billing"},
+		{businessUnit: "42", expected: "bu_42"},
+		{businessUnit: "!!!", expected: "ipamfacade"},
+		{businessUnit: "Infrastructure Engineering Platform", expected: "infrastructure_engineering_p"},
+		{businessUnit: "Infrastructure Engineerings Platform", expected: "infrastructure_engineerings"},
+	}
+	for _, tt := range tc {
+		t.Run(tt.businessUnit, func(t *testing.T) {
+			name := nameAfter(tt.businessUnit)
+			require.Equal(t, tt.expected, name)
+			require.Regexp(t, prefixListName, name)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package v1
+
+import (
+	"context"
+	"time"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+	"github.com/asecurityteam/ipam-facade/pkg/logs"
+)
+
+// qualityCategories are the data quality finding categories that may be requested.
+var qualityCategories = map[string]bool{
+	domain.QualitySubnetWithoutCustomer: true,
+	domain.QualityCustomerEmptyOwner:    true,
+	domain.QualityIPUnknownSubnet:       true,
+	domain.QualityOverlappingSubnets:    true,
+	domain.QualityInvalidSubnet:         true,
+	domain.QualitySourceConflict:        true,
+}
+
+// QualityReportRequest contains an optional data quality category by which to filter findings.
+type QualityReportRequest struct {
+	Category string `json:"category"`
+}
+
+// QualityReportResponse provides the response structure for the data quality report of the most
+// recent IPAM data sync. Summary counts the returned findings by category. GeneratedAt is empty
+// if no sync has completed yet.
+type QualityReportResponse struct {
+	GeneratedAt string           `json:"generatedAt"`
+	Summary     map[string]int   `json:"summary"`
+	Findings    []QualityFinding `json:"findings"`
+}
+
+// QualityFinding provides the response structure for a single data quality finding.
+type QualityFinding struct {
+	Category   string `json:"category"`
+	RecordType string `json:"recordType"`
+	RecordID   string `json:"recordID"`
+	Detail     string `json:"detail"`
+}
+
+// QualityReportHandler uses its QualityReportFetcher implementation to serve requests
+// for the data quality report.
+type QualityReportHandler struct {
+	QualityReportFetcher domain.QualityReportFetcher
+	LogFn                domain.LogFn
+}
+
+// Handle processes an incoming QualityReportRequest and returns a QualityReportResponse or an error.
+func (h *QualityReportHandler) Handle(ctx context.Context, input QualityReportRequest) (QualityReportResponse, error) {
+	logger := h.LogFn(ctx)
+
+	if input.Category != "" && !qualityCategories[input.Category] {
+		err := domain.InvalidInput{Input: input.Category}
+		logger.Info(logs.InvalidInput{Reason: err.Error()})
+		return QualityReportResponse{}, err
+	}
+
+	report, err := h.QualityReportFetcher.FetchQualityReport(ctx, input.Category)
+	if err != nil {
+		logger.Error(logs.AssetFetcherFailure{Reason: err.Error()})
+		return QualityReportResponse{}, err
+	}
+
+	response := QualityReportResponse{
+		Summary:  make(map[string]int),
+		Findings: make([]QualityFinding, 0, len(report.Findings)),
+	}
+	if !report.GeneratedAt.IsZero() {
+		response.GeneratedAt = report.GeneratedAt.UTC().Format(time.RFC3339)
+	}
+	for _, finding := range report.Findings {
+		response.Summary[finding.Category]++
+		response.Findings = append(response.Findings, QualityFinding(finding))
+	}
+	return response, nil
+}
@@ -0,0 +1,111 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+	gomock "github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQualityReportHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	report := domain.QualityReport{
+		GeneratedAt: time.Date(2019, 7, 1, 12, 0, 0, 0, time.FixedZone("EST", -5*60*60)),
+		Findings: []domain.QualityFinding{
+			{Category: domain.QualitySubnetWithoutCustomer, RecordType: domain.QualityRecordSubnet, RecordID: "1", Detail: "subnet 10.0.0.0/24 has no customer"},
+			{Category: domain.QualitySubnetWithoutCustomer, RecordType: domain.QualityRecordSubnet, RecordID: "2", Detail: "subnet 10.0.1.0/24 has no customer"},
+			{Category: domain.QualityCustomerEmptyOwner, RecordType: domain.QualityRecordCustomer, RecordID: "3", Detail: "customer has no resource owner"},
+		},
+	}
+	expected := QualityReportResponse{
+		GeneratedAt: "2019-07-01T17:00:00Z",
+		Summary: map[string]int{
+			domain.QualitySubnetWithoutCustomer: 2,
+			domain.QualityCustomerEmptyOwner:    1,
+		},
+		Findings: []QualityFinding{
+			{Category: domain.QualitySubnetWithoutCustomer, RecordType: domain.QualityRecordSubnet, RecordID: "1", Detail: "subnet 10.0.0.0/24 has no customer"},
+			{Category: domain.QualitySubnetWithoutCustomer, RecordType: domain.QualityRecordSubnet, RecordID: "2", Detail: "subnet 10.0.1.0/24 has no customer"},
+			{Category: domain.QualityCustomerEmptyOwner, RecordType: domain.QualityRecordCustomer, RecordID: "3", Detail: "customer has no resource owner"},
+		},
+	}
+
+	mockFetcher := NewMockQualityReportFetcher(ctrl)
+	mockFetcher.EXPECT().FetchQualityReport(gomock.Any(), "").Return(report, nil)
+	handler := QualityReportHandler{
+		QualityReportFetcher: mockFetcher,
+		LogFn:                testLogFn,
+	}
+
+	response, err := handler.Handle(context.Background(), QualityReportRequest{})
+	require.NoError(t, err)
+	require.Equal(t, expected, response)
+}
+
+func TestQualityReportHandlerNoReport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFetcher := NewMockQualityReportFetcher(ctrl)
+	mockFetcher.EXPECT().FetchQualityReport(gomock.Any(), domain.QualityInvalidSubnet).Return(domain.QualityReport{}, nil)
+	handler := QualityReportHandler{
+		QualityReportFetcher: mockFetcher,
+		LogFn:                testLogFn,
+	}
+
+	response, err := handler.Handle(context.Background(), QualityReportRequest{Category: domain.QualityInvalidSubnet})
+	require.NoError(t, err)
+	require.Equal(t, QualityReportResponse{Summary: map[string]int{}, Findings: []QualityFinding{}}, response)
+}
+
+func TestQualityReportHandlerSourceConflict(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	finding := domain.QualityFinding{Category: domain.QualitySourceConflict, RecordType: domain.QualityRecordSubnet, RecordID: "1", Detail: "sources disagree about the resource owner of subnet 10.0.0.0/24"}
+	mockFetcher := NewMockQualityReportFetcher(ctrl)
+	mockFetcher.EXPECT().FetchQualityReport(gomock.Any(), domain.QualitySourceConflict).Return(domain.QualityReport{Findings: []domain.QualityFinding{finding}}, nil)
+	handler := QualityReportHandler{
+		QualityReportFetcher: mockFetcher,
+		LogFn:                testLogFn,
+	}
+
+	response, err := handler.Handle(context.Background(), QualityReportRequest{Category: domain.QualitySourceConflict})
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{domain.QualitySourceConflict: 1}, response.Summary)
+	require.Equal(t, []QualityFinding{{Category: finding.Category, RecordType: finding.RecordType, RecordID: finding.RecordID, Detail: finding.Detail}}, response.Findings)
+}
+
+func TestQualityReportHandlerInvalidCategory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := QualityReportHandler{
+		QualityReportFetcher: NewMockQualityReportFetcher(ctrl),
+		LogFn:                testLogFn,
+	}
+
+	_, err := handler.Handle(context.Background(), QualityReportRequest{Category: "unknown"})
+	require.IsType(t, domain.InvalidInput{}, err)
+}
+
+func TestQualityReportHandlerFetchError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFetcher := NewMockQualityReportFetcher(ctrl)
+	mockFetcher.EXPECT().FetchQualityReport(gomock.Any(), "").Return(domain.QualityReport{}, errors.New("boom"))
+	handler := QualityReportHandler{
+		QualityReportFetcher: mockFetcher,
+		LogFn:                testLogFn,
+	}
+
+	_, err := handler.Handle(context.Background(), QualityReportRequest{})
+	require.Error(t, err)
+}
@@ -7,32 +7,115 @@ import (
 	"github.com/asecurityteam/ipam-facade/pkg/logs"
 )
 
+// SyncResult provides the response structure for a completed sync, listing the records
+// that failed validation and were left out of local storage.
+type SyncResult struct {
+	JobID       string              `json:"jobId"`
+	Quarantined []QuarantinedRecord `json:"quarantined"`
+}
+
+// QuarantinedRecord provides the response structure for a record that failed validation.
+type QuarantinedRecord struct {
+	RecordType string `json:"recordType"`
+	RecordID   string `json:"recordID"`
+	Reason     string `json:"reason"`
+}
+
 // SyncIPAMDataHandler uses its IPAMDataFetcher implementation to serve sync requests
-// for refreshing the local IPAM data from the CMDB data source.
+// for refreshing the local IPAM data from the CMDB data source. When an AssetCacheInvalidator
+// is set, the cached lookups are invalidated whenever new data is stored. When the
+// IPAMDataFetcher is also a domain.IPAMDataCommitter, its fetch is committed once the fetched
+// data is stored, so that it only moves past stored data. When a SyncLocker
+// is set, a sync is not started while another one is running, and fails with
+// domain.SyncInProgress instead.
 type SyncIPAMDataHandler struct {
-	IPAMDataFetcher     domain.IPAMDataFetcher
-	PhysicalAssetStorer domain.PhysicalAssetStorer
-	LogFn               domain.LogFn
+	IPAMDataFetcher       domain.IPAMDataFetcher
+	IPAMDataValidator     domain.IPAMDataValidator
+	PhysicalAssetStorer   domain.PhysicalAssetStorer
+	QualityAnalyzer       domain.QualityAnalyzer
+	QualityReportStorer   domain.QualityReportStorer
+	AssetCacheInvalidator domain.AssetCacheInvalidator
+	SyncLocker            domain.SyncLocker
+	LogFn                 domain.LogFn
 }
 
-// Handle fetches IPAM data from a CMDB and stores the data locally.
+// Handle syncs the IPAM data as Sync does, without a result, for the unversioned /sync
+// endpoint that responds with no content on success, and for queued sync jobs. The result
+// is kept with the quality report of the sync, where SyncJobHandler reads it by job ID.
 func (h *SyncIPAMDataHandler) Handle(ctx context.Context, jobMetadata JobMetadata) error {
+	_, err := h.Sync(ctx, jobMetadata)
+	return err
+}
+
+// Sync fetches IPAM data from a CMDB, quarantines the records that fail validation, and
+// stores the remaining data locally. The fetched data, including any quarantined records,
+// is then analyzed and its data quality report is stored with the quarantined records, whether
+// or not the data itself could be stored, as bad source data is a common reason for the store
+// to fail.
+func (h *SyncIPAMDataHandler) Sync(ctx context.Context, jobMetadata JobMetadata) (SyncResult, error) {
 	logger := h.LogFn(ctx)
 
+	if h.SyncLocker != nil {
+		unlock, err := h.SyncLocker.LockSync(ctx)
+		if err != nil {
+			if _, ok := err.(domain.SyncInProgress); ok {
+				logger.Info(logs.SyncInProgress{JobID: jobMetadata.JobID})
+			} else {
+				logger.Error(logs.SyncLockFailure{JobID: jobMetadata.JobID, Reason: err.Error()})
+			}
+			return SyncResult{}, err
+		}
+		defer unlock()
+	}
+
 	ipamData, err := h.IPAMDataFetcher.FetchIPAMData(ctx)
 	if err != nil {
 		logger.Error(logs.IPAMDataFetcherFailure{JobID: jobMetadata.JobID, Reason: err.Error()})
-		return err
+		return SyncResult{}, err
+	}
+
+	validData, quarantined := h.IPAMDataValidator.ValidateIPAMData(ctx, ipamData)
+	storeErr := h.PhysicalAssetStorer.StorePhysicalAssets(ctx, validData)
+	if storeErr != nil {
+		logger.Error(logs.AssetStorerFailure{JobID: jobMetadata.JobID, Reason: storeErr.Error()})
+	} else {
+		// the new data is stored, so failing to commit the fetch or to invalidate the cache
+		// does not fail the sync; an uncommitted fetch is fetched again by the next sync
+		if committer, ok := h.IPAMDataFetcher.(domain.IPAMDataCommitter); ok {
+			if err := committer.CommitIPAMData(ctx); err != nil {
+				logger.Error(logs.IPAMDataCommitFailure{JobID: jobMetadata.JobID, Reason: err.Error()})
+			}
+		}
+		if h.AssetCacheInvalidator != nil {
+			if err := h.AssetCacheInvalidator.InvalidateAssetCache(ctx); err != nil {
+				logger.Error(logs.AssetCacheInvalidationFailure{JobID: jobMetadata.JobID, Reason: err.Error()})
+			}
+		}
 	}
 
-	if err := h.PhysicalAssetStorer.StorePhysicalAssets(ctx, ipamData); err != nil {
-		logger.Error(logs.AssetStorerFailure{JobID: jobMetadata.JobID, Reason: err.Error()})
-		return err
+	findings := h.QualityAnalyzer.AnalyzeIPAMData(ctx, ipamData)
+	report := domain.QualityReport{JobID: jobMetadata.JobID, Findings: findings, Quarantined: quarantined}
+	if err := h.QualityReportStorer.StoreQualityReport(ctx, report); err != nil {
+		// the quality report is informational, so failing to store it does not fail the sync
+		logger.Error(logs.QualityReportFailure{JobID: jobMetadata.JobID, Reason: err.Error()})
+	} else {
+		logger.Info(logs.QualityReportComplete{JobID: jobMetadata.JobID, Findings: len(findings)})
+	}
+
+	if storeErr != nil {
+		return SyncResult{}, storeErr
 	}
 
 	if len(jobMetadata.JobID) > 0 {
-		logger.Info(logs.DataSyncJobComplete{JobID: jobMetadata.JobID})
+		logger.Info(logs.DataSyncJobComplete{JobID: jobMetadata.JobID, Quarantined: len(quarantined)})
 	}
 
-	return nil
+	result := SyncResult{
+		JobID:       jobMetadata.JobID,
+		Quarantined: make([]QuarantinedRecord, 0, len(quarantined)),
+	}
+	for _, record := range quarantined {
+		result.Quarantined = append(result.Quarantined, QuarantinedRecord(record))
+	}
+	return result, nil
 }
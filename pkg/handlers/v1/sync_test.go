@@ -40,19 +40,32 @@ func TestSyncHandlerSuccess(t *testing.T) {
 			},
 		},
 	}
+	findings := []domain.QualityFinding{
+		{Category: domain.QualityIPUnknownSubnet, RecordType: domain.QualityRecordIP, RecordID: "127.0.0.1"},
+	}
 
 	mockIPAMDataFetcher := NewMockIPAMDataFetcher(ctrl)
+	mockIPAMDataValidator := NewMockIPAMDataValidator(ctrl)
 	mockAssetStorer := NewMockPhysicalAssetStorer(ctrl)
+	mockQualityAnalyzer := NewMockQualityAnalyzer(ctrl)
+	mockQualityReportStorer := NewMockQualityReportStorer(ctrl)
 	handler := SyncIPAMDataHandler{
 		IPAMDataFetcher:     mockIPAMDataFetcher,
+		IPAMDataValidator:   mockIPAMDataValidator,
 		PhysicalAssetStorer: mockAssetStorer,
+		QualityAnalyzer:     mockQualityAnalyzer,
+		QualityReportStorer: mockQualityReportStorer,
 		LogFn:               testLogFn,
 	}
 
 	mockIPAMDataFetcher.EXPECT().FetchIPAMData(gomock.Any()).Return(ipamData, nil)
+	mockIPAMDataValidator.EXPECT().ValidateIPAMData(gomock.Any(), ipamData).Return(ipamData, []domain.QuarantinedRecord{})
 	mockAssetStorer.EXPECT().StorePhysicalAssets(gomock.Any(), ipamData).Return(nil)
-	err := handler.Handle(context.Background(), JobMetadata{JobID: "foo-bar-baz-quux"})
+	mockQualityAnalyzer.EXPECT().AnalyzeIPAMData(gomock.Any(), ipamData).Return(findings)
+	mockQualityReportStorer.EXPECT().StoreQualityReport(gomock.Any(), domain.QualityReport{JobID: "foo-bar-baz-quux", Findings: findings, Quarantined: []domain.QuarantinedRecord{}}).Return(nil)
+	result, err := handler.Sync(context.Background(), JobMetadata{JobID: "foo-bar-baz-quux"})
 	require.Equal(t, nil, err)
+	require.Equal(t, SyncResult{JobID: "foo-bar-baz-quux", Quarantined: []QuarantinedRecord{}}, result)
 }
 
 func TestSyncHandlerIPAMDataFetchFailure(t *testing.T) {
@@ -60,15 +73,21 @@ func TestSyncHandlerIPAMDataFetchFailure(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockIPAMDataFetcher := NewMockIPAMDataFetcher(ctrl)
+	mockIPAMDataValidator := NewMockIPAMDataValidator(ctrl)
 	mockAssetStorer := NewMockPhysicalAssetStorer(ctrl)
+	mockQualityAnalyzer := NewMockQualityAnalyzer(ctrl)
+	mockQualityReportStorer := NewMockQualityReportStorer(ctrl)
 	handler := SyncIPAMDataHandler{
 		IPAMDataFetcher:     mockIPAMDataFetcher,
+		IPAMDataValidator:   mockIPAMDataValidator,
 		PhysicalAssetStorer: mockAssetStorer,
+		QualityAnalyzer:     mockQualityAnalyzer,
+		QualityReportStorer: mockQualityReportStorer,
 		LogFn:               testLogFn,
 	}
 
 	mockIPAMDataFetcher.EXPECT().FetchIPAMData(gomock.Any()).Return(domain.IPAMData{}, errors.New("boom"))
-	err := handler.Handle(context.Background(), JobMetadata{JobID: "foo-bar-baz-quux"})
+	_, err := handler.Sync(context.Background(), JobMetadata{JobID: "foo-bar-baz-quux"})
 	require.Equal(t, errors.New("boom"), err)
 }
 
@@ -101,17 +120,346 @@ func TestSyncHandlerIPAMDataStorerFailure(t *testing.T) {
 			},
 		},
 	}
+	findings := []domain.QualityFinding{
+		{Category: domain.QualityIPUnknownSubnet, RecordType: domain.QualityRecordIP, RecordID: "127.0.0.1"},
+	}
 
 	mockIPAMDataFetcher := NewMockIPAMDataFetcher(ctrl)
+	mockIPAMDataValidator := NewMockIPAMDataValidator(ctrl)
 	mockAssetStorer := NewMockPhysicalAssetStorer(ctrl)
+	mockQualityAnalyzer := NewMockQualityAnalyzer(ctrl)
+	mockQualityReportStorer := NewMockQualityReportStorer(ctrl)
+	// the cache is not invalidated when no new data is stored
+	mockAssetCacheInvalidator := NewMockAssetCacheInvalidator(ctrl)
+	handler := SyncIPAMDataHandler{
+		IPAMDataFetcher:       mockIPAMDataFetcher,
+		IPAMDataValidator:     mockIPAMDataValidator,
+		PhysicalAssetStorer:   mockAssetStorer,
+		QualityAnalyzer:       mockQualityAnalyzer,
+		QualityReportStorer:   mockQualityReportStorer,
+		AssetCacheInvalidator: mockAssetCacheInvalidator,
+		LogFn:                 testLogFn,
+	}
+
+	mockIPAMDataFetcher.EXPECT().FetchIPAMData(gomock.Any()).Return(ipamData, nil)
+	mockIPAMDataValidator.EXPECT().ValidateIPAMData(gomock.Any(), ipamData).Return(ipamData, []domain.QuarantinedRecord{})
+	mockAssetStorer.EXPECT().StorePhysicalAssets(gomock.Any(), ipamData).Return(errors.New("boom"))
+	mockQualityAnalyzer.EXPECT().AnalyzeIPAMData(gomock.Any(), ipamData).Return(findings)
+	mockQualityReportStorer.EXPECT().StoreQualityReport(gomock.Any(), domain.QualityReport{JobID: "foo-bar-baz-quux", Findings: findings, Quarantined: []domain.QuarantinedRecord{}}).Return(nil)
+	_, err := handler.Sync(context.Background(), JobMetadata{JobID: "foo-bar-baz-quux"})
+	require.Equal(t, errors.New("boom"), err)
+}
+
+func TestSyncHandlerQualityReportStorerFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ipamData := domain.IPAMData{
+		Customers: []domain.Customer{
+			domain.Customer{
+				ID: "1",
+			},
+		},
+	}
+	findings := []domain.QualityFinding{
+		{Category: domain.QualityCustomerEmptyOwner, RecordType: domain.QualityRecordCustomer, RecordID: "1"},
+	}
+
+	mockIPAMDataFetcher := NewMockIPAMDataFetcher(ctrl)
+	mockIPAMDataValidator := NewMockIPAMDataValidator(ctrl)
+	mockAssetStorer := NewMockPhysicalAssetStorer(ctrl)
+	mockQualityAnalyzer := NewMockQualityAnalyzer(ctrl)
+	mockQualityReportStorer := NewMockQualityReportStorer(ctrl)
 	handler := SyncIPAMDataHandler{
 		IPAMDataFetcher:     mockIPAMDataFetcher,
+		IPAMDataValidator:   mockIPAMDataValidator,
 		PhysicalAssetStorer: mockAssetStorer,
+		QualityAnalyzer:     mockQualityAnalyzer,
+		QualityReportStorer: mockQualityReportStorer,
 		LogFn:               testLogFn,
 	}
 
 	mockIPAMDataFetcher.EXPECT().FetchIPAMData(gomock.Any()).Return(ipamData, nil)
-	mockAssetStorer.EXPECT().StorePhysicalAssets(gomock.Any(), ipamData).Return(errors.New("boom"))
+	mockIPAMDataValidator.EXPECT().ValidateIPAMData(gomock.Any(), ipamData).Return(ipamData, []domain.QuarantinedRecord{})
+	mockAssetStorer.EXPECT().StorePhysicalAssets(gomock.Any(), ipamData).Return(nil)
+	mockQualityAnalyzer.EXPECT().AnalyzeIPAMData(gomock.Any(), ipamData).Return(findings)
+	mockQualityReportStorer.EXPECT().StoreQualityReport(gomock.Any(), domain.QualityReport{JobID: "foo-bar-baz-quux", Findings: findings, Quarantined: []domain.QuarantinedRecord{}}).Return(errors.New("boom"))
+	_, err := handler.Sync(context.Background(), JobMetadata{JobID: "foo-bar-baz-quux"})
+	require.Equal(t, nil, err)
+}
+
+func TestSyncHandlerQuarantinedRecords(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	validDevice := domain.Device{ID: "1", IP: "10.0.0.1", SubnetID: "1"}
+	invalidDevice := domain.Device{ID: "2", IP: "10.0.0.2", SubnetID: "42"}
+	subnet := domain.Subnet{ID: "1", Network: "10.0.0.0", MaskBits: 24}
+	ipamData := domain.IPAMData{
+		Devices: []domain.Device{validDevice, invalidDevice},
+		Subnets: []domain.Subnet{subnet},
+	}
+	validData := domain.IPAMData{
+		Devices: []domain.Device{validDevice},
+		Subnets: []domain.Subnet{subnet},
+	}
+	quarantined := []domain.QuarantinedRecord{
+		{RecordType: domain.QualityRecordIP, RecordID: "10.0.0.2", Reason: "references unknown subnet 42"},
+	}
+	findings := []domain.QualityFinding{
+		{Category: domain.QualityIPUnknownSubnet, RecordType: domain.QualityRecordIP, RecordID: "10.0.0.2"},
+	}
+
+	mockIPAMDataFetcher := NewMockIPAMDataFetcher(ctrl)
+	mockIPAMDataValidator := NewMockIPAMDataValidator(ctrl)
+	mockAssetStorer := NewMockPhysicalAssetStorer(ctrl)
+	mockQualityAnalyzer := NewMockQualityAnalyzer(ctrl)
+	mockQualityReportStorer := NewMockQualityReportStorer(ctrl)
+	handler := SyncIPAMDataHandler{
+		IPAMDataFetcher:     mockIPAMDataFetcher,
+		IPAMDataValidator:   mockIPAMDataValidator,
+		PhysicalAssetStorer: mockAssetStorer,
+		QualityAnalyzer:     mockQualityAnalyzer,
+		QualityReportStorer: mockQualityReportStorer,
+		LogFn:               testLogFn,
+	}
+
+	mockIPAMDataFetcher.EXPECT().FetchIPAMData(gomock.Any()).Return(ipamData, nil)
+	mockIPAMDataValidator.EXPECT().ValidateIPAMData(gomock.Any(), ipamData).Return(validData, quarantined)
+	mockAssetStorer.EXPECT().StorePhysicalAssets(gomock.Any(), validData).Return(nil)
+	mockQualityAnalyzer.EXPECT().AnalyzeIPAMData(gomock.Any(), ipamData).Return(findings)
+	mockQualityReportStorer.EXPECT().StoreQualityReport(gomock.Any(), domain.QualityReport{JobID: "foo-bar-baz-quux", Findings: findings, Quarantined: quarantined}).Return(nil)
+	result, err := handler.Sync(context.Background(), JobMetadata{JobID: "foo-bar-baz-quux"})
+	require.Equal(t, nil, err)
+	require.Equal(t, SyncResult{
+		JobID: "foo-bar-baz-quux",
+		Quarantined: []QuarantinedRecord{
+			{RecordType: domain.QualityRecordIP, RecordID: "10.0.0.2", Reason: "references unknown subnet 42"},
+		},
+	}, result)
+}
+
+func TestSyncHandlerHandle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ipamData := domain.IPAMData{
+		Devices: []domain.Device{{ID: "2", IP: "10.0.0.2", SubnetID: "42"}},
+	}
+	quarantined := []domain.QuarantinedRecord{
+		{RecordType: domain.QualityRecordIP, RecordID: "10.0.0.2", Reason: "references unknown subnet 42"},
+	}
+
+	mockIPAMDataFetcher := NewMockIPAMDataFetcher(ctrl)
+	mockIPAMDataValidator := NewMockIPAMDataValidator(ctrl)
+	mockAssetStorer := NewMockPhysicalAssetStorer(ctrl)
+	mockQualityAnalyzer := NewMockQualityAnalyzer(ctrl)
+	mockQualityReportStorer := NewMockQualityReportStorer(ctrl)
+	handler := SyncIPAMDataHandler{
+		IPAMDataFetcher:     mockIPAMDataFetcher,
+		IPAMDataValidator:   mockIPAMDataValidator,
+		PhysicalAssetStorer: mockAssetStorer,
+		QualityAnalyzer:     mockQualityAnalyzer,
+		QualityReportStorer: mockQualityReportStorer,
+		LogFn:               testLogFn,
+	}
+
+	mockIPAMDataFetcher.EXPECT().FetchIPAMData(gomock.Any()).Return(ipamData, nil)
+	mockIPAMDataValidator.EXPECT().ValidateIPAMData(gomock.Any(), ipamData).Return(domain.IPAMData{}, quarantined)
+	mockAssetStorer.EXPECT().StorePhysicalAssets(gomock.Any(), domain.IPAMData{}).Return(nil)
+	mockQualityAnalyzer.EXPECT().AnalyzeIPAMData(gomock.Any(), ipamData).Return([]domain.QualityFinding{})
+	mockQualityReportStorer.EXPECT().StoreQualityReport(gomock.Any(), domain.QualityReport{JobID: "foo-bar-baz-quux", Findings: []domain.QualityFinding{}, Quarantined: quarantined}).Return(nil)
 	err := handler.Handle(context.Background(), JobMetadata{JobID: "foo-bar-baz-quux"})
-	require.Equal(t, errors.New("boom"), err)
+	require.Equal(t, nil, err)
+}
+
+func TestSyncHandlerInvalidatesAssetCache(t *testing.T) {
+	tc := []struct {
+		name            string
+		invalidationErr error
+	}{
+		{
+			name: "invalidated",
+		},
+		{
+			name:            "invalidation failure",
+			invalidationErr: errors.New("boom"),
+		},
+	}
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ipamData := domain.IPAMData{
+				Subnets: []domain.Subnet{{ID: "1", Network: "127.0.0.0", MaskBits: 31}},
+			}
+
+			mockIPAMDataFetcher := NewMockIPAMDataFetcher(ctrl)
+			mockIPAMDataValidator := NewMockIPAMDataValidator(ctrl)
+			mockAssetStorer := NewMockPhysicalAssetStorer(ctrl)
+			mockQualityAnalyzer := NewMockQualityAnalyzer(ctrl)
+			mockQualityReportStorer := NewMockQualityReportStorer(ctrl)
+			mockAssetCacheInvalidator := NewMockAssetCacheInvalidator(ctrl)
+			handler := SyncIPAMDataHandler{
+				IPAMDataFetcher:       mockIPAMDataFetcher,
+				IPAMDataValidator:     mockIPAMDataValidator,
+				PhysicalAssetStorer:   mockAssetStorer,
+				QualityAnalyzer:       mockQualityAnalyzer,
+				QualityReportStorer:   mockQualityReportStorer,
+				AssetCacheInvalidator: mockAssetCacheInvalidator,
+				LogFn:                 testLogFn,
+			}
+
+			mockIPAMDataFetcher.EXPECT().FetchIPAMData(gomock.Any()).Return(ipamData, nil)
+			mockIPAMDataValidator.EXPECT().ValidateIPAMData(gomock.Any(), ipamData).Return(ipamData, []domain.QuarantinedRecord{})
+			stored := mockAssetStorer.EXPECT().StorePhysicalAssets(gomock.Any(), ipamData).Return(nil)
+			mockAssetCacheInvalidator.EXPECT().InvalidateAssetCache(gomock.Any()).Return(tt.invalidationErr).After(stored)
+			mockQualityAnalyzer.EXPECT().AnalyzeIPAMData(gomock.Any(), ipamData).Return(nil)
+			mockQualityReportStorer.EXPECT().StoreQualityReport(gomock.Any(), gomock.Any()).Return(nil)
+			result, err := handler.Sync(context.Background(), JobMetadata{JobID: "foo-bar-baz-quux"})
+			require.Nil(t, err)
+			require.Equal(t, SyncResult{JobID: "foo-bar-baz-quux", Quarantined: []QuarantinedRecord{}}, result)
+		})
+	}
+}
+
+// committingIPAMDataFetcher is an IPAMDataFetcher that keeps state between fetches.
+type committingIPAMDataFetcher struct {
+	*MockIPAMDataFetcher
+	*MockIPAMDataCommitter
+}
+
+func TestSyncHandlerCommitsStoredData(t *testing.T) {
+	tc := []struct {
+		name      string
+		storeErr  error
+		commitErr error
+		commits   int
+	}{
+		{
+			name:    "committed",
+			commits: 1,
+		},
+		{
+			name:      "commit failure",
+			commitErr: errors.New("boom"),
+			commits:   1,
+		},
+		{
+			name:     "store failure",
+			storeErr: errors.New("boom"),
+		},
+	}
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ipamData := domain.IPAMData{
+				Subnets: []domain.Subnet{{ID: "1", Network: "127.0.0.0", MaskBits: 31}},
+			}
+
+			mockIPAMDataFetcher := NewMockIPAMDataFetcher(ctrl)
+			mockIPAMDataCommitter := NewMockIPAMDataCommitter(ctrl)
+			mockIPAMDataValidator := NewMockIPAMDataValidator(ctrl)
+			mockAssetStorer := NewMockPhysicalAssetStorer(ctrl)
+			mockQualityAnalyzer := NewMockQualityAnalyzer(ctrl)
+			mockQualityReportStorer := NewMockQualityReportStorer(ctrl)
+			handler := SyncIPAMDataHandler{
+				IPAMDataFetcher:     committingIPAMDataFetcher{mockIPAMDataFetcher, mockIPAMDataCommitter},
+				IPAMDataValidator:   mockIPAMDataValidator,
+				PhysicalAssetStorer: mockAssetStorer,
+				QualityAnalyzer:     mockQualityAnalyzer,
+				QualityReportStorer: mockQualityReportStorer,
+				LogFn:               testLogFn,
+			}
+
+			mockIPAMDataFetcher.EXPECT().FetchIPAMData(gomock.Any()).Return(ipamData, nil)
+			mockIPAMDataValidator.EXPECT().ValidateIPAMData(gomock.Any(), ipamData).Return(ipamData, []domain.QuarantinedRecord{})
+			stored := mockAssetStorer.EXPECT().StorePhysicalAssets(gomock.Any(), ipamData).Return(tt.storeErr)
+			mockIPAMDataCommitter.EXPECT().CommitIPAMData(gomock.Any()).Return(tt.commitErr).After(stored).Times(tt.commits)
+			mockQualityAnalyzer.EXPECT().AnalyzeIPAMData(gomock.Any(), ipamData).Return(nil)
+			mockQualityReportStorer.EXPECT().StoreQualityReport(gomock.Any(), gomock.Any()).Return(nil)
+			_, err := handler.Sync(context.Background(), JobMetadata{JobID: "foo-bar-baz-quux"})
+			require.Equal(t, tt.storeErr, err)
+		})
+	}
+}
+
+func TestSyncHandlerSyncLock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ipamData := domain.IPAMData{
+		Subnets: []domain.Subnet{{ID: "1", Network: "127.0.0.0", MaskBits: 31}},
+	}
+
+	mockIPAMDataFetcher := NewMockIPAMDataFetcher(ctrl)
+	mockIPAMDataValidator := NewMockIPAMDataValidator(ctrl)
+	mockAssetStorer := NewMockPhysicalAssetStorer(ctrl)
+	mockQualityAnalyzer := NewMockQualityAnalyzer(ctrl)
+	mockQualityReportStorer := NewMockQualityReportStorer(ctrl)
+	mockSyncLocker := NewMockSyncLocker(ctrl)
+	handler := SyncIPAMDataHandler{
+		IPAMDataFetcher:     mockIPAMDataFetcher,
+		IPAMDataValidator:   mockIPAMDataValidator,
+		PhysicalAssetStorer: mockAssetStorer,
+		QualityAnalyzer:     mockQualityAnalyzer,
+		QualityReportStorer: mockQualityReportStorer,
+		SyncLocker:          mockSyncLocker,
+		LogFn:               testLogFn,
+	}
+
+	unlocked := false
+	mockSyncLocker.EXPECT().LockSync(gomock.Any()).Return(func() { unlocked = true }, nil)
+	mockIPAMDataFetcher.EXPECT().FetchIPAMData(gomock.Any()).Return(ipamData, nil)
+	mockIPAMDataValidator.EXPECT().ValidateIPAMData(gomock.Any(), ipamData).Return(ipamData, []domain.QuarantinedRecord{})
+	mockAssetStorer.EXPECT().StorePhysicalAssets(gomock.Any(), ipamData).DoAndReturn(
+		func(context.Context, domain.IPAMData) error {
+			require.False(t, unlocked, "the sync lock should be held while storing")
+			return nil
+		})
+	mockQualityAnalyzer.EXPECT().AnalyzeIPAMData(gomock.Any(), ipamData).Return(nil)
+	mockQualityReportStorer.EXPECT().StoreQualityReport(gomock.Any(), gomock.Any()).Return(nil)
+	_, err := handler.Sync(context.Background(), JobMetadata{JobID: "foo-bar-baz-quux"})
+	require.Nil(t, err)
+	require.True(t, unlocked, "the sync lock should be released")
+}
+
+func TestSyncHandlerSyncLockFailure(t *testing.T) {
+	tc := []struct {
+		name    string
+		lockErr error
+	}{
+		{
+			name:    "in progress",
+			lockErr: domain.SyncInProgress{},
+		},
+		{
+			name:    "lock error",
+			lockErr: errors.New("boom"),
+		},
+	}
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSyncLocker := NewMockSyncLocker(ctrl)
+			handler := SyncIPAMDataHandler{
+				IPAMDataFetcher:     NewMockIPAMDataFetcher(ctrl),
+				IPAMDataValidator:   NewMockIPAMDataValidator(ctrl),
+				PhysicalAssetStorer: NewMockPhysicalAssetStorer(ctrl),
+				QualityAnalyzer:     NewMockQualityAnalyzer(ctrl),
+				QualityReportStorer: NewMockQualityReportStorer(ctrl),
+				SyncLocker:          mockSyncLocker,
+				LogFn:               testLogFn,
+			}
+
+			mockSyncLocker.EXPECT().LockSync(gomock.Any()).Return(nil, tt.lockErr)
+			_, err := handler.Sync(context.Background(), JobMetadata{JobID: "foo-bar-baz-quux"})
+			require.Equal(t, tt.lockErr, err)
+		})
+	}
 }
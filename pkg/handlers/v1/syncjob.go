@@ -0,0 +1,47 @@
+package v1
+
+import (
+	"context"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+	"github.com/asecurityteam/ipam-facade/pkg/logs"
+)
+
+// SyncJobHandler uses its SyncJobReportFetcher implementation to serve requests for the
+// result of a sync job, including the jobs queued by the trigger endpoint and EnqueueSync,
+// whose results are only kept with their quality reports.
+type SyncJobHandler struct {
+	SyncJobReportFetcher domain.SyncJobReportFetcher
+	LogFn                domain.LogFn
+}
+
+// Handle processes an incoming JobMetadata and returns the SyncResult of the job or an error.
+func (h *SyncJobHandler) Handle(ctx context.Context, input JobMetadata) (SyncResult, error) {
+	logger := h.LogFn(ctx)
+
+	if input.JobID == "" {
+		err := domain.InvalidInput{Input: input.JobID}
+		logger.Info(logs.InvalidInput{Reason: err.Error()})
+		return SyncResult{}, err
+	}
+
+	report, err := h.SyncJobReportFetcher.FetchSyncJobReport(ctx, input.JobID)
+	switch err.(type) {
+	case nil:
+	case domain.SyncJobNotFound:
+		logger.Info(logs.SyncJobNotFound{Reason: err.Error()})
+		return SyncResult{}, err
+	default:
+		logger.Error(logs.AssetFetcherFailure{Reason: err.Error()})
+		return SyncResult{}, err
+	}
+
+	result := SyncResult{
+		JobID:       input.JobID,
+		Quarantined: make([]QuarantinedRecord, 0, len(report.Quarantined)),
+	}
+	for _, record := range report.Quarantined {
+		result.Quarantined = append(result.Quarantined, QuarantinedRecord(record))
+	}
+	return result, nil
+}
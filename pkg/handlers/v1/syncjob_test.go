@@ -0,0 +1,67 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+	gomock "github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncJobHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	report := domain.QualityReport{
+		JobID: "foo-bar-baz-quux",
+		Quarantined: []domain.QuarantinedRecord{
+			{RecordType: domain.QualityRecordIP, RecordID: "10.0.0.2", Reason: "references unknown subnet 42"},
+		},
+	}
+
+	mockFetcher := NewMockSyncJobReportFetcher(ctrl)
+	mockFetcher.EXPECT().FetchSyncJobReport(gomock.Any(), "foo-bar-baz-quux").Return(report, nil)
+
+	handler := SyncJobHandler{SyncJobReportFetcher: mockFetcher, LogFn: testLogFn}
+	result, err := handler.Handle(context.Background(), JobMetadata{JobID: "foo-bar-baz-quux"})
+	require.Nil(t, err)
+	require.Equal(t, SyncResult{
+		JobID: "foo-bar-baz-quux",
+		Quarantined: []QuarantinedRecord{
+			{RecordType: domain.QualityRecordIP, RecordID: "10.0.0.2", Reason: "references unknown subnet 42"},
+		},
+	}, result)
+}
+
+func TestSyncJobHandlerErrors(t *testing.T) {
+	tc := []struct {
+		name     string
+		jobID    string
+		fetchErr error
+	}{
+		{name: "empty job ID", jobID: ""},
+		{name: "not found", jobID: "foo-bar-baz-quux", fetchErr: domain.SyncJobNotFound{JobID: "foo-bar-baz-quux"}},
+		{name: "fetch failure", jobID: "foo-bar-baz-quux", fetchErr: errors.New("boom")},
+	}
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockFetcher := NewMockSyncJobReportFetcher(ctrl)
+			if tt.fetchErr != nil {
+				mockFetcher.EXPECT().FetchSyncJobReport(gomock.Any(), tt.jobID).Return(domain.QualityReport{}, tt.fetchErr)
+			}
+
+			handler := SyncJobHandler{SyncJobReportFetcher: mockFetcher, LogFn: testLogFn}
+			_, err := handler.Handle(context.Background(), JobMetadata{JobID: tt.jobID})
+			if tt.fetchErr != nil {
+				require.Equal(t, tt.fetchErr, err)
+			} else {
+				require.IsType(t, domain.InvalidInput{}, err)
+			}
+		})
+	}
+}
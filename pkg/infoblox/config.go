@@ -0,0 +1,139 @@
+package infoblox
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+
+	httpclient "github.com/asecurityteam/component-httpclient"
+)
+
+const defaultPageSize = 1000
+
+// Config contains configuration settings for an Infoblox Client
+type Config struct {
+	Endpoint              string `description:"Base URL of the Infoblox WAPI, including the version, such as https://infoblox.example.com/wapi/v2.7"`
+	Username              string `description:"Infoblox user used for HTTP basic authentication."`
+	Password              string `description:"Password of the Infoblox user."`
+	PageSize              int    `description:"Maximum number of objects requested in each WAPI page."`
+	NetworkView           string `description:"Only sync objects in this network view. All network views are synced if empty."`
+	OwnerAttribute        string `description:"Name of the network extensible attribute holding the resource owner."`
+	BusinessUnitAttribute string `description:"Name of the network extensible attribute holding the business unit."`
+	LocationAttribute     string `description:"Name of the network extensible attribute holding the location."`
+	HTTP                  *httpclient.Config
+}
+
+// Name is used by the settings library to replace the default naming convention.
+func (*Config) Name() string {
+	return "Infoblox"
+}
+
+// NewComponent generates a new, unititialized Component
+func NewComponent() *Component {
+	return &Component{
+		HTTP: httpclient.NewComponent(),
+	}
+}
+
+// Component satisfies the settings library Component API,
+// and may be used by the settings.NewComponent function.
+type Component struct {
+	HTTP *httpclient.Component
+}
+
+// Settings generates a config with default values applied.
+func (c *Component) Settings() *Config {
+	return &Config{
+		PageSize:              defaultPageSize,
+		OwnerAttribute:        "Owner",
+		BusinessUnitAttribute: "Business Unit",
+		LocationAttribute:     "Location",
+		HTTP:                  c.HTTP.Settings(),
+	}
+}
+
+// New constructs a Client from a config.
+func (c *Component) New(ctx context.Context, conf *Config) (*Client, error) {
+	rt, e := c.HTTP.New(ctx, conf.HTTP)
+	if e != nil {
+		return nil, e
+	}
+	u, e := url.Parse(conf.Endpoint)
+	if e != nil {
+		return nil, e
+	}
+	return &Client{
+		Endpoint:    u,
+		PageSize:    conf.PageSize,
+		NetworkView: conf.NetworkView,
+		Attributes: Attributes{
+			Owner:        conf.OwnerAttribute,
+			BusinessUnit: conf.BusinessUnitAttribute,
+			Location:     conf.LocationAttribute,
+		},
+		Client: &http.Client{
+			Transport: &BasicAuthTransport{Username: conf.Username, Password: conf.Password, Wrapped: rt},
+		},
+	}, nil
+}
+
+// BasicAuthTransport authenticates every request to the WAPI with HTTP basic authentication.
+type BasicAuthTransport struct {
+	Username string
+	Password string
+	Wrapped  http.RoundTripper
+}
+
+// RoundTrip adds the basic authentication header to a copy of the request.
+func (t *BasicAuthTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	header := make(http.Header, len(r.Header)+1)
+	for key, values := range r.Header {
+		header[key] = values
+	}
+	r = r.WithContext(r.Context())
+	r.Header = header
+	r.SetBasicAuth(t.Username, t.Password)
+	return t.Wrapped.RoundTrip(r)
+}
+
+// Attributes names the extensible attributes of Infoblox networks that are mapped onto
+// the resource owner, business unit, and location of the IPAM data.
+type Attributes struct {
+	Owner        string
+	BusinessUnit string
+	Location     string
+}
+
+// Client contains values to configure an Infoblox WAPI client
+type Client struct {
+	Client      *http.Client
+	Endpoint    *url.URL
+	PageSize    int
+	NetworkView string
+	Attributes  Attributes
+}
+
+// CheckDependencies makes a call to the WAPI grid object. Client is the only dependency
+// shared amongst the Infoblox fetchers, so those do not need to be checked individually.
+func (c *Client) CheckDependencies(ctx context.Context) error {
+	u := c.objectURL("grid")
+	req, _ := http.NewRequest(http.MethodGet, u.String(), http.NoBody)
+	res, err := c.Client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("Infoblox Client unexpectedly returned non-200 response code: %d attempting to GET: %s", res.StatusCode, u.String())
+	}
+	return nil
+}
+
+// objectURL returns the URL of a WAPI object type.
+func (c *Client) objectURL(objectType string) *url.URL {
+	u, _ := url.Parse(c.Endpoint.String())
+	u.Path = path.Join(u.Path, objectType)
+	return u
+}
@@ -0,0 +1,292 @@
+package infoblox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+	"github.com/asecurityteam/ipam-facade/pkg/ipamvalidator"
+	"github.com/asecurityteam/ipam-facade/pkg/subnetassign"
+)
+
+type extAttr struct {
+	Value interface{} `json:"value"`
+}
+
+type extAttrs map[string]extAttr
+
+// GetValue retrieves an extensible attribute value as a string. The values of multi-value
+// attributes are comma-delimited.
+func (e extAttrs) GetValue(name string) string {
+	attr, ok := e[name]
+	if !ok || attr.Value == nil {
+		return ""
+	}
+	if values, ok := attr.Value.([]interface{}); ok {
+		parts := make([]string, 0, len(values))
+		for _, value := range values {
+			parts = append(parts, fmt.Sprint(value))
+		}
+		return strings.Join(parts, ",")
+	}
+	return fmt.Sprint(attr.Value)
+}
+
+type network struct {
+	Network     string   `json:"network"`
+	NetworkView string   `json:"network_view"`
+	ExtAttrs    extAttrs `json:"extattrs"`
+}
+
+type fixedAddress struct {
+	IPv4Addr    string `json:"ipv4addr"`
+	IPv6Addr    string `json:"ipv6addr"`
+	NetworkView string `json:"network_view"`
+}
+
+// IP returns the IPv4 or IPv6 address of a fixed address or host record address.
+func (a fixedAddress) IP() string {
+	if a.IPv4Addr != "" {
+		return a.IPv4Addr
+	}
+	return a.IPv6Addr
+}
+
+type hostRecord struct {
+	IPv4Addrs   []fixedAddress `json:"ipv4addrs"`
+	IPv6Addrs   []fixedAddress `json:"ipv6addrs"`
+	NetworkView string         `json:"network_view"`
+}
+
+// NewIPAMDataFetcher generates a new IPAMDataFetcher for an Infoblox grid
+func NewIPAMDataFetcher(c *Client) *IPAMDataFetcher {
+	filters := url.Values{}
+	if c.NetworkView != "" {
+		filters.Set("network_view", c.NetworkView)
+	}
+	return &IPAMDataFetcher{
+		Networks: &WAPIPageFetcher{
+			Client: c.Client, Endpoint: c.objectURL("network"), PageSize: c.PageSize,
+			ReturnFields: []string{"network", "network_view", "extattrs"}, Filters: filters,
+		},
+		IPv6Networks: &WAPIPageFetcher{
+			Client: c.Client, Endpoint: c.objectURL("ipv6network"), PageSize: c.PageSize,
+			ReturnFields: []string{"network", "network_view", "extattrs"}, Filters: filters,
+		},
+		FixedAddresses: &WAPIPageFetcher{
+			Client: c.Client, Endpoint: c.objectURL("fixedaddress"), PageSize: c.PageSize,
+			ReturnFields: []string{"ipv4addr", "network_view"}, Filters: filters,
+		},
+		IPv6FixedAddresses: &WAPIPageFetcher{
+			Client: c.Client, Endpoint: c.objectURL("ipv6fixedaddress"), PageSize: c.PageSize,
+			ReturnFields: []string{"ipv6addr", "network_view"}, Filters: filters,
+		},
+		HostRecords: &WAPIPageFetcher{
+			Client: c.Client, Endpoint: c.objectURL("record:host"), PageSize: c.PageSize,
+			ReturnFields: []string{"ipv4addrs", "ipv6addrs", "network_view"}, Filters: filters,
+		},
+		Attributes: c.Attributes,
+	}
+}
+
+// IPAMDataFetcher implements the IPAMDataFetcher interface to retrieve IPv4 and IPv6 data from
+// Infoblox.
+//
+// Infoblox has no equivalent to a Device42 Customer, so one Customer is created for each
+// distinct combination of owner and business unit extensible attributes found on networks.
+// WAPI object references are not numeric, so the ID of a Subnet is derived from a hash of its
+// network view and network, and the ID of a Customer from a hash of its owner and business
+// unit, which keeps them stable between syncs whatever the order of the results. Fixed
+// addresses and host record addresses are assigned to the most specific network of their own
+// network view that contains them, as views may hold overlapping networks, and have no device
+// ID.
+type IPAMDataFetcher struct {
+	Networks           PageFetcher
+	IPv6Networks       PageFetcher
+	FixedAddresses     PageFetcher
+	IPv6FixedAddresses PageFetcher
+	HostRecords        PageFetcher
+	Attributes         Attributes
+}
+
+// FetchIPAMData retrieves IPv4 and IPv6 networks, fixed addresses, and host records from Infoblox
+func (f *IPAMDataFetcher) FetchIPAMData(ctx context.Context) (domain.IPAMData, error) {
+	networks := make([]network, 0)
+	for _, fetcher := range []PageFetcher{f.Networks, f.IPv6Networks} {
+		err := fetchAll(ctx, fetcher, func(result json.RawMessage) error {
+			var page []network
+			if err := json.Unmarshal(result, &page); err != nil {
+				return err
+			}
+			networks = append(networks, page...)
+			return nil
+		})
+		if err != nil {
+			return domain.IPAMData{}, err
+		}
+	}
+
+	subnetKeys := make([]string, 0, len(networks))
+	customerKeys := make([]string, 0)
+	for _, n := range networks {
+		subnetKeys = append(subnetKeys, n.NetworkView+"\x00"+n.Network)
+		if key, ok := f.customerKey(n); ok {
+			customerKeys = append(customerKeys, key)
+		}
+	}
+	subnetIDs := stableIDs(subnetKeys)
+	customerIDs := stableIDs(customerKeys)
+
+	customers := make([]domain.Customer, 0)
+	listed := make(map[string]bool)
+	subnets := make([]domain.Subnet, 0, len(networks))
+	subnetViews := make([]string, 0, len(networks))
+	for i, n := range networks {
+		subnet := toSubnet(n, subnetIDs[subnetKeys[i]])
+		subnet.Location = n.ExtAttrs.GetValue(f.Attributes.Location)
+		if key, ok := f.customerKey(n); ok {
+			subnet.CustomerID = customerIDs[key]
+			// each customer is listed once, where its first network was fetched
+			if !listed[key] {
+				listed[key] = true
+				customer := domain.Customer{
+					ID:            subnet.CustomerID,
+					ResourceOwner: n.ExtAttrs.GetValue(f.Attributes.Owner),
+					BusinessUnit:  n.ExtAttrs.GetValue(f.Attributes.BusinessUnit),
+				}
+				if customer.ResourceOwner != "" {
+					customer.ResourceOwnerRule = "extattr:" + f.Attributes.Owner
+				}
+				customers = append(customers, customer)
+			}
+		}
+		subnets = append(subnets, subnet)
+		subnetViews = append(subnetViews, n.NetworkView)
+	}
+
+	devices := make([]domain.Device, 0)
+	deviceViews := make([]string, 0)
+	for _, fetcher := range []PageFetcher{f.FixedAddresses, f.IPv6FixedAddresses} {
+		err := fetchAll(ctx, fetcher, func(result json.RawMessage) error {
+			var page []fixedAddress
+			if err := json.Unmarshal(result, &page); err != nil {
+				return err
+			}
+			for _, address := range page {
+				devices = append(devices, domain.Device{IP: address.IP()})
+				deviceViews = append(deviceViews, address.NetworkView)
+			}
+			return nil
+		})
+		if err != nil {
+			return domain.IPAMData{}, err
+		}
+	}
+	err := fetchAll(ctx, f.HostRecords, func(result json.RawMessage) error {
+		var page []hostRecord
+		if err := json.Unmarshal(result, &page); err != nil {
+			return err
+		}
+		for _, host := range page {
+			for _, address := range append(host.IPv4Addrs, host.IPv6Addrs...) {
+				devices = append(devices, domain.Device{IP: address.IP()})
+				deviceViews = append(deviceViews, host.NetworkView)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return domain.IPAMData{}, err
+	}
+
+	assignSubnets(devices, deviceViews, subnets, subnetViews)
+	return domain.IPAMData{
+		Customers: customers,
+		Subnets:   subnets,
+		Devices:   devices,
+	}, nil
+}
+
+// assignSubnets assigns each device to a subnet of the same network view, given the view of
+// every device and subnet.
+func assignSubnets(devices []domain.Device, deviceViews []string, subnets []domain.Subnet, subnetViews []string) {
+	viewSubnets := make(map[string][]domain.Subnet)
+	for i, subnet := range subnets {
+		viewSubnets[subnetViews[i]] = append(viewSubnets[subnetViews[i]], subnet)
+	}
+	viewDevices := make(map[string][]int)
+	for i := range devices {
+		viewDevices[deviceViews[i]] = append(viewDevices[deviceViews[i]], i)
+	}
+	for view, positions := range viewDevices {
+		assigned := make([]domain.Device, 0, len(positions))
+		for _, position := range positions {
+			assigned = append(assigned, devices[position])
+		}
+		subnetassign.AssignSubnets(assigned, viewSubnets[view])
+		for i, position := range positions {
+			devices[position] = assigned[i]
+		}
+	}
+}
+
+// customerKey identifies the customer of a network by its owner and business unit, and
+// returns false for a network with neither.
+func (f *IPAMDataFetcher) customerKey(n network) (string, bool) {
+	owner := n.ExtAttrs.GetValue(f.Attributes.Owner)
+	businessUnit := n.ExtAttrs.GetValue(f.Attributes.BusinessUnit)
+	if owner == "" && businessUnit == "" {
+		return "", false
+	}
+	return owner + "\x00" + businessUnit, true
+}
+
+// stableIDs assigns each distinct key an ID taken from its FNV-1a hash, within the positive
+// range of the 32 bit IDs of the other sources, so that the ID does not depend on the order in
+// which the keys were fetched. A key whose ID is taken by another one gets the next free ID,
+// with the keys visited in sorted order.
+func stableIDs(keys []string) map[string]string {
+	sorted := make([]string, len(keys))
+	copy(sorted, keys)
+	sort.Strings(sorted)
+	ids := make(map[string]string, len(sorted))
+	taken := make(map[uint32]bool, len(sorted))
+	for _, key := range sorted {
+		if _, ok := ids[key]; ok {
+			continue
+		}
+		hash := fnv.New32a()
+		_, _ = hash.Write([]byte(key))
+		id := hash.Sum32() & math.MaxInt32
+		for id == 0 || taken[id] {
+			id = (id + 1) & math.MaxInt32
+		}
+		taken[id] = true
+		ids[key] = strconv.FormatUint(uint64(id), 10)
+	}
+	return ids
+}
+
+// toSubnet converts a network into a Subnet. Malformed networks are passed along with a mask
+// of -1 rather than failing the fetch, so that the sync validation quarantines them.
+func toSubnet(n network, id string) domain.Subnet {
+	subnet := domain.Subnet{
+		ID:       id,
+		Network:  n.Network,
+		MaskBits: -1,
+	}
+	if slash := strings.LastIndex(n.Network, "/"); slash >= 0 {
+		subnet.Network = n.Network[:slash]
+		if maskBits, err := strconv.Atoi(n.Network[slash+1:]); err == nil && maskBits >= 0 && maskBits <= ipamvalidator.MaxMaskBits(subnet.Network) {
+			subnet.MaskBits = maskBits
+		}
+	}
+	return subnet
+}
@@ -0,0 +1,289 @@
+package infoblox
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeWAPI serves fixed result sets for WAPI object types using WAPI paging, where the
+// page ID encodes the object type and offset of the next page.
+type fakeWAPI struct {
+	Objects  map[string][]interface{}
+	Requests []string
+}
+
+func (f *fakeWAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.Requests = append(f.Requests, r.URL.RequestURI())
+	if username, password, ok := r.BasicAuth(); !ok || username != "admin" || password != "infoblox" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	objectType := strings.TrimPrefix(r.URL.Path, "/wapi/v2.7/")
+	if objectType == "grid" {
+		_, _ = w.Write([]byte(`[{"_ref": "grid/b25lLmNsdXN0ZXIkMA:Infoblox"}]`))
+		return
+	}
+	objects, ok := f.Objects[objectType]
+	if !ok {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	offset, pageSize := 0, 0
+	if pageID := r.URL.Query().Get("_page_id"); pageID != "" {
+		parts := strings.Split(pageID, ":")
+		if parts[0] != objectType {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		offset, _ = strconv.Atoi(parts[1])
+		pageSize, _ = strconv.Atoi(parts[2])
+	} else {
+		if r.URL.Query().Get("_paging") != "1" || r.URL.Query().Get("_return_as_object") != "1" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		pageSize, _ = strconv.Atoi(r.URL.Query().Get("_max_results"))
+	}
+	end := offset + pageSize
+	if end > len(objects) {
+		end = len(objects)
+	}
+	page := map[string]interface{}{"result": objects[offset:end]}
+	if end < len(objects) {
+		page["next_page_id"] = objectType + ":" + strconv.Itoa(end) + ":" + strconv.Itoa(pageSize)
+	}
+	_ = json.NewEncoder(w).Encode(page)
+}
+
+type object = map[string]interface{}
+
+func fakeWAPIObjects() map[string][]interface{} {
+	return map[string][]interface{}{
+		"network": {
+			object{"_ref": "network/a:10.0.0.0/16/default", "network": "10.0.0.0/16", "network_view": "default", "extattrs": object{
+				"Owner": object{"value": "alice@example.com"}, "Business Unit": object{"value": "Payments"}, "Location": object{"value": "DC1"},
+			}},
+			object{"_ref": "network/b:10.0.1.0/24/default", "network": "10.0.1.0/24", "network_view": "default", "extattrs": object{
+				"Owner": object{"value": "bob@example.com"}, "Business Unit": object{"value": "Security"}, "Location": object{"value": []interface{}{"DC1", "DC2"}},
+			}},
+			object{"_ref": "network/c:10.1.0.0/16/default", "network": "10.1.0.0/16", "network_view": "default", "extattrs": object{
+				"Owner": object{"value": "alice@example.com"}, "Business Unit": object{"value": "Payments"},
+			}},
+			object{"_ref": "network/d:10.2.0.0/16/default", "network": "10.2.0.0/16", "network_view": "default", "extattrs": object{}},
+			object{"_ref": "network/e:10.3.0.0/16/default", "network": "10.3.0.0/16", "network_view": "default", "extattrs": object{"Business Unit": object{"value": "Networking"}}},
+		},
+		"ipv6network": {
+			object{"_ref": "ipv6network/f:2001%3Adb8%3A%3A/48/default", "network": "2001:db8::/48", "network_view": "default", "extattrs": object{
+				"Owner": object{"value": "alice@example.com"}, "Business Unit": object{"value": "Payments"}, "Location": object{"value": "DC1"},
+			}},
+		},
+		"fixedaddress": {
+			object{"_ref": "fixedaddress/a:10.0.0.5/default", "ipv4addr": "10.0.0.5", "network_view": "default"},
+			object{"_ref": "fixedaddress/b:10.0.1.5/default", "ipv4addr": "10.0.1.5", "network_view": "default"},
+			object{"_ref": "fixedaddress/c:192.168.0.1/default", "ipv4addr": "192.168.0.1", "network_view": "default"},
+		},
+		"ipv6fixedaddress": {
+			object{"_ref": "ipv6fixedaddress/a:2001%3Adb8%3A%3A5/default", "ipv6addr": "2001:db8::5", "network_view": "default"},
+		},
+		"record:host": {
+			object{"_ref": "record:host/a:web.example.com/default", "ipv4addrs": []interface{}{
+				object{"ipv4addr": "10.1.0.10"}, object{"ipv4addr": "10.2.0.10"},
+			}, "ipv6addrs": []interface{}{
+				object{"ipv6addr": "2001:db8::10"},
+			}, "network_view": "default"},
+		},
+	}
+}
+
+func newTestClient(t *testing.T, endpoint string, password string) *Client {
+	component := NewComponent()
+	conf := component.Settings()
+	conf.Endpoint = endpoint + "/wapi/v2.7"
+	conf.Username = "admin"
+	conf.Password = password
+	conf.PageSize = 2
+	conf.NetworkView = "default"
+	client, err := component.New(context.Background(), conf)
+	require.NoError(t, err)
+	return client
+}
+
+func TestFetchIPAMData(t *testing.T) {
+	fake := &fakeWAPI{Objects: fakeWAPIObjects()}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	ipamData, err := NewIPAMDataFetcher(newTestClient(t, server.URL, "infoblox")).FetchIPAMData(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, []domain.Customer{
+		{ID: "400327089", ResourceOwner: "alice@example.com", ResourceOwnerRule: "extattr:Owner", BusinessUnit: "Payments"},
+		{ID: "749216573", ResourceOwner: "bob@example.com", ResourceOwnerRule: "extattr:Owner", BusinessUnit: "Security"},
+		{ID: "897641665", BusinessUnit: "Networking"},
+	}, ipamData.Customers)
+	assert.Equal(t, []domain.Subnet{
+		{ID: "703038481", Network: "10.0.0.0", MaskBits: 16, Location: "DC1", CustomerID: "400327089"},
+		{ID: "1149348117", Network: "10.0.1.0", MaskBits: 24, Location: "DC1,DC2", CustomerID: "749216573"},
+		{ID: "1611590940", Network: "10.1.0.0", MaskBits: 16, CustomerID: "400327089"},
+		{ID: "397830879", Network: "10.2.0.0", MaskBits: 16},
+		{ID: "920506954", Network: "10.3.0.0", MaskBits: 16, CustomerID: "897641665"},
+		{ID: "329544564", Network: "2001:db8::", MaskBits: 48, Location: "DC1", CustomerID: "400327089"},
+	}, ipamData.Subnets)
+	assert.Equal(t, []domain.Device{
+		{IP: "10.0.0.5", SubnetID: "703038481"},
+		{IP: "10.0.1.5", SubnetID: "1149348117"},
+		{IP: "192.168.0.1"},
+		{IP: "2001:db8::5", SubnetID: "329544564"},
+		{IP: "10.1.0.10", SubnetID: "1611590940"},
+		{IP: "10.2.0.10", SubnetID: "397830879"},
+		{IP: "2001:db8::10", SubnetID: "329544564"},
+	}, ipamData.Devices)
+
+	assert.Contains(t, fake.Requests, "/wapi/v2.7/network?_max_results=2&_paging=1&_return_as_object=1&_return_fields%2B=network%2Cnetwork_view%2Cextattrs&network_view=default")
+	assert.Contains(t, fake.Requests, "/wapi/v2.7/ipv6network?_max_results=2&_paging=1&_return_as_object=1&_return_fields%2B=network%2Cnetwork_view%2Cextattrs&network_view=default")
+	assert.Contains(t, fake.Requests, "/wapi/v2.7/network?_page_id=network%3A2%3A2")
+	assert.Contains(t, fake.Requests, "/wapi/v2.7/network?_page_id=network%3A4%3A2")
+	assert.Contains(t, fake.Requests, "/wapi/v2.7/fixedaddress?_page_id=fixedaddress%3A2%3A2")
+	assert.Contains(t, fake.Requests, "/wapi/v2.7/fixedaddress?_max_results=2&_paging=1&_return_as_object=1&_return_fields%2B=ipv4addr%2Cnetwork_view&network_view=default")
+	assert.Contains(t, fake.Requests, "/wapi/v2.7/record:host?_max_results=2&_paging=1&_return_as_object=1&_return_fields%2B=ipv4addrs%2Cipv6addrs%2Cnetwork_view&network_view=default")
+}
+
+func TestFetchIPAMDataNetworkViews(t *testing.T) {
+	objects := map[string][]interface{}{
+		"network": {
+			object{"network": "10.0.0.0/16", "network_view": "default", "extattrs": object{}},
+			object{"network": "10.0.0.0/16", "network_view": "lab", "extattrs": object{}},
+			object{"network": "10.0.0.0/24", "network_view": "lab", "extattrs": object{}},
+		},
+		"ipv6network":      {},
+		"ipv6fixedaddress": {},
+		"fixedaddress": {
+			object{"ipv4addr": "10.0.0.5", "network_view": "default"},
+			object{"ipv4addr": "10.0.0.5", "network_view": "lab"},
+			object{"ipv4addr": "10.0.1.5", "network_view": "lab"},
+			object{"ipv4addr": "10.0.1.5", "network_view": "staging"},
+		},
+		"record:host": {
+			object{"ipv4addrs": []interface{}{object{"ipv4addr": "10.0.2.10"}}, "network_view": "default"},
+		},
+	}
+	server := httptest.NewServer(&fakeWAPI{Objects: objects})
+	defer server.Close()
+	client := newTestClient(t, server.URL, "infoblox")
+	client.NetworkView = ""
+
+	ipamData, err := NewIPAMDataFetcher(client).FetchIPAMData(context.Background())
+	require.NoError(t, err)
+	require.Len(t, ipamData.Subnets, 3)
+	defaultView, labView, labSubnet := ipamData.Subnets[0].ID, ipamData.Subnets[1].ID, ipamData.Subnets[2].ID
+	assert.NotEqual(t, defaultView, labView)
+	// addresses are only assigned to the networks of their own view
+	assert.Equal(t, []domain.Device{
+		{IP: "10.0.0.5", SubnetID: defaultView},
+		{IP: "10.0.0.5", SubnetID: labSubnet},
+		{IP: "10.0.1.5", SubnetID: labView},
+		{IP: "10.0.1.5"},
+		{IP: "10.0.2.10", SubnetID: defaultView},
+	}, ipamData.Devices)
+}
+
+func TestFetchIPAMDataStableIDs(t *testing.T) {
+	objects := fakeWAPIObjects()
+	server := httptest.NewServer(&fakeWAPI{Objects: objects})
+	defer server.Close()
+	fetcher := NewIPAMDataFetcher(newTestClient(t, server.URL, "infoblox"))
+	first, err := fetcher.FetchIPAMData(context.Background())
+	require.NoError(t, err)
+
+	networks := objects["network"]
+	for i, j := 0, len(networks)-1; i < j; i, j = i+1, j-1 {
+		networks[i], networks[j] = networks[j], networks[i]
+	}
+	reordered, err := fetcher.FetchIPAMData(context.Background())
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, first.Subnets, reordered.Subnets)
+	assert.ElementsMatch(t, first.Customers, reordered.Customers)
+	assert.ElementsMatch(t, first.Devices, reordered.Devices)
+}
+
+func TestStableIDs(t *testing.T) {
+	// the FNV-1a hashes of these keys are equal within the 31 bits of an ID, so the key sorted
+	// last takes the next ID
+	colliding := []string{"default\x0011.46.193.0/24", "default\x0011.252.130.0/24"}
+	ids := stableIDs(colliding)
+	assert.Equal(t, map[string]string{
+		"default\x0011.252.130.0/24": "683053994",
+		"default\x0011.46.193.0/24":  "683053995",
+	}, ids)
+	assert.Equal(t, ids, stableIDs([]string{colliding[1], colliding[0], colliding[1]}))
+}
+
+func TestFetchIPAMDataUnauthorized(t *testing.T) {
+	server := httptest.NewServer(&fakeWAPI{Objects: fakeWAPIObjects()})
+	defer server.Close()
+
+	_, err := NewIPAMDataFetcher(newTestClient(t, server.URL, "wrong")).FetchIPAMData(context.Background())
+	require.Error(t, err)
+}
+
+func TestFetchIPAMDataMalformed(t *testing.T) {
+	objects := fakeWAPIObjects()
+	objects["record:host"] = []interface{}{object{"ipv4addrs": "not-a-list"}}
+	server := httptest.NewServer(&fakeWAPI{Objects: objects})
+	defer server.Close()
+
+	_, err := NewIPAMDataFetcher(newTestClient(t, server.URL, "infoblox")).FetchIPAMData(context.Background())
+	require.Error(t, err)
+}
+
+func TestToSubnet(t *testing.T) {
+	tc := []struct {
+		name     string
+		network  string
+		expected domain.Subnet
+	}{
+		{name: "ipv4", network: "10.0.0.0/24", expected: domain.Subnet{ID: "1", Network: "10.0.0.0", MaskBits: 24}},
+		{name: "ipv4 host", network: "10.0.0.1/32", expected: domain.Subnet{ID: "1", Network: "10.0.0.1", MaskBits: 32}},
+		{name: "ipv6 host", network: "2001:db8::1/128", expected: domain.Subnet{ID: "1", Network: "2001:db8::1", MaskBits: 128}},
+		{name: "ipv4 mask out of range", network: "10.0.0.0/33", expected: domain.Subnet{ID: "1", Network: "10.0.0.0", MaskBits: -1}},
+		{name: "ipv6 mask out of range", network: "2001:db8::/129", expected: domain.Subnet{ID: "1", Network: "2001:db8::", MaskBits: -1}},
+		{name: "negative mask", network: "10.0.0.0/-1", expected: domain.Subnet{ID: "1", Network: "10.0.0.0", MaskBits: -1}},
+		{name: "malformed mask", network: "10.0.0.0/x", expected: domain.Subnet{ID: "1", Network: "10.0.0.0", MaskBits: -1}},
+		{name: "malformed network", network: "garbage", expected: domain.Subnet{ID: "1", Network: "garbage", MaskBits: -1}},
+	}
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, toSubnet(network{Network: tt.network}, "1"))
+		})
+	}
+}
+
+func TestCheckDependencies(t *testing.T) {
+	server := httptest.NewServer(&fakeWAPI{Objects: fakeWAPIObjects()})
+	defer server.Close()
+
+	assert.NoError(t, newTestClient(t, server.URL, "infoblox").CheckDependencies(context.Background()))
+	assert.Error(t, newTestClient(t, server.URL, "wrong").CheckDependencies(context.Background()))
+}
+
+func TestConfig(t *testing.T) {
+	assert.Equal(t, "Infoblox", (&Config{}).Name())
+
+	component := NewComponent()
+	conf := component.Settings()
+	assert.Equal(t, defaultPageSize, conf.PageSize)
+	conf.Endpoint = "https://lo\\<calhost:443"
+	_, err := component.New(context.Background(), conf)
+	assert.Error(t, err)
+}
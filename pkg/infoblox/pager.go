@@ -0,0 +1,94 @@
+package infoblox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Page represents a page of WAPI results requested with _paging. NextPageID is empty on the last page.
+type Page struct {
+	Result     json.RawMessage `json:"result"`
+	NextPageID string          `json:"next_page_id"`
+}
+
+// PageFetcher encapsulates the logic for requesting individual pages of a WAPI object type.
+type PageFetcher interface {
+	// FetchPage requests the page with the given page ID, or the first page if the ID is empty.
+	FetchPage(ctx context.Context, pageID string) (Page, error)
+}
+
+// WAPIPageFetcher implements the PageFetcher interface using WAPI paging. The first page is
+// requested with _paging, _max_results, the ReturnFields, and the Filters; following pages
+// only need the _page_id returned by the previous page.
+type WAPIPageFetcher struct {
+	Client       *http.Client
+	Endpoint     *url.URL
+	PageSize     int
+	ReturnFields []string
+	Filters      url.Values
+}
+
+// FetchPage makes a request to the WAPI for a single page of objects.
+func (f *WAPIPageFetcher) FetchPage(ctx context.Context, pageID string) (Page, error) {
+	u, _ := url.Parse(f.Endpoint.String())
+	q := url.Values{}
+	if pageID != "" {
+		q.Set("_page_id", pageID)
+	} else {
+		for key, values := range f.Filters {
+			q[key] = values
+		}
+		q.Set("_paging", "1")
+		q.Set("_return_as_object", "1")
+		q.Set("_max_results", strconv.Itoa(f.PageSize))
+		if len(f.ReturnFields) > 0 {
+			q.Set("_return_fields+", strings.Join(f.ReturnFields, ","))
+		}
+	}
+	u.RawQuery = q.Encode()
+	req, _ := http.NewRequest(http.MethodGet, u.String(), http.NoBody)
+	res, err := f.Client.Do(req.WithContext(ctx))
+	if err != nil {
+		return Page{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return Page{}, fmt.Errorf("unexpected error from infoblox wapi: %d", res.StatusCode)
+	}
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return Page{}, err
+	}
+
+	var page Page
+	if err := json.Unmarshal(body, &page); err != nil {
+		return Page{}, err
+	}
+	return page, nil
+}
+
+// fetchAll decodes the results of every page of a WAPI object type, calling decode once per page.
+func fetchAll(ctx context.Context, pageFetcher PageFetcher, decode func(json.RawMessage) error) error {
+	pageID := ""
+	for {
+		page, err := pageFetcher.FetchPage(ctx, pageID)
+		if err != nil {
+			return err
+		}
+		if err := decode(page.Result); err != nil {
+			return err
+		}
+		if page.NextPageID == "" {
+			return nil
+		}
+		pageID = page.NextPageID
+	}
+}
@@ -0,0 +1,219 @@
+package ipamfetcher
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+const (
+	// NoAuth sends requests to Device42 without credentials, for deployments where a gateway adds them.
+	NoAuth = "none"
+	// BasicAuth authenticates requests to Device42 with a username and password.
+	BasicAuth = "basic"
+	// TokenAuth authenticates requests to Device42 with an API token.
+	TokenAuth = "token"
+
+	// EnvCredentials reads credentials from the Username, Password, and Token settings.
+	EnvCredentials = "env"
+	// FileCredentials reads credentials from a file, such as a mounted secret.
+	FileCredentials = "file"
+	// ExecCredentials reads credentials from the output of a credential helper command.
+	ExecCredentials = "exec"
+)
+
+// Credentials authenticate requests to Device42. Token is used for token authentication,
+// and Username and Password are used for basic authentication.
+type Credentials struct {
+	Username string
+	Password string
+	Token    string
+}
+
+// CredentialLoader encapsulates the logic for loading Device42 credentials. It is called again
+// whenever Device42 rejects the current credentials, so that rotated credentials are picked up.
+type CredentialLoader interface {
+	LoadCredentials(ctx context.Context) (Credentials, error)
+}
+
+// StaticCredentialLoader implements the CredentialLoader interface with fixed credentials,
+// such as those read from the environment at startup.
+type StaticCredentialLoader struct {
+	Credentials Credentials
+}
+
+// LoadCredentials returns the fixed credentials.
+func (l *StaticCredentialLoader) LoadCredentials(ctx context.Context) (Credentials, error) {
+	return l.Credentials, nil
+}
+
+// FileCredentialLoader implements the CredentialLoader interface by reading a file. The file
+// holds "username:password" for basic authentication, or the token for token authentication.
+type FileCredentialLoader struct {
+	AuthType string
+	Path     string
+}
+
+// LoadCredentials reads the credentials file.
+func (l *FileCredentialLoader) LoadCredentials(ctx context.Context) (Credentials, error) {
+	content, err := ioutil.ReadFile(l.Path)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("failed to read device42 credentials file: %v", err)
+	}
+	return parseCredentials(l.AuthType, string(content))
+}
+
+// ExecCredentialLoader implements the CredentialLoader interface by running a credential helper
+// command. The command's output has the same format as a credentials file.
+type ExecCredentialLoader struct {
+	AuthType string
+	Command  []string
+}
+
+// LoadCredentials runs the credential helper command.
+func (l *ExecCredentialLoader) LoadCredentials(ctx context.Context) (Credentials, error) {
+	// the command is run without a shell, so its arguments are never interpreted
+	output, err := exec.CommandContext(ctx, l.Command[0], l.Command[1:]...).Output()
+	if err != nil {
+		return Credentials{}, fmt.Errorf("device42 credential helper %q failed: %v", l.Command[0], err)
+	}
+	return parseCredentials(l.AuthType, string(output))
+}
+
+// parseCredentials parses the content of a credentials file or the output of a credential helper.
+func parseCredentials(authType string, content string) (Credentials, error) {
+	content = strings.TrimSpace(content)
+	if authType == TokenAuth {
+		if content == "" {
+			return Credentials{}, fmt.Errorf("device42 credentials are missing the token")
+		}
+		return Credentials{Token: content}, nil
+	}
+	separator := strings.Index(content, ":")
+	if separator < 1 {
+		return Credentials{}, fmt.Errorf("device42 credentials must have the form username:password")
+	}
+	return Credentials{Username: content[:separator], Password: content[separator+1:]}, nil
+}
+
+// newCredentialLoader builds the CredentialLoader described by a config.
+func newCredentialLoader(c *Device42ClientConfig, authType string) (CredentialLoader, error) {
+	switch strings.ToLower(c.CredentialSource) {
+	case EnvCredentials, "":
+		if authType == TokenAuth && c.Token == "" {
+			return nil, fmt.Errorf("Device42 token authentication requires a Token")
+		}
+		if authType == BasicAuth && c.Username == "" {
+			return nil, fmt.Errorf("Device42 basic authentication requires a Username")
+		}
+		return &StaticCredentialLoader{Credentials: Credentials{Username: c.Username, Password: c.Password, Token: c.Token}}, nil
+	case FileCredentials:
+		if c.CredentialFile == "" {
+			return nil, fmt.Errorf("Device42 file credentials require a CredentialFile")
+		}
+		return &FileCredentialLoader{AuthType: authType, Path: c.CredentialFile}, nil
+	case ExecCredentials:
+		command := strings.Fields(c.CredentialCommand)
+		if len(command) == 0 {
+			return nil, fmt.Errorf("Device42 exec credentials require a CredentialCommand")
+		}
+		return &ExecCredentialLoader{AuthType: authType, Command: command}, nil
+	default:
+		return nil, fmt.Errorf("unknown Device42 credential source %q", c.CredentialSource)
+	}
+}
+
+// AuthTransport authenticates every request to Device42. Credentials are loaded on the first
+// request and cached. When Device42 responds with a 401, the credentials are loaded again and,
+// if they have changed, the request is retried once with the new credentials.
+type AuthTransport struct {
+	AuthType string
+	Loader   CredentialLoader
+	Wrapped  http.RoundTripper
+
+	lock        sync.Mutex
+	credentials *Credentials
+}
+
+// RoundTrip adds the credentials to a copy of the request.
+func (t *AuthTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	credentials, err := t.current(r.Context())
+	if err != nil {
+		return nil, err
+	}
+	res, err := t.Wrapped.RoundTrip(t.authenticate(r, credentials))
+	if err != nil || res.StatusCode != http.StatusUnauthorized || !replayable(r) {
+		return res, err
+	}
+
+	reloaded, err := t.reload(r.Context(), credentials)
+	if err != nil || reloaded == credentials {
+		// the rejected response is more useful to the caller than a reload failure
+		return res, nil
+	}
+	res.Body.Close()
+	if r.GetBody != nil {
+		body, err := r.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		r = r.WithContext(r.Context())
+		r.Body = body
+	}
+	return t.Wrapped.RoundTrip(t.authenticate(r, reloaded))
+}
+
+// current returns the cached credentials, loading them if needed.
+func (t *AuthTransport) current(ctx context.Context) (Credentials, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.credentials == nil {
+		credentials, err := t.Loader.LoadCredentials(ctx)
+		if err != nil {
+			return Credentials{}, err
+		}
+		t.credentials = &credentials
+	}
+	return *t.credentials, nil
+}
+
+// reload loads the credentials again after Device42 rejected the given ones. Requests that fail
+// together only reload once, because later ones see that the cache already changed.
+func (t *AuthTransport) reload(ctx context.Context, rejected Credentials) (Credentials, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.credentials != nil && *t.credentials != rejected {
+		return *t.credentials, nil
+	}
+	credentials, err := t.Loader.LoadCredentials(ctx)
+	if err != nil {
+		return Credentials{}, err
+	}
+	t.credentials = &credentials
+	return credentials, nil
+}
+
+// authenticate returns a copy of the request with the authorization header set.
+func (t *AuthTransport) authenticate(r *http.Request, credentials Credentials) *http.Request {
+	header := make(http.Header, len(r.Header)+1)
+	for key, values := range r.Header {
+		header[key] = values
+	}
+	r = r.WithContext(r.Context())
+	r.Header = header
+	if t.AuthType == TokenAuth {
+		r.Header.Set("Authorization", "Bearer "+credentials.Token)
+	} else {
+		r.SetBasicAuth(credentials.Username, credentials.Password)
+	}
+	return r
+}
+
+// replayable reports whether a request can be sent again.
+func replayable(r *http.Request) bool {
+	return r.Body == nil || r.Body == http.NoBody || r.GetBody != nil
+}
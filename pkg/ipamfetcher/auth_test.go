@@ -0,0 +1,171 @@
+package ipamfetcher
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingLoader counts how many times credentials are loaded.
+type countingLoader struct {
+	Credentials []Credentials
+	Loads       int
+}
+
+func (l *countingLoader) LoadCredentials(ctx context.Context) (Credentials, error) {
+	credentials := l.Credentials[l.Loads]
+	if l.Loads < len(l.Credentials)-1 {
+		l.Loads++
+	}
+	return credentials, nil
+}
+
+func newAuthServer(authorization string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != authorization {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+func writeCredentialFile(t *testing.T, content string) string {
+	f, err := ioutil.TempFile("", "device42-credentials")
+	require.NoError(t, err)
+	_, err = f.WriteString(content)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	return f.Name()
+}
+
+func TestAuthTransportReloadsOnUnauthorized(t *testing.T) {
+	server := newAuthServer("Bearer new-token")
+	defer server.Close()
+
+	loader := &countingLoader{Credentials: []Credentials{{Token: "old-token"}, {Token: "new-token"}}}
+	client := &http.Client{Transport: &AuthTransport{AuthType: TokenAuth, Loader: loader, Wrapped: http.DefaultTransport}}
+	for i := 0; i < 2; i++ {
+		res, err := client.Get(server.URL)
+		require.NoError(t, err)
+		res.Body.Close()
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+	}
+	// loaded once at the first request and once after the 401, then cached
+	assert.Equal(t, 1, loader.Loads)
+}
+
+func TestAuthTransportUnchangedCredentials(t *testing.T) {
+	server := newAuthServer("Bearer valid-token")
+	defer server.Close()
+
+	loader := &countingLoader{Credentials: []Credentials{{Token: "expired-token"}}}
+	client := &http.Client{Transport: &AuthTransport{AuthType: TokenAuth, Loader: loader, Wrapped: http.DefaultTransport}}
+	res, err := client.Get(server.URL)
+	require.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+}
+
+func TestAuthTransportLoadError(t *testing.T) {
+	transport := &AuthTransport{
+		AuthType: BasicAuth,
+		Loader:   &FileCredentialLoader{AuthType: BasicAuth, Path: "/does/not/exist"},
+		Wrapped:  http.DefaultTransport,
+	}
+	_, err := (&http.Client{Transport: transport}).Get("http://localhost")
+	assert.Error(t, err)
+}
+
+func TestFileCredentialRotation(t *testing.T) {
+	server := newAuthServer("Basic " + "YWRtaW46bmV3LXBhc3N3b3Jk") // admin:new-password
+	defer server.Close()
+	path := writeCredentialFile(t, "admin:old-password\n")
+	defer os.Remove(path)
+
+	component := NewDevice42ClientComponent()
+	config := component.Settings()
+	config.Endpoint = server.URL
+	config.AuthType = BasicAuth
+	config.CredentialSource = FileCredentials
+	config.CredentialFile = path
+	client, err := component.New(context.Background(), config)
+	require.NoError(t, err)
+
+	assert.Error(t, client.CheckDependencies(context.Background()))
+	require.NoError(t, ioutil.WriteFile(path, []byte("admin:new-password\n"), 0600))
+	assert.NoError(t, client.CheckDependencies(context.Background()))
+}
+
+func TestExecCredentialLoader(t *testing.T) {
+	loader := &ExecCredentialLoader{AuthType: TokenAuth, Command: []string{"echo", "helper-token"}}
+	credentials, err := loader.LoadCredentials(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, Credentials{Token: "helper-token"}, credentials)
+
+	loader = &ExecCredentialLoader{AuthType: TokenAuth, Command: []string{"false"}}
+	_, err = loader.LoadCredentials(context.Background())
+	assert.Error(t, err)
+}
+
+func TestParseCredentials(t *testing.T) {
+	tc := []struct {
+		Name        string
+		AuthType    string
+		Content     string
+		Credentials Credentials
+		Err         error
+	}{
+		{Name: "basic", AuthType: BasicAuth, Content: "admin:pass:word\n", Credentials: Credentials{Username: "admin", Password: "pass:word"}},
+		{Name: "basic without password", AuthType: BasicAuth, Content: "admin", Err: errors.New("device42 credentials must have the form username:password")},
+		{Name: "basic without username", AuthType: BasicAuth, Content: ":password", Err: errors.New("device42 credentials must have the form username:password")},
+		{Name: "token", AuthType: TokenAuth, Content: " token\n", Credentials: Credentials{Token: "token"}},
+		{Name: "empty token", AuthType: TokenAuth, Content: "\n", Err: errors.New("device42 credentials are missing the token")},
+	}
+	for _, tt := range tc {
+		t.Run(tt.Name, func(t *testing.T) {
+			credentials, err := parseCredentials(tt.AuthType, tt.Content)
+			assert.Equal(t, tt.Err, err)
+			assert.Equal(t, tt.Credentials, credentials)
+		})
+	}
+}
+
+func TestAuthConfig(t *testing.T) {
+	tc := []struct {
+		Name   string
+		Modify func(*Device42ClientConfig)
+		Err    string
+	}{
+		{Name: "unknown auth type", Modify: func(c *Device42ClientConfig) { c.AuthType = "kerberos" }, Err: "unknown Device42 authentication type"},
+		{Name: "env basic without username", Modify: func(c *Device42ClientConfig) { c.AuthType = BasicAuth }, Err: "requires a Username"},
+		{Name: "env token without token", Modify: func(c *Device42ClientConfig) { c.AuthType = TokenAuth }, Err: "requires a Token"},
+		{Name: "file without path", Modify: func(c *Device42ClientConfig) { c.AuthType = TokenAuth; c.CredentialSource = FileCredentials }, Err: "require a CredentialFile"},
+		{Name: "exec without command", Modify: func(c *Device42ClientConfig) { c.AuthType = TokenAuth; c.CredentialSource = ExecCredentials }, Err: "require a CredentialCommand"},
+		{Name: "unknown source", Modify: func(c *Device42ClientConfig) { c.AuthType = TokenAuth; c.CredentialSource = "vault" }, Err: "unknown Device42 credential source"},
+		{Name: "env token", Modify: func(c *Device42ClientConfig) { c.AuthType = "TOKEN"; c.Token = "token" }},
+	}
+	for _, tt := range tc {
+		t.Run(tt.Name, func(t *testing.T) {
+			component := NewDevice42ClientComponent()
+			config := component.Settings()
+			tt.Modify(config)
+			client, err := component.New(context.Background(), config)
+			if tt.Err != "" {
+				require.Error(t, err)
+				assert.True(t, strings.Contains(err.Error(), tt.Err), err.Error())
+				return
+			}
+			require.NoError(t, err)
+			assert.IsType(t, &AuthTransport{}, client.Client.Transport)
+		})
+	}
+}
@@ -6,15 +6,40 @@ import (
 	"net/http"
 	"net/url"
 	"path"
+	"strings"
+	"time"
 
 	httpclient "github.com/asecurityteam/component-httpclient"
 )
 
+const (
+	// RESTMode fetches IPAM data by paging through the Device42 REST API.
+	RESTMode = "rest"
+	// DOQLMode fetches IPAM data with one Device42 DOQL query for each kind of record.
+	DOQLMode = "doql"
+)
+
 // Device42ClientConfig contains configuration settings for a Device42Client
 type Device42ClientConfig struct {
-	Endpoint string
-	Limit    int
-	HTTP     *httpclient.Config
+	Endpoint               string
+	Limit                  int
+	Mode                   string        `description:"How IPAM data is fetched from Device42. One of: rest, doql."`
+	DOQLCustomerQuery      string        `description:"DOQL query for customers, returning customer_id, name, and contact_info columns. Used in doql mode."`
+	DOQLContactQuery       string        `description:"DOQL query for customer contacts, returning customer_id, type, name, email, and phone columns. Used in doql mode."`
+	DOQLCustomerFieldQuery string        `description:"DOQL query for customer custom fields, returning customer_id, key, and value columns. Used in doql mode."`
+	DOQLSubnetQuery        string        `description:"DOQL query for subnets, returning subnet_id, network, mask_bits, location, and customer_id columns. Used in doql mode."`
+	DOQLIPQuery            string        `description:"DOQL query for IP addresses, returning ip, subnet_id, and device_id columns. Used in doql mode."`
+	AuthType               string        `description:"How requests to Device42 are authenticated. One of: none, basic, token. Use none when a gateway adds the credentials."`
+	CredentialSource       string        `description:"Where Device42 credentials are loaded from. One of: env, file, exec."`
+	Username               string        `description:"Device42 user for basic authentication. Used with the env credential source."`
+	Password               string        `description:"Password of the Device42 user. Used with the env credential source."`
+	Token                  string        `description:"Device42 API token for token authentication. Used with the env credential source."`
+	CredentialFile         string        `description:"File holding username:password for basic authentication, or the token. Used with the file credential source."`
+	CredentialCommand      string        `description:"Credential helper command, split on whitespace, whose output is read like a credential file. Used with the exec credential source."`
+	IncrementalSync        bool          `description:"Fetch only the subnets and IPs updated since the last sync, with a periodic full fetch to reconcile deletions. Used in rest mode."`
+	FullSyncInterval       time.Duration `description:"Time between the full fetches of an incremental sync."`
+	IncrementalLookback    time.Duration `description:"How far before the start of the previous sync an incremental sync looks for updates, to allow for clock skew."`
+	HTTP                   *httpclient.Config
 }
 
 // Name is used by the settings library to replace the default naming convention.
@@ -39,23 +64,66 @@ type Device42ClientComponent struct {
 // if none are provided via config.
 func (d *Device42ClientComponent) Settings() *Device42ClientConfig {
 	return &Device42ClientConfig{
-		HTTP: d.HTTP.Settings(),
+		Mode:                   RESTMode,
+		DOQLCustomerQuery:      defaultDOQLCustomerQuery,
+		DOQLContactQuery:       defaultDOQLContactQuery,
+		DOQLCustomerFieldQuery: defaultDOQLCustomerFieldQuery,
+		DOQLSubnetQuery:        defaultDOQLSubnetQuery,
+		DOQLIPQuery:            defaultDOQLIPQuery,
+		AuthType:               NoAuth,
+		CredentialSource:       EnvCredentials,
+		FullSyncInterval:       time.Hour,
+		IncrementalLookback:    5 * time.Minute,
+		HTTP:                   d.HTTP.Settings(),
 	}
 }
 
 // New constructs a Device42Client from a config.
 func (d *Device42ClientComponent) New(ctx context.Context, c *Device42ClientConfig) (*Device42Client, error) {
+	mode := strings.ToLower(c.Mode)
+	switch mode {
+	case "":
+		mode = RESTMode
+	case RESTMode, DOQLMode:
+	default:
+		return nil, fmt.Errorf("unknown Device42 fetch mode %q", c.Mode)
+	}
+	if c.IncrementalSync && mode != RESTMode {
+		return nil, fmt.Errorf("Device42 incremental sync requires the %s fetch mode", RESTMode)
+	}
 	rt, e := d.HTTP.New(ctx, c.HTTP)
 	if e != nil {
 		return nil, e
 	}
+	switch authType := strings.ToLower(c.AuthType); authType {
+	case NoAuth, "":
+	case BasicAuth, TokenAuth:
+		loader, e := newCredentialLoader(c, authType)
+		if e != nil {
+			return nil, e
+		}
+		rt = &AuthTransport{AuthType: authType, Loader: loader, Wrapped: rt}
+	default:
+		return nil, fmt.Errorf("unknown Device42 authentication type %q", c.AuthType)
+	}
 	u, e := url.Parse(c.Endpoint)
 	if e != nil {
 		return nil, e
 	}
 	return &Device42Client{
-		Endpoint: u,
-		Limit:    c.Limit,
+		Endpoint:            u,
+		Limit:               c.Limit,
+		Mode:                mode,
+		IncrementalSync:     c.IncrementalSync,
+		FullSyncInterval:    c.FullSyncInterval,
+		IncrementalLookback: c.IncrementalLookback,
+		DOQLQueries: DOQLQueries{
+			Customers:      c.DOQLCustomerQuery,
+			Contacts:       c.DOQLContactQuery,
+			CustomerFields: c.DOQLCustomerFieldQuery,
+			Subnets:        c.DOQLSubnetQuery,
+			IPs:            c.DOQLIPQuery,
+		},
 		Client: &http.Client{
 			Transport: rt,
 		},
@@ -64,9 +132,16 @@ func (d *Device42ClientComponent) New(ctx context.Context, c *Device42ClientConf
 
 // Device42Client contains values to configure a Device42 client
 type Device42Client struct {
-	Client   *http.Client
-	Endpoint *url.URL
-	Limit    int
+	Client      *http.Client
+	Endpoint    *url.URL
+	Limit       int
+	Mode        string
+	DOQLQueries DOQLQueries
+	// IncrementalSync, FullSyncInterval, and IncrementalLookback configure the
+	// Device42IncrementalFetcher in rest mode.
+	IncrementalSync     bool
+	FullSyncInterval    time.Duration
+	IncrementalLookback time.Duration
 }
 
 // CheckDependencies makes a call to Endpoint, no path is involved. This is the only
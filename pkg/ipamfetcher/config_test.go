@@ -27,6 +27,15 @@ func TestDefaultConfig(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestBadMode(t *testing.T) {
+	component := NewDevice42ClientComponent()
+	config := component.Settings()
+	assert.Equal(t, RESTMode, config.Mode)
+	config.Mode = "graphql"
+	_, err := component.New(context.Background(), config)
+	assert.Error(t, err)
+}
+
 func TestBadEndpoint(t *testing.T) {
 	component := NewDevice42ClientComponent()
 	config := &Device42ClientConfig{
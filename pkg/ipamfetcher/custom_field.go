@@ -21,3 +21,14 @@ func (c customFields) GetValue(key string) string {
 	}
 	return ""
 }
+
+// Values retrieves all string values from a set of custom fields, keyed by field key
+func (c customFields) Values() map[string]string {
+	values := make(map[string]string, len(c))
+	for _, field := range c {
+		if value, ok := field.Value.(string); ok {
+			values[field.Key] = value
+		}
+	}
+	return values
+}
@@ -7,42 +7,12 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
-	"os"
 	"path"
 	"strconv"
-	"strings"
 
 	"github.com/asecurityteam/ipam-facade/pkg/domain"
-	"github.com/asecurityteam/settings"
 )
 
-// config contains all configuration values for creating a system logger.
-type config struct {
-	TypeSearchOrder string `description:"Comma-delimited priority list of 'type' value in the IPAM Contacts objects in which to search for non-empty 'email' to use as the resource owner.  If no values are found, the logic falls back to using Customer.contact_info"`
-}
-
-// Name of the configuration as it might appear in config files.
-func (*config) Name() string {
-	return "Contact"
-}
-
-// component enables creating configured logic.
-type component struct{}
-
-// settings generates a Config with default values applied.
-func (*component) Settings() *config {
-	return &config{}
-}
-
-// New creates a function that loads the config from the designated source
-func (*component) New(_ context.Context, c *config) (*searchOrder, error) {
-	return &searchOrder{keys: strings.Split(c.TypeSearchOrder, ",")}, nil
-}
-
-type searchOrder struct {
-	keys []string
-}
-
 type customersResponse struct {
 	Customers []customer `json:"Customers"`
 }
@@ -58,23 +28,27 @@ type customer struct {
 // Contact represents each contact object under the Customer object "Contacts" array.
 type Contact struct {
 	Type  string `json:"type"`
+	Name  string `json:"name"`
 	Email string `json:"email"`
+	Phone string `json:"phone"`
 }
 
 // NewDevice42CustomerFetcher generates a new Device42CustomerFetcher
-func NewDevice42CustomerFetcher(dc *Device42Client) *Device42CustomerFetcher {
+func NewDevice42CustomerFetcher(dc *Device42Client, resolver OwnerResolver) *Device42CustomerFetcher {
 	resourceEndpoint, _ := url.Parse(dc.Endpoint.String())
 	resourceEndpoint.Path = path.Join(resourceEndpoint.Path, "api", "1.0", "customers")
 	return &Device42CustomerFetcher{
-		Client:   dc.Client,
-		Endpoint: resourceEndpoint,
+		Client:        dc.Client,
+		Endpoint:      resourceEndpoint,
+		OwnerResolver: resolver,
 	}
 }
 
 // Device42CustomerFetcher fetches customer data from Device42
 type Device42CustomerFetcher struct {
-	Client   *http.Client
-	Endpoint *url.URL
+	Client        *http.Client
+	Endpoint      *url.URL
+	OwnerResolver OwnerResolver
 }
 
 // FetchCustomers fetches customers from IPAM
@@ -102,63 +76,58 @@ func (d *Device42CustomerFetcher) FetchCustomers(ctx context.Context) ([]domain.
 	}
 	customers := make([]domain.Customer, 0, len(getCustomersResponse.Customers))
 	for _, customer := range getCustomersResponse.Customers {
-		businessUnit := customer.CustomFields.GetValue("Description")
-		if businessUnit == "" {
-			// fallback to customer name
-			businessUnit = customer.Name
-		}
-		customers = append(customers, domain.Customer{
-			ID:            strconv.Itoa(customer.ID),
-			ResourceOwner: getResourceOwner(customer),
-			BusinessUnit:  businessUnit,
-		})
+		customers = append(customers, toCustomer(customer, d.OwnerResolver))
 	}
 	return customers, nil
 }
 
-func getResourceOwner(customer customer) string {
-
-	/*
-	   Find the best resource owner by searching through the contacts
-	   for non-empty Value for the following Key, in the priority order
-	   specified by the CONTACT_TYPESEARCHORDER environment variable, with a
-	   fall back to The "contact_info" field.
-	*/
-
-	searchOrder := new(searchOrder)
-	source, err := settings.NewEnvSource(os.Environ())
-	if err != nil {
-		panic(err.Error())
+// toCustomer converts a Device42 customer into its domain representation. Both the REST
+// and DOQL fetch modes share this mapping so that they produce the same Customers.
+func toCustomer(customer customer, resolver OwnerResolver) domain.Customer {
+	businessUnit := customer.CustomFields.GetValue("Description")
+	if businessUnit == "" {
+		// fallback to customer name
+		businessUnit = customer.Name
 	}
-	err = settings.NewComponent(context.Background(), source, &component{}, searchOrder)
-	if err != nil {
-		panic(err.Error())
+	owner := getResourceOwner(customer, resolver)
+	return domain.Customer{
+		ID:                strconv.Itoa(customer.ID),
+		ResourceOwner:     owner.Owner,
+		ResourceOwnerRule: owner.Rule,
+		BusinessUnit:      businessUnit,
+		Contacts:          getContacts(customer),
 	}
+}
 
-	highestPriorityFound := len(searchOrder.keys)
-
-	owner := customer.ContactInfo
-
+// getContacts converts every contact registered for the customer into its domain
+// representation, regardless of which one was chosen as the resource owner.
+func getContacts(customer customer) []domain.Contact {
+	var contacts []domain.Contact
 	for _, contact := range customer.Contacts {
-		keyIndex := keyIndex(searchOrder.keys, contact.Type)
-		if keyIndex > -1 && keyIndex < highestPriorityFound && contact.Email != "" {
-			// this is one of the contacts we're looking for, and it's higher priority than any others we've found so far
-			owner = contact.Email
-			highestPriorityFound = keyIndex
-		}
+		contacts = append(contacts, domain.Contact{
+			Type:  contact.Type,
+			Name:  contact.Name,
+			Email: contact.Email,
+			Phone: contact.Phone,
+		})
 	}
-
-	return owner
-
+	return contacts
 }
 
-func keyIndex(haystack []string, needle string) int {
-	keyIndex := -1
-	for _, straw := range haystack {
-		keyIndex++
-		if straw == needle {
-			return keyIndex
+// getResourceOwner asks the OwnerResolver for the best resource owner of the customer,
+// with a fall back to the "contact_info" field.
+func getResourceOwner(customer customer, resolver OwnerResolver) OwnerResolution {
+	if resolver != nil {
+		candidate := OwnerCandidate{
+			ID:           customer.ID,
+			Name:         customer.Name,
+			ContactInfo:  customer.ContactInfo,
+			Contacts:     customer.Contacts,
+			CustomFields: customer.CustomFields.Values(),
+		}
+		if resolution, ok := resolver.ResolveOwner(candidate); ok {
+			return resolution
 		}
 	}
-	return -1
+	return OwnerResolution{Owner: customer.ContactInfo, Rule: ContactInfoRule}
 }
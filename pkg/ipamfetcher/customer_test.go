@@ -7,7 +7,6 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
-	"os"
 	"testing"
 
 	gomock "github.com/golang/mock/gomock"
@@ -32,7 +31,7 @@ func TestNewDevice42CustomerFetcher(t *testing.T) {
 		HTTP:     component.HTTP.Settings(),
 	}
 	client, _ := component.New(context.Background(), config)
-	fetcher := NewDevice42CustomerFetcher(client)
+	fetcher := NewDevice42CustomerFetcher(client, &ContactTypeResolver{})
 	assert.Equal(t, "https://localhost:443/api/1.0/customers", fetcher.Endpoint.String())
 }
 
@@ -53,7 +52,7 @@ func TestFetchCustomers(t *testing.T) {
 
 	customers, err := c.FetchCustomers(context.Background())
 	assert.Nil(t, err)
-	assert.ElementsMatch(t, []domain.Customer{domain.Customer{ID: "1", ResourceOwner: "foo@atlassian.com", BusinessUnit: "Security"}, domain.Customer{ID: "2", ResourceOwner: "bar@atlassian.com", BusinessUnit: "Bitbucket"}}, customers)
+	assert.ElementsMatch(t, []domain.Customer{domain.Customer{ID: "1", ResourceOwner: "foo@atlassian.com", ResourceOwnerRule: ContactInfoRule, BusinessUnit: "Security"}, domain.Customer{ID: "2", ResourceOwner: "bar@atlassian.com", ResourceOwnerRule: ContactInfoRule, BusinessUnit: "Bitbucket"}}, customers)
 }
 
 func TestFetchCustomersFallbackToName(t *testing.T) {
@@ -74,7 +73,7 @@ func TestFetchCustomersFallbackToName(t *testing.T) {
 
 	customers, err := c.FetchCustomers(context.Background())
 	assert.Nil(t, err)
-	assert.ElementsMatch(t, []domain.Customer{domain.Customer{ID: "1", ResourceOwner: "foo@atlassian.com", BusinessUnit: "BobTheBusinessUnit"}, domain.Customer{ID: "2", ResourceOwner: "bar@atlassian.com", BusinessUnit: "Bitbucket"}}, customers)
+	assert.ElementsMatch(t, []domain.Customer{domain.Customer{ID: "1", ResourceOwner: "foo@atlassian.com", ResourceOwnerRule: ContactInfoRule, BusinessUnit: "BobTheBusinessUnit"}, domain.Customer{ID: "2", ResourceOwner: "bar@atlassian.com", ResourceOwnerRule: ContactInfoRule, BusinessUnit: "Bitbucket"}}, customers)
 }
 
 func TestFetchCustomersNoContacts(t *testing.T) {
@@ -94,14 +93,10 @@ func TestFetchCustomersNoContacts(t *testing.T) {
 
 	customers, err := c.FetchCustomers(context.Background())
 	assert.Nil(t, err)
-	assert.ElementsMatch(t, []domain.Customer{domain.Customer{ID: "1", ResourceOwner: "contactinfo@atlassian.com", BusinessUnit: "Security"}}, customers)
+	assert.ElementsMatch(t, []domain.Customer{domain.Customer{ID: "1", ResourceOwner: "contactinfo@atlassian.com", ResourceOwnerRule: ContactInfoRule, BusinessUnit: "Security"}}, customers)
 }
 
 func TestFetchCustomersUseTeamLead(t *testing.T) {
-
-	os.Setenv("CONTACT_TYPESEARCHORDER", "Team Lead,Administrative,SRE,Technical")
-	defer os.Clearenv()
-
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	mockRT := NewMockRoundTripper(ctrl)
@@ -114,18 +109,14 @@ func TestFetchCustomersUseTeamLead(t *testing.T) {
 	)
 	endpoint, _ := url.Parse("http://locaEndpoint")
 
-	c := &Device42CustomerFetcher{Endpoint: endpoint, Client: &http.Client{Transport: mockRT}}
+	c := &Device42CustomerFetcher{Endpoint: endpoint, Client: &http.Client{Transport: mockRT}, OwnerResolver: &ContactTypeResolver{TypeSearchOrder: []string{"Team Lead", "Administrative", "SRE", "Technical"}}}
 
 	customers, err := c.FetchCustomers(context.Background())
 	assert.Nil(t, err)
-	assert.ElementsMatch(t, []domain.Customer{domain.Customer{ID: "1", ResourceOwner: "teamlead@atlassian.com", BusinessUnit: "Security"}}, customers)
+	assert.ElementsMatch(t, []domain.Customer{domain.Customer{ID: "1", ResourceOwner: "teamlead@atlassian.com", ResourceOwnerRule: "contact-type:Team Lead", BusinessUnit: "Security", Contacts: []domain.Contact{{Type: "Team Lead", Email: "teamlead@atlassian.com"}}}}, customers)
 }
 
 func TestFetchCustomersUseAdministrative(t *testing.T) {
-
-	os.Setenv("CONTACT_TYPESEARCHORDER", "Team Lead,Administrative,SRE,Technical")
-	defer os.Clearenv()
-
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	mockRT := NewMockRoundTripper(ctrl)
@@ -138,18 +129,14 @@ func TestFetchCustomersUseAdministrative(t *testing.T) {
 	)
 	endpoint, _ := url.Parse("http://locaEndpoint")
 
-	c := &Device42CustomerFetcher{Endpoint: endpoint, Client: &http.Client{Transport: mockRT}}
+	c := &Device42CustomerFetcher{Endpoint: endpoint, Client: &http.Client{Transport: mockRT}, OwnerResolver: &ContactTypeResolver{TypeSearchOrder: []string{"Team Lead", "Administrative", "SRE", "Technical"}}}
 
 	customers, err := c.FetchCustomers(context.Background())
 	assert.Nil(t, err)
-	assert.ElementsMatch(t, []domain.Customer{domain.Customer{ID: "1", ResourceOwner: "administrative@atlassian.com", BusinessUnit: "Security"}}, customers)
+	assert.ElementsMatch(t, []domain.Customer{domain.Customer{ID: "1", ResourceOwner: "administrative@atlassian.com", ResourceOwnerRule: "contact-type:Administrative", BusinessUnit: "Security", Contacts: []domain.Contact{{Type: "Administrative", Email: "administrative@atlassian.com"}, {Type: "SRE", Email: "sre@atlassian.com"}}}}, customers)
 }
 
 func TestFetchCustomersUseSRE(t *testing.T) {
-
-	os.Setenv("CONTACT_TYPESEARCHORDER", "Team Lead,Administrative,SRE,Technical")
-	defer os.Clearenv()
-
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	mockRT := NewMockRoundTripper(ctrl)
@@ -162,35 +149,61 @@ func TestFetchCustomersUseSRE(t *testing.T) {
 	)
 	endpoint, _ := url.Parse("http://locaEndpoint")
 
-	c := &Device42CustomerFetcher{Endpoint: endpoint, Client: &http.Client{Transport: mockRT}}
+	c := &Device42CustomerFetcher{Endpoint: endpoint, Client: &http.Client{Transport: mockRT}, OwnerResolver: &ContactTypeResolver{TypeSearchOrder: []string{"Team Lead", "Administrative", "SRE", "Technical"}}}
 
 	customers, err := c.FetchCustomers(context.Background())
 	assert.Nil(t, err)
-	assert.ElementsMatch(t, []domain.Customer{domain.Customer{ID: "1", ResourceOwner: "sre@atlassian.com", BusinessUnit: "Security"}}, customers)
+	assert.ElementsMatch(t, []domain.Customer{domain.Customer{ID: "1", ResourceOwner: "sre@atlassian.com", ResourceOwnerRule: "contact-type:SRE", BusinessUnit: "Security", Contacts: []domain.Contact{{Type: "Technical", Email: "technical@atlassian.com"}, {Type: "SRE", Email: "sre@atlassian.com"}}}}, customers)
 }
 
 func TestFetchCustomersUseTechnical(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRT := NewMockRoundTripper(ctrl)
+	mockRT.EXPECT().RoundTrip(gomock.Any()).Return(
+		&http.Response{
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte(`{"Customers": [{"id": 1, "contact_info": "contactinfo@atlassian.com", "custom_fields": [{"key": "Description", "value": "Security"}], "Contacts": [{"type": "Technical", "email": "technical@atlassian.com"}]}]}`))),
+			StatusCode: http.StatusOK,
+		},
+		nil,
+	)
+	endpoint, _ := url.Parse("http://locaEndpoint")
+
+	c := &Device42CustomerFetcher{Endpoint: endpoint, Client: &http.Client{Transport: mockRT}, OwnerResolver: &ContactTypeResolver{TypeSearchOrder: []string{"Team Lead", "Administrative", "SRE", "Technical"}}}
 
-	os.Setenv("CONTACT_TYPESEARCHORDER", "Team Lead,Administrative,SRE,Technical")
-	defer os.Clearenv()
+	customers, err := c.FetchCustomers(context.Background())
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []domain.Customer{domain.Customer{ID: "1", ResourceOwner: "technical@atlassian.com", ResourceOwnerRule: "contact-type:Technical", BusinessUnit: "Security", Contacts: []domain.Contact{{Type: "Technical", Email: "technical@atlassian.com"}}}}, customers)
+}
 
+func TestFetchCustomersAllContacts(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	mockRT := NewMockRoundTripper(ctrl)
 	mockRT.EXPECT().RoundTrip(gomock.Any()).Return(
 		&http.Response{
-			Body:       ioutil.NopCloser(bytes.NewReader([]byte(`{"Customers": [{"id": 1, "contact_info": "contactinfo@atlassian.com", "custom_fields": [{"key": "Description", "value": "Security"}], "Contacts": [{"type": "Technical", "email": "technical@atlassian.com"}]}]}`))),
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte(`{"Customers": [{"id": 1, "contact_info": "contactinfo@atlassian.com", "custom_fields": [{"key": "Description", "value": "Security"}], "Contacts": [{"type": "Technical", "name": "Alice", "email": "technical@atlassian.com", "phone": "555-0100"}, {"type": "Escalation", "name": "Bob", "email": "escalation@atlassian.com", "phone": "555-0199"}]}]}`))),
 			StatusCode: http.StatusOK,
 		},
 		nil,
 	)
 	endpoint, _ := url.Parse("http://locaEndpoint")
 
-	c := &Device42CustomerFetcher{Endpoint: endpoint, Client: &http.Client{Transport: mockRT}}
+	c := &Device42CustomerFetcher{Endpoint: endpoint, Client: &http.Client{Transport: mockRT}, OwnerResolver: &ContactTypeResolver{TypeSearchOrder: []string{"Technical"}}}
 
 	customers, err := c.FetchCustomers(context.Background())
 	assert.Nil(t, err)
-	assert.ElementsMatch(t, []domain.Customer{domain.Customer{ID: "1", ResourceOwner: "technical@atlassian.com", BusinessUnit: "Security"}}, customers)
+	expected := domain.Customer{
+		ID:                "1",
+		ResourceOwner:     "technical@atlassian.com",
+		ResourceOwnerRule: "contact-type:Technical",
+		BusinessUnit:      "Security",
+		Contacts: []domain.Contact{
+			{Type: "Technical", Name: "Alice", Email: "technical@atlassian.com", Phone: "555-0100"},
+			{Type: "Escalation", Name: "Bob", Email: "escalation@atlassian.com", Phone: "555-0199"},
+		},
+	}
+	assert.ElementsMatch(t, []domain.Customer{expected}, customers)
 }
 
 func TestFetchCustomersRequestError(t *testing.T) {
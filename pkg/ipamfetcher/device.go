@@ -18,6 +18,7 @@ type ipResponse struct {
 }
 
 type ip struct {
+	ID       int    `json:"id"`
 	DeviceID int    `json:"device_id"`
 	IP       string `json:"ip"`
 	SubnetID int    `json:"subnet_id"`
@@ -59,11 +60,16 @@ func (d *Device42DeviceFetcher) FetchDevices(ctx context.Context) ([]domain.Devi
 			return nil, err
 		}
 		for _, asset := range devicesResponse.IPs {
-			assets = append(assets, domain.Device{
+			device := domain.Device{
 				IP:       asset.IP,
 				ID:       strconv.Itoa(asset.DeviceID),
 				SubnetID: strconv.Itoa(asset.SubnetID),
-			})
+			}
+			// Device42 record IDs start at 1, so a zero ID is a record served without one
+			if asset.ID != 0 {
+				device.RecordID = strconv.Itoa(asset.ID)
+			}
+			assets = append(assets, device)
 		}
 	}
 	return assets, iterator.Close()
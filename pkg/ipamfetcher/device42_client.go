@@ -34,3 +34,20 @@ func (c *Client) FetchIPAMData(ctx context.Context) (domain.IPAMData, error) {
 		Devices:   devices,
 	}, nil
 }
+
+// NewDevice42IPAMDataFetcher generates a Client that fetches from Device42 in the fetch mode
+// of the Device42Client.
+func NewDevice42IPAMDataFetcher(dc *Device42Client, resolver OwnerResolver) *Client {
+	if dc.Mode == DOQLMode {
+		return &Client{
+			CustomerFetcher: NewDevice42DOQLCustomerFetcher(dc, resolver),
+			SubnetFetcher:   NewDevice42DOQLSubnetFetcher(dc),
+			DeviceFetcher:   NewDevice42DOQLDeviceFetcher(dc),
+		}
+	}
+	return &Client{
+		CustomerFetcher: NewDevice42CustomerFetcher(dc, resolver),
+		SubnetFetcher:   NewDevice42SubnetFetcher(dc),
+		DeviceFetcher:   NewDevice42DeviceFetcher(dc),
+	}
+}
@@ -3,11 +3,15 @@ package ipamfetcher
 import (
 	"context"
 	"errors"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/asecurityteam/ipam-facade/pkg/domain"
+	"github.com/asecurityteam/ipam-facade/pkg/ipamfetcher/device42fake"
 	gomock "github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestFetchIPAMData(t *testing.T) {
@@ -102,3 +106,64 @@ func TestFetchIPAMData(t *testing.T) {
 		})
 	}
 }
+
+func TestFetchIPAMDataDevice42Fake(t *testing.T) {
+	fake := device42fake.NewServer(device42fake.Dataset{
+		Customers: []device42fake.Customer{{
+			ID:           1,
+			Name:         "Payments",
+			ContactInfo:  "payments@example.com",
+			CustomFields: []device42fake.CustomField{{Key: "Description", Value: device42fake.String("Payments Engineering")}},
+		}},
+		Subnets: []device42fake.Subnet{
+			{SubnetID: 1, Network: "10.0.0.0", MaskBits: 24, CustomerID: device42fake.Int(1)},
+			{SubnetID: 2, Network: "10.0.1.0", MaskBits: 24},
+		},
+		IPs: []device42fake.IP{
+			{IP: "10.0.0.1", SubnetID: 1, DeviceID: device42fake.Int(7)},
+			{IP: "10.0.0.2", SubnetID: 1},
+			{IP: "10.0.1.1", SubnetID: 2},
+		},
+	})
+	fake.SetCredentials("admin", "secret")
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	component := NewDevice42ClientComponent()
+	config := component.Settings()
+	config.Endpoint = server.URL
+	config.Limit = 2
+	config.AuthType = BasicAuth
+	config.Username = "admin"
+	config.Password = "secret"
+	dc, err := component.New(context.Background(), config)
+	require.NoError(t, err)
+	require.NoError(t, dc.CheckDependencies(context.Background()))
+
+	fetcher := NewDevice42IPAMDataFetcher(dc, nil)
+	ipamData, err := fetcher.FetchIPAMData(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, domain.IPAMData{
+		Customers: []domain.Customer{{ID: "1", ResourceOwner: "payments@example.com", ResourceOwnerRule: ContactInfoRule, BusinessUnit: "Payments Engineering"}},
+		Subnets: []domain.Subnet{
+			{ID: "1", Network: "10.0.0.0", MaskBits: 24, CustomerID: "1"},
+			{ID: "2", Network: "10.0.1.0", MaskBits: 24, CustomerID: "0"},
+		},
+		Devices: []domain.Device{
+			{ID: "7", IP: "10.0.0.1", SubnetID: "1"},
+			{ID: "0", IP: "10.0.0.2", SubnetID: "1"},
+			{ID: "0", IP: "10.0.1.1", SubnetID: "2"},
+		},
+	}, ipamData)
+
+	fake.Inject(device42fake.Fault{Path: "/api/1.0/ips", Status: http.StatusTooManyRequests, Times: 1})
+	_, err = fetcher.FetchIPAMData(context.Background())
+	assert.EqualError(t, err, "unexpected error from device42 api: 429")
+
+	fake.Inject(device42fake.Fault{Path: "/api/1.0/subnets", Malformed: true, Times: 1})
+	_, err = fetcher.FetchIPAMData(context.Background())
+	assert.Error(t, err)
+
+	_, err = fetcher.FetchIPAMData(context.Background())
+	assert.NoError(t, err)
+}
@@ -62,6 +62,7 @@ type Device42PageIterator struct {
 	offset      int
 	totalCount  int
 	exhausted   bool
+	started     bool
 }
 
 // Current returns the current response if there are no issues with the state of the iterator
@@ -82,7 +83,9 @@ func (it *Device42PageIterator) Close() error {
 
 // Next fetches the next page from the API and makes necessary updates to iterator state
 func (it *Device42PageIterator) Next() bool {
-	if it.currentPage.TotalCount > 0 && it.offset >= it.totalCount {
+	// an empty listing reports a total_count of 0, so the first page is what marks
+	// the total as known
+	if (it.currentPage.TotalCount > 0 || it.started) && it.offset >= it.totalCount {
 		it.exhausted = true
 		return false
 	}
@@ -93,6 +96,7 @@ func (it *Device42PageIterator) Next() bool {
 		return false
 	}
 
+	it.started = true
 	it.currentPage = nextPage
 	it.offset = it.offset + it.Limit
 	it.totalCount = nextPage.TotalCount
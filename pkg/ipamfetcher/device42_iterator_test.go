@@ -132,6 +132,21 @@ func TestDevice42PageIteratorNext(t *testing.T) {
 	}
 }
 
+func TestDevice42PageIteratorEmptyListing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockPageFetcher := NewMockPageFetcher(ctrl)
+	mockPageFetcher.EXPECT().FetchPage(gomock.Any(), 0, 10).Return(PagedResponse{TotalCount: 0, Limit: 10}, nil)
+	iterator := &Device42PageIterator{
+		PageFetcher: mockPageFetcher,
+		Limit:       10,
+	}
+
+	assert.True(t, iterator.Next())
+	assert.False(t, iterator.Next())
+	assert.NoError(t, iterator.Close())
+}
+
 func TestDevice42PageIteratorClose(t *testing.T) {
 	p := &Device42PageIterator{err: errors.New("error")}
 	err := p.Close()
@@ -0,0 +1,86 @@
+// Package device42fake provides an in-process fake of the Device42 IPAM API. It serves the
+// endpoints used by the ipamfetcher package from an in-memory dataset, with fault injection,
+// so that the sync pipeline can be exercised end to end without a network or a gateway.
+package device42fake
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"time"
+)
+
+// Dataset holds the records served by the fake, in their Device42 wire format.
+type Dataset struct {
+	Customers []Customer `json:"customers"`
+	Subnets   []Subnet   `json:"subnets"`
+	IPs       []IP       `json:"ips"`
+}
+
+// Customer is a Device42 customer.
+type Customer struct {
+	ID           int           `json:"id"`
+	Name         string        `json:"name"`
+	ContactInfo  string        `json:"contact_info"`
+	Contacts     []Contact     `json:"Contacts"`
+	CustomFields []CustomField `json:"custom_fields"`
+}
+
+// Contact is a contact registered for a Device42 customer.
+type Contact struct {
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	Phone string `json:"phone"`
+}
+
+// CustomField is a Device42 custom field. A nil Value is served as null.
+type CustomField struct {
+	Key   string  `json:"key"`
+	Value *string `json:"value"`
+	Notes string  `json:"notes"`
+}
+
+// Subnet is a Device42 subnet. A nil CustomerID is served as null.
+type Subnet struct {
+	SubnetID     int           `json:"subnet_id"`
+	Network      string        `json:"network"`
+	MaskBits     int           `json:"mask_bits"`
+	CustomerID   *int          `json:"customer_id"`
+	CustomFields []CustomField `json:"custom_fields"`
+	// LastUpdated is compared with the last_updated_gt filter. It is not served.
+	LastUpdated time.Time `json:"-"`
+}
+
+// IP is a Device42 IP address. A nil DeviceID is served as null.
+type IP struct {
+	ID       int    `json:"id"`
+	IP       string `json:"ip"`
+	SubnetID int    `json:"subnet_id"`
+	DeviceID *int   `json:"device_id"`
+	// LastUpdated is compared with the last_updated_gt filter. It is not served.
+	LastUpdated time.Time `json:"-"`
+}
+
+// Int returns a pointer to an int, for the nullable fields of a Dataset.
+func Int(i int) *int {
+	return &i
+}
+
+// String returns a pointer to a string, for the nullable fields of a Dataset.
+func String(s string) *string {
+	return &s
+}
+
+// LoadDataset reads a Dataset from a JSON file with top level "customers", "subnets",
+// and "ips" lists of records in their Device42 wire format.
+func LoadDataset(path string) (Dataset, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Dataset{}, err
+	}
+	var dataset Dataset
+	if err := json.Unmarshal(content, &dataset); err != nil {
+		return Dataset{}, err
+	}
+	return dataset, nil
+}
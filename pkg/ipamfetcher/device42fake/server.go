@@ -0,0 +1,259 @@
+package device42fake
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultLimit is the page size used when a request has no limit.
+	DefaultLimit = 100
+	// MaxLimit is the largest page size the fake serves. Larger limits are reduced to it,
+	// and the reduced limit is reported in the page.
+	MaxLimit = 1000
+	// LastUpdatedFilter is the query parameter that limits the subnets and IPs served to those
+	// updated after a time, in the LastUpdatedFormat.
+	LastUpdatedFilter = "last_updated_gt"
+	// LastUpdatedFormat is the time format of the LastUpdatedFilter parameter, in UTC.
+	LastUpdatedFormat = "2006-01-02T15:04:05"
+)
+
+// Fault changes how the fake responds to matching requests.
+type Fault struct {
+	// Path limits the fault to one endpoint, such as "/api/1.0/ips". Every endpoint matches if empty.
+	Path string
+	// Times is the number of matching requests affected, after which the fault is removed.
+	// Every matching request is affected if zero.
+	Times int
+	// Latency delays the response.
+	Latency time.Duration
+	// Status replaces the response with an empty response of this status code. A 429 response
+	// carries a Retry-After header.
+	Status int
+	// Truncate serves only the first half of the records of a page, while still reporting the
+	// full total_count, as a Device42 instance under load sometimes does.
+	Truncate bool
+	// Malformed serves a page whose JSON body is cut off.
+	Malformed bool
+}
+
+func (f *Fault) matches(path string) bool {
+	return f.Path == "" || strings.TrimSuffix(f.Path, "/") == path
+}
+
+// Server is an http.Handler that fakes the Device42 IPAM API. It serves /api/1.0/ips,
+// /api/1.0/subnets, /api/1.0/customers, and /api/1.0/vrfgroup from a Dataset, with the
+// limit and offset semantics of Device42, and the last updated filter. Customers are served in a single response, as
+// Device42 does. When Username is set, requests must carry matching basic auth credentials.
+type Server struct {
+	Dataset  Dataset
+	Username string
+	Password string
+
+	lock     sync.Mutex
+	faults   []*Fault
+	requests []string
+}
+
+// NewServer generates a new Server for a dataset.
+func NewServer(dataset Dataset) *Server {
+	return &Server{Dataset: dataset}
+}
+
+// Inject adds a fault. Faults apply in the order they were added, and every matching fault
+// applies to a request.
+func (s *Server) Inject(fault Fault) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.faults = append(s.faults, &fault)
+}
+
+// ClearFaults removes every fault.
+func (s *Server) ClearFaults() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.faults = nil
+}
+
+// Requests returns the request URIs served so far, in order.
+func (s *Server) Requests() []string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+// SetCredentials changes the basic auth credentials the fake accepts, such as to rotate them
+// during a test.
+func (s *Server) SetCredentials(username string, password string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.Username = username
+	s.Password = password
+}
+
+// ServeHTTP serves a Device42 API request.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimSuffix(r.URL.Path, "/")
+	faults, authorized := s.record(r, path)
+
+	var fault Fault
+	for _, f := range faults {
+		if f.Latency > 0 {
+			time.Sleep(f.Latency)
+		}
+		fault.Status = firstNonZero(fault.Status, f.Status)
+		fault.Truncate = fault.Truncate || f.Truncate
+		fault.Malformed = fault.Malformed || f.Malformed
+	}
+	if fault.Status != 0 {
+		if fault.Status == http.StatusTooManyRequests {
+			w.Header().Set("Retry-After", "1")
+		}
+		w.WriteHeader(fault.Status)
+		return
+	}
+	if !authorized {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var body interface{}
+	switch path {
+	case "/api/1.0/vrfgroup":
+		body = []interface{}{}
+	case "/api/1.0/customers":
+		body = map[string]interface{}{"Customers": s.Dataset.Customers}
+	case "/api/1.0/subnets", "/api/1.0/ips":
+		limit, offset, ok := pageParameters(r)
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var since time.Time
+		if value := r.URL.Query().Get(LastUpdatedFilter); value != "" {
+			parsed, err := time.Parse(LastUpdatedFormat, value)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			since = parsed
+		}
+		records := s.records(path, since)
+		total := len(records)
+		start, end := clamp(offset, total), clamp(offset+limit, total)
+		if fault.Truncate {
+			end = start + (end-start)/2
+		}
+		body = map[string]interface{}{
+			"limit":                               limit,
+			"offset":                              offset,
+			"total_count":                         total,
+			strings.TrimPrefix(path, "/api/1.0/"): records[start:end],
+		}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	content, _ := json.Marshal(body)
+	if fault.Malformed {
+		content = content[:len(content)/2]
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(content)
+}
+
+// record logs a request, checks its credentials, and returns the faults that apply to it.
+func (s *Server) record(r *http.Request, path string) ([]Fault, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.requests = append(s.requests, r.URL.RequestURI())
+
+	username, password, _ := r.BasicAuth()
+	authorized := s.Username == "" || (username == s.Username && password == s.Password)
+
+	var matched []Fault
+	remaining := s.faults[:0]
+	for _, f := range s.faults {
+		if f.matches(path) {
+			matched = append(matched, *f)
+			if f.Times > 0 {
+				f.Times--
+				if f.Times == 0 {
+					continue
+				}
+			}
+		}
+		remaining = append(remaining, f)
+	}
+	s.faults = remaining
+	return matched, authorized
+}
+
+// records returns the records of a paged endpoint updated after a time, as a slice that can
+// be paged through. Every record is returned for a zero time.
+func (s *Server) records(path string, since time.Time) []interface{} {
+	records := make([]interface{}, 0)
+	if path == "/api/1.0/subnets" {
+		for _, subnet := range s.Dataset.Subnets {
+			if subnet.LastUpdated.After(since) || since.IsZero() {
+				records = append(records, subnet)
+			}
+		}
+		return records
+	}
+	for _, ip := range s.Dataset.IPs {
+		if ip.LastUpdated.After(since) || since.IsZero() {
+			records = append(records, ip)
+		}
+	}
+	return records
+}
+
+// pageParameters reads the limit and offset of a request, applying the Device42 defaults.
+func pageParameters(r *http.Request) (int, int, bool) {
+	limit, offset := DefaultLimit, 0
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			return 0, 0, false
+		}
+		// a zero limit falls back to the default
+		if parsed > 0 {
+			limit = parsed
+		}
+	}
+	if value := r.URL.Query().Get("offset"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			return 0, 0, false
+		}
+		offset = parsed
+	}
+	if limit > MaxLimit {
+		limit = MaxLimit
+	}
+	return limit, offset, true
+}
+
+func clamp(i int, max int) int {
+	if i > max {
+		return max
+	}
+	return i
+}
+
+func firstNonZero(a int, b int) int {
+	if a != 0 {
+		return a
+	}
+	return b
+}
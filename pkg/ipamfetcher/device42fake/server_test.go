@@ -0,0 +1,141 @@
+package device42fake
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type page struct {
+	Limit      int  `json:"limit"`
+	Offset     int  `json:"offset"`
+	TotalCount int  `json:"total_count"`
+	IPs        []IP `json:"ips"`
+}
+
+func testDataset() Dataset {
+	dataset := Dataset{
+		Customers: []Customer{{ID: 1, Name: "Payments", ContactInfo: "payments@example.com"}},
+		Subnets:   []Subnet{{SubnetID: 1, Network: "10.0.0.0", MaskBits: 24, CustomerID: Int(1)}},
+	}
+	for i := 1; i <= 5; i++ {
+		dataset.IPs = append(dataset.IPs, IP{IP: "10.0.0." + strconv.Itoa(i), SubnetID: 1})
+	}
+	return dataset
+}
+
+func get(t *testing.T, server *httptest.Server, uri string) (*http.Response, []byte) {
+	res, err := http.Get(server.URL + uri)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+	require.NoError(t, err)
+	return res, body
+}
+
+func getPage(t *testing.T, server *httptest.Server, uri string) page {
+	res, body := get(t, server, uri)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	var p page
+	require.NoError(t, json.Unmarshal(body, &p))
+	return p
+}
+
+func TestPaging(t *testing.T) {
+	server := httptest.NewServer(NewServer(testDataset()))
+	defer server.Close()
+
+	p := getPage(t, server, "/api/1.0/ips?limit=2&offset=2")
+	assert.Equal(t, page{Limit: 2, Offset: 2, TotalCount: 5, IPs: []IP{{IP: "10.0.0.3", SubnetID: 1}, {IP: "10.0.0.4", SubnetID: 1}}}, p)
+
+	p = getPage(t, server, "/api/1.0/ips/?limit=2&offset=4")
+	assert.Len(t, p.IPs, 1)
+
+	p = getPage(t, server, "/api/1.0/ips?offset=10")
+	assert.Equal(t, DefaultLimit, p.Limit)
+	assert.Empty(t, p.IPs)
+
+	p = getPage(t, server, "/api/1.0/ips?limit=5000")
+	assert.Equal(t, MaxLimit, p.Limit)
+	assert.Len(t, p.IPs, 5)
+
+	res, _ := get(t, server, "/api/1.0/ips?limit=abc")
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
+
+func TestEndpoints(t *testing.T) {
+	server := httptest.NewServer(NewServer(testDataset()))
+	defer server.Close()
+
+	res, body := get(t, server, "/api/1.0/customers")
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.JSONEq(t, `{"Customers": [{"id": 1, "name": "Payments", "contact_info": "payments@example.com", "Contacts": null, "custom_fields": null}]}`, string(body))
+
+	res, body = get(t, server, "/api/1.0/subnets")
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.JSONEq(t, `{"limit": 100, "offset": 0, "total_count": 1, "subnets": [{"subnet_id": 1, "network": "10.0.0.0", "mask_bits": 24, "customer_id": 1, "custom_fields": null}]}`, string(body))
+
+	res, _ = get(t, server, "/api/1.0/vrfgroup")
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	res, _ = get(t, server, "/api/1.0/devices")
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+}
+
+func TestFaults(t *testing.T) {
+	fake := NewServer(testDataset())
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	fake.Inject(Fault{Path: "/api/1.0/ips", Status: http.StatusTooManyRequests, Times: 1})
+	res, _ := get(t, server, "/api/1.0/subnets")
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	res, _ = get(t, server, "/api/1.0/ips")
+	assert.Equal(t, http.StatusTooManyRequests, res.StatusCode)
+	assert.Equal(t, "1", res.Header.Get("Retry-After"))
+	res, _ = get(t, server, "/api/1.0/ips")
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	fake.Inject(Fault{Truncate: true})
+	p := getPage(t, server, "/api/1.0/ips?limit=4")
+	assert.Equal(t, 5, p.TotalCount)
+	assert.Len(t, p.IPs, 2)
+	fake.ClearFaults()
+
+	fake.Inject(Fault{Malformed: true, Times: 1})
+	_, body := get(t, server, "/api/1.0/ips")
+	assert.Error(t, json.Unmarshal(body, &page{}))
+
+	fake.Inject(Fault{Latency: 50 * time.Millisecond, Times: 1})
+	start := time.Now()
+	get(t, server, "/api/1.0/vrfgroup")
+	assert.True(t, time.Since(start) >= 50*time.Millisecond)
+
+	assert.Equal(t, []string{
+		"/api/1.0/subnets", "/api/1.0/ips", "/api/1.0/ips", "/api/1.0/ips?limit=4", "/api/1.0/ips", "/api/1.0/vrfgroup",
+	}, fake.Requests())
+}
+
+func TestCredentials(t *testing.T) {
+	fake := NewServer(testDataset())
+	fake.SetCredentials("admin", "secret")
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	res, _ := get(t, server, "/api/1.0/vrfgroup")
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/api/1.0/vrfgroup", http.NoBody)
+	req.SetBasicAuth("admin", "secret")
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+}
@@ -0,0 +1,271 @@
+package ipamfetcher
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+)
+
+const (
+	defaultDOQLCustomerQuery      = `SELECT customer_pk AS customer_id, name, contact_info FROM view_customer_v1`
+	defaultDOQLContactQuery       = `SELECT cc.customer_fk AS customer_id, cc.type, ci.name, ci.email, ci.phone FROM view_customer_contacts_v1 cc JOIN view_contactinfo_v1 ci ON ci.contactinfo_pk = cc.contactinfo_fk`
+	defaultDOQLCustomerFieldQuery = `SELECT customer_fk AS customer_id, key, value FROM view_customer_custom_fields_v1`
+	defaultDOQLSubnetQuery        = `SELECT s.subnet_pk AS subnet_id, host(s.network) AS network, s.mask_bits, cf.value AS location, s.customer_fk AS customer_id FROM view_subnet_v1 s LEFT JOIN view_subnet_custom_fields_v1 cf ON cf.subnet_fk = s.subnet_pk AND cf.key = 'Location'`
+	defaultDOQLIPQuery            = `SELECT host(i.ip_address) AS ip, i.subnet_fk AS subnet_id, n.device_fk AS device_id FROM view_ipaddress_v1 i LEFT JOIN view_netport_v1 n ON n.netport_pk = i.netport_fk`
+)
+
+// DOQLQueries contains the DOQL queries used to fetch each kind of record in DOQL mode.
+// Columns are matched by name, so a query may select them in any order.
+type DOQLQueries struct {
+	Customers      string
+	Contacts       string
+	CustomerFields string
+	Subnets        string
+	IPs            string
+}
+
+// DOQLRow is a single row of a DOQL result set, keyed by column name.
+type DOQLRow map[string]interface{}
+
+// GetValue retrieves a column value as a string. Null values are returned as an empty string.
+func (r DOQLRow) GetValue(column string) string {
+	switch value := r[column].(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// DOQLQuerier encapsulates the logic for running a DOQL query against Device42.
+type DOQLQuerier interface {
+	Query(ctx context.Context, query string) ([]DOQLRow, error)
+}
+
+// Device42DOQLQuerier implements the DOQLQuerier interface with the Device42 DOQL endpoint,
+// which returns the whole result set of a query in a single response.
+type Device42DOQLQuerier struct {
+	Client   *http.Client
+	Endpoint *url.URL
+}
+
+// NewDevice42DOQLQuerier generates a new Device42DOQLQuerier
+func NewDevice42DOQLQuerier(dc *Device42Client) *Device42DOQLQuerier {
+	resourceEndpoint, _ := url.Parse(dc.Endpoint.String())
+	// the DOQL endpoint requires the trailing slash
+	resourceEndpoint.Path = path.Join(resourceEndpoint.Path, "services", "data", "v1.0", "query") + "/"
+	return &Device42DOQLQuerier{
+		Client:   dc.Client,
+		Endpoint: resourceEndpoint,
+	}
+}
+
+// Query runs a DOQL query and returns its result set.
+func (q *Device42DOQLQuerier) Query(ctx context.Context, query string) ([]DOQLRow, error) {
+	// the query is sent in the URL because the HTTP component sets a JSON content type on every request
+	u, _ := url.Parse(q.Endpoint.String())
+	params := url.Values{}
+	params.Set("query", query)
+	params.Set("output_type", "json")
+	u.RawQuery = params.Encode()
+	req, _ := http.NewRequest(http.MethodGet, u.String(), http.NoBody)
+	res, err := q.Client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected error from device42 doql: %d", res.StatusCode)
+	}
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	// numbers are kept as written so that IDs are not reformatted as floats
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var rows []DOQLRow
+	if err := decoder.Decode(&rows); err != nil {
+		return nil, fmt.Errorf("unexpected response from device42 doql: %v", err)
+	}
+	return rows, nil
+}
+
+// queryColumns runs a DOQL query and verifies that its result set has the expected columns.
+func queryColumns(ctx context.Context, querier DOQLQuerier, name string, query string, columns ...string) ([]DOQLRow, error) {
+	rows, err := querier.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) > 0 {
+		for _, column := range columns {
+			if _, ok := rows[0][column]; !ok {
+				return nil, fmt.Errorf("device42 doql %s query result is missing the %q column", name, column)
+			}
+		}
+	}
+	return rows, nil
+}
+
+// NewDevice42DOQLCustomerFetcher generates a new Device42DOQLCustomerFetcher
+func NewDevice42DOQLCustomerFetcher(dc *Device42Client, resolver OwnerResolver) *Device42DOQLCustomerFetcher {
+	return &Device42DOQLCustomerFetcher{
+		Querier:       NewDevice42DOQLQuerier(dc),
+		Queries:       dc.DOQLQueries,
+		OwnerResolver: resolver,
+	}
+}
+
+// Device42DOQLCustomerFetcher implements the CustomerFetcher interface with DOQL queries for
+// customers, their contacts, and their custom fields. Customers are mapped exactly as they are
+// in REST mode, so the same OwnerResolver chooses their resource owners.
+type Device42DOQLCustomerFetcher struct {
+	Querier       DOQLQuerier
+	Queries       DOQLQueries
+	OwnerResolver OwnerResolver
+}
+
+// FetchCustomers fetches customers from Device42 with DOQL
+func (d *Device42DOQLCustomerFetcher) FetchCustomers(ctx context.Context) ([]domain.Customer, error) {
+	customerRows, err := queryColumns(ctx, d.Querier, "customer", d.Queries.Customers, "customer_id", "name", "contact_info")
+	if err != nil {
+		return nil, err
+	}
+	contactRows, err := queryColumns(ctx, d.Querier, "contact", d.Queries.Contacts, "customer_id", "type", "name", "email", "phone")
+	if err != nil {
+		return nil, err
+	}
+	fieldRows, err := queryColumns(ctx, d.Querier, "customer field", d.Queries.CustomerFields, "customer_id", "key", "value")
+	if err != nil {
+		return nil, err
+	}
+
+	customers := make([]*customer, 0, len(customerRows))
+	customersByID := make(map[string]*customer, len(customerRows))
+	for _, row := range customerRows {
+		id, err := strconv.Atoi(row.GetValue("customer_id"))
+		if err != nil {
+			return nil, fmt.Errorf("device42 doql customer query returned a non-integer customer_id %q", row.GetValue("customer_id"))
+		}
+		c := &customer{
+			ID:          id,
+			Name:        row.GetValue("name"),
+			ContactInfo: row.GetValue("contact_info"),
+		}
+		customers = append(customers, c)
+		customersByID[row.GetValue("customer_id")] = c
+	}
+	for _, row := range contactRows {
+		if c, ok := customersByID[row.GetValue("customer_id")]; ok {
+			c.Contacts = append(c.Contacts, Contact{
+				Type:  row.GetValue("type"),
+				Name:  row.GetValue("name"),
+				Email: row.GetValue("email"),
+				Phone: row.GetValue("phone"),
+			})
+		}
+	}
+	for _, row := range fieldRows {
+		if c, ok := customersByID[row.GetValue("customer_id")]; ok && row["value"] != nil {
+			c.CustomFields = append(c.CustomFields, customField{Key: row.GetValue("key"), Value: row.GetValue("value")})
+		}
+	}
+
+	result := make([]domain.Customer, 0, len(customers))
+	for _, c := range customers {
+		result = append(result, toCustomer(*c, d.OwnerResolver))
+	}
+	return result, nil
+}
+
+// NewDevice42DOQLSubnetFetcher generates a new Device42DOQLSubnetFetcher
+func NewDevice42DOQLSubnetFetcher(dc *Device42Client) *Device42DOQLSubnetFetcher {
+	return &Device42DOQLSubnetFetcher{
+		Querier: NewDevice42DOQLQuerier(dc),
+		Query:   dc.DOQLQueries.Subnets,
+	}
+}
+
+// Device42DOQLSubnetFetcher implements the SubnetFetcher interface with a DOQL query
+type Device42DOQLSubnetFetcher struct {
+	Querier DOQLQuerier
+	Query   string
+}
+
+// FetchSubnets retrieves subnet information from Device42 with DOQL
+func (d *Device42DOQLSubnetFetcher) FetchSubnets(ctx context.Context) ([]domain.Subnet, error) {
+	rows, err := queryColumns(ctx, d.Querier, "subnet", d.Query, "subnet_id", "network", "mask_bits", "location", "customer_id")
+	if err != nil {
+		return nil, err
+	}
+	subnets := make([]domain.Subnet, 0, len(rows))
+	for _, row := range rows {
+		// a missing or malformed mask is passed along as -1 so that the sync validation quarantines it
+		maskBits, err := strconv.Atoi(row.GetValue("mask_bits"))
+		if err != nil {
+			maskBits = -1
+		}
+		subnets = append(subnets, domain.Subnet{
+			ID:         row.GetValue("subnet_id"),
+			Network:    stripPrefixLength(row.GetValue("network")),
+			MaskBits:   maskBits,
+			Location:   row.GetValue("location"),
+			CustomerID: row.GetValue("customer_id"),
+		})
+	}
+	return subnets, nil
+}
+
+// NewDevice42DOQLDeviceFetcher generates a new Device42DOQLDeviceFetcher
+func NewDevice42DOQLDeviceFetcher(dc *Device42Client) *Device42DOQLDeviceFetcher {
+	return &Device42DOQLDeviceFetcher{
+		Querier: NewDevice42DOQLQuerier(dc),
+		Query:   dc.DOQLQueries.IPs,
+	}
+}
+
+// Device42DOQLDeviceFetcher implements the DeviceFetcher interface with a DOQL query
+type Device42DOQLDeviceFetcher struct {
+	Querier DOQLQuerier
+	Query   string
+}
+
+// FetchDevices retrieves device information from Device42 with DOQL
+func (d *Device42DOQLDeviceFetcher) FetchDevices(ctx context.Context) ([]domain.Device, error) {
+	rows, err := queryColumns(ctx, d.Querier, "ip", d.Query, "ip", "subnet_id", "device_id")
+	if err != nil {
+		return nil, err
+	}
+	devices := make([]domain.Device, 0, len(rows))
+	for _, row := range rows {
+		devices = append(devices, domain.Device{
+			IP:       stripPrefixLength(row.GetValue("ip")),
+			ID:       row.GetValue("device_id"),
+			SubnetID: row.GetValue("subnet_id"),
+		})
+	}
+	return devices, nil
+}
+
+// stripPrefixLength removes the prefix length that DOQL includes when an inet column is
+// selected without the host function.
+func stripPrefixLength(address string) string {
+	if slash := strings.Index(address, "/"); slash >= 0 {
+		return address[:slash]
+	}
+	return address
+}
@@ -0,0 +1,191 @@
+package ipamfetcher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	gomock "github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+)
+
+func newDOQLTestClient(t *testing.T, endpoint string) *Device42Client {
+	component := NewDevice42ClientComponent()
+	config := component.Settings()
+	config.Endpoint = endpoint
+	config.Mode = "DOQL"
+	client, err := component.New(context.Background(), config)
+	require.NoError(t, err)
+	return client
+}
+
+func TestDevice42DOQLQuerier(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/services/data/v1.0/query/", r.URL.Path)
+		assert.Equal(t, "json", r.FormValue("output_type"))
+		if r.FormValue("query") != "SELECT 1" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`[{"subnet_id": 12345678901, "network": "10.0.0.0", "location": null}]`))
+	}))
+	defer server.Close()
+
+	querier := NewDevice42DOQLQuerier(newDOQLTestClient(t, server.URL))
+	rows, err := querier.Query(context.Background(), "SELECT 1")
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, "12345678901", rows[0].GetValue("subnet_id"))
+	assert.Equal(t, "10.0.0.0", rows[0].GetValue("network"))
+	assert.Equal(t, "", rows[0].GetValue("location"))
+
+	_, err = querier.Query(context.Background(), "SELECT 2")
+	assert.Error(t, err)
+}
+
+func TestDevice42DOQLQuerierMalformed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`query error: relation does not exist`))
+	}))
+	defer server.Close()
+
+	_, err := NewDevice42DOQLQuerier(newDOQLTestClient(t, server.URL)).Query(context.Background(), "SELECT 1")
+	assert.Error(t, err)
+}
+
+func TestFetchDOQLCustomers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	queries := DOQLQueries{Customers: "customers", Contacts: "contacts", CustomerFields: "fields"}
+	mockQuerier := NewMockDOQLQuerier(ctrl)
+	mockQuerier.EXPECT().Query(gomock.Any(), "customers").Return([]DOQLRow{
+		{"customer_id": json.Number("1"), "name": "Payments", "contact_info": "payments@example.com"},
+		{"customer_id": json.Number("2"), "name": "Security", "contact_info": "security@example.com"},
+	}, nil)
+	mockQuerier.EXPECT().Query(gomock.Any(), "contacts").Return([]DOQLRow{
+		{"customer_id": json.Number("2"), "type": "SRE", "name": "Bob", "email": "bob@example.com", "phone": nil},
+		{"customer_id": json.Number("3"), "type": "SRE", "name": "Carol", "email": "carol@example.com", "phone": nil},
+	}, nil)
+	mockQuerier.EXPECT().Query(gomock.Any(), "fields").Return([]DOQLRow{
+		{"customer_id": json.Number("1"), "key": "Description", "value": "Payments Engineering"},
+		{"customer_id": json.Number("2"), "key": "Description", "value": nil},
+	}, nil)
+
+	d := &Device42DOQLCustomerFetcher{
+		Querier:       mockQuerier,
+		Queries:       queries,
+		OwnerResolver: ChainOwnerResolver{&ContactTypeResolver{TypeSearchOrder: []string{"SRE"}}},
+	}
+	customers, err := d.FetchCustomers(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Customer{
+		{ID: "1", ResourceOwner: "payments@example.com", ResourceOwnerRule: ContactInfoRule, BusinessUnit: "Payments Engineering"},
+		{
+			ID:                "2",
+			ResourceOwner:     "bob@example.com",
+			ResourceOwnerRule: "contact-type:SRE",
+			BusinessUnit:      "Security",
+			Contacts:          []domain.Contact{{Type: "SRE", Name: "Bob", Email: "bob@example.com"}},
+		},
+	}, customers)
+}
+
+func TestFetchDOQLCustomersErrors(t *testing.T) {
+	tc := []struct {
+		Name      string
+		Customers []DOQLRow
+		Err       error
+	}{
+		{Name: "query error", Err: errors.New("unexpected error from device42 doql: 500")},
+		{Name: "missing column", Customers: []DOQLRow{{"customer_id": json.Number("1"), "name": "Payments"}}},
+		{Name: "non-integer id", Customers: []DOQLRow{{"customer_id": "abc", "name": "Payments", "contact_info": ""}}},
+	}
+	for _, tt := range tc {
+		t.Run(tt.Name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockQuerier := NewMockDOQLQuerier(ctrl)
+			mockQuerier.EXPECT().Query(gomock.Any(), "customers").Return(tt.Customers, tt.Err)
+			mockQuerier.EXPECT().Query(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+
+			d := &Device42DOQLCustomerFetcher{Querier: mockQuerier, Queries: DOQLQueries{Customers: "customers"}}
+			_, err := d.FetchCustomers(context.Background())
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestFetchDOQLSubnets(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockQuerier := NewMockDOQLQuerier(ctrl)
+	mockQuerier.EXPECT().Query(gomock.Any(), "subnets").Return([]DOQLRow{
+		{"subnet_id": json.Number("1"), "network": "192.168.1.0", "mask_bits": json.Number("24"), "location": "AUS", "customer_id": json.Number("1")},
+		{"subnet_id": json.Number("2"), "network": "10.0.0.0/8", "mask_bits": json.Number("8"), "location": nil, "customer_id": nil},
+		{"subnet_id": json.Number("3"), "network": "10.1.0.0", "mask_bits": nil, "location": nil, "customer_id": nil},
+	}, nil)
+
+	d := &Device42DOQLSubnetFetcher{Querier: mockQuerier, Query: "subnets"}
+	subnets, err := d.FetchSubnets(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Subnet{
+		{ID: "1", Network: "192.168.1.0", MaskBits: 24, Location: "AUS", CustomerID: "1"},
+		{ID: "2", Network: "10.0.0.0", MaskBits: 8},
+		{ID: "3", Network: "10.1.0.0", MaskBits: -1},
+	}, subnets)
+}
+
+func TestFetchDOQLSubnetsMissingColumn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockQuerier := NewMockDOQLQuerier(ctrl)
+	mockQuerier.EXPECT().Query(gomock.Any(), "subnets").Return([]DOQLRow{
+		{"subnet_id": json.Number("1"), "network": "192.168.1.0", "mask_bits": json.Number("24")},
+	}, nil)
+
+	d := &Device42DOQLSubnetFetcher{Querier: mockQuerier, Query: "subnets"}
+	_, err := d.FetchSubnets(context.Background())
+	assert.EqualError(t, err, `device42 doql subnet query result is missing the "location" column`)
+}
+
+func TestFetchDOQLDevices(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockQuerier := NewMockDOQLQuerier(ctrl)
+	mockQuerier.EXPECT().Query(gomock.Any(), "ips").Return([]DOQLRow{
+		{"ip": "192.168.1.1", "subnet_id": json.Number("1"), "device_id": json.Number("7")},
+		{"ip": "192.168.1.2/32", "subnet_id": json.Number("1"), "device_id": nil},
+	}, nil)
+
+	d := &Device42DOQLDeviceFetcher{Querier: mockQuerier, Query: "ips"}
+	devices, err := d.FetchDevices(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Device{
+		{ID: "7", IP: "192.168.1.1", SubnetID: "1"},
+		{IP: "192.168.1.2", SubnetID: "1"},
+	}, devices)
+}
+
+func TestNewDevice42IPAMDataFetcher(t *testing.T) {
+	client := newDOQLTestClient(t, "https://localhost:443")
+	fetcher := NewDevice42IPAMDataFetcher(client, nil)
+	subnetFetcher, ok := fetcher.SubnetFetcher.(*Device42DOQLSubnetFetcher)
+	require.True(t, ok)
+	assert.Equal(t, defaultDOQLSubnetQuery, subnetFetcher.Query)
+	assert.Equal(t, "https://localhost:443/services/data/v1.0/query/", subnetFetcher.Querier.(*Device42DOQLQuerier).Endpoint.String())
+	assert.IsType(t, &Device42DOQLCustomerFetcher{}, fetcher.CustomerFetcher)
+	assert.IsType(t, &Device42DOQLDeviceFetcher{}, fetcher.DeviceFetcher)
+
+	client.Mode = RESTMode
+	fetcher = NewDevice42IPAMDataFetcher(client, nil)
+	assert.IsType(t, &Device42CustomerFetcher{}, fetcher.CustomerFetcher)
+	assert.IsType(t, &Device42SubnetFetcher{}, fetcher.SubnetFetcher)
+	assert.IsType(t, &Device42DeviceFetcher{}, fetcher.DeviceFetcher)
+}
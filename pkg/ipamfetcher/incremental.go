@@ -0,0 +1,248 @@
+package ipamfetcher
+
+import (
+	"context"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+)
+
+const (
+	// lastUpdatedFilter is the Device42 query parameter that limits a listing to the records
+	// updated after a time. Endpoints that do not support it return every record, which is
+	// slower but still correct.
+	lastUpdatedFilter = "last_updated_gt"
+	// lastUpdatedFormat is the time format of the lastUpdatedFilter parameter.
+	lastUpdatedFormat = "2006-01-02T15:04:05"
+)
+
+// ipKey identifies an IP address in an incremental snapshot by its Device42 record ID, so that
+// an address that moves to another subnet or is renumbered replaces its previous version. IP
+// addresses without a record ID are identified by their address and subnet, as Device42 allows
+// the same address in subnets of different VRF groups.
+type ipKey struct {
+	RecordID string
+	IP       string
+	SubnetID string
+}
+
+func newIPKey(device domain.Device) ipKey {
+	if device.RecordID != "" {
+		return ipKey{RecordID: device.RecordID}
+	}
+	return ipKey{IP: device.IP, SubnetID: device.SubnetID}
+}
+
+// NewDevice42IncrementalFetcher generates a new Device42IncrementalFetcher
+func NewDevice42IncrementalFetcher(dc *Device42Client, resolver OwnerResolver) *Device42IncrementalFetcher {
+	return &Device42IncrementalFetcher{
+		Full:             NewDevice42IPAMDataFetcher(dc, resolver),
+		CustomerFetcher:  NewDevice42CustomerFetcher(dc, resolver),
+		SubnetFetcher:    func(since time.Time) domain.SubnetFetcher { return NewDevice42SubnetFetcher(changedSince(dc, since)) },
+		DeviceFetcher:    func(since time.Time) domain.DeviceFetcher { return NewDevice42DeviceFetcher(changedSince(dc, since)) },
+		FullSyncInterval: dc.FullSyncInterval,
+		Lookback:         dc.IncrementalLookback,
+		Now:              time.Now,
+	}
+}
+
+// changedSince returns a copy of a Device42Client whose endpoint filters listings to the
+// records updated after a time. The paged fetchers keep the query of their endpoint.
+func changedSince(dc *Device42Client, since time.Time) *Device42Client {
+	filtered := *dc
+	filtered.Endpoint, _ = url.Parse(dc.Endpoint.String())
+	q := filtered.Endpoint.Query()
+	q.Set(lastUpdatedFilter, since.UTC().Format(lastUpdatedFormat))
+	filtered.Endpoint.RawQuery = q.Encode()
+	return &filtered
+}
+
+// Device42IncrementalFetcher implements the IPAMDataFetcher interface by fetching the full
+// Device42 dataset once, then only the subnets and IPs updated since the previous fetch, which
+// are merged into the dataset of the previous fetch. Customers are always fetched in full, as
+// Device42 returns them in a single response. Records deleted from Device42 are not reported by
+// an incremental fetch, so the full dataset is fetched again once FullSyncInterval has passed
+// since the last full fetch to reconcile them.
+//
+// The whole merged dataset is returned on every fetch, so validation and storage see the same
+// data as with a full fetch. The start time of a fetch, moved back by Lookback to allow for
+// clock skew and updates made while the previous fetch was in progress, is used as its
+// high-water mark. The mark of a fetch is only kept once CommitIPAMData is called after its
+// data was stored, so a fetch whose data could not be stored is fetched again.
+//
+// When a StateStore is set, the mark and the time of the last full fetch are kept there
+// rather than in memory, so that they survive a restart and are shared with the other
+// instances. The dataset changes are merged into is then rebuilt from the stored records of
+// Source when a Stored fetcher is set. Otherwise the fetcher remembers the dataset of its last
+// committed fetch, and only builds on it while the stored state is still the one it committed,
+// falling back to a full fetch once another instance, or a rollback, has changed it.
+type Device42IncrementalFetcher struct {
+	Full             domain.IPAMDataFetcher
+	CustomerFetcher  domain.CustomerFetcher
+	SubnetFetcher    func(since time.Time) domain.SubnetFetcher
+	DeviceFetcher    func(since time.Time) domain.DeviceFetcher
+	FullSyncInterval time.Duration
+	Lookback         time.Duration
+	Now              func() time.Time
+	StateStore       domain.IncrementalSyncStateStore
+	Stored           domain.StoredSourceFetcher
+	Source           string
+
+	lock    sync.Mutex
+	state   incrementalState
+	pending *incrementalState
+}
+
+// incrementalState is the state of a fetch along with the dataset it fetched.
+type incrementalState struct {
+	domain.IncrementalSyncState
+	subnets []domain.Subnet
+	devices []domain.Device
+}
+
+// incrementalSnapshot is the dataset of a fetch, keyed for merging.
+type incrementalSnapshot struct {
+	subnets     []domain.Subnet
+	subnetIndex map[string]int
+	devices     []domain.Device
+	deviceIndex map[ipKey]int
+}
+
+// FetchIPAMData fetches the subnets and IPs updated since the last committed fetch, or the
+// full dataset when there is no committed fetch or a full reconciliation is due.
+func (f *Device42IncrementalFetcher) FetchIPAMData(ctx context.Context) (domain.IPAMData, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	state, err := f.committedState(ctx)
+	if err != nil {
+		return domain.IPAMData{}, err
+	}
+
+	// PostgreSQL keeps timestamps to the microsecond, so that the state compares equal to its
+	// stored copy
+	start := f.Now().Truncate(time.Microsecond)
+	if state.LastFull.IsZero() || start.Sub(state.LastFull) >= f.FullSyncInterval {
+		ipamData, err := f.Full.FetchIPAMData(ctx)
+		if err != nil {
+			return domain.IPAMData{}, err
+		}
+		snapshot := newIncrementalSnapshot(ipamData.Subnets, ipamData.Devices)
+		f.pending = &incrementalState{
+			IncrementalSyncState: domain.IncrementalSyncState{Watermark: start.Add(-f.Lookback), LastFull: start},
+			subnets:              snapshot.subnets,
+			devices:              snapshot.devices,
+		}
+		return ipamData, nil
+	}
+
+	customers, err := f.CustomerFetcher.FetchCustomers(ctx)
+	if err != nil {
+		return domain.IPAMData{}, err
+	}
+	subnets, err := f.SubnetFetcher(state.Watermark).FetchSubnets(ctx)
+	if err != nil {
+		return domain.IPAMData{}, err
+	}
+	devices, err := f.DeviceFetcher(state.Watermark).FetchDevices(ctx)
+	if err != nil {
+		return domain.IPAMData{}, err
+	}
+
+	snapshot := newIncrementalSnapshot(state.subnets, state.devices)
+	snapshot.merge(subnets, devices)
+	f.pending = &incrementalState{
+		IncrementalSyncState: domain.IncrementalSyncState{Watermark: start.Add(-f.Lookback), LastFull: state.LastFull},
+		subnets:              snapshot.subnets,
+		devices:              snapshot.devices,
+	}
+	return domain.IPAMData{
+		Customers: customers,
+		Subnets:   append([]domain.Subnet(nil), snapshot.subnets...),
+		Devices:   append([]domain.Device(nil), snapshot.devices...),
+	}, nil
+}
+
+// CommitIPAMData keeps the high-water mark of the last fetch, once its data has been stored,
+// for the next fetch to build on.
+func (f *Device42IncrementalFetcher) CommitIPAMData(ctx context.Context) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.pending == nil {
+		return nil
+	}
+	if f.StateStore != nil {
+		if err := f.StateStore.StoreIncrementalSyncState(ctx, f.pending.IncrementalSyncState); err != nil {
+			return err
+		}
+	}
+	f.state = *f.pending
+	f.pending = nil
+	if f.StateStore != nil && f.Stored != nil {
+		// the dataset is rebuilt from the stored records, so it need not be kept in memory
+		f.state.subnets, f.state.devices = nil, nil
+	}
+	return nil
+}
+
+// committedState returns the state of the last committed fetch, from the StateStore when
+// one is set. An empty state is returned when the dataset of that fetch is unknown.
+func (f *Device42IncrementalFetcher) committedState(ctx context.Context) (incrementalState, error) {
+	if f.StateStore == nil {
+		return f.state, nil
+	}
+	stored, err := f.StateStore.FetchIncrementalSyncState(ctx)
+	if err != nil {
+		return incrementalState{}, err
+	}
+	switch {
+	case stored.LastFull.IsZero():
+		return incrementalState{}, nil
+	case f.Stored != nil:
+		ipamData, err := f.Stored.FetchStoredSource(ctx, f.Source)
+		if err != nil {
+			return incrementalState{}, err
+		}
+		return incrementalState{IncrementalSyncState: stored, subnets: ipamData.Subnets, devices: ipamData.Devices}, nil
+	case stored.Watermark.Equal(f.state.Watermark) && stored.LastFull.Equal(f.state.LastFull):
+		return f.state, nil
+	default:
+		return incrementalState{}, nil
+	}
+}
+
+// newIncrementalSnapshot returns a snapshot of a dataset, copied so that merging into the
+// snapshot leaves the dataset unchanged.
+func newIncrementalSnapshot(subnets []domain.Subnet, devices []domain.Device) *incrementalSnapshot {
+	snapshot := &incrementalSnapshot{
+		subnetIndex: make(map[string]int, len(subnets)),
+		deviceIndex: make(map[ipKey]int, len(devices)),
+	}
+	snapshot.merge(subnets, devices)
+	return snapshot
+}
+
+// merge replaces the remembered subnets and IPs that were updated, and adds new ones in the
+// order they were fetched.
+func (s *incrementalSnapshot) merge(subnets []domain.Subnet, devices []domain.Device) {
+	for _, subnet := range subnets {
+		if i, ok := s.subnetIndex[subnet.ID]; ok {
+			s.subnets[i] = subnet
+			continue
+		}
+		s.subnetIndex[subnet.ID] = len(s.subnets)
+		s.subnets = append(s.subnets, subnet)
+	}
+	for _, device := range devices {
+		key := newIPKey(device)
+		if i, ok := s.deviceIndex[key]; ok {
+			s.devices[i] = device
+			continue
+		}
+		s.deviceIndex[key] = len(s.devices)
+		s.devices = append(s.devices, device)
+	}
+}
@@ -0,0 +1,292 @@
+package ipamfetcher
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+	"github.com/asecurityteam/ipam-facade/pkg/ipamfetcher/device42fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// filteredRequests returns the requests to the fake that carried the last updated filter.
+func filteredRequests(fake *device42fake.Server) []string {
+	var filtered []string
+	for _, request := range fake.Requests() {
+		if strings.Contains(request, lastUpdatedFilter) {
+			filtered = append(filtered, request)
+		}
+	}
+	return filtered
+}
+
+// memoryStateStore is an IncrementalSyncStateStore that keeps the state in memory.
+type memoryStateStore struct {
+	state  domain.IncrementalSyncState
+	stores int
+	err    error
+}
+
+func (s *memoryStateStore) FetchIncrementalSyncState(context.Context) (domain.IncrementalSyncState, error) {
+	return s.state, nil
+}
+
+func (s *memoryStateStore) StoreIncrementalSyncState(_ context.Context, state domain.IncrementalSyncState) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.state = state
+	s.stores++
+	return nil
+}
+
+// storedSource is a StoredSourceFetcher that returns the records of a single source.
+type storedSource struct {
+	source   string
+	ipamData domain.IPAMData
+}
+
+func (s *storedSource) FetchStoredSource(_ context.Context, source string) (domain.IPAMData, error) {
+	if source != s.source {
+		return domain.IPAMData{}, nil
+	}
+	return s.ipamData, nil
+}
+
+// newIncrementalTestClient returns a Device42Client for incremental syncs against a fake.
+func newIncrementalTestClient(t *testing.T, url string) *Device42Client {
+	component := NewDevice42ClientComponent()
+	config := component.Settings()
+	config.Endpoint = url
+	config.Limit = 10
+	config.IncrementalSync = true
+	dc, err := component.New(context.Background(), config)
+	require.NoError(t, err)
+	return dc
+}
+
+func TestDevice42IncrementalFetcher(t *testing.T) {
+	epoch := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	fake := device42fake.NewServer(device42fake.Dataset{
+		Subnets: []device42fake.Subnet{
+			{SubnetID: 1, Network: "10.0.0.0", MaskBits: 24, LastUpdated: epoch},
+			{SubnetID: 2, Network: "10.0.1.0", MaskBits: 24, LastUpdated: epoch},
+		},
+		IPs: []device42fake.IP{
+			{ID: 1, IP: "10.0.0.1", SubnetID: 1, LastUpdated: epoch},
+			{ID: 2, IP: "10.0.1.1", SubnetID: 2, LastUpdated: epoch},
+			{ID: 3, IP: "10.0.1.2", SubnetID: 2, LastUpdated: epoch},
+		},
+	})
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	now := epoch.Add(time.Hour)
+	fetcher := NewDevice42IncrementalFetcher(newIncrementalTestClient(t, server.URL), nil)
+	fetcher.Now = func() time.Time { return now }
+
+	ipamData, err := fetcher.FetchIPAMData(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, fetcher.CommitIPAMData(context.Background()))
+	assert.Len(t, ipamData.Devices, 3)
+	assert.Empty(t, filteredRequests(fake))
+
+	// update a subnet, add an IP, delete an IP, and move an IP to another subnet
+	fake.Dataset.Subnets[0].MaskBits = 16
+	fake.Dataset.Subnets[0].LastUpdated = now.Add(time.Minute)
+	fake.Dataset.IPs = []device42fake.IP{
+		{ID: 1, IP: "10.0.0.1", SubnetID: 1, LastUpdated: epoch},
+		{ID: 3, IP: "10.0.0.3", SubnetID: 1, LastUpdated: now.Add(time.Minute)},
+		{ID: 4, IP: "10.0.0.2", SubnetID: 1, DeviceID: device42fake.Int(9), LastUpdated: now.Add(time.Minute)},
+	}
+	now = now.Add(10 * time.Minute)
+
+	ipamData, err = fetcher.FetchIPAMData(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, fetcher.CommitIPAMData(context.Background()))
+	assert.Equal(t, []domain.Subnet{
+		{ID: "1", Network: "10.0.0.0", MaskBits: 16, CustomerID: "0"},
+		{ID: "2", Network: "10.0.1.0", MaskBits: 24, CustomerID: "0"},
+	}, ipamData.Subnets)
+	// the deleted IP is kept until the next full fetch, and the moved IP replaces its record
+	assert.Equal(t, []domain.Device{
+		{ID: "0", IP: "10.0.0.1", SubnetID: "1", RecordID: "1"},
+		{ID: "0", IP: "10.0.1.1", SubnetID: "2", RecordID: "2"},
+		{ID: "0", IP: "10.0.0.3", SubnetID: "1", RecordID: "3"},
+		{ID: "9", IP: "10.0.0.2", SubnetID: "1", RecordID: "4"},
+	}, ipamData.Devices)
+	// the watermark is the start of the first fetch, less the default five minute lookback
+	assert.Equal(t, []string{
+		"/api/1.0/subnets?last_updated_gt=2024-03-01T12%3A55%3A00&limit=10&offset=0",
+		"/api/1.0/ips?last_updated_gt=2024-03-01T12%3A55%3A00&limit=10&offset=0",
+	}, filteredRequests(fake))
+
+	now = now.Add(time.Hour)
+	ipamData, err = fetcher.FetchIPAMData(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Device{
+		{ID: "0", IP: "10.0.0.1", SubnetID: "1", RecordID: "1"},
+		{ID: "0", IP: "10.0.0.3", SubnetID: "1", RecordID: "3"},
+		{ID: "9", IP: "10.0.0.2", SubnetID: "1", RecordID: "4"},
+	}, ipamData.Devices)
+	assert.Len(t, filteredRequests(fake), 2)
+}
+
+func TestDevice42IncrementalFetcherFailure(t *testing.T) {
+	fake := device42fake.NewServer(device42fake.Dataset{})
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	fetcher := NewDevice42IncrementalFetcher(newIncrementalTestClient(t, server.URL), nil)
+	fetcher.Now = func() time.Time { return now }
+	_, err := fetcher.FetchIPAMData(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, fetcher.CommitIPAMData(context.Background()))
+
+	fake.Inject(device42fake.Fault{Path: "/api/1.0/ips", Status: http.StatusServiceUnavailable, Times: 1})
+	now = now.Add(time.Minute)
+	_, err = fetcher.FetchIPAMData(context.Background())
+	assert.Error(t, err)
+
+	// the failed fetch does not advance the watermark
+	now = now.Add(time.Minute)
+	_, err = fetcher.FetchIPAMData(context.Background())
+	require.NoError(t, err)
+	requests := filteredRequests(fake)
+	require.Len(t, requests, 4)
+	assert.Equal(t, requests[0], requests[2])
+	assert.True(t, strings.Contains(requests[2], "2024-03-01T11%3A55%3A00"), requests[2])
+
+	// nor does a fetch that is not committed, as when its data could not be stored
+	now = now.Add(time.Minute)
+	_, err = fetcher.FetchIPAMData(context.Background())
+	require.NoError(t, err)
+	requests = filteredRequests(fake)
+	require.Len(t, requests, 6)
+	assert.Equal(t, requests[0], requests[4])
+}
+
+func TestDevice42IncrementalFetcherStateStore(t *testing.T) {
+	epoch := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	fake := device42fake.NewServer(device42fake.Dataset{
+		Subnets: []device42fake.Subnet{{SubnetID: 1, Network: "10.0.0.0", MaskBits: 24, LastUpdated: epoch}},
+		IPs:     []device42fake.IP{{ID: 1, IP: "10.0.0.1", SubnetID: 1, LastUpdated: epoch}},
+	})
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	dc := newIncrementalTestClient(t, server.URL)
+
+	now := epoch.Add(time.Hour)
+	store := &memoryStateStore{}
+	stored := &storedSource{source: "device42"}
+	fetcher := NewDevice42IncrementalFetcher(dc, nil)
+	fetcher.Now = func() time.Time { return now }
+	fetcher.StateStore = store
+	fetcher.Stored = stored
+	fetcher.Source = "device42"
+	ipamData, err := fetcher.FetchIPAMData(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, store.stores)
+	require.NoError(t, fetcher.CommitIPAMData(context.Background()))
+	assert.Equal(t, 1, store.stores)
+	assert.Equal(t, domain.IncrementalSyncState{Watermark: now.Add(-5 * time.Minute), LastFull: now}, store.state)
+	stored.ipamData = domain.IPAMData{Subnets: ipamData.Subnets, Devices: ipamData.Devices}
+
+	// a new fetcher, as after a restart, carries on from the stored state and records
+	fake.Dataset.IPs = append(fake.Dataset.IPs, device42fake.IP{ID: 2, IP: "10.0.0.2", SubnetID: 1, LastUpdated: now})
+	now = now.Add(time.Minute)
+	restarted := NewDevice42IncrementalFetcher(dc, nil)
+	restarted.Now = func() time.Time { return now }
+	restarted.StateStore = store
+	restarted.Stored = stored
+	restarted.Source = "device42"
+	ipamData, err = restarted.FetchIPAMData(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, filteredRequests(fake), 2)
+	assert.Equal(t, []domain.Device{
+		{ID: "0", IP: "10.0.0.1", SubnetID: "1", RecordID: "1"},
+		{ID: "0", IP: "10.0.0.2", SubnetID: "1", RecordID: "2"},
+	}, ipamData.Devices)
+
+	// a failure to store the state leaves the fetch uncommitted
+	store.err = errors.New("boom")
+	assert.Error(t, restarted.CommitIPAMData(context.Background()))
+	assert.Equal(t, 1, store.stores)
+	assert.Equal(t, now.Add(-time.Minute), store.state.LastFull)
+
+	// a reset state, as after a rollback, makes the next fetch a full one
+	store.err = nil
+	store.state = domain.IncrementalSyncState{}
+	now = now.Add(time.Minute)
+	_, err = restarted.FetchIPAMData(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, filteredRequests(fake), 2)
+}
+
+func TestDevice42IncrementalFetcherStateStoreWithoutStoredSource(t *testing.T) {
+	epoch := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	fake := device42fake.NewServer(device42fake.Dataset{
+		Subnets: []device42fake.Subnet{{SubnetID: 1, Network: "10.0.0.0", MaskBits: 24, LastUpdated: epoch}},
+		IPs:     []device42fake.IP{{ID: 1, IP: "10.0.0.1", SubnetID: 1, LastUpdated: epoch}},
+	})
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	dc := newIncrementalTestClient(t, server.URL)
+
+	// the fetcher builds on the dataset it remembers while the stored state is its own
+	now := epoch.Add(time.Hour).Add(123456 * time.Nanosecond)
+	store := &memoryStateStore{}
+	fetcher := NewDevice42IncrementalFetcher(dc, nil)
+	fetcher.Now = func() time.Time { return now }
+	fetcher.StateStore = store
+	_, err := fetcher.FetchIPAMData(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, fetcher.CommitIPAMData(context.Background()))
+	// the state is kept to the precision of a PostgreSQL timestamp
+	assert.Equal(t, now.Truncate(time.Microsecond), store.state.LastFull)
+
+	fake.Dataset.IPs = append(fake.Dataset.IPs, device42fake.IP{ID: 2, IP: "10.0.0.2", SubnetID: 1, LastUpdated: now})
+	now = now.Add(time.Minute)
+	ipamData, err := fetcher.FetchIPAMData(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, fetcher.CommitIPAMData(context.Background()))
+	assert.Len(t, filteredRequests(fake), 2)
+	assert.Len(t, ipamData.Devices, 2)
+
+	// but not once another instance, or a rollback, has changed it, nor after a restart
+	store.state.Watermark = store.state.Watermark.Add(time.Second)
+	now = now.Add(time.Minute)
+	_, err = fetcher.FetchIPAMData(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, fetcher.CommitIPAMData(context.Background()))
+	assert.Len(t, filteredRequests(fake), 2)
+
+	restarted := NewDevice42IncrementalFetcher(dc, nil)
+	restarted.Now = func() time.Time { return now }
+	restarted.StateStore = store
+	now = now.Add(time.Minute)
+	_, err = restarted.FetchIPAMData(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, filteredRequests(fake), 2)
+}
+
+func TestNewIPKey(t *testing.T) {
+	assert.Equal(t, ipKey{RecordID: "7"}, newIPKey(domain.Device{IP: "10.0.0.1", SubnetID: "1", RecordID: "7"}))
+	assert.Equal(t, ipKey{IP: "10.0.0.1", SubnetID: "1"}, newIPKey(domain.Device{IP: "10.0.0.1", SubnetID: "1"}))
+}
+
+func TestIncrementalSyncRequiresRESTMode(t *testing.T) {
+	component := NewDevice42ClientComponent()
+	config := component.Settings()
+	config.Mode = DOQLMode
+	config.IncrementalSync = true
+	_, err := component.New(context.Background(), config)
+	assert.Error(t, err)
+}
@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/asecurityteam/ipam-facade/pkg/ipamfetcher (interfaces: DOQLQuerier)
+
+// Package ipamfetcher is a generated GoMock package.
+package ipamfetcher
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockDOQLQuerier is a mock of DOQLQuerier interface
+type MockDOQLQuerier struct {
+	ctrl     *gomock.Controller
+	recorder *MockDOQLQuerierMockRecorder
+}
+
+// MockDOQLQuerierMockRecorder is the mock recorder for MockDOQLQuerier
+type MockDOQLQuerierMockRecorder struct {
+	mock *MockDOQLQuerier
+}
+
+// NewMockDOQLQuerier creates a new mock instance
+func NewMockDOQLQuerier(ctrl *gomock.Controller) *MockDOQLQuerier {
+	mock := &MockDOQLQuerier{ctrl: ctrl}
+	mock.recorder = &MockDOQLQuerierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockDOQLQuerier) EXPECT() *MockDOQLQuerierMockRecorder {
+	return m.recorder
+}
+
+// Query mocks base method
+func (m *MockDOQLQuerier) Query(ctx context.Context, query string) ([]DOQLRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Query", ctx, query)
+	ret0, _ := ret[0].([]DOQLRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Query indicates an expected call of Query
+func (mr *MockDOQLQuerierMockRecorder) Query(ctx, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Query", reflect.TypeOf((*MockDOQLQuerier)(nil).Query), ctx, query)
+}
@@ -0,0 +1,242 @@
+package ipamfetcher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	contactTypeResolverName = "contacttype"
+	emailDomainResolverName = "emaildomain"
+	customFieldResolverName = "customfield"
+	mappingResolverName     = "mapping"
+
+	// ContactInfoRule is recorded when no OwnerResolver could resolve an owner
+	// and the Customer contact_info field was used instead.
+	ContactInfoRule = "contact-info"
+)
+
+// OwnerCandidate contains the values of a Device42 Customer that are available
+// to an OwnerResolver when choosing a resource owner.
+type OwnerCandidate struct {
+	ID           int
+	Name         string
+	ContactInfo  string
+	Contacts     []Contact
+	CustomFields map[string]string
+}
+
+// OwnerResolution is the resource owner chosen by an OwnerResolver along with
+// the rule that chose it.
+type OwnerResolution struct {
+	Owner string
+	Rule  string
+}
+
+// OwnerResolver chooses the resource owner for a Device42 Customer. The boolean
+// return value is false when the resolver has no opinion about the candidate.
+type OwnerResolver interface {
+	ResolveOwner(candidate OwnerCandidate) (OwnerResolution, bool)
+}
+
+// ChainOwnerResolver tries each OwnerResolver in order and returns the first resolution.
+type ChainOwnerResolver []OwnerResolver
+
+// ResolveOwner returns the resolution of the first resolver in the chain that has one.
+func (c ChainOwnerResolver) ResolveOwner(candidate OwnerCandidate) (OwnerResolution, bool) {
+	for _, resolver := range c {
+		if resolution, ok := resolver.ResolveOwner(candidate); ok {
+			return resolution, true
+		}
+	}
+	return OwnerResolution{}, false
+}
+
+// ContactTypeResolver chooses the non-empty email of the Contact whose type appears
+// earliest in TypeSearchOrder.
+type ContactTypeResolver struct {
+	TypeSearchOrder []string
+}
+
+// ResolveOwner searches the candidate contacts in the priority order of TypeSearchOrder.
+func (r *ContactTypeResolver) ResolveOwner(candidate OwnerCandidate) (OwnerResolution, bool) {
+	highestPriorityFound := len(r.TypeSearchOrder)
+	var resolution OwnerResolution
+	for _, contact := range candidate.Contacts {
+		keyIndex := keyIndex(r.TypeSearchOrder, contact.Type)
+		if keyIndex > -1 && keyIndex < highestPriorityFound && contact.Email != "" {
+			// this is one of the contacts we're looking for, and it's higher priority than any others we've found so far
+			resolution = OwnerResolution{Owner: contact.Email, Rule: "contact-type:" + contact.Type}
+			highestPriorityFound = keyIndex
+		}
+	}
+	return resolution, resolution.Owner != ""
+}
+
+// EmailDomainResolver chooses the first contact email, followed by the contact_info
+// field, that belongs to one of the allowed Domains.
+type EmailDomainResolver struct {
+	Domains []string
+}
+
+// ResolveOwner searches the candidate contacts, then contact_info, for an email in an allowed domain.
+func (r *EmailDomainResolver) ResolveOwner(candidate OwnerCandidate) (OwnerResolution, bool) {
+	emails := make([]string, 0, len(candidate.Contacts)+1)
+	for _, contact := range candidate.Contacts {
+		emails = append(emails, contact.Email)
+	}
+	emails = append(emails, candidate.ContactInfo)
+	for _, email := range emails {
+		at := strings.LastIndex(email, "@")
+		if at < 0 {
+			continue
+		}
+		emailDomain := email[at+1:]
+		for _, domain := range r.Domains {
+			if strings.EqualFold(emailDomain, domain) {
+				return OwnerResolution{Owner: email, Rule: "email-domain:" + domain}, true
+			}
+		}
+	}
+	return OwnerResolution{}, false
+}
+
+// CustomFieldRegexResolver chooses the owner by matching Pattern against custom field
+// values. Only the fields named in Keys are searched, in order, unless Keys is empty in
+// which case every custom field is searched in key order. If Pattern contains a capturing
+// group, the first group is used as the owner; otherwise the entire match is.
+type CustomFieldRegexResolver struct {
+	Keys    []string
+	Pattern *regexp.Regexp
+}
+
+// ResolveOwner returns the first custom field match for Pattern.
+func (r *CustomFieldRegexResolver) ResolveOwner(candidate OwnerCandidate) (OwnerResolution, bool) {
+	keys := r.Keys
+	if len(keys) == 0 {
+		keys = make([]string, 0, len(candidate.CustomFields))
+		for key := range candidate.CustomFields {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+	}
+	for _, key := range keys {
+		match := r.Pattern.FindStringSubmatch(candidate.CustomFields[key])
+		if match == nil {
+			continue
+		}
+		owner := match[0]
+		if len(match) > 1 {
+			owner = match[1]
+		}
+		if owner != "" {
+			return OwnerResolution{Owner: owner, Rule: "custom-field:" + key}, true
+		}
+	}
+	return OwnerResolution{}, false
+}
+
+// MappingResolver chooses the owner from a fixed table keyed by either the
+// Device42 Customer name or its numeric ID.
+type MappingResolver struct {
+	Owners map[string]string
+}
+
+// ResolveOwner looks up the candidate by name, then by ID.
+func (r *MappingResolver) ResolveOwner(candidate OwnerCandidate) (OwnerResolution, bool) {
+	for _, key := range []string{candidate.Name, strconv.Itoa(candidate.ID)} {
+		if owner, ok := r.Owners[key]; ok && key != "" && owner != "" {
+			return OwnerResolution{Owner: owner, Rule: "mapping:" + key}, true
+		}
+	}
+	return OwnerResolution{}, false
+}
+
+// OwnerResolverConfig contains the settings used to build the OwnerResolver of a
+// Device42CustomerFetcher.
+type OwnerResolverConfig struct {
+	Resolvers          string `description:"Comma-delimited, ordered list of resolvers used to choose a Customer resource owner. Any of: contacttype, emaildomain, customfield, mapping. When none of them resolve an owner, the logic falls back to using Customer.contact_info"`
+	TypeSearchOrder    string `description:"Comma-delimited priority list of 'type' value in the IPAM Contacts objects in which to search for non-empty 'email' to use as the resource owner. Used by the contacttype resolver."`
+	EmailDomains       string `description:"Comma-delimited list of email domains that a contact email must belong to. Used by the emaildomain resolver."`
+	CustomFieldKeys    string `description:"Comma-delimited list of Customer custom field keys to search. All custom fields are searched if empty. Used by the customfield resolver."`
+	CustomFieldPattern string `description:"Regular expression matched against Customer custom field values. The first capturing group, or the entire match, is used as the resource owner. Used by the customfield resolver."`
+	Mapping            string `description:"JSON object mapping Customer names or IDs to a resource owner. Used by the mapping resolver."`
+}
+
+// Name of the configuration as it might appear in config files.
+func (*OwnerResolverConfig) Name() string {
+	return "Contact"
+}
+
+// NewOwnerResolverComponent generates a new OwnerResolverComponent.
+func NewOwnerResolverComponent() *OwnerResolverComponent {
+	return &OwnerResolverComponent{}
+}
+
+// OwnerResolverComponent satisfies the settings library Component API,
+// and may be used by the settings.NewComponent function.
+type OwnerResolverComponent struct{}
+
+// Settings generates a config with default values applied.
+func (*OwnerResolverComponent) Settings() *OwnerResolverConfig {
+	return &OwnerResolverConfig{
+		Resolvers: contactTypeResolverName,
+	}
+}
+
+// New constructs a ChainOwnerResolver from the configured list of resolvers.
+func (*OwnerResolverComponent) New(_ context.Context, c *OwnerResolverConfig) (OwnerResolver, error) {
+	chain := make(ChainOwnerResolver, 0)
+	for _, name := range splitList(c.Resolvers) {
+		switch strings.ToLower(name) {
+		case contactTypeResolverName:
+			chain = append(chain, &ContactTypeResolver{TypeSearchOrder: splitList(c.TypeSearchOrder)})
+		case emailDomainResolverName:
+			chain = append(chain, &EmailDomainResolver{Domains: splitList(c.EmailDomains)})
+		case customFieldResolverName:
+			pattern, err := regexp.Compile(c.CustomFieldPattern)
+			if err != nil {
+				return nil, err
+			}
+			chain = append(chain, &CustomFieldRegexResolver{Keys: splitList(c.CustomFieldKeys), Pattern: pattern})
+		case mappingResolverName:
+			owners := make(map[string]string)
+			if c.Mapping != "" {
+				if err := json.Unmarshal([]byte(c.Mapping), &owners); err != nil {
+					return nil, err
+				}
+			}
+			chain = append(chain, &MappingResolver{Owners: owners})
+		default:
+			return nil, fmt.Errorf("unknown owner resolver %q", name)
+		}
+	}
+	return chain, nil
+}
+
+// splitList splits a comma-delimited setting, dropping empty values.
+func splitList(s string) []string {
+	values := make([]string, 0)
+	for _, value := range strings.Split(s, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func keyIndex(haystack []string, needle string) int {
+	keyIndex := -1
+	for _, straw := range haystack {
+		keyIndex++
+		if straw == needle {
+			return keyIndex
+		}
+	}
+	return -1
+}
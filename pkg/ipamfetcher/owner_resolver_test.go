@@ -0,0 +1,186 @@
+package ipamfetcher
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOwnerResolverConfigName(t *testing.T) {
+	c := &OwnerResolverConfig{}
+	assert.Equal(t, "Contact", c.Name())
+}
+
+func TestContactTypeResolver(t *testing.T) {
+	candidate := OwnerCandidate{
+		ContactInfo: "contactinfo@example.com",
+		Contacts: []Contact{
+			{Type: "Technical", Email: "technical@example.com"},
+			{Type: "SRE", Email: ""},
+			{Type: "Administrative", Email: "administrative@example.com"},
+		},
+	}
+	tc := []struct {
+		name     string
+		order    []string
+		expected OwnerResolution
+		ok       bool
+	}{
+		{"highest priority", []string{"Administrative", "Technical"}, OwnerResolution{Owner: "administrative@example.com", Rule: "contact-type:Administrative"}, true},
+		{"skips empty email", []string{"SRE", "Technical"}, OwnerResolution{Owner: "technical@example.com", Rule: "contact-type:Technical"}, true},
+		{"no match", []string{"Team Lead"}, OwnerResolution{}, false},
+		{"empty order", nil, OwnerResolution{}, false},
+	}
+	for _, test := range tc {
+		t.Run(test.name, func(tt *testing.T) {
+			resolver := &ContactTypeResolver{TypeSearchOrder: test.order}
+			resolution, ok := resolver.ResolveOwner(candidate)
+			assert.Equal(tt, test.ok, ok)
+			assert.Equal(tt, test.expected, resolution)
+		})
+	}
+}
+
+func TestEmailDomainResolver(t *testing.T) {
+	tc := []struct {
+		name      string
+		candidate OwnerCandidate
+		expected  OwnerResolution
+		ok        bool
+	}{
+		{
+			"contact match",
+			OwnerCandidate{ContactInfo: "info@example.com", Contacts: []Contact{{Email: "vendor@vendor.com"}, {Email: "owner@Example.com"}}},
+			OwnerResolution{Owner: "owner@Example.com", Rule: "email-domain:example.com"},
+			true,
+		},
+		{
+			"contact info match",
+			OwnerCandidate{ContactInfo: "info@example.com", Contacts: []Contact{{Email: "vendor@vendor.com"}}},
+			OwnerResolution{Owner: "info@example.com", Rule: "email-domain:example.com"},
+			true,
+		},
+		{
+			"no match",
+			OwnerCandidate{ContactInfo: "not an email", Contacts: []Contact{{Email: "vendor@vendor.com"}}},
+			OwnerResolution{},
+			false,
+		},
+	}
+	for _, test := range tc {
+		t.Run(test.name, func(tt *testing.T) {
+			resolver := &EmailDomainResolver{Domains: []string{"example.com"}}
+			resolution, ok := resolver.ResolveOwner(test.candidate)
+			assert.Equal(tt, test.ok, ok)
+			assert.Equal(tt, test.expected, resolution)
+		})
+	}
+}
+
+func TestCustomFieldRegexResolver(t *testing.T) {
+	candidate := OwnerCandidate{
+		CustomFields: map[string]string{
+			"Notes": "escalate to oncall@example.com",
+			"Owner": "owner: payments@example.com",
+		},
+	}
+	tc := []struct {
+		name     string
+		keys     []string
+		pattern  string
+		expected OwnerResolution
+		ok       bool
+	}{
+		{"whole match in key order", nil, `[a-z]+@example\.com`, OwnerResolution{Owner: "oncall@example.com", Rule: "custom-field:Notes"}, true},
+		{"capturing group", []string{"Owner"}, `owner: (\S+)`, OwnerResolution{Owner: "payments@example.com", Rule: "custom-field:Owner"}, true},
+		{"missing key", []string{"Missing"}, `.+`, OwnerResolution{}, false},
+		{"no match", nil, `nomatch`, OwnerResolution{}, false},
+	}
+	for _, test := range tc {
+		t.Run(test.name, func(tt *testing.T) {
+			resolver := &CustomFieldRegexResolver{Keys: test.keys, Pattern: regexp.MustCompile(test.pattern)}
+			resolution, ok := resolver.ResolveOwner(candidate)
+			assert.Equal(tt, test.ok, ok)
+			assert.Equal(tt, test.expected, resolution)
+		})
+	}
+}
+
+func TestMappingResolver(t *testing.T) {
+	resolver := &MappingResolver{Owners: map[string]string{
+		"Payments": "payments@example.com",
+		"42":       "fortytwo@example.com",
+	}}
+
+	resolution, ok := resolver.ResolveOwner(OwnerCandidate{ID: 1, Name: "Payments"})
+	assert.True(t, ok)
+	assert.Equal(t, OwnerResolution{Owner: "payments@example.com", Rule: "mapping:Payments"}, resolution)
+
+	resolution, ok = resolver.ResolveOwner(OwnerCandidate{ID: 42, Name: "Unmapped"})
+	assert.True(t, ok)
+	assert.Equal(t, OwnerResolution{Owner: "fortytwo@example.com", Rule: "mapping:42"}, resolution)
+
+	_, ok = resolver.ResolveOwner(OwnerCandidate{ID: 2, Name: "Unmapped"})
+	assert.False(t, ok)
+}
+
+func TestChainOwnerResolver(t *testing.T) {
+	chain := ChainOwnerResolver{
+		&ContactTypeResolver{TypeSearchOrder: []string{"SRE"}},
+		&MappingResolver{Owners: map[string]string{"Payments": "payments@example.com"}},
+	}
+
+	resolution, ok := chain.ResolveOwner(OwnerCandidate{Name: "Payments", Contacts: []Contact{{Type: "SRE", Email: "sre@example.com"}}})
+	assert.True(t, ok)
+	assert.Equal(t, OwnerResolution{Owner: "sre@example.com", Rule: "contact-type:SRE"}, resolution)
+
+	resolution, ok = chain.ResolveOwner(OwnerCandidate{Name: "Payments"})
+	assert.True(t, ok)
+	assert.Equal(t, OwnerResolution{Owner: "payments@example.com", Rule: "mapping:Payments"}, resolution)
+
+	_, ok = chain.ResolveOwner(OwnerCandidate{Name: "Unmapped"})
+	assert.False(t, ok)
+}
+
+func TestOwnerResolverComponent(t *testing.T) {
+	component := NewOwnerResolverComponent()
+
+	resolver, err := component.New(context.Background(), component.Settings())
+	assert.NoError(t, err)
+	assert.Equal(t, ChainOwnerResolver{&ContactTypeResolver{TypeSearchOrder: []string{}}}, resolver)
+
+	resolver, err = component.New(context.Background(), &OwnerResolverConfig{
+		Resolvers:          "contacttype, emaildomain,customfield,mapping",
+		TypeSearchOrder:    "SRE, Technical",
+		EmailDomains:       "example.com",
+		CustomFieldKeys:    "Owner",
+		CustomFieldPattern: `(\S+@\S+)`,
+		Mapping:            `{"Payments": "payments@example.com"}`,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, ChainOwnerResolver{
+		&ContactTypeResolver{TypeSearchOrder: []string{"SRE", "Technical"}},
+		&EmailDomainResolver{Domains: []string{"example.com"}},
+		&CustomFieldRegexResolver{Keys: []string{"Owner"}, Pattern: regexp.MustCompile(`(\S+@\S+)`)},
+		&MappingResolver{Owners: map[string]string{"Payments": "payments@example.com"}},
+	}, resolver)
+}
+
+func TestOwnerResolverComponentErrors(t *testing.T) {
+	tc := []struct {
+		name   string
+		config *OwnerResolverConfig
+	}{
+		{"unknown resolver", &OwnerResolverConfig{Resolvers: "unknown"}},
+		{"bad pattern", &OwnerResolverConfig{Resolvers: "customfield", CustomFieldPattern: "("}},
+		{"bad mapping", &OwnerResolverConfig{Resolvers: "mapping", Mapping: "notjson"}},
+	}
+	for _, test := range tc {
+		t.Run(test.name, func(tt *testing.T) {
+			_, err := NewOwnerResolverComponent().New(context.Background(), test.config)
+			assert.Error(tt, err)
+		})
+	}
+}
@@ -64,7 +64,7 @@ func (d *Device42SubnetFetcher) FetchSubnets(ctx context.Context) ([]domain.Subn
 			subnets = append(subnets, domain.Subnet{
 				ID:         strconv.Itoa(subnet.SubnetID),
 				Network:    subnet.Network,
-				MaskBits:   int8(subnet.MaskBits),
+				MaskBits:   subnet.MaskBits,
 				Location:   subnet.CustomFields.GetValue("Location"),
 				CustomerID: strconv.Itoa(subnet.CustomerID),
 			})
@@ -37,7 +37,7 @@ func TestFetchSubnets(t *testing.T) {
 	}
 
 	subnets, err := d.FetchSubnets(context.Background())
-	assert.ElementsMatch(t, []domain.Subnet{domain.Subnet{ID: "1", Network: "192.168.1.1", MaskBits: int8(32), Location: "AUS", CustomerID: "1"}}, subnets)
+	assert.ElementsMatch(t, []domain.Subnet{domain.Subnet{ID: "1", Network: "192.168.1.1", MaskBits: 32, Location: "AUS", CustomerID: "1"}}, subnets)
 	assert.Nil(t, err)
 }
 
@@ -56,9 +56,9 @@ func TestFetchSubnetsMultiple(t *testing.T) {
 
 	subnets, err := d.FetchSubnets(context.Background())
 	assert.ElementsMatch(t, []domain.Subnet{
-		domain.Subnet{ID: "1", Network: "192.168.1.1", MaskBits: int8(32), Location: "AUS", CustomerID: "1"},
-		domain.Subnet{ID: "2", Network: "192.168.1.0", MaskBits: int8(28), Location: "SYD", CustomerID: "2"},
-		domain.Subnet{ID: "3", Network: "192.168.1.3", MaskBits: int8(32), Location: "LON", CustomerID: "3"},
+		domain.Subnet{ID: "1", Network: "192.168.1.1", MaskBits: 32, Location: "AUS", CustomerID: "1"},
+		domain.Subnet{ID: "2", Network: "192.168.1.0", MaskBits: 28, Location: "SYD", CustomerID: "2"},
+		domain.Subnet{ID: "3", Network: "192.168.1.3", MaskBits: 32, Location: "LON", CustomerID: "3"},
 	}, subnets)
 	assert.Nil(t, err)
 }
@@ -79,9 +79,9 @@ func TestFetchSubnetsMissingCustomerID(t *testing.T) {
 
 	subnets, err := d.FetchSubnets(context.Background())
 	assert.ElementsMatch(t, []domain.Subnet{
-		domain.Subnet{ID: "1", Network: "192.168.1.1", MaskBits: int8(32), Location: "AUS", CustomerID: "1"},
-		domain.Subnet{ID: "2", Network: "192.168.1.0", MaskBits: int8(28), Location: "SYD", CustomerID: "2"},
-		domain.Subnet{ID: "3", Network: "192.168.1.3", MaskBits: int8(32), Location: "LON", CustomerID: "0"},
+		domain.Subnet{ID: "1", Network: "192.168.1.1", MaskBits: 32, Location: "AUS", CustomerID: "1"},
+		domain.Subnet{ID: "2", Network: "192.168.1.0", MaskBits: 28, Location: "SYD", CustomerID: "2"},
+		domain.Subnet{ID: "3", Network: "192.168.1.3", MaskBits: 32, Location: "LON", CustomerID: "0"},
 	}, subnets)
 	assert.Nil(t, err)
 }
@@ -0,0 +1,113 @@
+package ipammerge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const (
+	customerEntity = "customer"
+	subnetEntity   = "subnet"
+	ipEntity       = "ip"
+
+	resourceOwnerField = "resourceOwner"
+	contactsField      = "contacts"
+	locationField      = "location"
+	customerField      = "customer"
+	deviceIDField      = "deviceID"
+	subnetField        = "subnet"
+)
+
+// mergedFields lists the fields of each entity that may be given their own precedence.
+var mergedFields = map[string][]string{
+	customerEntity: {resourceOwnerField, contactsField},
+	subnetEntity:   {locationField, customerField},
+	ipEntity:       {deviceIDField, subnetField},
+}
+
+// Precedence maps an entity, such as "subnet", or an entity field, such as "subnet.location",
+// to the order in which sources are consulted for it. Sources missing from an order are
+// consulted afterwards, in the order the sources are configured.
+type Precedence map[string][]string
+
+// order returns the sources to consult for a field of an entity, most preferred first. The
+// field precedence is used if there is one, then the entity precedence, then the defaults.
+func (p Precedence) order(entity string, field string, defaults []string) []string {
+	explicit, ok := p[entity+"."+field]
+	if !ok {
+		explicit = p[entity]
+	}
+	order := make([]string, 0, len(defaults))
+	seen := make(map[string]bool, len(defaults))
+	for _, name := range append(append([]string{}, explicit...), defaults...) {
+		if !seen[name] {
+			seen[name] = true
+			order = append(order, name)
+		}
+	}
+	return order
+}
+
+// validate checks that every key of the Precedence names a known entity or field.
+func (p Precedence) validate() error {
+	for key := range p {
+		parts := strings.SplitN(key, ".", 2)
+		fields, ok := mergedFields[parts[0]]
+		if !ok {
+			return fmt.Errorf("unknown entity %q in merge precedence", parts[0])
+		}
+		if len(parts) == 2 && !contains(fields, parts[1]) {
+			return fmt.Errorf("unknown field %q of %s in merge precedence", parts[1], parts[0])
+		}
+	}
+	return nil
+}
+
+func contains(haystack []string, needle string) bool {
+	for _, straw := range haystack {
+		if straw == needle {
+			return true
+		}
+	}
+	return false
+}
+
+// Config contains the settings used to merge several IPAM data sources.
+type Config struct {
+	Precedence string `description:"JSON object mapping an entity (customer, subnet, ip) or an entity field (customer.resourceOwner, customer.contacts, subnet.location, subnet.customer, ip.deviceID, ip.subnet) to an ordered list of sources, overriding the order of IPAMFACADE_SOURCE."`
+}
+
+// Name is used by the settings library to replace the default naming convention.
+func (*Config) Name() string {
+	return "Merge"
+}
+
+// NewComponent generates a new, unititialized Component
+func NewComponent() *Component {
+	return &Component{}
+}
+
+// Component satisfies the settings library Component API,
+// and may be used by the settings.NewComponent function.
+type Component struct{}
+
+// Settings generates a config with default values applied.
+func (*Component) Settings() *Config {
+	return &Config{}
+}
+
+// New parses the configured Precedence.
+func (*Component) New(_ context.Context, conf *Config) (Precedence, error) {
+	precedence := make(Precedence)
+	if conf.Precedence != "" {
+		if err := json.Unmarshal([]byte(conf.Precedence), &precedence); err != nil {
+			return nil, err
+		}
+	}
+	if err := precedence.validate(); err != nil {
+		return nil, err
+	}
+	return precedence, nil
+}
@@ -0,0 +1,42 @@
+package ipammerge
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrecedenceOrder(t *testing.T) {
+	defaults := []string{"device42", "file", "netbox"}
+	precedence := Precedence{
+		"subnet":          {"netbox"},
+		"subnet.location": {"file", "netbox"},
+	}
+	assert.Equal(t, []string{"file", "netbox", "device42"}, precedence.order("subnet", "location", defaults))
+	assert.Equal(t, []string{"netbox", "device42", "file"}, precedence.order("subnet", "customer", defaults))
+	assert.Equal(t, []string{"netbox", "device42", "file"}, precedence.order("subnet", "", defaults))
+	assert.Equal(t, defaults, precedence.order("customer", "contacts", defaults))
+}
+
+func TestComponent(t *testing.T) {
+	assert.Equal(t, "Merge", (&Config{}).Name())
+
+	component := NewComponent()
+	conf := component.Settings()
+	precedence, err := component.New(context.Background(), conf)
+	require.NoError(t, err)
+	assert.Empty(t, precedence)
+
+	conf.Precedence = `{"customer.resourceOwner": ["file", "device42"], "ip": ["netbox"]}`
+	precedence, err = component.New(context.Background(), conf)
+	require.NoError(t, err)
+	assert.Equal(t, Precedence{"customer.resourceOwner": {"file", "device42"}, "ip": {"netbox"}}, precedence)
+
+	for _, invalid := range []string{`{"subnet": "netbox"}`, `{"device": ["netbox"]}`, `{"customer.location": ["netbox"]}`} {
+		conf.Precedence = invalid
+		_, err = component.New(context.Background(), conf)
+		assert.Error(t, err, invalid)
+	}
+}
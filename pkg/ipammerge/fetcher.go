@@ -0,0 +1,416 @@
+package ipammerge
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+	"github.com/asecurityteam/ipam-facade/pkg/logs"
+	"github.com/pkg/errors"
+)
+
+// Source is an IPAM data source along with the name recorded as the provenance of its records.
+type Source struct {
+	Name    string
+	Fetcher domain.IPAMDataFetcher
+}
+
+// NewIPAMDataFetcher generates an IPAMDataFetcher for the sources, which are listed from the
+// highest to the lowest default precedence.
+func NewIPAMDataFetcher(sources []Source, precedence Precedence, logFn domain.LogFn) (*IPAMDataFetcher, error) {
+	if len(sources) == 0 {
+		return nil, errors.New("at least one IPAM data source is required")
+	}
+	names := make([]string, 0, len(sources))
+	for _, source := range sources {
+		if contains(names, source.Name) {
+			return nil, fmt.Errorf("IPAM data source %q is listed more than once", source.Name)
+		}
+		names = append(names, source.Name)
+	}
+	if err := precedence.validate(); err != nil {
+		return nil, err
+	}
+	for key, order := range precedence {
+		for _, name := range order {
+			if !contains(names, name) {
+				return nil, fmt.Errorf("merge precedence of %s names unconfigured source %q", key, name)
+			}
+		}
+	}
+	return &IPAMDataFetcher{Sources: sources, Precedence: precedence, LogFn: logFn}, nil
+}
+
+// IPAMDataFetcher implements the IPAMDataFetcher interface by fetching from several sources
+// and merging their records into one IPAMData. Every record is tagged with the names of the
+// sources it was merged from.
+//
+// Records from different sources are the same record when they share a business unit, for
+// Customers, a network, for Subnets, or an IP address, for Devices. Records whose key is not
+// unique within their own source are never merged. Each field of a merged record takes the
+// first non-empty value in the precedence order of that field. The IDs of records from a single
+// source cannot be shared with other sources, so the merged records keep the ID of their most
+// preferred source when it is a free integer, and are otherwise numbered after the highest ID.
+// When there is only one source, its records are only tagged.
+type IPAMDataFetcher struct {
+	Sources    []Source
+	Precedence Precedence
+	LogFn      domain.LogFn
+}
+
+// FetchIPAMData fetches the IPAM data of every source and merges it.
+func (f *IPAMDataFetcher) FetchIPAMData(ctx context.Context) (domain.IPAMData, error) {
+	names := make([]string, 0, len(f.Sources))
+	data := make(map[string]domain.IPAMData, len(f.Sources))
+	for _, source := range f.Sources {
+		ipamData, err := source.Fetcher.FetchIPAMData(ctx)
+		if err != nil {
+			return domain.IPAMData{}, errors.Wrapf(err, "failed to fetch IPAM data from %s", source.Name)
+		}
+		names = append(names, source.Name)
+		data[source.Name] = ipamData
+	}
+	if len(names) == 1 {
+		return tag(data[names[0]], names[0]), nil
+	}
+	m := &merger{names: names, data: data, precedence: f.Precedence}
+	customers := m.mergeCustomers()
+	subnets, conflicts := m.mergeSubnets(customers)
+	devices := m.mergeDevices()
+	logger := f.LogFn(ctx)
+	for _, conflict := range conflicts {
+		logger.Info(logs.SourceConflict{
+			Network: conflict.Network,
+			Field:   conflict.Field,
+			Values:  conflict.Values,
+			Kept:    conflict.Kept,
+		})
+	}
+	return domain.IPAMData{
+		Customers: customers,
+		Subnets:   subnets,
+		Devices:   devices,
+		Conflicts: conflicts,
+	}, nil
+}
+
+// CommitIPAMData commits the last fetch of every source that keeps state between fetches,
+// once the merged data has been stored.
+func (f *IPAMDataFetcher) CommitIPAMData(ctx context.Context) error {
+	for _, source := range f.Sources {
+		committer, ok := source.Fetcher.(domain.IPAMDataCommitter)
+		if !ok {
+			continue
+		}
+		if err := committer.CommitIPAMData(ctx); err != nil {
+			return errors.Wrapf(err, "failed to commit IPAM data from %s", source.Name)
+		}
+	}
+	return nil
+}
+
+// tag records the source of every record of a single source.
+func tag(ipamData domain.IPAMData, name string) domain.IPAMData {
+	tagged := domain.IPAMData{
+		Customers: make([]domain.Customer, 0, len(ipamData.Customers)),
+		Subnets:   make([]domain.Subnet, 0, len(ipamData.Subnets)),
+		Devices:   make([]domain.Device, 0, len(ipamData.Devices)),
+	}
+	for _, customer := range ipamData.Customers {
+		customer.Source = name
+		customer.Contacts = tagContacts(customer.Contacts, name)
+		tagged.Customers = append(tagged.Customers, customer)
+	}
+	for _, subnet := range ipamData.Subnets {
+		subnet.Source = name
+		tagged.Subnets = append(tagged.Subnets, subnet)
+	}
+	for _, device := range ipamData.Devices {
+		device.Source = name
+		tagged.Devices = append(tagged.Devices, device)
+	}
+	return tagged
+}
+
+// tagContacts returns a copy of the contacts with the source of each set to name.
+func tagContacts(contacts []domain.Contact, name string) []domain.Contact {
+	if contacts == nil {
+		return nil
+	}
+	tagged := make([]domain.Contact, 0, len(contacts))
+	for _, contact := range contacts {
+		contact.Source = name
+		tagged = append(tagged, contact)
+	}
+	return tagged
+}
+
+// group collects the records of each source that share a key, by the index of the record in its source.
+type group struct {
+	Records map[string]int
+}
+
+// groupRecords groups the records of every source by key. Records with a key that is empty or
+// repeated within their own source are given a key of their own. Groups are ordered by their
+// first record, taking the sources in order.
+func groupRecords(names []string, count func(string) int, key func(string, int) string) []group {
+	groups := make([]group, 0)
+	index := make(map[string]int)
+	for _, name := range names {
+		keys := make([]string, count(name))
+		occurrences := make(map[string]int)
+		for i := range keys {
+			keys[i] = key(name, i)
+			occurrences[keys[i]]++
+		}
+		for i, k := range keys {
+			if k == "" || occurrences[k] > 1 {
+				k = fmt.Sprintf("%s/%d", name, i)
+			}
+			position, ok := index[k]
+			if !ok {
+				position = len(groups)
+				index[k] = position
+				groups = append(groups, group{Records: make(map[string]int)})
+			}
+			groups[position].Records[name] = i
+		}
+	}
+	return groups
+}
+
+// sources lists the sources of a group in the given order.
+func (g group) sources(order []string) []string {
+	sources := make([]string, 0, len(g.Records))
+	for _, name := range order {
+		if _, ok := g.Records[name]; ok {
+			sources = append(sources, name)
+		}
+	}
+	return sources
+}
+
+// first returns the most preferred source of a group with a non-empty value.
+func (g group) first(order []string, empty func(string, int) bool) (string, int, bool) {
+	for _, name := range g.sources(order) {
+		if !empty(name, g.Records[name]) {
+			return name, g.Records[name], true
+		}
+	}
+	return "", 0, false
+}
+
+// assignIDs keeps each preferred ID that is a positive integer not already taken, and numbers
+// the remaining records after the highest ID.
+func assignIDs(preferred []string) []string {
+	ids := make([]string, len(preferred))
+	taken := make(map[int64]bool)
+	var highest int64
+	for i, id := range preferred {
+		n, err := strconv.ParseInt(id, 10, 32)
+		if err != nil || n <= 0 || taken[n] {
+			continue
+		}
+		ids[i] = id
+		taken[n] = true
+		if n > highest {
+			highest = n
+		}
+	}
+	for i := range ids {
+		if ids[i] == "" {
+			highest++
+			ids[i] = strconv.FormatInt(highest, 10)
+		}
+	}
+	return ids
+}
+
+type merger struct {
+	names      []string
+	data       map[string]domain.IPAMData
+	precedence Precedence
+	// customerIDs and subnetIDs map the IDs of each source to the merged IDs
+	customerIDs map[string]map[string]string
+	subnetIDs   map[string]map[string]string
+	// owners maps the customer IDs of each source to their resource owner
+	owners map[string]map[string]string
+}
+
+// reference returns the merged ID of a record referenced by a source. References to records
+// the source does not have are kept, qualified by the source name, so that the sync validation
+// quarantines the referencing record.
+func reference(ids map[string]map[string]string, name string, id string) string {
+	if merged, ok := ids[name][id]; ok {
+		return merged
+	}
+	return name + ":" + id
+}
+
+func (m *merger) mergeCustomers() []domain.Customer {
+	groups := groupRecords(m.names,
+		func(name string) int { return len(m.data[name].Customers) },
+		func(name string, i int) string {
+			return strings.ToLower(strings.TrimSpace(m.data[name].Customers[i].BusinessUnit))
+		})
+	order := m.precedence.order(customerEntity, "", m.names)
+	ownerOrder := m.precedence.order(customerEntity, resourceOwnerField, m.names)
+	contactsOrder := m.precedence.order(customerEntity, contactsField, m.names)
+
+	preferred := make([]string, 0, len(groups))
+	for _, g := range groups {
+		name := g.sources(order)[0]
+		preferred = append(preferred, m.data[name].Customers[g.Records[name]].ID)
+	}
+	ids := assignIDs(preferred)
+	m.customerIDs = make(map[string]map[string]string)
+	m.owners = make(map[string]map[string]string)
+	for _, name := range m.names {
+		m.customerIDs[name] = make(map[string]string)
+		m.owners[name] = make(map[string]string)
+		for _, customer := range m.data[name].Customers {
+			m.owners[name][customer.ID] = customer.ResourceOwner
+		}
+	}
+
+	customers := make([]domain.Customer, 0, len(groups))
+	for i, g := range groups {
+		sources := g.sources(order)
+		base := m.data[sources[0]].Customers[g.Records[sources[0]]]
+		customer := domain.Customer{
+			ID:           ids[i],
+			BusinessUnit: base.BusinessUnit,
+			Source:       strings.Join(sources, ","),
+		}
+		if name, j, ok := g.first(ownerOrder, func(name string, j int) bool { return m.data[name].Customers[j].ResourceOwner == "" }); ok {
+			customer.ResourceOwner = m.data[name].Customers[j].ResourceOwner
+			customer.ResourceOwnerRule = m.data[name].Customers[j].ResourceOwnerRule
+		}
+		if name, j, ok := g.first(contactsOrder, func(name string, j int) bool { return len(m.data[name].Customers[j].Contacts) == 0 }); ok {
+			customer.Contacts = tagContacts(m.data[name].Customers[j].Contacts, name)
+		}
+		for name, j := range g.Records {
+			m.customerIDs[name][m.data[name].Customers[j].ID] = customer.ID
+		}
+		customers = append(customers, customer)
+	}
+	return customers
+}
+
+// subnetKey returns the network of a Subnet in CIDR notation, or an empty string if it is malformed.
+func subnetKey(subnet domain.Subnet) string {
+	_, network, err := net.ParseCIDR(fmt.Sprintf("%s/%d", subnet.Network, subnet.MaskBits))
+	if err != nil {
+		return ""
+	}
+	return network.String()
+}
+
+func hasCustomer(subnet domain.Subnet) bool {
+	return subnet.CustomerID != "" && subnet.CustomerID != "0"
+}
+
+func (m *merger) mergeSubnets(customers []domain.Customer) ([]domain.Subnet, []domain.SourceConflict) {
+	groups := groupRecords(m.names,
+		func(name string) int { return len(m.data[name].Subnets) },
+		func(name string, i int) string { return subnetKey(m.data[name].Subnets[i]) })
+	order := m.precedence.order(subnetEntity, "", m.names)
+	locationOrder := m.precedence.order(subnetEntity, locationField, m.names)
+	customerOrder := m.precedence.order(subnetEntity, customerField, m.names)
+
+	preferred := make([]string, 0, len(groups))
+	for _, g := range groups {
+		name := g.sources(order)[0]
+		preferred = append(preferred, m.data[name].Subnets[g.Records[name]].ID)
+	}
+	ids := assignIDs(preferred)
+	m.subnetIDs = make(map[string]map[string]string)
+	for _, name := range m.names {
+		m.subnetIDs[name] = make(map[string]string)
+	}
+	owners := make(map[string]string, len(customers))
+	for _, customer := range customers {
+		owners[customer.ID] = customer.ResourceOwner
+	}
+
+	subnets := make([]domain.Subnet, 0, len(groups))
+	conflicts := make([]domain.SourceConflict, 0)
+	for i, g := range groups {
+		sources := g.sources(order)
+		base := m.data[sources[0]].Subnets[g.Records[sources[0]]]
+		subnet := domain.Subnet{
+			ID:       ids[i],
+			Network:  base.Network,
+			MaskBits: base.MaskBits,
+			Source:   strings.Join(sources, ","),
+		}
+		if name, j, ok := g.first(locationOrder, func(name string, j int) bool { return m.data[name].Subnets[j].Location == "" }); ok {
+			subnet.Location = m.data[name].Subnets[j].Location
+		}
+		if name, j, ok := g.first(customerOrder, func(name string, j int) bool { return !hasCustomer(m.data[name].Subnets[j]) }); ok {
+			subnet.CustomerID = reference(m.customerIDs, name, m.data[name].Subnets[j].CustomerID)
+		}
+		for name, j := range g.Records {
+			m.subnetIDs[name][m.data[name].Subnets[j].ID] = subnet.ID
+		}
+		if conflict, ok := m.ownerConflict(g, customerOrder, owners[subnet.CustomerID]); ok {
+			conflict.SubnetID = subnet.ID
+			conflict.Network = subnetKey(subnet)
+			conflicts = append(conflicts, conflict)
+		}
+		subnets = append(subnets, subnet)
+	}
+	return subnets, conflicts
+}
+
+// ownerConflict reports the resource owners of a network when its sources disagree about them.
+func (m *merger) ownerConflict(g group, order []string, kept string) (domain.SourceConflict, bool) {
+	values := make([]string, 0, len(g.Records))
+	distinct := make(map[string]bool)
+	for _, name := range g.sources(order) {
+		subnet := m.data[name].Subnets[g.Records[name]]
+		if owner := m.owners[name][subnet.CustomerID]; hasCustomer(subnet) && owner != "" {
+			values = append(values, name+"="+owner)
+			distinct[strings.ToLower(owner)] = true
+		}
+	}
+	if len(distinct) < 2 {
+		return domain.SourceConflict{}, false
+	}
+	return domain.SourceConflict{Field: resourceOwnerField, Values: strings.Join(values, ","), Kept: kept}, true
+}
+
+func (m *merger) mergeDevices() []domain.Device {
+	groups := groupRecords(m.names,
+		func(name string) int { return len(m.data[name].Devices) },
+		func(name string, i int) string {
+			ip := net.ParseIP(m.data[name].Devices[i].IP)
+			if ip == nil {
+				return ""
+			}
+			return ip.String()
+		})
+	order := m.precedence.order(ipEntity, "", m.names)
+	deviceIDOrder := m.precedence.order(ipEntity, deviceIDField, m.names)
+	subnetOrder := m.precedence.order(ipEntity, subnetField, m.names)
+
+	devices := make([]domain.Device, 0, len(groups))
+	for _, g := range groups {
+		sources := g.sources(order)
+		device := domain.Device{
+			IP:     m.data[sources[0]].Devices[g.Records[sources[0]]].IP,
+			Source: strings.Join(sources, ","),
+		}
+		if name, j, ok := g.first(deviceIDOrder, func(name string, j int) bool { return m.data[name].Devices[j].ID == "" }); ok {
+			device.ID = m.data[name].Devices[j].ID
+		}
+		if name, j, ok := g.first(subnetOrder, func(name string, j int) bool { return m.data[name].Devices[j].SubnetID == "" }); ok {
+			device.SubnetID = reference(m.subnetIDs, name, m.data[name].Devices[j].SubnetID)
+		}
+		devices = append(devices, device)
+	}
+	return devices
+}
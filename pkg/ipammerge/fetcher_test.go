@@ -0,0 +1,233 @@
+package ipammerge
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+	"github.com/asecurityteam/ipam-facade/pkg/logs"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMockSource(ctrl *gomock.Controller, name string, ipamData domain.IPAMData) Source {
+	fetcher := NewMockIPAMDataFetcher(ctrl)
+	fetcher.EXPECT().FetchIPAMData(gomock.Any()).Return(ipamData, nil)
+	return Source{Name: name, Fetcher: fetcher}
+}
+
+// recordingLogger keeps the events logged at the info level.
+type recordingLogger struct {
+	Events []interface{}
+}
+
+func (*recordingLogger) Debug(event interface{})                 {}
+func (l *recordingLogger) Info(event interface{})                { l.Events = append(l.Events, event) }
+func (*recordingLogger) Warn(event interface{})                  {}
+func (*recordingLogger) Error(event interface{})                 {}
+func (*recordingLogger) SetField(name string, value interface{}) {}
+func (l *recordingLogger) Copy() domain.Logger {
+	return l
+}
+
+func (l *recordingLogger) LogFn(context.Context) domain.Logger { return l }
+
+func TestFetchIPAMDataMerge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	technical := domain.Contact{Type: "Technical", Name: "Alice", Email: "alice@example.com"}
+	device42 := newMockSource(ctrl, "device42", domain.IPAMData{
+		Customers: []domain.Customer{
+			{ID: "1", ResourceOwner: "alice@example.com", ResourceOwnerRule: "contact-type:Technical", BusinessUnit: "Payments", Contacts: []domain.Contact{technical}},
+			{ID: "2", ResourceOwner: "security@example.com", ResourceOwnerRule: "contact-info", BusinessUnit: "Security"},
+			{ID: "3", BusinessUnit: "Shared"},
+			{ID: "4", BusinessUnit: "Shared"},
+		},
+		Subnets: []domain.Subnet{
+			{ID: "10", Network: "10.0.0.0", MaskBits: 16, Location: "DC1", CustomerID: "1"},
+			{ID: "11", Network: "10.1.0.0", MaskBits: 16, CustomerID: "2"},
+		},
+		Devices: []domain.Device{
+			{ID: "70", IP: "10.0.0.5", SubnetID: "10"},
+		},
+	})
+	file := newMockSource(ctrl, "file", domain.IPAMData{
+		Customers: []domain.Customer{
+			{ID: "1", ResourceOwner: "override@example.com", ResourceOwnerRule: "file", BusinessUnit: "payments"},
+		},
+		Subnets: []domain.Subnet{
+			{ID: "1", Network: "10.1.0.0", MaskBits: 16, Location: "Lab", CustomerID: "1"},
+		},
+	})
+	netbox := newMockSource(ctrl, "netbox", domain.IPAMData{
+		Customers: []domain.Customer{
+			{ID: "1", BusinessUnit: "Networking"},
+		},
+		Subnets: []domain.Subnet{
+			{ID: "1", Network: "10.2.0.0", MaskBits: 16, CustomerID: "1"},
+			{ID: "2", Network: "10.0.0.0", MaskBits: 16, Location: "NB-DC", CustomerID: "1"},
+		},
+		Devices: []domain.Device{
+			{ID: "5", IP: "10.0.0.5", SubnetID: "2"},
+			{IP: "10.2.0.9", SubnetID: "1"},
+			{IP: "10.9.9.9", SubnetID: "99"},
+		},
+	})
+
+	logger := &recordingLogger{}
+	precedence := Precedence{
+		"customer.resourceOwner": {"file"},
+		"subnet.location":        {"netbox"},
+	}
+	fetcher, err := NewIPAMDataFetcher([]Source{device42, file, netbox}, precedence, logger.LogFn)
+	require.NoError(t, err)
+
+	ipamData, err := fetcher.FetchIPAMData(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Customer{
+		{ID: "1", ResourceOwner: "override@example.com", ResourceOwnerRule: "file", BusinessUnit: "Payments", Contacts: []domain.Contact{
+			{Type: "Technical", Name: "Alice", Email: "alice@example.com", Source: "device42"},
+		}, Source: "device42,file"},
+		{ID: "2", ResourceOwner: "security@example.com", ResourceOwnerRule: "contact-info", BusinessUnit: "Security", Source: "device42"},
+		{ID: "3", BusinessUnit: "Shared", Source: "device42"},
+		{ID: "4", BusinessUnit: "Shared", Source: "device42"},
+		{ID: "5", BusinessUnit: "Networking", Source: "netbox"},
+	}, ipamData.Customers)
+	assert.Equal(t, []domain.Subnet{
+		{ID: "10", Network: "10.0.0.0", MaskBits: 16, Location: "NB-DC", CustomerID: "1", Source: "device42,netbox"},
+		{ID: "11", Network: "10.1.0.0", MaskBits: 16, Location: "Lab", CustomerID: "2", Source: "device42,file"},
+		{ID: "1", Network: "10.2.0.0", MaskBits: 16, CustomerID: "5", Source: "netbox"},
+	}, ipamData.Subnets)
+	assert.Equal(t, []domain.Device{
+		{ID: "70", IP: "10.0.0.5", SubnetID: "10", Source: "device42,netbox"},
+		{IP: "10.2.0.9", SubnetID: "1", Source: "netbox"},
+		{IP: "10.9.9.9", SubnetID: "netbox:99", Source: "netbox"},
+	}, ipamData.Devices)
+	assert.Equal(t, []domain.SourceConflict{{
+		SubnetID: "11",
+		Network:  "10.1.0.0/16",
+		Field:    "resourceOwner",
+		Values:   "device42=security@example.com,file=override@example.com",
+		Kept:     "security@example.com",
+	}}, ipamData.Conflicts)
+	assert.Equal(t, []interface{}{logs.SourceConflict{
+		Network: "10.1.0.0/16",
+		Field:   "resourceOwner",
+		Values:  "device42=security@example.com,file=override@example.com",
+		Kept:    "security@example.com",
+	}}, logger.Events)
+}
+
+func TestFetchIPAMDataEntityPrecedence(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	device42 := newMockSource(ctrl, "device42", domain.IPAMData{
+		Subnets: []domain.Subnet{{ID: "1", Network: "10.0.0.0", MaskBits: 8, Location: "DC1"}},
+		Devices: []domain.Device{{ID: "7", IP: "10.0.0.1", SubnetID: "1"}},
+	})
+	netbox := newMockSource(ctrl, "netbox", domain.IPAMData{
+		Subnets: []domain.Subnet{{ID: "2", Network: "10.0.0.0", MaskBits: 8, Location: "DC2"}},
+		Devices: []domain.Device{{ID: "8", IP: "10.0.0.1"}},
+	})
+
+	fetcher, err := NewIPAMDataFetcher([]Source{device42, netbox}, Precedence{"subnet": {"netbox"}, "ip.deviceID": {"netbox"}}, (&recordingLogger{}).LogFn)
+	require.NoError(t, err)
+	ipamData, err := fetcher.FetchIPAMData(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Subnet{
+		{ID: "2", Network: "10.0.0.0", MaskBits: 8, Location: "DC2", Source: "netbox,device42"},
+	}, ipamData.Subnets)
+	// the subnet is taken from device42, which has the only subnet link, and mapped to the merged subnet
+	assert.Equal(t, []domain.Device{
+		{ID: "8", IP: "10.0.0.1", SubnetID: "2", Source: "device42,netbox"},
+	}, ipamData.Devices)
+}
+
+func TestFetchIPAMDataSingleSource(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	source := newMockSource(ctrl, "device42", domain.IPAMData{
+		Customers: []domain.Customer{{ID: "9", BusinessUnit: "Payments", Contacts: []domain.Contact{{Type: "SRE", Email: "sre@example.com"}}}},
+		Subnets:   []domain.Subnet{{ID: "8", Network: "10.0.0.0", MaskBits: 8, CustomerID: "9"}},
+		Devices:   []domain.Device{{IP: "10.0.0.1", SubnetID: "8"}},
+	})
+	fetcher, err := NewIPAMDataFetcher([]Source{source}, Precedence{}, (&recordingLogger{}).LogFn)
+	require.NoError(t, err)
+
+	ipamData, err := fetcher.FetchIPAMData(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, domain.IPAMData{
+		Customers: []domain.Customer{{ID: "9", BusinessUnit: "Payments", Contacts: []domain.Contact{{Type: "SRE", Email: "sre@example.com", Source: "device42"}}, Source: "device42"}},
+		Subnets:   []domain.Subnet{{ID: "8", Network: "10.0.0.0", MaskBits: 8, CustomerID: "9", Source: "device42"}},
+		Devices:   []domain.Device{{IP: "10.0.0.1", SubnetID: "8", Source: "device42"}},
+	}, ipamData)
+}
+
+func TestFetchIPAMDataSourceError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	failing := NewMockIPAMDataFetcher(ctrl)
+	failing.EXPECT().FetchIPAMData(gomock.Any()).Return(domain.IPAMData{}, errors.New("connection refused"))
+	fetcher, err := NewIPAMDataFetcher([]Source{newMockSource(ctrl, "device42", domain.IPAMData{}), {Name: "netbox", Fetcher: failing}}, Precedence{}, (&recordingLogger{}).LogFn)
+	require.NoError(t, err)
+
+	_, err = fetcher.FetchIPAMData(context.Background())
+	require.Error(t, err)
+	assert.Equal(t, "failed to fetch IPAM data from netbox: connection refused", err.Error())
+}
+
+// committingFetcher is an IPAMDataFetcher that keeps state between fetches.
+type committingFetcher struct {
+	*MockIPAMDataFetcher
+	*MockIPAMDataCommitter
+}
+
+func TestCommitIPAMData(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	committer := NewMockIPAMDataCommitter(ctrl)
+	committer.EXPECT().CommitIPAMData(gomock.Any()).Return(nil)
+	sources := []Source{
+		{Name: "device42", Fetcher: committingFetcher{NewMockIPAMDataFetcher(ctrl), committer}},
+		{Name: "netbox", Fetcher: NewMockIPAMDataFetcher(ctrl)},
+	}
+	fetcher, err := NewIPAMDataFetcher(sources, Precedence{}, (&recordingLogger{}).LogFn)
+	require.NoError(t, err)
+	require.NoError(t, fetcher.CommitIPAMData(context.Background()))
+
+	committer.EXPECT().CommitIPAMData(gomock.Any()).Return(errors.New("connection refused"))
+	err = fetcher.CommitIPAMData(context.Background())
+	require.Error(t, err)
+	assert.Equal(t, "failed to commit IPAM data from device42: connection refused", err.Error())
+}
+
+func TestNewIPAMDataFetcherErrors(t *testing.T) {
+	source := Source{Name: "device42"}
+	tc := []struct {
+		Name       string
+		Sources    []Source
+		Precedence Precedence
+	}{
+		{Name: "no sources", Precedence: Precedence{}},
+		{Name: "duplicate source", Sources: []Source{source, source}, Precedence: Precedence{}},
+		{Name: "unknown source", Sources: []Source{source}, Precedence: Precedence{"subnet": {"netbox"}}},
+		{Name: "unknown field", Sources: []Source{source}, Precedence: Precedence{"subnet.vlan": {"device42"}}},
+	}
+	for _, tt := range tc {
+		t.Run(tt.Name, func(t *testing.T) {
+			_, err := NewIPAMDataFetcher(tt.Sources, tt.Precedence, nil)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestAssignIDs(t *testing.T) {
+	assert.Equal(t, []string{"3", "1", "4", "5", "6", "7"}, assignIDs([]string{"3", "1", "3", "", "abc", "-2"}))
+}
@@ -0,0 +1,171 @@
+package ipamvalidator
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+	"github.com/asecurityteam/ipam-facade/pkg/logs"
+)
+
+// RecordValidator implements the IPAMDataValidator interface by checking each record
+// against the constraints of the local storage schema. Invalid records are quarantined
+// individually rather than failing the entire sync.
+type RecordValidator struct {
+	LogFn domain.LogFn
+}
+
+// ValidateIPAMData returns the valid subset of the given IPAM data along with the records
+// that were quarantined. Customers are validated first, then subnets, then IP addresses,
+// so that a record referencing a quarantined record is quarantined as well.
+func (v *RecordValidator) ValidateIPAMData(ctx context.Context, ipamData domain.IPAMData) (domain.IPAMData, []domain.QuarantinedRecord) {
+	logger := v.LogFn(ctx)
+	valid := domain.IPAMData{
+		Customers: make([]domain.Customer, 0, len(ipamData.Customers)),
+		Subnets:   make([]domain.Subnet, 0, len(ipamData.Subnets)),
+		Devices:   make([]domain.Device, 0, len(ipamData.Devices)),
+	}
+	quarantined := make([]domain.QuarantinedRecord, 0)
+
+	customers := make(map[string]bool, len(ipamData.Customers))
+	for _, customer := range ipamData.Customers {
+		if err := validateCustomer(customer, customers); err != nil {
+			logger.Info(logs.InvalidCustomer{ID: customer.ID, Reason: err.Error()})
+			quarantined = append(quarantined, domain.QuarantinedRecord{
+				RecordType: domain.QualityRecordCustomer,
+				RecordID:   customer.ID,
+				Reason:     err.Error(),
+			})
+			continue
+		}
+		customers[customer.ID] = true
+		valid.Customers = append(valid.Customers, customer)
+	}
+
+	subnets := make(map[string]*net.IPNet, len(ipamData.Subnets))
+	fetchedSubnets := make(map[string]bool, len(ipamData.Subnets))
+	for _, subnet := range ipamData.Subnets {
+		network, err := validateSubnet(subnet, subnets, customers)
+		fetchedSubnets[subnet.ID] = true
+		if err != nil {
+			logger.Info(logs.InvalidSubnet{ID: subnet.ID, Reason: err.Error()})
+			quarantined = append(quarantined, domain.QuarantinedRecord{
+				RecordType: domain.QualityRecordSubnet,
+				RecordID:   subnet.ID,
+				Reason:     err.Error(),
+			})
+			continue
+		}
+		subnets[subnet.ID] = network
+		valid.Subnets = append(valid.Subnets, subnet)
+	}
+
+	for _, device := range ipamData.Devices {
+		if err := validateDevice(device, subnets, fetchedSubnets); err != nil {
+			logger.Info(logs.InvalidIP{IP: device.IP, Reason: err.Error()})
+			quarantined = append(quarantined, domain.QuarantinedRecord{
+				RecordType: domain.QualityRecordIP,
+				RecordID:   device.IP,
+				Reason:     err.Error(),
+			})
+			continue
+		}
+		valid.Devices = append(valid.Devices, device)
+	}
+
+	return valid, quarantined
+}
+
+func validateCustomer(customer domain.Customer, customers map[string]bool) error {
+	if err := validateID(customer.ID); err != nil {
+		return err
+	}
+	if customers[customer.ID] {
+		return fmt.Errorf("duplicate customer ID %s", customer.ID)
+	}
+	return nil
+}
+
+func validateSubnet(subnet domain.Subnet, subnets map[string]*net.IPNet, customers map[string]bool) (*net.IPNet, error) {
+	if err := validateID(subnet.ID); err != nil {
+		return nil, err
+	}
+	if _, ok := subnets[subnet.ID]; ok {
+		return nil, fmt.Errorf("duplicate subnet ID %s", subnet.ID)
+	}
+	network, err := ParseSubnet(subnet)
+	if err != nil {
+		return nil, err
+	}
+	if HasCustomer(subnet) && !customers[subnet.CustomerID] {
+		return nil, fmt.Errorf("references unknown customer %s", subnet.CustomerID)
+	}
+	return network, nil
+}
+
+func validateDevice(device domain.Device, subnets map[string]*net.IPNet, fetchedSubnets map[string]bool) error {
+	ip := net.ParseIP(device.IP)
+	if ip == nil {
+		return fmt.Errorf("invalid IP address %q", device.IP)
+	}
+	if device.ID != "" {
+		if err := validateID(device.ID); err != nil {
+			return err
+		}
+	}
+	network, ok := subnets[device.SubnetID]
+	switch {
+	case !ok && fetchedSubnets[device.SubnetID]:
+		return fmt.Errorf("references quarantined subnet %s", device.SubnetID)
+	case !ok:
+		return fmt.Errorf("references unknown subnet %s", device.SubnetID)
+	case !network.Contains(ip):
+		return fmt.Errorf("IP address %s is not in subnet %s (%s)", device.IP, device.SubnetID, network)
+	}
+	return nil
+}
+
+// validateID checks that a record ID fits the INTEGER columns of the storage schema.
+func validateID(id string) error {
+	if _, err := strconv.ParseInt(id, 10, 32); err != nil {
+		return fmt.Errorf("invalid ID %q", id)
+	}
+	return nil
+}
+
+// MaxMaskBits returns the longest mask of a network: 32 bits for an IPv4 network, and 128
+// bits for any other, which ParseSubnet rejects unless it is an IPv6 network.
+func MaxMaskBits(network string) int {
+	if ip := net.ParseIP(network); ip != nil && ip.To4() != nil {
+		return net.IPv4len * 8
+	}
+	return net.IPv6len * 8
+}
+
+// ParseSubnet returns the network of a Subnet, or an error if the network and mask
+// bits do not form a valid CIDR with no host bits set.
+func ParseSubnet(subnet domain.Subnet) (*net.IPNet, error) {
+	ip := net.ParseIP(subnet.Network)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid network %q", subnet.Network)
+	}
+	if subnet.MaskBits < 0 || subnet.MaskBits > MaxMaskBits(subnet.Network) {
+		return nil, fmt.Errorf("mask bits %d out of range for network %s", subnet.MaskBits, subnet.Network)
+	}
+	cidr := fmt.Sprintf("%s/%d", subnet.Network, subnet.MaskBits)
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid network %s", cidr)
+	}
+	if !network.IP.Equal(ip) {
+		return nil, fmt.Errorf("network %s has host bits set", cidr)
+	}
+	return network, nil
+}
+
+// HasCustomer follows the storer in treating an empty or zero customer ID as no customer.
+func HasCustomer(subnet domain.Subnet) bool {
+	return subnet.CustomerID != "" && subnet.CustomerID != "0"
+}
@@ -0,0 +1,105 @@
+package ipamvalidator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+	"github.com/stretchr/testify/require"
+)
+
+type nopLogger struct{}
+
+func (*nopLogger) Debug(event interface{})                 {}
+func (*nopLogger) Info(event interface{})                  {}
+func (*nopLogger) Warn(event interface{})                  {}
+func (*nopLogger) Error(event interface{})                 {}
+func (*nopLogger) SetField(name string, value interface{}) {}
+func (logger *nopLogger) Copy() domain.Logger {
+	return logger
+}
+
+func testLogFn(context.Context) domain.Logger { return &nopLogger{} }
+
+func TestValidateIPAMDataValid(t *testing.T) {
+	ipamData := domain.IPAMData{
+		Customers: []domain.Customer{
+			{ID: "1", ResourceOwner: "alice@example.com"},
+		},
+		Subnets: []domain.Subnet{
+			{ID: "1", Network: "10.0.0.0", MaskBits: 24, CustomerID: "1"},
+			{ID: "2", Network: "10.0.1.0", MaskBits: 24, CustomerID: "0"},
+			{ID: "3", Network: "2001:db8::", MaskBits: 64},
+			{ID: "4", Network: "2001:db8::2", MaskBits: 128},
+		},
+		Devices: []domain.Device{
+			{ID: "1", IP: "10.0.0.1", SubnetID: "1"},
+			{IP: "10.0.1.1", SubnetID: "2"},
+			{ID: "2", IP: "2001:db8::1", SubnetID: "3"},
+			{ID: "3", IP: "2001:db8::2", SubnetID: "4"},
+		},
+	}
+
+	validator := &RecordValidator{LogFn: testLogFn}
+	valid, quarantined := validator.ValidateIPAMData(context.Background(), ipamData)
+	require.Equal(t, ipamData, valid)
+	require.Equal(t, []domain.QuarantinedRecord{}, quarantined)
+}
+
+func TestValidateIPAMDataQuarantine(t *testing.T) {
+	ipamData := domain.IPAMData{
+		Customers: []domain.Customer{
+			{ID: "1", ResourceOwner: "alice@example.com"},
+			{ID: "1", ResourceOwner: "bob@example.com"},
+			{ID: "abc", ResourceOwner: "carol@example.com"},
+		},
+		Subnets: []domain.Subnet{
+			{ID: "1", Network: "10.0.0.0", MaskBits: 24, CustomerID: "1"},
+			{ID: "1", Network: "10.0.9.0", MaskBits: 24, CustomerID: "1"},
+			{ID: "2", Network: "10.0.1.0/24", MaskBits: 24, CustomerID: "1"},
+			{ID: "3", Network: "10.0.2.0", MaskBits: 33, CustomerID: "1"},
+			{ID: "4", Network: "10.0.3.1", MaskBits: 24, CustomerID: "1"},
+			{ID: "5", Network: "10.0.4.0", MaskBits: 24, CustomerID: "42"},
+			{ID: "6", Network: "2001:db8::", MaskBits: -1},
+		},
+		Devices: []domain.Device{
+			{ID: "1", IP: "10.0.0.1", SubnetID: "1"},
+			{ID: "2", IP: "not-an-ip", SubnetID: "1"},
+			{ID: "3", IP: "10.0.1.1", SubnetID: "2"},
+			{ID: "4", IP: "10.0.0.4", SubnetID: "99"},
+			{ID: "5", IP: "10.0.5.1", SubnetID: "1"},
+			{ID: "device", IP: "10.0.0.6", SubnetID: "1"},
+		},
+	}
+	expectedValid := domain.IPAMData{
+		Customers: []domain.Customer{
+			{ID: "1", ResourceOwner: "alice@example.com"},
+		},
+		Subnets: []domain.Subnet{
+			{ID: "1", Network: "10.0.0.0", MaskBits: 24, CustomerID: "1"},
+		},
+		Devices: []domain.Device{
+			{ID: "1", IP: "10.0.0.1", SubnetID: "1"},
+		},
+	}
+	expectedQuarantined := []domain.QuarantinedRecord{
+		{RecordType: domain.QualityRecordCustomer, RecordID: "1", Reason: "duplicate customer ID 1"},
+		{RecordType: domain.QualityRecordCustomer, RecordID: "abc", Reason: `invalid ID "abc"`},
+		{RecordType: domain.QualityRecordSubnet, RecordID: "1", Reason: "duplicate subnet ID 1"},
+		{RecordType: domain.QualityRecordSubnet, RecordID: "2", Reason: `invalid network "10.0.1.0/24"`},
+		{RecordType: domain.QualityRecordSubnet, RecordID: "3", Reason: "mask bits 33 out of range for network 10.0.2.0"},
+		{RecordType: domain.QualityRecordSubnet, RecordID: "4", Reason: "network 10.0.3.1/24 has host bits set"},
+		{RecordType: domain.QualityRecordSubnet, RecordID: "5", Reason: "references unknown customer 42"},
+		{RecordType: domain.QualityRecordSubnet, RecordID: "6", Reason: "mask bits -1 out of range for network 2001:db8::"},
+		{RecordType: domain.QualityRecordIP, RecordID: "not-an-ip", Reason: `invalid IP address "not-an-ip"`},
+		{RecordType: domain.QualityRecordIP, RecordID: "10.0.1.1", Reason: "references quarantined subnet 2"},
+		{RecordType: domain.QualityRecordIP, RecordID: "10.0.0.4", Reason: "references unknown subnet 99"},
+		{RecordType: domain.QualityRecordIP, RecordID: "10.0.5.1", Reason: "IP address 10.0.5.1 is not in subnet 1 (10.0.0.0/24)"},
+		{RecordType: domain.QualityRecordIP, RecordID: "10.0.0.6", Reason: `invalid ID "device"`},
+	}
+
+	validator := &RecordValidator{LogFn: testLogFn}
+	valid, quarantined := validator.ValidateIPAMData(context.Background(), ipamData)
+	require.Equal(t, expectedValid, valid)
+	require.Equal(t, expectedQuarantined, quarantined)
+}
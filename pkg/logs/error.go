@@ -12,6 +12,12 @@ type AssetNotFound struct {
 	Reason  string `logevent:"reason"`
 }
 
+// SyncJobNotFound is logged when no report was found in storage for a given sync job ID.
+type SyncJobNotFound struct {
+	Message string `logevent:"message,default=sync-job-not-found"`
+	Reason  string `logevent:"reason"`
+}
+
 // AssetFetcherFailure is logged when an unexpected error occurs attempting to fetch an asset from storage.
 type AssetFetcherFailure struct {
 	Message string `logevent:"message,default=asset-fetch-failure"`
@@ -43,3 +49,49 @@ type ProducerError struct {
 	Message string `logevent:"message,default=producer-error"`
 	Reason  string `logevent:"reason"`
 }
+
+// QualityReportFailure is logged when storing the data quality report for a data sync fails.
+type QualityReportFailure struct {
+	Message string `logevent:"message,default=quality-report-failure"`
+	Reason  string `logevent:"reason"`
+	JobID   string `logevent:"jobId"`
+}
+
+// AssetCacheInvalidationFailure is logged when the cached lookups of other instances could not
+// be invalidated after a sync stored new IPAM data.
+type AssetCacheInvalidationFailure struct {
+	Message string `logevent:"message,default=asset-cache-invalidation-failure"`
+	Reason  string `logevent:"reason"`
+	JobID   string `logevent:"jobId"`
+}
+
+// IPAMDataCommitFailure is logged when the state an IPAM data source keeps between syncs
+// could not be advanced after a sync stored new IPAM data.
+type IPAMDataCommitFailure struct {
+	Message string `logevent:"message,default=ipam-data-commit-failure"`
+	Reason  string `logevent:"reason"`
+	JobID   string `logevent:"jobId"`
+}
+
+// SyncLockFailure is logged when a sync could not be started because taking the sync lock
+// failed.
+type SyncLockFailure struct {
+	Message string `logevent:"message,default=sync-lock-failure"`
+	Reason  string `logevent:"reason"`
+	JobID   string `logevent:"jobId"`
+}
+
+// AssetGraphFetchFailure is logged when fetching the customers, subnets, or IP addresses of a
+// GraphQL query from storage fails.
+type AssetGraphFetchFailure struct {
+	Message string `logevent:"message,default=asset-graph-fetch-failure"`
+	Reason  string `logevent:"reason"`
+}
+
+// AssetExportFailure is logged when an export of the stored customers, subnets, IP addresses,
+// or prefix lists fails. An export that fails once streaming has started is cut short.
+type AssetExportFailure struct {
+	Message string `logevent:"message,default=asset-export-failure"`
+	Reason  string `logevent:"reason"`
+	Kind    string `logevent:"kind"`
+}
@@ -10,7 +10,52 @@ type InvalidSubnet struct {
 // DataSyncJobComplete is logged when an asynchronous job to synchronize the local
 // IPAM data cache completes.
 type DataSyncJobComplete struct {
-	Message string `logevent:"message,default=ipam-sync-complete"`
-	JobID   string `logevent:"jobid"`
+	Message     string `logevent:"message,default=ipam-sync-complete"`
+	JobID       string `logevent:"jobid"`
+	Reason      string `logevent:"reason"`
+	Quarantined int    `logevent:"quarantined"`
+}
+
+// SyncInProgress is logged when a sync is not started because another sync is still running.
+type SyncInProgress struct {
+	Message string `logevent:"message,default=sync-in-progress"`
+	JobID   string `logevent:"jobId"`
+}
+
+// QualityReportComplete is logged when the data quality report for a data sync has been stored.
+type QualityReportComplete struct {
+	Message  string `logevent:"message,default=quality-report-complete"`
+	JobID    string `logevent:"jobId"`
+	Findings int    `logevent:"findings"`
+}
+
+// InvalidCustomer is logged when a Customer is returned from Device42 which is invalid or incomplete
+type InvalidCustomer struct {
+	Message string `logevent:"message,default=invalid-customer"`
+	ID      string `logevent:"id"`
+	Reason  string `logevent:"reason"`
+}
+
+// InvalidIP is logged when a Device IP address is returned from Device42 which is invalid or incomplete
+type InvalidIP struct {
+	Message string `logevent:"message,default=invalid-ip"`
+	IP      string `logevent:"ip"`
+	Reason  string `logevent:"reason"`
+}
+
+// SourceConflict is logged when the IPAM data sources merged for a sync disagree about the
+// resource owner of the same network. The owner from the source with the highest precedence is kept.
+type SourceConflict struct {
+	Message string `logevent:"message,default=source-conflict"`
+	Network string `logevent:"network"`
+	Field   string `logevent:"field"`
+	Values  string `logevent:"values"`
+	Kept    string `logevent:"kept"`
+}
+
+// AssetGraphQueryRejected is logged when a GraphQL query is rejected before it is executed,
+// because it nests fields too deeply or would fetch too many records.
+type AssetGraphQueryRejected struct {
+	Message string `logevent:"message,default=asset-graph-query-rejected"`
 	Reason  string `logevent:"reason"`
 }
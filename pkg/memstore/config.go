@@ -0,0 +1,38 @@
+package memstore
+
+import (
+	"context"
+)
+
+// Config contains configuration settings for an in-memory Store
+type Config struct {
+	SnapshotPath string `description:"Path of a file to save the stored assets and their sync generation to after each sync, and to load them from at startup. Assets are only kept in memory if empty."`
+}
+
+// Name is used by the settings library to replace the default naming convention.
+func (*Config) Name() string {
+	return "Memory"
+}
+
+// NewComponent generates a new, unititialized Component
+func NewComponent() *Component {
+	return &Component{}
+}
+
+// Component satisfies the settings library Component API,
+// and may be used by the settings.NewComponent function.
+type Component struct{}
+
+// Settings generates a config with default values applied.
+func (*Component) Settings() *Config {
+	return &Config{}
+}
+
+// New constructs a Store from a config, loading the snapshot file if there is one.
+func (*Component) New(_ context.Context, conf *Config) (*Store, error) {
+	store := &Store{SnapshotPath: conf.SnapshotPath}
+	if err := store.Load(); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
@@ -0,0 +1,76 @@
+package memstore
+
+import (
+	"context"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+)
+
+// ExportAssets streams every stored record of a kind to the writer. The records are read from
+// the dataset stored when the export starts, so a sync stored during the export is not seen
+// by it. The sync generation of the dataset counts the syncs stored, and is carried over
+// restarts by the snapshot file when there is one.
+func (s *Store) ExportAssets(ctx context.Context, kind domain.AssetExportKind, writer domain.AssetExportWriter) error {
+	switch kind {
+	case domain.ExportCustomers, domain.ExportSubnets, domain.ExportIPs:
+	default:
+		return domain.UnknownExportKind{Kind: string(kind)}
+	}
+	data := s.dataset()
+	if err := writer.WriteSnapshot(domain.AssetSnapshot{Generation: data.generation, SyncedAt: data.syncedAt}); err != nil {
+		return err
+	}
+
+	switch kind {
+	case domain.ExportCustomers:
+		for _, entry := range data.customers {
+			contacts := make([]domain.Contact, 0, len(entry.customer.Contacts))
+			contacts = append(contacts, entry.customer.Contacts...)
+			if err := writer.WriteCustomer(domain.ExportedCustomer{
+				ID:                entry.id,
+				ResourceOwner:     entry.customer.ResourceOwner,
+				ResourceOwnerRule: entry.customer.ResourceOwnerRule,
+				BusinessUnit:      entry.customer.BusinessUnit,
+				Contacts:          contacts,
+			}); err != nil {
+				return err
+			}
+		}
+	case domain.ExportSubnets:
+		for _, entry := range data.subnetsByID {
+			subnet := domain.ExportedSubnet{
+				ID:       entry.id,
+				Network:  entry.network,
+				Location: entry.location,
+			}
+			if entry.customer != nil {
+				subnet.CustomerID = entry.customer.id
+				subnet.ResourceOwner = entry.customer.customer.ResourceOwner
+				subnet.BusinessUnit = entry.customer.customer.BusinessUnit
+			}
+			if err := writer.WriteSubnet(subnet); err != nil {
+				return err
+			}
+		}
+	case domain.ExportIPs:
+		for _, entry := range data.ips {
+			ip := domain.ExportedIP{
+				IP:       entry.ip,
+				SubnetID: entry.subnet.id,
+				Network:  entry.subnet.network,
+				Location: entry.subnet.location,
+			}
+			if entry.deviceID != nil {
+				ip.DeviceID = *entry.deviceID
+			}
+			if entry.subnet.customer != nil {
+				ip.ResourceOwner = entry.subnet.customer.customer.ResourceOwner
+				ip.BusinessUnit = entry.subnet.customer.customer.BusinessUnit
+			}
+			if err := writer.WriteIP(ip); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,275 @@
+package memstore
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+)
+
+// QueryCustomers fetches the customers selected by a query, with the same semantics as the
+// PostgreSQL domain.AssetGraphFetcher.
+func (s *Store) QueryCustomers(ctx context.Context, query domain.CustomerQuery) ([]domain.GraphCustomer, error) {
+	data := s.dataset()
+	ids := idSet(query.IDs)
+	customers := make([]domain.GraphCustomer, 0)
+	for _, entry := range data.customers {
+		if query.Limit > 0 && len(customers) == query.Limit {
+			break
+		}
+		if (ids != nil && !ids[entry.id]) ||
+			(query.ResourceOwner != "" && entry.customer.ResourceOwner != query.ResourceOwner) ||
+			(query.BusinessUnit != "" && entry.customer.BusinessUnit != query.BusinessUnit) ||
+			entry.id <= query.After {
+			continue
+		}
+		customers = append(customers, domain.GraphCustomer{
+			ID:                entry.id,
+			ResourceOwner:     entry.customer.ResourceOwner,
+			ResourceOwnerRule: entry.customer.ResourceOwnerRule,
+			BusinessUnit:      entry.customer.BusinessUnit,
+		})
+	}
+	return customers, nil
+}
+
+// QueryContacts fetches the contacts of several customers.
+func (s *Store) QueryContacts(ctx context.Context, customerIDs []int64) ([]domain.GraphContact, error) {
+	data := s.dataset()
+	ids := idSet(customerIDs)
+	contacts := make([]domain.GraphContact, 0)
+	for _, entry := range data.customers {
+		if !ids[entry.id] {
+			continue
+		}
+		for _, contact := range entry.customer.Contacts {
+			contacts = append(contacts, domain.GraphContact{CustomerID: entry.id, Contact: contact})
+		}
+	}
+	return contacts, nil
+}
+
+// QuerySubnets fetches the subnets selected by a query, with the same semantics as the
+// PostgreSQL domain.AssetGraphFetcher.
+func (s *Store) QuerySubnets(ctx context.Context, query domain.SubnetQuery) ([]domain.GraphSubnet, error) {
+	data := s.dataset()
+	var containing map[*subnetEntry]bool
+	if query.Containing != nil {
+		containing = make(map[*subnetEntry]bool)
+		for _, network := range query.Containing {
+			key, bits, index, err := data.parseNetwork(network)
+			if err != nil {
+				return nil, err
+			}
+			for _, subnet := range index.containing(key, bits) {
+				containing[subnet] = true
+			}
+		}
+	}
+	var within *prefix
+	if query.Within != "" {
+		key, bits, index, err := data.parseNetwork(query.Within)
+		if err != nil {
+			return nil, err
+		}
+		within = &prefix{key: key, bits: bits, index: index}
+	}
+
+	candidates := data.subnetsByID
+	customerIDs := idSet(query.CustomerIDs)
+	if customerIDs != nil {
+		candidates = nil
+		for _, customer := range data.customers {
+			if customerIDs[customer.id] {
+				candidates = append(candidates, customer.subnets...)
+			}
+		}
+		sort.Slice(candidates, func(i, j int) bool { return candidates[i].id < candidates[j].id })
+	}
+	ids := idSet(query.IDs)
+	limit := newLimiter(query.Limit, customerIDs != nil)
+	subnets := make([]domain.GraphSubnet, 0)
+	for _, entry := range candidates {
+		if limit.full() {
+			break
+		}
+		if (ids != nil && !ids[entry.id]) ||
+			(containing != nil && !containing[entry]) ||
+			(within != nil && !within.containsSubnet(data, entry)) ||
+			(query.Location != "" && entry.location != query.Location) ||
+			entry.id <= query.After {
+			continue
+		}
+		var customerID int64
+		if entry.customer != nil {
+			customerID = entry.customer.id
+		}
+		if !limit.take(customerID) {
+			continue
+		}
+		subnets = append(subnets, domain.GraphSubnet{
+			ID:         entry.id,
+			Network:    entry.network,
+			Location:   entry.location,
+			CustomerID: customerID,
+		})
+	}
+	return subnets, nil
+}
+
+// QueryIPs fetches the IP addresses selected by a query, with the same semantics as the
+// PostgreSQL domain.AssetGraphFetcher.
+func (s *Store) QueryIPs(ctx context.Context, query domain.IPQuery) ([]domain.GraphIP, error) {
+	data := s.dataset()
+	address := ""
+	if query.Address != "" {
+		ip := net.ParseIP(query.Address)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid IP address %q", query.Address)
+		}
+		address = ip.String()
+	}
+	var within *prefix
+	if query.Within != "" {
+		key, bits, index, err := data.parseNetwork(query.Within)
+		if err != nil {
+			return nil, err
+		}
+		within = &prefix{key: key, bits: bits, index: index}
+	}
+
+	candidates := data.ips
+	subnetIDs := idSet(query.SubnetIDs)
+	if subnetIDs != nil {
+		candidates = nil
+		for _, subnet := range data.subnetsByID {
+			if subnetIDs[subnet.id] {
+				candidates = append(candidates, subnet.assigned...)
+			}
+		}
+		sort.Slice(candidates, func(i, j int) bool { return candidates[i].id < candidates[j].id })
+	}
+	limit := newLimiter(query.Limit, subnetIDs != nil)
+	ips := make([]domain.GraphIP, 0)
+	for _, entry := range candidates {
+		if limit.full() {
+			break
+		}
+		if (address != "" && entry.ip != address) ||
+			(query.DeviceID > 0 && (entry.deviceID == nil || *entry.deviceID != query.DeviceID)) ||
+			(within != nil && !within.containsIP(data, entry)) ||
+			entry.id <= query.After {
+			continue
+		}
+		if !limit.take(entry.subnet.id) {
+			continue
+		}
+		ip := domain.GraphIP{ID: entry.id, IP: entry.ip, SubnetID: entry.subnet.id}
+		if entry.deviceID != nil {
+			ip.DeviceID = *entry.deviceID
+		}
+		ips = append(ips, ip)
+	}
+	return ips, nil
+}
+
+// parseNetwork parses a network, or an address as the network of that single address,
+// returning its key and the trie of its family. As with the PostgreSQL cidr type, a network
+// with bits set after the mask is rejected.
+func (d *dataset) parseNetwork(network string) ([]byte, int, *trie, error) {
+	v6 := strings.Contains(network, ":")
+	if !strings.Contains(network, "/") {
+		ip := net.ParseIP(network)
+		if ip == nil {
+			return nil, 0, nil, fmt.Errorf("invalid network %q", network)
+		}
+		key, index := d.index(ip, v6)
+		return key, index.bits, index, nil
+	}
+	ip, ipNet, err := net.ParseCIDR(network)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	if !ip.Equal(ipNet.IP) {
+		return nil, 0, nil, fmt.Errorf("network %s has bits set after the mask", network)
+	}
+	bits, _ := ipNet.Mask.Size()
+	key, index := d.index(ipNet.IP, v6)
+	return key, bits, index, nil
+}
+
+// prefix is a parsed network of a query.
+type prefix struct {
+	key   []byte
+	bits  int
+	index *trie
+}
+
+// contains reports whether a network of a family is inside the prefix.
+func (p *prefix) contains(key []byte, bits int, index *trie) bool {
+	return index == p.index && bits >= p.bits && commonPrefixLen(key, p.key, p.bits) == p.bits
+}
+
+func (p *prefix) containsSubnet(d *dataset, subnet *subnetEntry) bool {
+	key, index := d.index(subnet.key, len(subnet.key) == net.IPv6len)
+	return p.contains(key, subnet.bits, index)
+}
+
+func (p *prefix) containsIP(d *dataset, ip *ipEntry) bool {
+	key, index := d.index(net.ParseIP(ip.ip), strings.Contains(ip.ip, ":"))
+	return p.contains(key, index.bits, index)
+}
+
+// limiter limits the number of records of a query, either overall or for each parent.
+type limiter struct {
+	limit  int
+	counts map[int64]int
+	count  int
+}
+
+func newLimiter(limit int, perParent bool) *limiter {
+	l := &limiter{limit: limit}
+	if perParent {
+		l.counts = make(map[int64]int)
+	}
+	return l
+}
+
+// take reports whether another record of a parent fits within the limit, counting it if so.
+func (l *limiter) take(parent int64) bool {
+	if l.limit <= 0 {
+		return true
+	}
+	if l.counts != nil {
+		if l.counts[parent] >= l.limit {
+			return false
+		}
+		l.counts[parent]++
+		return true
+	}
+	if l.count >= l.limit {
+		return false
+	}
+	l.count++
+	return true
+}
+
+// full reports whether no more records fit within an overall limit.
+func (l *limiter) full() bool {
+	return l.counts == nil && l.limit > 0 && l.count >= l.limit
+}
+
+// idSet returns the set of the IDs, or nil when they are nil so that they select everything.
+func idSet(ids []int64) map[int64]bool {
+	if ids == nil {
+		return nil
+	}
+	set := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set
+}
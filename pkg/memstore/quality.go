@@ -0,0 +1,71 @@
+package memstore
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+)
+
+// QualityReportStore stores and fetches the data quality reports of the most recent IPAM data
+// syncs in memory. The reports are not saved to the snapshot file, so they are empty until the
+// first sync after startup.
+type QualityReportStore struct {
+	lock    sync.RWMutex
+	reports []domain.QualityReport
+}
+
+// StoreQualityReport adds the given report as the most recent one, keeping only the last
+// domain.RetainedQualityReports reports.
+func (s *QualityReportStore) StoreQualityReport(ctx context.Context, report domain.QualityReport) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.reports = append(s.reports, domain.QualityReport{
+		GeneratedAt: time.Now(),
+		JobID:       report.JobID,
+		Findings:    append([]domain.QualityFinding(nil), report.Findings...),
+		Quarantined: append([]domain.QuarantinedRecord(nil), report.Quarantined...),
+	})
+	if len(s.reports) > domain.RetainedQualityReports {
+		s.reports = append([]domain.QualityReport(nil), s.reports[len(s.reports)-domain.RetainedQualityReports:]...)
+	}
+	return nil
+}
+
+// FetchQualityReport fetches the most recent data quality report, including only the findings
+// of the given category unless it is empty. A zero GeneratedAt indicates that no report
+// has been stored yet.
+func (s *QualityReportStore) FetchQualityReport(ctx context.Context, category string) (domain.QualityReport, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	report := domain.QualityReport{Findings: make([]domain.QualityFinding, 0)}
+	if len(s.reports) == 0 {
+		return report, nil
+	}
+	latest := s.reports[len(s.reports)-1]
+	report.GeneratedAt = latest.GeneratedAt
+	report.JobID = latest.JobID
+	for _, finding := range latest.Findings {
+		if category == "" || finding.Category == category {
+			report.Findings = append(report.Findings, finding)
+		}
+	}
+	return report, nil
+}
+
+// FetchSyncJobReport fetches the data quality report of the sync job with the given ID,
+// including the records the sync quarantined.
+func (s *QualityReportStore) FetchSyncJobReport(ctx context.Context, jobID string) (domain.QualityReport, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	for i := len(s.reports) - 1; i >= 0; i-- {
+		if s.reports[i].JobID == jobID {
+			report := s.reports[i]
+			report.Findings = append(make([]domain.QualityFinding, 0), report.Findings...)
+			report.Quarantined = append(make([]domain.QuarantinedRecord, 0), report.Quarantined...)
+			return report, nil
+		}
+	}
+	return domain.QualityReport{}, domain.SyncJobNotFound{JobID: jobID}
+}
@@ -0,0 +1,42 @@
+package memstore
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQualityReportStoreSyncJobReport(t *testing.T) {
+	store := &QualityReportStore{}
+	quarantined := []domain.QuarantinedRecord{{RecordType: domain.QualityRecordSubnet, RecordID: "3", Reason: "invalid network"}}
+	require.NoError(t, store.StoreQualityReport(context.Background(), domain.QualityReport{JobID: "job-0", Quarantined: quarantined}))
+	for i := 1; i <= domain.RetainedQualityReports; i++ {
+		require.NoError(t, store.StoreQualityReport(context.Background(), domain.QualityReport{JobID: "job-" + strconv.Itoa(i)}))
+	}
+
+	_, err := store.FetchSyncJobReport(context.Background(), "job-0")
+	require.Equal(t, domain.SyncJobNotFound{JobID: "job-0"}, err)
+
+	report, err := store.FetchSyncJobReport(context.Background(), "job-1")
+	require.NoError(t, err)
+	require.Equal(t, "job-1", report.JobID)
+	require.Empty(t, report.Quarantined)
+
+	latest, err := store.FetchQualityReport(context.Background(), "")
+	require.NoError(t, err)
+	require.Equal(t, "job-"+strconv.Itoa(domain.RetainedQualityReports), latest.JobID)
+}
+
+func TestQualityReportStoreQuarantined(t *testing.T) {
+	store := &QualityReportStore{}
+	quarantined := []domain.QuarantinedRecord{{RecordType: domain.QualityRecordSubnet, RecordID: "3", Reason: "invalid network"}}
+	require.NoError(t, store.StoreQualityReport(context.Background(), domain.QualityReport{JobID: "job-1", Quarantined: quarantined}))
+
+	report, err := store.FetchSyncJobReport(context.Background(), "job-1")
+	require.NoError(t, err)
+	require.Equal(t, quarantined, report.Quarantined)
+	require.False(t, report.GeneratedAt.IsZero())
+}
@@ -0,0 +1,459 @@
+// Package memstore stores physical assets in memory, for deployments that do not need a
+// PostgreSQL database. Subnets are indexed by a radix trie per address family, so that the
+// subnets containing an IP address are found with a single walk of the trie.
+package memstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+)
+
+// ErrNoSubnet is the inner error of a domain.AssetNotFound when no subnet contains an IP address.
+var ErrNoSubnet = errors.New("no subnet contains the IP address")
+
+// Names of the PhysicalAsset fields that may be inherited from an ancestor subnet.
+const (
+	inheritedResourceOwner = "resourceOwner"
+	inheritedBusinessUnit  = "businessUnit"
+	inheritedCustomerID    = "customerID"
+	inheritedContacts      = "contacts"
+)
+
+// Store stores physical assets in memory, and fetches them with the same semantics as the
+// PostgreSQL implementations of domain.PhysicalAssetStorer and domain.Fetcher. When
+// SnapshotPath is set, every stored dataset is also written to that file, along with its sync
+// generation and the time it was stored, so that it can be loaded again when the Store is
+// created. LockSync ensures that a single sync stores physical assets at a time.
+type Store struct {
+	InheritOwnership bool
+	SnapshotPath     string
+
+	lock sync.RWMutex
+	data *dataset
+	// storeLock serializes the datasets being stored, so that each one is numbered and written
+	// to the snapshot in turn, without holding the lock that lookups wait on.
+	storeLock sync.Mutex
+
+	syncLock sync.Mutex
+	syncing  bool
+}
+
+type customerEntry struct {
+	id       int64
+	customer domain.Customer
+	// subnets holds the subnets of the customer, in the order of their IDs.
+	subnets []*subnetEntry
+}
+
+type subnetEntry struct {
+	id       int64
+	key      net.IP // the network address, in the form returned by net.ParseCIDR
+	network  string
+	bits     int
+	location string
+	customer *customerEntry
+	// ips holds the IP addresses assigned to the subnet, keyed by their text form.
+	ips map[string][]*ipEntry
+	// assigned holds the IP addresses assigned to the subnet, in the order they were stored.
+	assigned []*ipEntry
+}
+
+type ipEntry struct {
+	// id is the position of the IP address in the stored data, counting from 1, as the
+	// PostgreSQL storer assigns it.
+	id       int64
+	ip       string
+	subnet   *subnetEntry
+	deviceID *int64
+}
+
+// dataset is a stored set of physical assets. It is never changed once built, so that it can be
+// read without holding the lock of the Store.
+type dataset struct {
+	// generation counts the datasets stored, including those stored before the snapshot was
+	// loaded, and syncedAt is the time the dataset was stored.
+	generation int64
+	syncedAt   time.Time
+
+	subnets []*subnetEntry
+	ips     []*ipEntry
+	// customers and subnetsByID hold the customers and subnets in the order of their IDs.
+	customers   []*customerEntry
+	subnetsByID []*subnetEntry
+	v4          *trie
+	v6          *trie
+}
+
+// snapshot is the content of the snapshot file: a stored dataset along with its sync
+// generation and the time it was stored. Snapshots written before the generation was
+// recorded have neither, and are loaded as generation 0 stored at the time of the file.
+type snapshot struct {
+	domain.IPAMData
+	Generation int64     `json:"generation"`
+	SyncedAt   time.Time `json:"syncedAt"`
+}
+
+// Load replaces the stored physical assets with the contents of the snapshot file, if it exists.
+func (s *Store) Load() error {
+	if s.SnapshotPath == "" {
+		return nil
+	}
+	info, err := os.Stat(s.SnapshotPath)
+	switch {
+	case os.IsNotExist(err):
+		return nil
+	case err != nil:
+		return err
+	}
+	content, err := ioutil.ReadFile(s.SnapshotPath)
+	if err != nil {
+		return err
+	}
+	var stored snapshot
+	if err := json.Unmarshal(content, &stored); err != nil {
+		return fmt.Errorf("snapshot %s: %v", s.SnapshotPath, err)
+	}
+	data, err := newDataset(stored.IPAMData)
+	if err != nil {
+		return fmt.Errorf("snapshot %s: %v", s.SnapshotPath, err)
+	}
+	data.generation = stored.Generation
+	data.syncedAt = stored.SyncedAt
+	if data.syncedAt.IsZero() {
+		// the snapshot was written when its dataset was stored
+		data.syncedAt = info.ModTime()
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.data = data
+	return nil
+}
+
+// StorePhysicalAssets replaces the stored physical assets. As with the PostgreSQL storer, data
+// that repeats an ID or refers to a missing customer or subnet is rejected with an error, and
+// the previously stored data is kept.
+func (s *Store) StorePhysicalAssets(ctx context.Context, ipamData domain.IPAMData) error {
+	data, err := newDataset(ipamData)
+	if err != nil {
+		return err
+	}
+	s.storeLock.Lock()
+	defer s.storeLock.Unlock()
+	data.generation = s.dataset().generation + 1
+	data.syncedAt = time.Now()
+	if s.SnapshotPath != "" {
+		stored := snapshot{IPAMData: ipamData, Generation: data.generation, SyncedAt: data.syncedAt}
+		if err := writeSnapshot(s.SnapshotPath, stored); err != nil {
+			return err
+		}
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.data = data
+	return nil
+}
+
+// writeSnapshot writes the snapshot to a temporary file that replaces the snapshot file once
+// complete, so that a failed write never leaves a partial snapshot behind.
+func writeSnapshot(path string, stored snapshot) error {
+	content, err := json.Marshal(stored)
+	if err != nil {
+		return err
+	}
+	file, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(content); err != nil {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+		return err
+	}
+	if err := file.Close(); err != nil {
+		_ = os.Remove(file.Name())
+		return err
+	}
+	if err := os.Rename(file.Name(), path); err != nil {
+		_ = os.Remove(file.Name())
+		return err
+	}
+	return nil
+}
+
+func (s *Store) dataset() *dataset {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	if s.data == nil {
+		return emptyDataset()
+	}
+	return s.data
+}
+
+// FetchPhysicalAsset fetches a physical asset by IP address. Of the subnets that contain the
+// address, those with a device at the address are preferred, then the most specific. Subnets
+// that tie are decided by the order in which they were stored.
+func (s *Store) FetchPhysicalAsset(ctx context.Context, ipAddress string) (domain.PhysicalAsset, error) {
+	ip := net.ParseIP(ipAddress)
+	if ip == nil {
+		return domain.PhysicalAsset{}, fmt.Errorf("invalid IP address %q", ipAddress)
+	}
+	data := s.dataset()
+	key, index := data.index(ip, strings.Contains(ipAddress, ":"))
+
+	var matched *subnetEntry
+	var device *ipEntry
+	for _, subnet := range index.containing(key, index.bits) {
+		var candidate *ipEntry
+		for _, entry := range subnet.ips[ip.String()] {
+			if entry.deviceID != nil {
+				candidate = entry
+				break
+			}
+		}
+		if matched == nil || preferred(subnet, candidate, matched, device) {
+			matched, device = subnet, candidate
+		}
+	}
+	if matched == nil {
+		return domain.PhysicalAsset{}, domain.AssetNotFound{Inner: ErrNoSubnet, IP: ipAddress}
+	}
+
+	asset := domain.PhysicalAsset{
+		IP:       ipAddress,
+		Network:  matched.network,
+		Location: matched.location,
+		SubnetID: matched.id,
+	}
+	if device != nil {
+		asset.IP = device.ip
+		asset.DeviceID = *device.deviceID
+	}
+
+	customer := matched.customer
+	if customer == nil && s.InheritOwnership {
+		if ancestor := data.ancestor(matched); ancestor != nil {
+			customer = ancestor.customer
+			asset.InheritedFromSubnetID = ancestor.id
+			asset.InheritedFields = []string{
+				inheritedResourceOwner, inheritedBusinessUnit, inheritedCustomerID, inheritedContacts}
+		}
+	}
+	if customer != nil {
+		asset.CustomerID = customer.id
+		asset.ResourceOwner = customer.customer.ResourceOwner
+		asset.ResourceOwnerRule = customer.customer.ResourceOwnerRule
+		asset.BusinessUnit = customer.customer.BusinessUnit
+		asset.Contacts = append(make([]domain.Contact, 0, len(customer.customer.Contacts)), customer.customer.Contacts...)
+	}
+	return asset, nil
+}
+
+// preferred reports whether a subnet, with its device at the address if any, is a better match
+// than the current one, following the ORDER BY of the PostgreSQL fetcher.
+func preferred(subnet *subnetEntry, device *ipEntry, matched *subnetEntry, matchedDevice *ipEntry) bool {
+	if (device != nil) != (matchedDevice != nil) {
+		return device != nil
+	}
+	return subnet.bits > matched.bits
+}
+
+// ancestor returns the most specific subnet that strictly contains a subnet and has a
+// customer, or nil if there is none.
+func (d *dataset) ancestor(subnet *subnetEntry) *subnetEntry {
+	key, index := d.index(subnet.key, len(subnet.key) == net.IPv6len)
+
+	var ancestor *subnetEntry
+	for _, candidate := range index.containing(key, subnet.bits-1) {
+		if candidate.customer != nil && (ancestor == nil || candidate.bits > ancestor.bits) {
+			ancestor = candidate
+		}
+	}
+	return ancestor
+}
+
+// FetchSubnets fetches a single page of subnets, in the order they were stored.
+func (s *Store) FetchSubnets(ctx context.Context, limit, offset int) ([]domain.AssetSubnet, error) {
+	if limit < 0 || offset < 0 {
+		return nil, fmt.Errorf("invalid page limit %d or offset %d", limit, offset)
+	}
+	data := s.dataset()
+	subnets := make([]domain.AssetSubnet, 0, limit)
+	for _, subnet := range page(len(data.subnets), limit, offset) {
+		entry := data.subnets[subnet]
+		assetSubnet := domain.AssetSubnet{
+			Network:  entry.network,
+			Location: entry.location,
+		}
+		if entry.customer != nil {
+			assetSubnet.ResourceOwner = entry.customer.customer.ResourceOwner
+			assetSubnet.BusinessUnit = entry.customer.customer.BusinessUnit
+		}
+		subnets = append(subnets, assetSubnet)
+	}
+	return subnets, nil
+}
+
+// FetchIPs fetches a single page of IP addresses, in the order they were stored.
+func (s *Store) FetchIPs(ctx context.Context, limit, offset int) ([]domain.AssetIP, error) {
+	if limit < 0 || offset < 0 {
+		return nil, fmt.Errorf("invalid page limit %d or offset %d", limit, offset)
+	}
+	data := s.dataset()
+	ips := make([]domain.AssetIP, 0, limit)
+	for _, ip := range page(len(data.ips), limit, offset) {
+		entry := data.ips[ip]
+		assetIP := domain.AssetIP{
+			IP:       entry.ip,
+			Network:  entry.subnet.network,
+			Location: entry.subnet.location,
+		}
+		if entry.subnet.customer != nil {
+			assetIP.ResourceOwner = entry.subnet.customer.customer.ResourceOwner
+			assetIP.BusinessUnit = entry.subnet.customer.customer.BusinessUnit
+		}
+		ips = append(ips, assetIP)
+	}
+	return ips, nil
+}
+
+// page returns the indexes of a page of a list of the given length.
+func page(length int, limit int, offset int) []int {
+	indexes := make([]int, 0, limit)
+	for i := offset; i < length && i < offset+limit; i++ {
+		indexes = append(indexes, i)
+	}
+	return indexes
+}
+
+func emptyDataset() *dataset {
+	return &dataset{v4: newTrie(8 * net.IPv4len), v6: newTrie(8 * net.IPv6len)}
+}
+
+// index returns the key of an address and the trie of its family. As in PostgreSQL, an
+// IPv4-mapped IPv6 address belongs to the IPv6 family.
+func (d *dataset) index(ip net.IP, v6 bool) ([]byte, *trie) {
+	if v6 {
+		return ip.To16(), d.v6
+	}
+	return ip.To4(), d.v4
+}
+
+// newDataset builds a dataset, checking the same constraints as the PostgreSQL schema: IDs
+// are unique integers, networks have no bits set after the mask, and subnets and IPs refer
+// to customers and subnets that exist. A subnet customer ID of "" or "0" means no customer,
+// and an empty device ID means no device.
+func newDataset(ipamData domain.IPAMData) (*dataset, error) {
+	data := emptyDataset()
+
+	customers := make(map[int64]*customerEntry, len(ipamData.Customers))
+	for _, customer := range ipamData.Customers {
+		id, err := parseID(customer.ID)
+		if err != nil {
+			return nil, fmt.Errorf("customer %q: %v", customer.ID, err)
+		}
+		if _, ok := customers[id]; ok {
+			return nil, fmt.Errorf("customer %q: duplicate ID", customer.ID)
+		}
+		customers[id] = &customerEntry{id: id, customer: customer}
+		data.customers = append(data.customers, customers[id])
+	}
+	sort.Slice(data.customers, func(i, j int) bool { return data.customers[i].id < data.customers[j].id })
+
+	subnets := make(map[int64]*subnetEntry, len(ipamData.Subnets))
+	for _, subnet := range ipamData.Subnets {
+		id, err := parseID(subnet.ID)
+		if err != nil {
+			return nil, fmt.Errorf("subnet %q: %v", subnet.ID, err)
+		}
+		if _, ok := subnets[id]; ok {
+			return nil, fmt.Errorf("subnet %q: duplicate ID", subnet.ID)
+		}
+		cidr := fmt.Sprintf("%s/%d", subnet.Network, subnet.MaskBits)
+		ip, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("subnet %q: %v", subnet.ID, err)
+		}
+		if !ip.Equal(network.IP) {
+			return nil, fmt.Errorf("subnet %q: network %s has bits set after the mask", subnet.ID, cidr)
+		}
+		entry := &subnetEntry{
+			id:       id,
+			key:      network.IP,
+			network:  network.String(),
+			bits:     subnet.MaskBits,
+			location: subnet.Location,
+			ips:      make(map[string][]*ipEntry),
+		}
+		if subnet.CustomerID != "" && subnet.CustomerID != "0" {
+			customerID, err := parseID(subnet.CustomerID)
+			if err != nil {
+				return nil, fmt.Errorf("subnet %q: customer %q: %v", subnet.ID, subnet.CustomerID, err)
+			}
+			if entry.customer = customers[customerID]; entry.customer == nil {
+				return nil, fmt.Errorf("subnet %q: customer %q does not exist", subnet.ID, subnet.CustomerID)
+			}
+		}
+		subnets[id] = entry
+		data.subnets = append(data.subnets, entry)
+		data.subnetsByID = append(data.subnetsByID, entry)
+		key, index := data.index(entry.key, len(entry.key) == net.IPv6len)
+		index.insert(key, entry.bits, entry)
+	}
+
+	for _, device := range ipamData.Devices {
+		ip := net.ParseIP(device.IP)
+		if ip == nil {
+			return nil, fmt.Errorf("ip %q: invalid IP address", device.IP)
+		}
+		subnetID, err := parseID(device.SubnetID)
+		if err != nil {
+			return nil, fmt.Errorf("ip %q: subnet %q: %v", device.IP, device.SubnetID, err)
+		}
+		subnet := subnets[subnetID]
+		if subnet == nil {
+			return nil, fmt.Errorf("ip %q: subnet %q does not exist", device.IP, device.SubnetID)
+		}
+		entry := &ipEntry{id: int64(len(data.ips) + 1), ip: ip.String(), subnet: subnet}
+		if device.ID != "" {
+			deviceID, err := parseID(device.ID)
+			if err != nil {
+				return nil, fmt.Errorf("ip %q: device %q: %v", device.IP, device.ID, err)
+			}
+			entry.deviceID = &deviceID
+		}
+		subnet.ips[entry.ip] = append(subnet.ips[entry.ip], entry)
+		subnet.assigned = append(subnet.assigned, entry)
+		data.ips = append(data.ips, entry)
+	}
+
+	sort.Slice(data.subnetsByID, func(i, j int) bool { return data.subnetsByID[i].id < data.subnetsByID[j].id })
+	for _, subnet := range data.subnetsByID {
+		if subnet.customer != nil {
+			subnet.customer.subnets = append(subnet.customer.subnets, subnet)
+		}
+	}
+
+	return data, nil
+}
+
+// parseID parses an ID into the range of a PostgreSQL INTEGER.
+func parseID(id string) (int64, error) {
+	parsed, err := strconv.ParseInt(id, 10, 32)
+	if err != nil {
+		return 0, errors.New("ID is not a 32 bit integer")
+	}
+	return parsed, nil
+}
@@ -0,0 +1,219 @@
+package memstore
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/asecurityteam/ipam-facade/pkg/assettest"
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreContract(t *testing.T) {
+	assettest.Run(t, func(t *testing.T, inheritOwnership bool) assettest.Backend {
+		store := &Store{InheritOwnership: inheritOwnership}
+		return assettest.Backend{Storer: store, Fetcher: store, Graph: store, Exporter: store}
+	})
+}
+
+func TestStoreSnapshot(t *testing.T) {
+	dir, err := ioutil.TempDir("", "memstore")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "snapshot.json")
+
+	component := NewComponent()
+	config := component.Settings()
+	config.SnapshotPath = path
+	store, err := component.New(context.Background(), config)
+	require.NoError(t, err)
+	_, err = store.FetchPhysicalAsset(context.Background(), "10.0.0.1")
+	require.IsType(t, domain.AssetNotFound{}, err)
+
+	require.NoError(t, store.StorePhysicalAssets(context.Background(), domain.IPAMData{
+		Subnets: []domain.Subnet{{ID: "1", Network: "10.0.0.0", MaskBits: 8, Location: "DC1"}},
+		Devices: []domain.Device{{ID: "5", IP: "10.0.0.1", SubnetID: "1"}},
+	}))
+	// rejected data does not replace the snapshot
+	require.Error(t, store.StorePhysicalAssets(context.Background(), domain.IPAMData{
+		Devices: []domain.Device{{ID: "5", IP: "10.0.0.1", SubnetID: "1"}},
+	}))
+
+	stored := &snapshotRecorder{}
+	require.NoError(t, store.ExportAssets(context.Background(), domain.ExportSubnets, stored))
+
+	restored, err := component.New(context.Background(), config)
+	require.NoError(t, err)
+	asset, err := restored.FetchPhysicalAsset(context.Background(), "10.0.0.1")
+	require.NoError(t, err)
+	assert.Equal(t, domain.PhysicalAsset{IP: "10.0.0.1", Network: "10.0.0.0/8", Location: "DC1", SubnetID: 1, DeviceID: 5}, asset)
+
+	// the sync generation and the time it was stored are restored with the dataset
+	recorder := &snapshotRecorder{}
+	require.NoError(t, restored.ExportAssets(context.Background(), domain.ExportSubnets, recorder))
+	assert.Equal(t, int64(1), recorder.snapshot.Generation)
+	assert.True(t, stored.snapshot.SyncedAt.Equal(recorder.snapshot.SyncedAt))
+	require.NoError(t, restored.StorePhysicalAssets(context.Background(), domain.IPAMData{}))
+	require.NoError(t, restored.ExportAssets(context.Background(), domain.ExportSubnets, recorder))
+	assert.Equal(t, int64(2), recorder.snapshot.Generation)
+
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+}
+
+func TestStoreSnapshotWithoutGeneration(t *testing.T) {
+	file, err := ioutil.TempFile("", "memstore")
+	require.NoError(t, err)
+	defer os.Remove(file.Name())
+	_, err = file.WriteString(`{"Subnets": [{"ID": "1", "Network": "10.0.0.0", "MaskBits": 8}]}`)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+	info, err := os.Stat(file.Name())
+	require.NoError(t, err)
+
+	component := NewComponent()
+	config := component.Settings()
+	config.SnapshotPath = file.Name()
+	store, err := component.New(context.Background(), config)
+	require.NoError(t, err)
+	recorder := &snapshotRecorder{}
+	require.NoError(t, store.ExportAssets(context.Background(), domain.ExportSubnets, recorder))
+	assert.Equal(t, domain.AssetSnapshot{Generation: 0, SyncedAt: info.ModTime()}, recorder.snapshot)
+}
+
+func TestStoreBadSnapshot(t *testing.T) {
+	file, err := ioutil.TempFile("", "memstore")
+	require.NoError(t, err)
+	defer os.Remove(file.Name())
+	_, err = file.WriteString(`{"Subnets": [{"ID": "1", "Network": "10.0.0.1", "MaskBits": 8}]}`)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	component := NewComponent()
+	config := component.Settings()
+	config.SnapshotPath = file.Name()
+	_, err = component.New(context.Background(), config)
+	assert.Error(t, err)
+}
+
+func TestStoreInvalidData(t *testing.T) {
+	tc := []struct {
+		name     string
+		ipamData domain.IPAMData
+	}{
+		{
+			name:     "customer ID",
+			ipamData: domain.IPAMData{Customers: []domain.Customer{{ID: "abc"}}},
+		},
+		{
+			name:     "customer ID out of range",
+			ipamData: domain.IPAMData{Customers: []domain.Customer{{ID: "4294967296"}}},
+		},
+		{
+			name:     "duplicate customer",
+			ipamData: domain.IPAMData{Customers: []domain.Customer{{ID: "1"}, {ID: "1"}}},
+		},
+		{
+			name:     "network",
+			ipamData: domain.IPAMData{Subnets: []domain.Subnet{{ID: "1", Network: "10.0.0", MaskBits: 8}}},
+		},
+		{
+			name:     "mask bits",
+			ipamData: domain.IPAMData{Subnets: []domain.Subnet{{ID: "1", Network: "10.0.0.0", MaskBits: 33}}},
+		},
+		{
+			name:     "host bits",
+			ipamData: domain.IPAMData{Subnets: []domain.Subnet{{ID: "1", Network: "10.0.0.1", MaskBits: 8}}},
+		},
+		{
+			name: "IP",
+			ipamData: domain.IPAMData{
+				Subnets: []domain.Subnet{{ID: "1", Network: "10.0.0.0", MaskBits: 8}},
+				Devices: []domain.Device{{IP: "10.0.0", SubnetID: "1"}},
+			},
+		},
+		{
+			name: "device ID",
+			ipamData: domain.IPAMData{
+				Subnets: []domain.Subnet{{ID: "1", Network: "10.0.0.0", MaskBits: 8}},
+				Devices: []domain.Device{{ID: "abc", IP: "10.0.0.1", SubnetID: "1"}},
+			},
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &Store{}
+			assert.Error(t, store.StorePhysicalAssets(context.Background(), tt.ipamData))
+		})
+	}
+}
+
+func TestStoreIPv4MappedAddress(t *testing.T) {
+	store := &Store{}
+	require.NoError(t, store.StorePhysicalAssets(context.Background(), domain.IPAMData{
+		Subnets: []domain.Subnet{{ID: "1", Network: "10.0.0.0", MaskBits: 8}},
+	}))
+
+	// as in PostgreSQL, an IPv4-mapped IPv6 address is not in an IPv4 subnet
+	_, err := store.FetchPhysicalAsset(context.Background(), "::ffff:10.0.0.1")
+	require.IsType(t, domain.AssetNotFound{}, err)
+	_, err = store.FetchPhysicalAsset(context.Background(), "10.0.0.1")
+	require.NoError(t, err)
+}
+
+func TestStoreLockSync(t *testing.T) {
+	store := &Store{}
+	unlock, err := store.LockSync(context.Background())
+	require.NoError(t, err)
+
+	_, err = store.LockSync(context.Background())
+	require.Equal(t, domain.SyncInProgress{}, err)
+
+	unlock()
+	unlock, err = store.LockSync(context.Background())
+	require.NoError(t, err)
+	unlock()
+}
+
+// snapshotRecorder records the snapshot of an export, discarding its records.
+type snapshotRecorder struct {
+	snapshot domain.AssetSnapshot
+}
+
+func (r *snapshotRecorder) WriteSnapshot(snapshot domain.AssetSnapshot) error {
+	r.snapshot = snapshot
+	return nil
+}
+
+func (*snapshotRecorder) WriteCustomer(domain.ExportedCustomer) error { return nil }
+
+func (*snapshotRecorder) WriteSubnet(domain.ExportedSubnet) error { return nil }
+
+func (*snapshotRecorder) WriteIP(domain.ExportedIP) error { return nil }
+
+func TestStoreExportSnapshot(t *testing.T) {
+	ctx := context.Background()
+	store := &Store{}
+	recorder := &snapshotRecorder{}
+	require.NoError(t, store.ExportAssets(ctx, domain.ExportSubnets, recorder))
+	assert.Equal(t, domain.AssetSnapshot{}, recorder.snapshot)
+
+	ipamData := domain.IPAMData{Subnets: []domain.Subnet{{ID: "1", Network: "10.0.0.0", MaskBits: 8, Location: "DC1"}}}
+	require.NoError(t, store.StorePhysicalAssets(ctx, ipamData))
+	require.NoError(t, store.ExportAssets(ctx, domain.ExportSubnets, recorder))
+	assert.Equal(t, int64(1), recorder.snapshot.Generation)
+	assert.WithinDuration(t, time.Now(), recorder.snapshot.SyncedAt, time.Minute)
+
+	// rejected data does not start a new generation
+	require.Error(t, store.StorePhysicalAssets(ctx, domain.IPAMData{Devices: []domain.Device{{IP: "10.0.0.1", SubnetID: "2"}}}))
+	require.NoError(t, store.StorePhysicalAssets(ctx, ipamData))
+	require.NoError(t, store.ExportAssets(ctx, domain.ExportSubnets, recorder))
+	assert.Equal(t, int64(2), recorder.snapshot.Generation)
+}
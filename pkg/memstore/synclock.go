@@ -0,0 +1,24 @@
+package memstore
+
+import (
+	"context"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+)
+
+// LockSync takes the sync lock without waiting for it, returning domain.SyncInProgress when
+// another sync holds it. The in-memory store is not shared, so the lock only excludes the
+// syncs of this instance.
+func (s *Store) LockSync(ctx context.Context) (func(), error) {
+	s.syncLock.Lock()
+	defer s.syncLock.Unlock()
+	if s.syncing {
+		return nil, domain.SyncInProgress{}
+	}
+	s.syncing = true
+	return func() {
+		s.syncLock.Lock()
+		s.syncing = false
+		s.syncLock.Unlock()
+	}, nil
+}
@@ -0,0 +1,94 @@
+package memstore
+
+// trie is a path-compressed binary radix (Patricia) trie of network prefixes. Each node
+// holds the subnets whose network is exactly the node's prefix, so that every subnet
+// containing an address is found by walking a single path from the root.
+type trie struct {
+	root *trieNode
+	bits int // the number of bits in an address of the trie's family
+}
+
+type trieNode struct {
+	key     []byte
+	bits    int
+	subnets []*subnetEntry
+	child   [2]*trieNode
+}
+
+func newTrie(bits int) *trie {
+	return &trie{bits: bits}
+}
+
+// insert adds a subnet under its network prefix.
+func (t *trie) insert(key []byte, bits int, subnet *subnetEntry) {
+	link := &t.root
+	for {
+		n := *link
+		if n == nil {
+			*link = &trieNode{key: key, bits: bits, subnets: []*subnetEntry{subnet}}
+			return
+		}
+		common := commonPrefixLen(n.key, key, minInt(n.bits, bits))
+		switch {
+		case common == n.bits && common == bits:
+			n.subnets = append(n.subnets, subnet)
+			return
+		case common == n.bits:
+			// the node's prefix contains the new one, so it belongs below the node
+			link = &n.child[bitAt(key, n.bits)]
+		case common == bits:
+			// the new prefix contains the node's, so the node moves below it
+			inserted := &trieNode{key: key, bits: bits, subnets: []*subnetEntry{subnet}}
+			inserted.child[bitAt(n.key, bits)] = n
+			*link = inserted
+			return
+		default:
+			// the prefixes diverge, so they become the children of a new branch node
+			branch := &trieNode{key: key, bits: common}
+			branch.child[bitAt(n.key, common)] = n
+			branch.child[bitAt(key, common)] = &trieNode{key: key, bits: bits, subnets: []*subnetEntry{subnet}}
+			*link = branch
+			return
+		}
+	}
+}
+
+// containing returns every subnet whose network contains the first maxBits bits of the
+// address, from the least to the most specific. Subnets with the same network are returned
+// in the order they were inserted.
+func (t *trie) containing(address []byte, maxBits int) []*subnetEntry {
+	var subnets []*subnetEntry
+	for n := t.root; n != nil && n.bits <= maxBits; {
+		if commonPrefixLen(n.key, address, n.bits) < n.bits {
+			break
+		}
+		subnets = append(subnets, n.subnets...)
+		if n.bits == maxBits {
+			break
+		}
+		n = n.child[bitAt(address, n.bits)]
+	}
+	return subnets
+}
+
+// commonPrefixLen returns the number of leading bits, up to max, that a and b have in common.
+func commonPrefixLen(a []byte, b []byte, max int) int {
+	for i := 0; i < max; i++ {
+		if bitAt(a, i) != bitAt(b, i) {
+			return i
+		}
+	}
+	return max
+}
+
+// bitAt returns the bit of the key at the given position, counted from the most significant.
+func bitAt(key []byte, position int) int {
+	return int(key[position/8]>>(7-uint(position%8))) & 1
+}
+
+func minInt(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,41 @@
+package memstore
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrieContaining(t *testing.T) {
+	networks := []string{"10.0.0.0/8", "10.1.0.0/16", "10.1.1.0/24", "10.2.0.0/16", "0.0.0.0/0", "10.1.0.0/16", "10.1.1.128/25"}
+	index := newTrie(32)
+	for i, network := range networks {
+		_, ipNet, _ := net.ParseCIDR(network)
+		bits, _ := ipNet.Mask.Size()
+		index.insert(ipNet.IP, bits, &subnetEntry{id: int64(i), network: network})
+	}
+
+	tc := []struct {
+		address  string
+		bits     int
+		expected []int64
+	}{
+		{address: "10.1.1.200", bits: 32, expected: []int64{4, 0, 1, 5, 2, 6}},
+		{address: "10.1.1.5", bits: 32, expected: []int64{4, 0, 1, 5, 2}},
+		{address: "10.2.3.4", bits: 32, expected: []int64{4, 0, 3}},
+		{address: "192.168.0.1", bits: 32, expected: []int64{4}},
+		{address: "10.1.1.0", bits: 23, expected: []int64{4, 0, 1, 5}},
+		{address: "10.1.1.0", bits: -1, expected: nil},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.address, func(t *testing.T) {
+			var ids []int64
+			for _, subnet := range index.containing(net.ParseIP(tt.address).To4(), tt.bits) {
+				ids = append(ids, subnet.id)
+			}
+			assert.Equal(t, tt.expected, ids)
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package netbox
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+
+	httpclient "github.com/asecurityteam/component-httpclient"
+)
+
+// Config contains configuration settings for a NetBox Client
+type Config struct {
+	Endpoint string `description:"Base URL of the NetBox instance, without the /api path."`
+	Token    string `description:"NetBox API token sent in the Authorization header."`
+	Limit    int    `description:"Page size to request from the NetBox API. The NetBox default is used if zero."`
+	HTTP     *httpclient.Config
+}
+
+// Name is used by the settings library to replace the default naming convention.
+func (*Config) Name() string {
+	return "NetBox"
+}
+
+// NewComponent generates a new, unititialized Component
+func NewComponent() *Component {
+	return &Component{
+		HTTP: httpclient.NewComponent(),
+	}
+}
+
+// Component satisfies the settings library Component API,
+// and may be used by the settings.NewComponent function.
+type Component struct {
+	HTTP *httpclient.Component
+}
+
+// Settings generates a config with default values applied.
+func (c *Component) Settings() *Config {
+	return &Config{
+		HTTP: c.HTTP.Settings(),
+	}
+}
+
+// New constructs a Client from a config.
+func (c *Component) New(ctx context.Context, conf *Config) (*Client, error) {
+	rt, e := c.HTTP.New(ctx, conf.HTTP)
+	if e != nil {
+		return nil, e
+	}
+	u, e := url.Parse(conf.Endpoint)
+	if e != nil {
+		return nil, e
+	}
+	return &Client{
+		Endpoint: u,
+		Limit:    conf.Limit,
+		Client: &http.Client{
+			Transport: &TokenTransport{Token: conf.Token, Wrapped: rt},
+		},
+	}, nil
+}
+
+// TokenTransport authenticates every request to the NetBox API with an API token.
+type TokenTransport struct {
+	Token   string
+	Wrapped http.RoundTripper
+}
+
+// RoundTrip adds the token Authorization header to a copy of the request.
+func (t *TokenTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	header := make(http.Header, len(r.Header)+2)
+	for key, values := range r.Header {
+		header[key] = values
+	}
+	r = r.WithContext(r.Context())
+	r.Header = header
+	r.Header.Set("Authorization", "Token "+t.Token)
+	r.Header.Set("Accept", "application/json")
+	return t.Wrapped.RoundTrip(r)
+}
+
+// Client contains values to configure a NetBox client
+type Client struct {
+	Client   *http.Client
+	Endpoint *url.URL
+	Limit    int
+}
+
+// CheckDependencies makes a call to the NetBox status endpoint. Client is the only dependency
+// shared amongst the NetBox fetchers, so those do not need to be checked individually.
+func (c *Client) CheckDependencies(ctx context.Context) error {
+	u := c.resourceURL("status")
+	req, _ := http.NewRequest(http.MethodGet, u.String(), http.NoBody)
+	res, err := c.Client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("NetBox Client unexpectedly returned non-200 response code: %d attempting to GET: %s", res.StatusCode, u.String())
+	}
+	return nil
+}
+
+// resourceURL returns the URL of a NetBox API resource.
+func (c *Client) resourceURL(elem ...string) *url.URL {
+	u, _ := url.Parse(c.Endpoint.String())
+	u.Path = path.Join(append([]string{u.Path, "api"}, elem...)...) + "/"
+	return u
+}
@@ -0,0 +1,65 @@
+package netbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+)
+
+const testToken = "0123456789abcdef"
+
+// fakeNetBox serves the NetBox list endpoints used by the fetchers from fixed result
+// sets, paginated with NetBox style limit/offset "next" cursors, and rejects requests
+// that do not carry the test token.
+type fakeNetBox struct {
+	Resources map[string][]interface{}
+	Requests  []string
+}
+
+func (f *fakeNetBox) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.Requests = append(f.Requests, r.URL.RequestURI())
+	if r.Header.Get("Authorization") != "Token "+testToken {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"detail": "Invalid token"}`))
+		return
+	}
+	if r.URL.Path == "/api/status/" {
+		_, _ = w.Write([]byte(`{"netbox-version": "3.7.0"}`))
+		return
+	}
+	results, ok := f.Resources[r.URL.Path]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit <= 0 {
+		limit = 50
+	}
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+	end := offset + limit
+	if end > len(results) {
+		end = len(results)
+	}
+	page := map[string]interface{}{
+		"count":    len(results),
+		"next":     nil,
+		"previous": nil,
+		"results":  results[offset:end],
+	}
+	if end < len(results) {
+		page["next"] = fmt.Sprintf("http://%s%s?limit=%d&offset=%d", r.Host, r.URL.Path, limit, end)
+	}
+	_ = json.NewEncoder(w).Encode(page)
+}
+
+func newFakeNetBoxServer(resources map[string][]interface{}) (*httptest.Server, *fakeNetBox) {
+	fake := &fakeNetBox{Resources: resources}
+	return httptest.NewServer(fake), fake
+}
+
+// object is shorthand for a JSON object in a fake NetBox result set.
+type object = map[string]interface{}
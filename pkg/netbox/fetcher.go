@@ -0,0 +1,50 @@
+package netbox
+
+import (
+	"context"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+	"github.com/asecurityteam/ipam-facade/pkg/ipamfetcher"
+	"github.com/asecurityteam/ipam-facade/pkg/subnetassign"
+)
+
+// NewIPAMDataFetcher generates a new IPAMDataFetcher for a NetBox instance
+func NewIPAMDataFetcher(c *Client, resolver ipamfetcher.OwnerResolver) *IPAMDataFetcher {
+	return &IPAMDataFetcher{
+		CustomerFetcher: NewTenantFetcher(c, resolver),
+		SubnetFetcher:   NewPrefixFetcher(c),
+		DeviceFetcher:   NewIPAddressFetcher(c),
+	}
+}
+
+// IPAMDataFetcher implements the IPAMDataFetcher interface to retrieve data from NetBox.
+// Because NetBox IP addresses are not linked to prefixes, each Device is assigned the
+// most specific Subnet that contains its IP address. VRFs are not taken into account.
+type IPAMDataFetcher struct {
+	CustomerFetcher domain.CustomerFetcher
+	SubnetFetcher   domain.SubnetFetcher
+	DeviceFetcher   domain.DeviceFetcher
+}
+
+// FetchIPAMData retrieves tenants, prefixes, and IP addresses from NetBox
+func (f *IPAMDataFetcher) FetchIPAMData(ctx context.Context) (domain.IPAMData, error) {
+	customers, err := f.CustomerFetcher.FetchCustomers(ctx)
+	if err != nil {
+		return domain.IPAMData{}, err
+	}
+	subnets, err := f.SubnetFetcher.FetchSubnets(ctx)
+	if err != nil {
+		return domain.IPAMData{}, err
+	}
+	devices, err := f.DeviceFetcher.FetchDevices(ctx)
+	if err != nil {
+		return domain.IPAMData{}, err
+	}
+
+	subnetassign.AssignSubnets(devices, subnets)
+	return domain.IPAMData{
+		Customers: customers,
+		Subnets:   subnets,
+		Devices:   devices,
+	}, nil
+}
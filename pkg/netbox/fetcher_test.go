@@ -0,0 +1,179 @@
+package netbox
+
+import (
+	"context"
+	"testing"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+	"github.com/asecurityteam/ipam-facade/pkg/ipamfetcher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fakeNetBoxResources() map[string][]interface{} {
+	return map[string][]interface{}{
+		"/api/tenancy/tenants/": {
+			object{"id": 1, "name": "Payments", "group": object{"id": 10, "name": "Finance"}, "custom_fields": object{"owner": "payments@example.com", "cost_center": 42}},
+			object{"id": 2, "name": "Security", "group": nil, "custom_fields": object{}},
+			object{"id": 3, "name": "Unowned", "custom_fields": object{}},
+		},
+		"/api/tenancy/contacts/": {
+			object{"id": 100, "name": "Alice", "email": "alice@example.com", "phone": "555-0100"},
+			object{"id": 101, "name": "Bob", "email": "bob@example.com", "phone": ""},
+		},
+		"/api/tenancy/contact-assignments/": {
+			object{"id": 1, "content_type": "tenancy.tenant", "object_id": 1, "contact": object{"id": 100, "name": "Alice"}, "role": object{"id": 1, "name": "Technical"}},
+			object{"id": 2, "object_type": "tenancy.tenant", "object_id": 2, "contact": object{"id": 101, "name": "Bob"}, "role": object{"id": 2, "name": "SRE"}},
+			object{"id": 3, "content_type": "dcim.site", "object_id": 1, "contact": object{"id": 101, "name": "Bob"}, "role": object{"id": 2, "name": "SRE"}},
+		},
+		"/api/ipam/prefixes/": {
+			object{"id": 1, "prefix": "10.0.0.0/16", "site": object{"id": 1, "name": "DC1"}, "tenant": object{"id": 1, "name": "Payments"}},
+			object{"id": 2, "prefix": "10.0.1.0/24", "scope": object{"id": 2, "name": "DC2"}, "tenant": object{"id": 2, "name": "Security"}},
+			object{"id": 3, "prefix": "2001:db8::/32", "site": nil, "tenant": nil},
+			object{"id": 4, "prefix": "garbage", "tenant": nil},
+		},
+		"/api/ipam/ip-addresses/": {
+			object{"id": 1, "address": "10.0.0.5/16", "assigned_object_type": "dcim.interface", "assigned_object": object{"id": 7, "device": object{"id": 70, "name": "server"}}},
+			object{"id": 2, "address": "10.0.1.5/24", "assigned_object_type": "virtualization.vminterface", "assigned_object": object{"id": 8, "virtual_machine": object{"id": 80, "name": "vm"}}},
+			object{"id": 3, "address": "2001:db8::1/64", "assigned_object_type": nil, "assigned_object": nil},
+			object{"id": 4, "address": "192.168.0.1/24", "assigned_object_type": nil, "assigned_object": nil},
+		},
+	}
+}
+
+func newTestClient(t *testing.T, endpoint string, token string) *Client {
+	component := NewComponent()
+	conf := component.Settings()
+	conf.Endpoint = endpoint
+	conf.Token = token
+	conf.Limit = 2
+	client, err := component.New(context.Background(), conf)
+	require.NoError(t, err)
+	return client
+}
+
+func TestFetchIPAMData(t *testing.T) {
+	server, fake := newFakeNetBoxServer(fakeNetBoxResources())
+	defer server.Close()
+
+	resolver := ipamfetcher.ChainOwnerResolver{
+		&ipamfetcher.ContactTypeResolver{TypeSearchOrder: []string{"SRE", "Technical"}},
+	}
+	fetcher := NewIPAMDataFetcher(newTestClient(t, server.URL, testToken), resolver)
+	ipamData, err := fetcher.FetchIPAMData(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, []domain.Customer{
+		{
+			ID:                "1",
+			ResourceOwner:     "alice@example.com",
+			ResourceOwnerRule: "contact-type:Technical",
+			BusinessUnit:      "Finance",
+			Contacts:          []domain.Contact{{Type: "Technical", Name: "Alice", Email: "alice@example.com", Phone: "555-0100"}},
+		},
+		{
+			ID:                "2",
+			ResourceOwner:     "bob@example.com",
+			ResourceOwnerRule: "contact-type:SRE",
+			BusinessUnit:      "Security",
+			Contacts:          []domain.Contact{{Type: "SRE", Name: "Bob", Email: "bob@example.com", Phone: ""}},
+		},
+		{
+			ID:           "3",
+			BusinessUnit: "Unowned",
+		},
+	}, ipamData.Customers)
+	assert.Equal(t, []domain.Subnet{
+		{ID: "1", Network: "10.0.0.0", MaskBits: 16, Location: "DC1", CustomerID: "1"},
+		{ID: "2", Network: "10.0.1.0", MaskBits: 24, Location: "DC2", CustomerID: "2"},
+		{ID: "3", Network: "2001:db8::", MaskBits: 32},
+		{ID: "4", Network: "garbage", MaskBits: -1},
+	}, ipamData.Subnets)
+	assert.Equal(t, []domain.Device{
+		{ID: "70", IP: "10.0.0.5", SubnetID: "1"},
+		{IP: "10.0.1.5", SubnetID: "2"},
+		{IP: "2001:db8::1", SubnetID: "3"},
+		{IP: "192.168.0.1"},
+	}, ipamData.Devices)
+
+	// every list endpoint is paged through its "next" cursor
+	assert.Contains(t, fake.Requests, "/api/ipam/prefixes/?limit=2")
+	assert.Contains(t, fake.Requests, "/api/ipam/prefixes/?limit=2&offset=2")
+	assert.Contains(t, fake.Requests, "/api/ipam/ip-addresses/?limit=2&offset=2")
+	assert.Contains(t, fake.Requests, "/api/tenancy/tenants/?limit=2&offset=2")
+}
+
+func TestFetchIPAMDataCustomFieldOwner(t *testing.T) {
+	server, _ := newFakeNetBoxServer(fakeNetBoxResources())
+	defer server.Close()
+
+	resolver := ipamfetcher.ChainOwnerResolver{
+		&ipamfetcher.MappingResolver{Owners: map[string]string{"Security": "security@example.com"}},
+	}
+	fetcher := NewTenantFetcher(newTestClient(t, server.URL, testToken), resolver)
+	customers, err := fetcher.FetchCustomers(context.Background())
+	require.NoError(t, err)
+	require.Len(t, customers, 3)
+	assert.Equal(t, "", customers[0].ResourceOwner)
+	assert.Equal(t, "security@example.com", customers[1].ResourceOwner)
+	assert.Equal(t, "mapping:Security", customers[1].ResourceOwnerRule)
+}
+
+func TestFetchIPAMDataBadToken(t *testing.T) {
+	server, _ := newFakeNetBoxServer(fakeNetBoxResources())
+	defer server.Close()
+
+	fetcher := NewIPAMDataFetcher(newTestClient(t, server.URL, "wrong"), nil)
+	_, err := fetcher.FetchIPAMData(context.Background())
+	require.Error(t, err)
+}
+
+func TestFetchIPAMDataMalformedPage(t *testing.T) {
+	resources := fakeNetBoxResources()
+	resources["/api/ipam/ip-addresses/"] = []interface{}{object{"id": "not-a-number"}}
+	server, _ := newFakeNetBoxServer(resources)
+	defer server.Close()
+
+	fetcher := NewIPAMDataFetcher(newTestClient(t, server.URL, testToken), nil)
+	_, err := fetcher.FetchIPAMData(context.Background())
+	require.Error(t, err)
+}
+
+func TestCheckDependencies(t *testing.T) {
+	server, _ := newFakeNetBoxServer(fakeNetBoxResources())
+	defer server.Close()
+
+	assert.NoError(t, newTestClient(t, server.URL, testToken).CheckDependencies(context.Background()))
+	assert.Error(t, newTestClient(t, server.URL, "wrong").CheckDependencies(context.Background()))
+}
+
+func TestConfig(t *testing.T) {
+	assert.Equal(t, "NetBox", (&Config{}).Name())
+
+	component := NewComponent()
+	conf := component.Settings()
+	conf.Endpoint = "https://lo\\<calhost:443"
+	_, err := component.New(context.Background(), conf)
+	assert.Error(t, err)
+}
+
+func TestToSubnet(t *testing.T) {
+	tc := []struct {
+		name     string
+		prefix   string
+		expected domain.Subnet
+	}{
+		{name: "ipv4", prefix: "10.0.0.0/24", expected: domain.Subnet{ID: "1", Network: "10.0.0.0", MaskBits: 24}},
+		{name: "ipv4 host", prefix: "10.0.0.1/32", expected: domain.Subnet{ID: "1", Network: "10.0.0.1", MaskBits: 32}},
+		{name: "ipv6 host", prefix: "2001:db8::1/128", expected: domain.Subnet{ID: "1", Network: "2001:db8::1", MaskBits: 128}},
+		{name: "ipv4 mask out of range", prefix: "10.0.0.0/33", expected: domain.Subnet{ID: "1", Network: "10.0.0.0", MaskBits: -1}},
+		{name: "ipv6 mask out of range", prefix: "2001:db8::/129", expected: domain.Subnet{ID: "1", Network: "2001:db8::", MaskBits: -1}},
+		{name: "malformed mask", prefix: "10.0.0.0/x", expected: domain.Subnet{ID: "1", Network: "10.0.0.0", MaskBits: -1}},
+		{name: "malformed prefix", prefix: "garbage", expected: domain.Subnet{ID: "1", Network: "garbage", MaskBits: -1}},
+	}
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, toSubnet(prefix{ID: 1, Prefix: tt.prefix}))
+		})
+	}
+}
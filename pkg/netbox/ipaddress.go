@@ -0,0 +1,67 @@
+package netbox
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+)
+
+const interfaceObjectType = "dcim.interface"
+
+type ipAddress struct {
+	ID                 int    `json:"id"`
+	Address            string `json:"address"`
+	AssignedObjectType string `json:"assigned_object_type"`
+	AssignedObject     *struct {
+		Device *namedObject `json:"device"`
+	} `json:"assigned_object"`
+}
+
+// NewIPAddressFetcher generates a new IPAddressFetcher
+func NewIPAddressFetcher(c *Client) *IPAddressFetcher {
+	return &IPAddressFetcher{
+		PageFetcher: &HTTPPageFetcher{Client: c.Client, Endpoint: c.resourceURL("ipam", "ip-addresses"), Limit: c.Limit},
+	}
+}
+
+// IPAddressFetcher implements the DeviceFetcher interface by mapping NetBox IP addresses onto
+// Devices. NetBox does not link IP addresses to prefixes, so the SubnetID of each Device is
+// left empty for the IPAMDataFetcher to fill in.
+type IPAddressFetcher struct {
+	PageFetcher PageFetcher
+}
+
+// FetchDevices retrieves IP addresses from NetBox
+func (f *IPAddressFetcher) FetchDevices(ctx context.Context) ([]domain.Device, error) {
+	devices := make([]domain.Device, 0)
+	err := fetchAll(ctx, f.PageFetcher, func(results json.RawMessage) error {
+		var page []ipAddress
+		if err := json.Unmarshal(results, &page); err != nil {
+			return err
+		}
+		for _, address := range page {
+			devices = append(devices, toDevice(address))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return devices, nil
+}
+
+// toDevice converts an IP address into a Device. Only addresses assigned to a device
+// interface have a device ID; those assigned to virtual machines do not.
+func toDevice(address ipAddress) domain.Device {
+	device := domain.Device{IP: address.Address}
+	if slash := strings.Index(address.Address, "/"); slash >= 0 {
+		device.IP = address.Address[:slash]
+	}
+	if address.AssignedObjectType == interfaceObjectType && address.AssignedObject != nil && address.AssignedObject.Device != nil {
+		device.ID = strconv.Itoa(address.AssignedObject.Device.ID)
+	}
+	return device
+}
@@ -0,0 +1,127 @@
+package netbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Page represents the standard structure of NetBox paginated response payloads. Next
+// is the URL of the following page, or empty on the last page.
+type Page struct {
+	Count   int             `json:"count"`
+	Next    string          `json:"next"`
+	Results json.RawMessage `json:"results"`
+}
+
+// PageFetcher encapsulates the logic for requesting individual pages from a NetBox list endpoint.
+type PageFetcher interface {
+	// FetchPage requests the page at the given cursor URL, or the first page if the cursor is empty.
+	FetchPage(ctx context.Context, cursor string) (Page, error)
+}
+
+// PageIterator iterates over the pages of a NetBox list endpoint by following the
+// "next" cursor of each page.
+type PageIterator struct {
+	Context     context.Context
+	PageFetcher PageFetcher
+	err         error
+	currentPage Page
+	started     bool
+	exhausted   bool
+}
+
+// Next fetches the next page from the API. It returns false when there are no more pages.
+func (it *PageIterator) Next() bool {
+	if it.exhausted || it.err != nil || (it.started && it.currentPage.Next == "") {
+		it.exhausted = true
+		return false
+	}
+
+	nextPage, err := it.PageFetcher.FetchPage(it.Context, it.currentPage.Next)
+	if err != nil {
+		it.err = err
+		return false
+	}
+
+	it.started = true
+	it.currentPage = nextPage
+	return true
+}
+
+// Current returns the current page if there are no issues with the state of the iterator
+func (it *PageIterator) Current() Page {
+	if it.exhausted || it.err != nil {
+		return Page{}
+	}
+	return it.currentPage
+}
+
+// Close returns the error from the iterator if any
+func (it *PageIterator) Close() error {
+	return it.err
+}
+
+// HTTPPageFetcher implements the PageFetcher interface for NetBox list endpoints.
+type HTTPPageFetcher struct {
+	Client   *http.Client
+	Endpoint *url.URL
+	Limit    int
+}
+
+// FetchPage requests a page from NetBox. The first page is requested from Endpoint with the
+// configured Limit, and following pages from the cursor URLs returned by NetBox.
+func (f *HTTPPageFetcher) FetchPage(ctx context.Context, cursor string) (Page, error) {
+	u, _ := url.Parse(f.Endpoint.String())
+	if cursor != "" {
+		next, err := url.Parse(cursor)
+		if err != nil {
+			return Page{}, err
+		}
+		u = u.ResolveReference(next)
+	} else if f.Limit > 0 {
+		q := u.Query()
+		q.Set("limit", strconv.Itoa(f.Limit))
+		u.RawQuery = q.Encode()
+	}
+	req, _ := http.NewRequest(http.MethodGet, u.String(), http.NoBody)
+	res, err := f.Client.Do(req.WithContext(ctx))
+	if err != nil {
+		return Page{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return Page{}, fmt.Errorf("unexpected error from netbox api: %d", res.StatusCode)
+	}
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return Page{}, err
+	}
+
+	var page Page
+	if err := json.Unmarshal(body, &page); err != nil {
+		return Page{}, err
+	}
+	return page, nil
+}
+
+// fetchAll decodes the results of every page of a NetBox list endpoint, calling
+// decode once per page.
+func fetchAll(ctx context.Context, pageFetcher PageFetcher, decode func(json.RawMessage) error) error {
+	iterator := &PageIterator{
+		Context:     ctx,
+		PageFetcher: pageFetcher,
+	}
+	for iterator.Next() {
+		if err := decode(iterator.Current().Results); err != nil {
+			return err
+		}
+	}
+	return iterator.Close()
+}
@@ -0,0 +1,78 @@
+package netbox
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+	"github.com/asecurityteam/ipam-facade/pkg/ipamvalidator"
+)
+
+type prefix struct {
+	ID     int          `json:"id"`
+	Prefix string       `json:"prefix"`
+	Site   *namedObject `json:"site"`
+	Scope  *namedObject `json:"scope"`
+	Tenant *namedObject `json:"tenant"`
+}
+
+// NewPrefixFetcher generates a new PrefixFetcher
+func NewPrefixFetcher(c *Client) *PrefixFetcher {
+	return &PrefixFetcher{
+		PageFetcher: &HTTPPageFetcher{Client: c.Client, Endpoint: c.resourceURL("ipam", "prefixes"), Limit: c.Limit},
+	}
+}
+
+// PrefixFetcher implements the SubnetFetcher interface by mapping NetBox prefixes onto Subnets.
+// The prefix site, or the scope in NetBox 4.2 and later, is used as the location, and the
+// prefix tenant as the customer.
+type PrefixFetcher struct {
+	PageFetcher PageFetcher
+}
+
+// FetchSubnets retrieves prefixes from NetBox
+func (f *PrefixFetcher) FetchSubnets(ctx context.Context) ([]domain.Subnet, error) {
+	subnets := make([]domain.Subnet, 0)
+	err := fetchAll(ctx, f.PageFetcher, func(results json.RawMessage) error {
+		var page []prefix
+		if err := json.Unmarshal(results, &page); err != nil {
+			return err
+		}
+		for _, p := range page {
+			subnets = append(subnets, toSubnet(p))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return subnets, nil
+}
+
+// toSubnet converts a prefix into a Subnet. Malformed prefixes are passed along with a mask
+// of -1 rather than failing the fetch, so that the sync validation quarantines them.
+func toSubnet(p prefix) domain.Subnet {
+	subnet := domain.Subnet{
+		ID:       strconv.Itoa(p.ID),
+		Network:  p.Prefix,
+		MaskBits: -1,
+	}
+	if slash := strings.LastIndex(p.Prefix, "/"); slash >= 0 {
+		subnet.Network = p.Prefix[:slash]
+		if maskBits, err := strconv.Atoi(p.Prefix[slash+1:]); err == nil && maskBits >= 0 && maskBits <= ipamvalidator.MaxMaskBits(subnet.Network) {
+			subnet.MaskBits = maskBits
+		}
+	}
+	switch {
+	case p.Site != nil:
+		subnet.Location = p.Site.Name
+	case p.Scope != nil:
+		subnet.Location = p.Scope.Name
+	}
+	if p.Tenant != nil {
+		subnet.CustomerID = strconv.Itoa(p.Tenant.ID)
+	}
+	return subnet
+}
@@ -0,0 +1,165 @@
+package netbox
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+	"github.com/asecurityteam/ipam-facade/pkg/ipamfetcher"
+)
+
+const tenantContentType = "tenancy.tenant"
+
+// namedObject is the brief representation NetBox uses for nested objects.
+type namedObject struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type tenant struct {
+	ID           int                    `json:"id"`
+	Name         string                 `json:"name"`
+	Group        *namedObject           `json:"group"`
+	CustomFields map[string]interface{} `json:"custom_fields"`
+}
+
+type contact struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	Phone string `json:"phone"`
+}
+
+// contactAssignment links a contact to an object with a role. NetBox 3 names the type
+// of the object content_type, while NetBox 4 names it object_type.
+type contactAssignment struct {
+	ContentType string       `json:"content_type"`
+	ObjectType  string       `json:"object_type"`
+	ObjectID    int          `json:"object_id"`
+	Contact     namedObject  `json:"contact"`
+	Role        *namedObject `json:"role"`
+}
+
+// NewTenantFetcher generates a new TenantFetcher
+func NewTenantFetcher(c *Client, resolver ipamfetcher.OwnerResolver) *TenantFetcher {
+	return &TenantFetcher{
+		Tenants:            &HTTPPageFetcher{Client: c.Client, Endpoint: c.resourceURL("tenancy", "tenants"), Limit: c.Limit},
+		Contacts:           &HTTPPageFetcher{Client: c.Client, Endpoint: c.resourceURL("tenancy", "contacts"), Limit: c.Limit},
+		ContactAssignments: &HTTPPageFetcher{Client: c.Client, Endpoint: c.resourceURL("tenancy", "contact-assignments"), Limit: c.Limit},
+		OwnerResolver:      resolver,
+	}
+}
+
+// TenantFetcher implements the CustomerFetcher interface by mapping NetBox tenants, along with
+// the contacts assigned to them, onto Customers. The contact role is used as the Contact type.
+type TenantFetcher struct {
+	Tenants            PageFetcher
+	Contacts           PageFetcher
+	ContactAssignments PageFetcher
+	OwnerResolver      ipamfetcher.OwnerResolver
+}
+
+// FetchCustomers fetches tenants from NetBox
+func (f *TenantFetcher) FetchCustomers(ctx context.Context) ([]domain.Customer, error) {
+	contacts := make(map[int]contact)
+	err := fetchAll(ctx, f.Contacts, func(results json.RawMessage) error {
+		var page []contact
+		if err := json.Unmarshal(results, &page); err != nil {
+			return err
+		}
+		for _, c := range page {
+			contacts[c.ID] = c
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tenantContacts := make(map[int][]ipamfetcher.Contact)
+	err = fetchAll(ctx, f.ContactAssignments, func(results json.RawMessage) error {
+		var page []contactAssignment
+		if err := json.Unmarshal(results, &page); err != nil {
+			return err
+		}
+		for _, assignment := range page {
+			if assignment.ContentType != tenantContentType && assignment.ObjectType != tenantContentType {
+				continue
+			}
+			c := contacts[assignment.Contact.ID]
+			var role string
+			if assignment.Role != nil {
+				role = assignment.Role.Name
+			}
+			tenantContacts[assignment.ObjectID] = append(tenantContacts[assignment.ObjectID], ipamfetcher.Contact{
+				Type:  role,
+				Name:  assignment.Contact.Name,
+				Email: c.Email,
+				Phone: c.Phone,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	customers := make([]domain.Customer, 0)
+	err = fetchAll(ctx, f.Tenants, func(results json.RawMessage) error {
+		var page []tenant
+		if err := json.Unmarshal(results, &page); err != nil {
+			return err
+		}
+		for _, t := range page {
+			customers = append(customers, f.toCustomer(t, tenantContacts[t.ID]))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return customers, nil
+}
+
+// toCustomer converts a tenant into a Customer. The tenant group is used as the business unit,
+// with a fall back to the tenant name.
+func (f *TenantFetcher) toCustomer(t tenant, contacts []ipamfetcher.Contact) domain.Customer {
+	businessUnit := t.Name
+	if t.Group != nil && t.Group.Name != "" {
+		businessUnit = t.Group.Name
+	}
+
+	var owner ipamfetcher.OwnerResolution
+	if f.OwnerResolver != nil {
+		owner, _ = f.OwnerResolver.ResolveOwner(ipamfetcher.OwnerCandidate{
+			ID:           t.ID,
+			Name:         t.Name,
+			Contacts:     contacts,
+			CustomFields: stringValues(t.CustomFields),
+		})
+	}
+
+	var domainContacts []domain.Contact
+	for _, c := range contacts {
+		domainContacts = append(domainContacts, domain.Contact{Type: c.Type, Name: c.Name, Email: c.Email, Phone: c.Phone})
+	}
+	return domain.Customer{
+		ID:                strconv.Itoa(t.ID),
+		ResourceOwner:     owner.Owner,
+		ResourceOwnerRule: owner.Rule,
+		BusinessUnit:      businessUnit,
+		Contacts:          domainContacts,
+	}
+}
+
+// stringValues retrieves the string values of NetBox custom fields.
+func stringValues(customFields map[string]interface{}) map[string]string {
+	values := make(map[string]string, len(customFields))
+	for key, value := range customFields {
+		if s, ok := value.(string); ok {
+			values[key] = s
+		}
+	}
+	return values
+}
@@ -0,0 +1,131 @@
+package prefixlist
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+)
+
+// prefix is a network, with the address in the 4 or 16 byte form of its family.
+type prefix struct {
+	address net.IP
+	bits    int
+}
+
+func parsePrefix(network string) (prefix, error) {
+	_, ipNet, err := net.ParseCIDR(network)
+	if err != nil {
+		return prefix{}, err
+	}
+	bits, _ := ipNet.Mask.Size()
+	// as in PostgreSQL, an IPv4-mapped IPv6 network belongs to the IPv6 family
+	if strings.Contains(network, ":") {
+		return prefix{address: ipNet.IP.To16(), bits: bits}, nil
+	}
+	return prefix{address: ipNet.IP.To4(), bits: bits}, nil
+}
+
+func (p prefix) String() string {
+	if len(p.address) == net.IPv6len {
+		// formatted by hand so that IPv4-mapped networks keep their IPv6 form
+		return fmt.Sprintf("%s/%d", formatIPv6(p.address), p.bits)
+	}
+	return fmt.Sprintf("%s/%d", p.address, p.bits)
+}
+
+// formatIPv6 formats an IPv6 address as net.IP does, without turning an IPv4-mapped address
+// into its IPv4 form.
+func formatIPv6(address net.IP) string {
+	if address.To4() == nil {
+		return address.String()
+	}
+	return "::ffff:" + address[12:].String()
+}
+
+// bit returns the bit of the address at a position, counting from the most significant.
+func (p prefix) bit(position int) byte {
+	return p.address[position/8] >> uint(7-position%8) & 1
+}
+
+// contains reports whether the prefix contains, or is, another prefix of the same family.
+func (p prefix) contains(other prefix) bool {
+	if other.bits < p.bits {
+		return false
+	}
+	whole := p.bits / 8
+	if !bytes.Equal(p.address[:whole], other.address[:whole]) {
+		return false
+	}
+	if rest := uint(p.bits % 8); rest > 0 {
+		mask := byte(0xff << (8 - rest))
+		return p.address[whole]&mask == other.address[whole]&mask
+	}
+	return true
+}
+
+// merge returns the prefix that covers exactly two adjacent prefixes of the same length, the
+// lower and upper halves of it, and whether they are such halves.
+func merge(lower prefix, upper prefix) (prefix, bool) {
+	if lower.bits != upper.bits || lower.bits == 0 || lower.bit(lower.bits-1) != 0 {
+		return prefix{}, false
+	}
+	parent := prefix{address: lower.address, bits: lower.bits - 1}
+	if !parent.contains(upper) {
+		return prefix{}, false
+	}
+	return parent, true
+}
+
+// aggregate returns the smallest set of prefixes that covers the networks: networks inside
+// others are dropped, and adjacent networks that together make up a larger one are replaced
+// by it. IPv4 prefixes are returned before IPv6 ones, each in address order.
+func aggregate(networks []string) ([]string, error) {
+	var v4, v6 []prefix
+	for _, network := range networks {
+		p, err := parsePrefix(network)
+		if err != nil {
+			return nil, err
+		}
+		if len(p.address) == net.IPv6len {
+			v6 = append(v6, p)
+		} else {
+			v4 = append(v4, p)
+		}
+	}
+	aggregated := make([]string, 0, len(networks))
+	for _, family := range [][]prefix{v4, v6} {
+		for _, p := range aggregateFamily(family) {
+			aggregated = append(aggregated, p.String())
+		}
+	}
+	return aggregated, nil
+}
+
+// aggregateFamily aggregates the prefixes of a single family. Once sorted by address, and
+// the shorter of prefixes with the same address first, a prefix can only be inside the last
+// one kept, and can only merge with it, so a single pass over a stack aggregates them all.
+func aggregateFamily(prefixes []prefix) []prefix {
+	sort.Slice(prefixes, func(i, j int) bool {
+		if order := bytes.Compare(prefixes[i].address, prefixes[j].address); order != 0 {
+			return order < 0
+		}
+		return prefixes[i].bits < prefixes[j].bits
+	})
+	stack := make([]prefix, 0, len(prefixes))
+	for _, p := range prefixes {
+		if len(stack) > 0 && stack[len(stack)-1].contains(p) {
+			continue
+		}
+		stack = append(stack, p)
+		for len(stack) >= 2 {
+			parent, ok := merge(stack[len(stack)-2], stack[len(stack)-1])
+			if !ok {
+				break
+			}
+			stack = append(stack[:len(stack)-2], parent)
+		}
+	}
+	return stack
+}
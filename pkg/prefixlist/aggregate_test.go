@@ -0,0 +1,86 @@
+package prefixlist
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregate(t *testing.T) {
+	tc := []struct {
+		name     string
+		networks []string
+		expected []string
+	}{
+		{
+			name:     "none",
+			networks: []string{},
+			expected: []string{},
+		},
+		{
+			name:     "adjacent halves",
+			networks: []string{"10.0.1.0/24", "10.0.0.0/24"},
+			expected: []string{"10.0.0.0/23"},
+		},
+		{
+			name:     "adjacent but not halves of the same network",
+			networks: []string{"10.0.1.0/24", "10.0.2.0/24"},
+			expected: []string{"10.0.1.0/24", "10.0.2.0/24"},
+		},
+		{
+			name:     "nested",
+			networks: []string{"10.1.2.0/24", "10.0.0.0/8", "10.1.0.0/16"},
+			expected: []string{"10.0.0.0/8"},
+		},
+		{
+			name:     "repeated",
+			networks: []string{"192.168.0.0/16", "192.168.0.0/16"},
+			expected: []string{"192.168.0.0/16"},
+		},
+		{
+			name: "merged in several steps",
+			networks: []string{
+				"10.0.0.0/26", "10.0.0.64/26", "10.0.0.128/25", "10.0.1.0/24",
+				"10.0.2.0/23", "10.0.4.0/24",
+			},
+			expected: []string{"10.0.0.0/22", "10.0.4.0/24"},
+		},
+		{
+			name:     "merged with a nested network",
+			networks: []string{"10.0.0.0/25", "10.0.0.0/26", "10.0.0.128/25", "10.0.0.200/32"},
+			expected: []string{"10.0.0.0/24"},
+		},
+		{
+			name:     "single addresses",
+			networks: []string{"10.0.0.1/32", "10.0.0.0/32", "10.0.0.2/32"},
+			expected: []string{"10.0.0.0/31", "10.0.0.2/32"},
+		},
+		{
+			name:     "IPv6 after IPv4",
+			networks: []string{"2001:db8:1::/48", "10.0.0.0/8", "2001:db8::/48", "2001:db8:3::/48"},
+			expected: []string{"10.0.0.0/8", "2001:db8::/47", "2001:db8:3::/48"},
+		},
+		{
+			name:     "whole address space",
+			networks: []string{"0.0.0.0/1", "128.0.0.0/1"},
+			expected: []string{"0.0.0.0/0"},
+		},
+		{
+			name:     "IPv4-mapped IPv6 networks stay IPv6",
+			networks: []string{"::ffff:10.0.0.0/104", "::ffff:11.0.0.0/104", "10.0.0.0/8"},
+			expected: []string{"10.0.0.0/8", "::ffff:10.0.0.0/103"},
+		},
+	}
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			aggregated, err := aggregate(tt.networks)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, aggregated)
+		})
+	}
+}
+
+func TestAggregateInvalidNetwork(t *testing.T) {
+	_, err := aggregate([]string{"10.0.0.0/8", "10.0.0.0"})
+	require.Error(t, err)
+}
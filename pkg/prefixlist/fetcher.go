@@ -0,0 +1,57 @@
+// Package prefixlist fetches the networks of the subnets owned by a business unit, or selected
+// by other filters, as the smallest set of prefixes that covers them, from which allowlists
+// for network devices are built.
+package prefixlist
+
+import (
+	"context"
+	"net"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+)
+
+// Fetcher fetches prefixes through the subnet queries of its AssetGraphFetcher, so that the
+// subnets are selected by the storage backend as for GraphQL queries.
+type Fetcher struct {
+	Graph domain.AssetGraphFetcher
+}
+
+// FetchPrefixes fetches the aggregated networks of the subnets selected by the filter. When
+// the filter selects customers, subnets without a customer are left out. The Within network
+// must not have bits set after its mask.
+func (f *Fetcher) FetchPrefixes(ctx context.Context, filter domain.PrefixFilter) ([]string, error) {
+	query := domain.SubnetQuery{Location: filter.Location, Within: filter.Within}
+	if filter.Within != "" {
+		ip, network, err := net.ParseCIDR(filter.Within)
+		if err != nil || !ip.Equal(network.IP) {
+			return nil, domain.InvalidInput{Input: "network " + filter.Within}
+		}
+	}
+
+	if filter.BusinessUnit != "" || filter.ResourceOwner != "" {
+		customers, err := f.Graph.QueryCustomers(ctx, domain.CustomerQuery{
+			BusinessUnit:  filter.BusinessUnit,
+			ResourceOwner: filter.ResourceOwner,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(customers) == 0 {
+			return []string{}, nil
+		}
+		query.CustomerIDs = make([]int64, 0, len(customers))
+		for _, customer := range customers {
+			query.CustomerIDs = append(query.CustomerIDs, customer.ID)
+		}
+	}
+
+	subnets, err := f.Graph.QuerySubnets(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	networks := make([]string, 0, len(subnets))
+	for _, subnet := range subnets {
+		networks = append(networks, subnet.Network)
+	}
+	return aggregate(networks)
+}
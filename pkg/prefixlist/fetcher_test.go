@@ -0,0 +1,106 @@
+package prefixlist
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+	"github.com/asecurityteam/ipam-facade/pkg/memstore"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func newStore(t *testing.T) *memstore.Store {
+	store := &memstore.Store{}
+	require.NoError(t, store.StorePhysicalAssets(context.Background(), domain.IPAMData{
+		Customers: []domain.Customer{
+			{ID: "1", ResourceOwner: "alice@example.com", BusinessUnit: "Payments"},
+			{ID: "2", ResourceOwner: "bob@example.com", BusinessUnit: "Payments"},
+			{ID: "3", ResourceOwner: "carol@example.com", BusinessUnit: "Search"},
+		},
+		Subnets: []domain.Subnet{
+			{ID: "1", Network: "10.0.0.0", MaskBits: 24, Location: "DC1", CustomerID: "1"},
+			{ID: "2", Network: "10.0.1.0", MaskBits: 24, Location: "DC1", CustomerID: "2"},
+			{ID: "3", Network: "10.0.0.128", MaskBits: 25, Location: "DC1", CustomerID: "1"},
+			{ID: "4", Network: "10.0.2.0", MaskBits: 24, Location: "DC2", CustomerID: "1"},
+			{ID: "5", Network: "10.0.3.0", MaskBits: 24, Location: "DC2", CustomerID: "3"},
+			{ID: "6", Network: "2001:db8::", MaskBits: 48, Location: "DC2", CustomerID: "2"},
+			{ID: "7", Network: "172.16.0.0", MaskBits: 12, Location: "DC1"},
+		},
+	}))
+	return store
+}
+
+func TestFetchPrefixes(t *testing.T) {
+	tc := []struct {
+		name     string
+		filter   domain.PrefixFilter
+		expected []string
+	}{
+		{
+			name:     "all",
+			expected: []string{"10.0.0.0/22", "172.16.0.0/12", "2001:db8::/48"},
+		},
+		{
+			name:     "business unit",
+			filter:   domain.PrefixFilter{BusinessUnit: "Payments"},
+			expected: []string{"10.0.0.0/23", "10.0.2.0/24", "2001:db8::/48"},
+		},
+		{
+			name:     "resource owner",
+			filter:   domain.PrefixFilter{ResourceOwner: "alice@example.com"},
+			expected: []string{"10.0.0.0/24", "10.0.2.0/24"},
+		},
+		{
+			name:     "business unit and location",
+			filter:   domain.PrefixFilter{BusinessUnit: "Payments", Location: "DC1"},
+			expected: []string{"10.0.0.0/23"},
+		},
+		{
+			name:     "within",
+			filter:   domain.PrefixFilter{Within: "10.0.0.0/23"},
+			expected: []string{"10.0.0.0/23"},
+		},
+		{
+			name:     "unknown business unit",
+			filter:   domain.PrefixFilter{BusinessUnit: "Marketing"},
+			expected: []string{},
+		},
+	}
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			fetcher := &Fetcher{Graph: newStore(t)}
+			prefixes, err := fetcher.FetchPrefixes(context.Background(), tt.filter)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, prefixes)
+		})
+	}
+}
+
+func TestFetchPrefixesInvalidWithin(t *testing.T) {
+	for _, within := range []string{"10.0.0.1/8", "10.0.0.1", "subnet"} {
+		t.Run(within, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			fetcher := &Fetcher{Graph: NewMockAssetGraphFetcher(ctrl)}
+			_, err := fetcher.FetchPrefixes(context.Background(), domain.PrefixFilter{Within: within})
+			require.IsType(t, domain.InvalidInput{}, err)
+		})
+	}
+}
+
+func TestFetchPrefixesError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	queryErr := errors.New("connection refused")
+	mockGraph := NewMockAssetGraphFetcher(ctrl)
+	mockGraph.EXPECT().QueryCustomers(gomock.Any(), domain.CustomerQuery{BusinessUnit: "Payments"}).Return([]domain.GraphCustomer{{ID: 1}, {ID: 2}}, nil)
+	mockGraph.EXPECT().QuerySubnets(gomock.Any(), domain.SubnetQuery{CustomerIDs: []int64{1, 2}, Location: "DC1"}).Return(nil, queryErr)
+
+	fetcher := &Fetcher{Graph: mockGraph}
+	_, err := fetcher.FetchPrefixes(context.Background(), domain.PrefixFilter{BusinessUnit: "Payments", Location: "DC1"})
+	require.Equal(t, queryErr, err)
+}
@@ -0,0 +1,95 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/asecurityteam/ipam-facade/pkg/domain (interfaces: AssetGraphFetcher)
+
+// Package prefixlist is a generated GoMock package.
+package prefixlist
+
+import (
+	context "context"
+	domain "github.com/asecurityteam/ipam-facade/pkg/domain"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockAssetGraphFetcher is a mock of AssetGraphFetcher interface
+type MockAssetGraphFetcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockAssetGraphFetcherMockRecorder
+}
+
+// MockAssetGraphFetcherMockRecorder is the mock recorder for MockAssetGraphFetcher
+type MockAssetGraphFetcherMockRecorder struct {
+	mock *MockAssetGraphFetcher
+}
+
+// NewMockAssetGraphFetcher creates a new mock instance
+func NewMockAssetGraphFetcher(ctrl *gomock.Controller) *MockAssetGraphFetcher {
+	mock := &MockAssetGraphFetcher{ctrl: ctrl}
+	mock.recorder = &MockAssetGraphFetcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockAssetGraphFetcher) EXPECT() *MockAssetGraphFetcherMockRecorder {
+	return m.recorder
+}
+
+// QueryContacts mocks base method
+func (m *MockAssetGraphFetcher) QueryContacts(arg0 context.Context, arg1 []int64) ([]domain.GraphContact, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryContacts", arg0, arg1)
+	ret0, _ := ret[0].([]domain.GraphContact)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryContacts indicates an expected call of QueryContacts
+func (mr *MockAssetGraphFetcherMockRecorder) QueryContacts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryContacts", reflect.TypeOf((*MockAssetGraphFetcher)(nil).QueryContacts), arg0, arg1)
+}
+
+// QueryCustomers mocks base method
+func (m *MockAssetGraphFetcher) QueryCustomers(arg0 context.Context, arg1 domain.CustomerQuery) ([]domain.GraphCustomer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryCustomers", arg0, arg1)
+	ret0, _ := ret[0].([]domain.GraphCustomer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryCustomers indicates an expected call of QueryCustomers
+func (mr *MockAssetGraphFetcherMockRecorder) QueryCustomers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryCustomers", reflect.TypeOf((*MockAssetGraphFetcher)(nil).QueryCustomers), arg0, arg1)
+}
+
+// QueryIPs mocks base method
+func (m *MockAssetGraphFetcher) QueryIPs(arg0 context.Context, arg1 domain.IPQuery) ([]domain.GraphIP, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryIPs", arg0, arg1)
+	ret0, _ := ret[0].([]domain.GraphIP)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryIPs indicates an expected call of QueryIPs
+func (mr *MockAssetGraphFetcherMockRecorder) QueryIPs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryIPs", reflect.TypeOf((*MockAssetGraphFetcher)(nil).QueryIPs), arg0, arg1)
+}
+
+// QuerySubnets mocks base method
+func (m *MockAssetGraphFetcher) QuerySubnets(arg0 context.Context, arg1 domain.SubnetQuery) ([]domain.GraphSubnet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QuerySubnets", arg0, arg1)
+	ret0, _ := ret[0].([]domain.GraphSubnet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QuerySubnets indicates an expected call of QuerySubnets
+func (mr *MockAssetGraphFetcherMockRecorder) QuerySubnets(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QuerySubnets", reflect.TypeOf((*MockAssetGraphFetcher)(nil).QuerySubnets), arg0, arg1)
+}
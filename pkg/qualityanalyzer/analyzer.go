@@ -0,0 +1,134 @@
+package qualityanalyzer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+	"github.com/asecurityteam/ipam-facade/pkg/ipamvalidator"
+)
+
+// IPAMDataAnalyzer implements the QualityAnalyzer interface by checking IPAM data
+// fetched from Device42 for records that would produce wrong or missing lookups.
+type IPAMDataAnalyzer struct{}
+
+// parsedSubnet is a valid Subnet along with its parsed network.
+type parsedSubnet struct {
+	subnet  domain.Subnet
+	network *net.IPNet
+}
+
+// AnalyzeIPAMData returns the data quality findings for the given IPAM data, ordered by
+// customers, subnets, then IP addresses.
+func (*IPAMDataAnalyzer) AnalyzeIPAMData(_ context.Context, ipamData domain.IPAMData) []domain.QualityFinding {
+	findings := make([]domain.QualityFinding, 0)
+
+	for _, customer := range ipamData.Customers {
+		if strings.TrimSpace(customer.ResourceOwner) == "" {
+			findings = append(findings, domain.QualityFinding{
+				Category:   domain.QualityCustomerEmptyOwner,
+				RecordType: domain.QualityRecordCustomer,
+				RecordID:   customer.ID,
+				Detail:     "customer has no resource owner",
+			})
+		}
+	}
+
+	subnetIDs := make(map[string]bool, len(ipamData.Subnets))
+	parsed := make([]parsedSubnet, 0, len(ipamData.Subnets))
+	for _, subnet := range ipamData.Subnets {
+		subnetIDs[subnet.ID] = true
+		if !ipamvalidator.HasCustomer(subnet) {
+			findings = append(findings, domain.QualityFinding{
+				Category:   domain.QualitySubnetWithoutCustomer,
+				RecordType: domain.QualityRecordSubnet,
+				RecordID:   subnet.ID,
+				Detail:     fmt.Sprintf("subnet %s/%d has no customer", subnet.Network, subnet.MaskBits),
+			})
+		}
+		network, err := ipamvalidator.ParseSubnet(subnet)
+		if err != nil {
+			findings = append(findings, domain.QualityFinding{
+				Category:   domain.QualityInvalidSubnet,
+				RecordType: domain.QualityRecordSubnet,
+				RecordID:   subnet.ID,
+				Detail:     err.Error(),
+			})
+			continue
+		}
+		parsed = append(parsed, parsedSubnet{subnet: subnet, network: network})
+	}
+	findings = append(findings, overlappingSubnets(parsed)...)
+	for _, conflict := range ipamData.Conflicts {
+		findings = append(findings, domain.QualityFinding{
+			Category:   domain.QualitySourceConflict,
+			RecordType: domain.QualityRecordSubnet,
+			RecordID:   conflict.SubnetID,
+			Detail: fmt.Sprintf("sources of subnet %s disagree about its %s: %s; kept %q",
+				conflict.Network, conflict.Field, conflict.Values, conflict.Kept),
+		})
+	}
+
+	for _, device := range ipamData.Devices {
+		if !subnetIDs[device.SubnetID] {
+			findings = append(findings, domain.QualityFinding{
+				Category:   domain.QualityIPUnknownSubnet,
+				RecordType: domain.QualityRecordIP,
+				RecordID:   device.IP,
+				Detail:     fmt.Sprintf("device %q references unknown subnet %q", device.ID, device.SubnetID),
+			})
+		}
+	}
+
+	return findings
+}
+
+// overlappingSubnets finds the subnets that are contained by a subnet belonging to a
+// different customer. CIDR networks either nest or are disjoint, so after sorting by
+// address family, starting address, and then prefix length, the subnets that contain
+// each subnet are exactly those left on a stack of enclosing networks.
+func overlappingSubnets(subnets []parsedSubnet) []domain.QualityFinding {
+	sort.SliceStable(subnets, func(i, j int) bool {
+		if len(subnets[i].network.IP) != len(subnets[j].network.IP) {
+			return len(subnets[i].network.IP) < len(subnets[j].network.IP)
+		}
+		if c := bytes.Compare(subnets[i].network.IP, subnets[j].network.IP); c != 0 {
+			return c < 0
+		}
+		return prefixLength(subnets[i].network) < prefixLength(subnets[j].network)
+	})
+
+	findings := make([]domain.QualityFinding, 0)
+	enclosing := make([]parsedSubnet, 0)
+	for _, current := range subnets {
+		for len(enclosing) > 0 && !contains(enclosing[len(enclosing)-1].network, current.network) {
+			enclosing = enclosing[:len(enclosing)-1]
+		}
+		for _, outer := range enclosing {
+			if ipamvalidator.HasCustomer(outer.subnet) && ipamvalidator.HasCustomer(current.subnet) && outer.subnet.CustomerID != current.subnet.CustomerID {
+				findings = append(findings, domain.QualityFinding{
+					Category:   domain.QualityOverlappingSubnets,
+					RecordType: domain.QualityRecordSubnet,
+					RecordID:   current.subnet.ID,
+					Detail: fmt.Sprintf("subnet %s of customer %s overlaps subnet %s (ID %s) of customer %s",
+						current.network, current.subnet.CustomerID, outer.network, outer.subnet.ID, outer.subnet.CustomerID),
+				})
+			}
+		}
+		enclosing = append(enclosing, current)
+	}
+	return findings
+}
+
+func prefixLength(network *net.IPNet) int {
+	ones, _ := network.Mask.Size()
+	return ones
+}
+
+func contains(outer *net.IPNet, inner *net.IPNet) bool {
+	return len(outer.IP) == len(inner.IP) && outer.Contains(inner.IP) && prefixLength(outer) <= prefixLength(inner)
+}
@@ -0,0 +1,115 @@
+package qualityanalyzer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeIPAMDataClean(t *testing.T) {
+	ipamData := domain.IPAMData{
+		Customers: []domain.Customer{
+			{ID: "1", ResourceOwner: "alice@example.com"},
+			{ID: "2", ResourceOwner: "bob@example.com"},
+		},
+		Subnets: []domain.Subnet{
+			{ID: "1", Network: "10.0.0.0", MaskBits: 16, CustomerID: "1"},
+			{ID: "2", Network: "10.0.1.0", MaskBits: 24, CustomerID: "1"},
+			{ID: "3", Network: "10.1.0.0", MaskBits: 24, CustomerID: "2"},
+			{ID: "4", Network: "2001:db8::", MaskBits: 32, CustomerID: "2"},
+		},
+		Devices: []domain.Device{
+			{ID: "1", IP: "10.0.1.1", SubnetID: "2"},
+		},
+	}
+
+	analyzer := &IPAMDataAnalyzer{}
+	require.Equal(t, []domain.QualityFinding{}, analyzer.AnalyzeIPAMData(context.Background(), ipamData))
+}
+
+func TestAnalyzeIPAMData(t *testing.T) {
+	ipamData := domain.IPAMData{
+		Customers: []domain.Customer{
+			{ID: "1", ResourceOwner: "alice@example.com"},
+			{ID: "2", ResourceOwner: " "},
+			{ID: "3", ResourceOwner: "carol@example.com"},
+		},
+		Subnets: []domain.Subnet{
+			{ID: "1", Network: "10.0.0.0", MaskBits: 16, CustomerID: "1"},
+			{ID: "2", Network: "10.0.1.0", MaskBits: 24, CustomerID: "2"},
+			{ID: "3", Network: "10.0.1.128", MaskBits: 25, CustomerID: "3"},
+			{ID: "4", Network: "10.0.2.0", MaskBits: 24, CustomerID: "0"},
+			{ID: "5", Network: "10.0.3.1", MaskBits: 24, CustomerID: "1"},
+			{ID: "6", Network: "not-a-network", MaskBits: 24, CustomerID: "1"},
+			{ID: "7", Network: "::", MaskBits: 0, CustomerID: "3"},
+		},
+		Devices: []domain.Device{
+			{ID: "1", IP: "10.0.1.1", SubnetID: "2"},
+			{ID: "", IP: "10.9.9.9", SubnetID: "42"},
+		},
+		Conflicts: []domain.SourceConflict{
+			{SubnetID: "1", Network: "10.0.0.0/16", Field: "resourceOwner", Values: "device42=alice@example.com,netbox=bob@example.com", Kept: "alice@example.com"},
+		},
+	}
+	expected := []domain.QualityFinding{
+		{
+			Category:   domain.QualityCustomerEmptyOwner,
+			RecordType: domain.QualityRecordCustomer,
+			RecordID:   "2",
+			Detail:     "customer has no resource owner",
+		},
+		{
+			Category:   domain.QualitySubnetWithoutCustomer,
+			RecordType: domain.QualityRecordSubnet,
+			RecordID:   "4",
+			Detail:     "subnet 10.0.2.0/24 has no customer",
+		},
+		{
+			Category:   domain.QualityInvalidSubnet,
+			RecordType: domain.QualityRecordSubnet,
+			RecordID:   "5",
+			Detail:     "network 10.0.3.1/24 has host bits set",
+		},
+		{
+			Category:   domain.QualityInvalidSubnet,
+			RecordType: domain.QualityRecordSubnet,
+			RecordID:   "6",
+			Detail:     `invalid network "not-a-network"`,
+		},
+		{
+			Category:   domain.QualityOverlappingSubnets,
+			RecordType: domain.QualityRecordSubnet,
+			RecordID:   "2",
+			Detail:     "subnet 10.0.1.0/24 of customer 2 overlaps subnet 10.0.0.0/16 (ID 1) of customer 1",
+		},
+		{
+			Category:   domain.QualityOverlappingSubnets,
+			RecordType: domain.QualityRecordSubnet,
+			RecordID:   "3",
+			Detail:     "subnet 10.0.1.128/25 of customer 3 overlaps subnet 10.0.0.0/16 (ID 1) of customer 1",
+		},
+		{
+			Category:   domain.QualityOverlappingSubnets,
+			RecordType: domain.QualityRecordSubnet,
+			RecordID:   "3",
+			Detail:     "subnet 10.0.1.128/25 of customer 3 overlaps subnet 10.0.1.0/24 (ID 2) of customer 2",
+		},
+		{
+			Category:   domain.QualitySourceConflict,
+			RecordType: domain.QualityRecordSubnet,
+			RecordID:   "1",
+			Detail:     `sources of subnet 10.0.0.0/16 disagree about its resourceOwner: device42=alice@example.com,netbox=bob@example.com; kept "alice@example.com"`,
+		},
+		{
+			Category:   domain.QualityIPUnknownSubnet,
+			RecordType: domain.QualityRecordIP,
+			RecordID:   "10.9.9.9",
+			Detail:     `device "" references unknown subnet "42"`,
+		},
+	}
+
+	analyzer := &IPAMDataAnalyzer{}
+	require.Equal(t, expected, analyzer.AnalyzeIPAMData(context.Background(), ipamData))
+}
@@ -0,0 +1,173 @@
+package qualityanalyzer
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+	"github.com/pkg/errors"
+)
+
+const (
+	insertReportStatement      = `INSERT INTO quality_reports (job_id) VALUES ($1) RETURNING id`
+	insertFindingStatement     = `INSERT INTO quality_findings VALUES (DEFAULT, $1, $2, $3, $4, $5)`
+	insertQuarantinedStatement = `INSERT INTO quarantined_records (report_id, record_type, record_id, reason) VALUES ($1, $2, $3, $4)`
+	// pruneReportsStatement removes all but the most recent reports, along with their
+	// findings and quarantined records by the ON DELETE CASCADE foreign keys
+	pruneReportsStatement = `DELETE FROM quality_reports
+						WHERE id NOT IN (SELECT id FROM quality_reports ORDER BY id DESC LIMIT $1)`
+)
+
+const fetchReportQuery = `SELECT id, generated_at, job_id
+						FROM quality_reports
+						ORDER BY id DESC
+						LIMIT 1;`
+
+const fetchJobReportQuery = `SELECT id, generated_at, job_id
+						FROM quality_reports
+						WHERE job_id = $1
+						ORDER BY id DESC
+						LIMIT 1;`
+
+const fetchFindingsQuery = `SELECT category, record_type, record_id, detail
+						FROM quality_findings
+						WHERE report_id = $1
+						AND ($2 = '' OR category = $2)
+						ORDER BY id;`
+
+const fetchQuarantinedQuery = `SELECT record_type, record_id, reason
+						FROM quarantined_records
+						WHERE report_id = $1
+						ORDER BY id;`
+
+// PostgresQualityReportStore stores and fetches the data quality reports of the most recent
+// IPAM data syncs in a PostgreSQL database. The domain.RetainedQualityReports most recent
+// reports are kept, so that the report of a sync job can be read once later syncs have run.
+type PostgresQualityReportStore struct {
+	DB domain.SQLDB
+}
+
+// StoreQualityReport stores a data quality report as the most recent one, and removes the
+// reports that are no longer retained.
+func (s *PostgresQualityReportStore) StoreQualityReport(ctx context.Context, report domain.QualityReport) error {
+	tx, err := s.DB.Conn().BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err = saveQualityReport(ctx, report, tx); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			return errors.Wrap(rollbackErr, err.Error())
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+func saveQualityReport(ctx context.Context, report domain.QualityReport, tx *sql.Tx) error {
+	var reportID int64
+	if err := tx.QueryRowContext(ctx, insertReportStatement, report.JobID).Scan(&reportID); err != nil {
+		return err
+	}
+
+	for _, finding := range report.Findings {
+		if _, err := tx.ExecContext(ctx, insertFindingStatement, reportID, finding.Category, finding.RecordType, finding.RecordID, finding.Detail); err != nil {
+			return err
+		}
+	}
+
+	for _, record := range report.Quarantined {
+		if _, err := tx.ExecContext(ctx, insertQuarantinedStatement, reportID, record.RecordType, record.RecordID, record.Reason); err != nil {
+			return err
+		}
+	}
+
+	_, err := tx.ExecContext(ctx, pruneReportsStatement, domain.RetainedQualityReports)
+	return err
+}
+
+// FetchQualityReport fetches the most recent data quality report, including only the findings
+// of the given category unless it is empty. A zero GeneratedAt indicates that no report
+// has been stored yet. The quarantined records of the report are not fetched.
+func (s *PostgresQualityReportStore) FetchQualityReport(ctx context.Context, category string) (domain.QualityReport, error) {
+	conn := s.DB.Conn()
+
+	report := domain.QualityReport{Findings: make([]domain.QualityFinding, 0)}
+	var reportID int64
+	err := conn.QueryRowContext(ctx, fetchReportQuery).Scan(&reportID, &report.GeneratedAt, &report.JobID)
+	switch {
+	case err == sql.ErrNoRows:
+		return report, nil
+	case err != nil:
+		return domain.QualityReport{}, err
+	}
+
+	if report.Findings, err = fetchFindings(ctx, conn, reportID, category); err != nil {
+		return domain.QualityReport{}, err
+	}
+	return report, nil
+}
+
+// FetchSyncJobReport fetches the data quality report of the most recent sync that ran as the
+// given job, along with the records it quarantined, or returns domain.SyncJobNotFound.
+func (s *PostgresQualityReportStore) FetchSyncJobReport(ctx context.Context, jobID string) (domain.QualityReport, error) {
+	conn := s.DB.Conn()
+
+	var report domain.QualityReport
+	var reportID int64
+	err := conn.QueryRowContext(ctx, fetchJobReportQuery, jobID).Scan(&reportID, &report.GeneratedAt, &report.JobID)
+	switch {
+	case err == sql.ErrNoRows:
+		return domain.QualityReport{}, domain.SyncJobNotFound{JobID: jobID}
+	case err != nil:
+		return domain.QualityReport{}, err
+	}
+
+	if report.Findings, err = fetchFindings(ctx, conn, reportID, ""); err != nil {
+		return domain.QualityReport{}, err
+	}
+	if report.Quarantined, err = fetchQuarantined(ctx, conn, reportID); err != nil {
+		return domain.QualityReport{}, err
+	}
+	return report, nil
+}
+
+func fetchFindings(ctx context.Context, conn *sql.DB, reportID int64, category string) ([]domain.QualityFinding, error) {
+	rows, err := conn.QueryContext(ctx, fetchFindingsQuery, reportID, category)
+	if err != nil {
+		return nil, err
+	}
+	findings := make([]domain.QualityFinding, 0)
+	for rows.Next() {
+		var finding domain.QualityFinding
+		if err := rows.Scan(&finding.Category, &finding.RecordType, &finding.RecordID, &finding.Detail); err != nil {
+			_ = rows.Close()
+			return nil, err
+		}
+		findings = append(findings, finding)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	return findings, nil
+}
+
+func fetchQuarantined(ctx context.Context, conn *sql.DB, reportID int64) ([]domain.QuarantinedRecord, error) {
+	rows, err := conn.QueryContext(ctx, fetchQuarantinedQuery, reportID)
+	if err != nil {
+		return nil, err
+	}
+	quarantined := make([]domain.QuarantinedRecord, 0)
+	for rows.Next() {
+		var record domain.QuarantinedRecord
+		if err := rows.Scan(&record.RecordType, &record.RecordID, &record.Reason); err != nil {
+			_ = rows.Close()
+			return nil, err
+		}
+		quarantined = append(quarantined, record)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	return quarantined, nil
+}
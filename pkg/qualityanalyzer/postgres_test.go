@@ -0,0 +1,202 @@
+package qualityanalyzer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreQualityReport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockSQLDB := NewMockSQLDB(ctrl)
+
+	mockdb, mock, err := sqlmock.New()
+	require.Nil(t, err)
+	defer mockdb.Close()
+	mockSQLDB.EXPECT().Conn().Return(mockdb)
+
+	findings := []domain.QualityFinding{
+		{Category: domain.QualityCustomerEmptyOwner, RecordType: domain.QualityRecordCustomer, RecordID: "1", Detail: "customer has no resource owner"},
+		{Category: domain.QualityIPUnknownSubnet, RecordType: domain.QualityRecordIP, RecordID: "10.0.0.1", Detail: `device "1" references unknown subnet "2"`},
+	}
+
+	quarantined := []domain.QuarantinedRecord{
+		{RecordType: domain.QualityRecordSubnet, RecordID: "3", Reason: "invalid network"},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO quality_reports").WithArgs("job-1").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	for _, finding := range findings {
+		mock.ExpectExec("INSERT INTO quality_findings").WithArgs(7, finding.Category, finding.RecordType, finding.RecordID, finding.Detail).WillReturnResult(sqlmock.NewResult(1, 1))
+	}
+	mock.ExpectExec("INSERT INTO quarantined_records").WithArgs(7, domain.QualityRecordSubnet, "3", "invalid network").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("DELETE FROM quality_reports WHERE id NOT IN").WithArgs(domain.RetainedQualityReports).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	store := PostgresQualityReportStore{DB: mockSQLDB}
+	require.Nil(t, store.StoreQualityReport(context.Background(), domain.QualityReport{JobID: "job-1", Findings: findings, Quarantined: quarantined}))
+	require.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestStoreQualityReportRollback(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockSQLDB := NewMockSQLDB(ctrl)
+
+	mockdb, mock, err := sqlmock.New()
+	require.Nil(t, err)
+	defer mockdb.Close()
+	mockSQLDB.EXPECT().Conn().Return(mockdb)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO quality_reports").WillReturnError(errors.New("boom"))
+	mock.ExpectRollback()
+
+	store := PostgresQualityReportStore{DB: mockSQLDB}
+	require.Error(t, store.StoreQualityReport(context.Background(), domain.QualityReport{}))
+	require.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestFetchQualityReport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockSQLDB := NewMockSQLDB(ctrl)
+
+	mockdb, mock, err := sqlmock.New()
+	require.Nil(t, err)
+	defer mockdb.Close()
+	mockSQLDB.EXPECT().Conn().Return(mockdb)
+
+	generatedAt := time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC)
+	mock.ExpectQuery("SELECT id, generated_at, job_id").WillReturnRows(sqlmock.NewRows([]string{"id", "generated_at", "job_id"}).AddRow(7, generatedAt, "job-1"))
+	mock.ExpectQuery("SELECT category, record_type, record_id, detail").WithArgs(7, domain.QualitySubnetWithoutCustomer).WillReturnRows(
+		sqlmock.NewRows([]string{"category", "record_type", "record_id", "detail"}).
+			AddRow(domain.QualitySubnetWithoutCustomer, domain.QualityRecordSubnet, "1", "subnet 10.0.0.0/24 has no customer"))
+
+	store := PostgresQualityReportStore{DB: mockSQLDB}
+	report, err := store.FetchQualityReport(context.Background(), domain.QualitySubnetWithoutCustomer)
+	require.Nil(t, err)
+	require.Equal(t, domain.QualityReport{
+		GeneratedAt: generatedAt,
+		JobID:       "job-1",
+		Findings: []domain.QualityFinding{
+			{Category: domain.QualitySubnetWithoutCustomer, RecordType: domain.QualityRecordSubnet, RecordID: "1", Detail: "subnet 10.0.0.0/24 has no customer"},
+		},
+	}, report)
+	require.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestFetchQualityReportNoReport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockSQLDB := NewMockSQLDB(ctrl)
+
+	mockdb, mock, err := sqlmock.New()
+	require.Nil(t, err)
+	defer mockdb.Close()
+	mockSQLDB.EXPECT().Conn().Return(mockdb)
+
+	mock.ExpectQuery("SELECT id, generated_at, job_id").WillReturnRows(sqlmock.NewRows([]string{"id", "generated_at", "job_id"}))
+
+	store := PostgresQualityReportStore{DB: mockSQLDB}
+	report, err := store.FetchQualityReport(context.Background(), "")
+	require.Nil(t, err)
+	require.Equal(t, domain.QualityReport{Findings: []domain.QualityFinding{}}, report)
+	require.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestFetchQualityReportFindingsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockSQLDB := NewMockSQLDB(ctrl)
+
+	mockdb, mock, err := sqlmock.New()
+	require.Nil(t, err)
+	defer mockdb.Close()
+	mockSQLDB.EXPECT().Conn().Return(mockdb)
+
+	mock.ExpectQuery("SELECT id, generated_at, job_id").WillReturnRows(sqlmock.NewRows([]string{"id", "generated_at", "job_id"}).AddRow(7, time.Now(), ""))
+	mock.ExpectQuery("SELECT category, record_type, record_id, detail").WillReturnError(errors.New("boom"))
+
+	store := PostgresQualityReportStore{DB: mockSQLDB}
+	_, err = store.FetchQualityReport(context.Background(), "")
+	require.Error(t, err)
+	require.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestFetchSyncJobReport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockSQLDB := NewMockSQLDB(ctrl)
+
+	mockdb, mock, err := sqlmock.New()
+	require.Nil(t, err)
+	defer mockdb.Close()
+	mockSQLDB.EXPECT().Conn().Return(mockdb)
+
+	generatedAt := time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC)
+	mock.ExpectQuery("SELECT id, generated_at, job_id FROM quality_reports WHERE job_id = \\$1").WithArgs("job-1").WillReturnRows(
+		sqlmock.NewRows([]string{"id", "generated_at", "job_id"}).AddRow(7, generatedAt, "job-1"))
+	mock.ExpectQuery("SELECT category, record_type, record_id, detail").WithArgs(7, "").WillReturnRows(
+		sqlmock.NewRows([]string{"category", "record_type", "record_id", "detail"}))
+	mock.ExpectQuery("SELECT record_type, record_id, reason").WithArgs(7).WillReturnRows(
+		sqlmock.NewRows([]string{"record_type", "record_id", "reason"}).AddRow(domain.QualityRecordSubnet, "3", "invalid network"))
+
+	store := PostgresQualityReportStore{DB: mockSQLDB}
+	report, err := store.FetchSyncJobReport(context.Background(), "job-1")
+	require.Nil(t, err)
+	require.Equal(t, domain.QualityReport{
+		GeneratedAt: generatedAt,
+		JobID:       "job-1",
+		Findings:    []domain.QualityFinding{},
+		Quarantined: []domain.QuarantinedRecord{{RecordType: domain.QualityRecordSubnet, RecordID: "3", Reason: "invalid network"}},
+	}, report)
+	require.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestFetchSyncJobReportNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockSQLDB := NewMockSQLDB(ctrl)
+
+	mockdb, mock, err := sqlmock.New()
+	require.Nil(t, err)
+	defer mockdb.Close()
+	mockSQLDB.EXPECT().Conn().Return(mockdb)
+
+	mock.ExpectQuery("SELECT id, generated_at, job_id").WithArgs("job-1").WillReturnRows(sqlmock.NewRows([]string{"id", "generated_at", "job_id"}))
+
+	store := PostgresQualityReportStore{DB: mockSQLDB}
+	_, err = store.FetchSyncJobReport(context.Background(), "job-1")
+	require.Equal(t, domain.SyncJobNotFound{JobID: "job-1"}, err)
+	require.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestFetchSyncJobReportQuarantinedError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockSQLDB := NewMockSQLDB(ctrl)
+
+	mockdb, mock, err := sqlmock.New()
+	require.Nil(t, err)
+	defer mockdb.Close()
+	mockSQLDB.EXPECT().Conn().Return(mockdb)
+
+	mock.ExpectQuery("SELECT id, generated_at, job_id").WithArgs("job-1").WillReturnRows(
+		sqlmock.NewRows([]string{"id", "generated_at", "job_id"}).AddRow(7, time.Now(), "job-1"))
+	mock.ExpectQuery("SELECT category, record_type, record_id, detail").WillReturnRows(
+		sqlmock.NewRows([]string{"category", "record_type", "record_id", "detail"}))
+	mock.ExpectQuery("SELECT record_type, record_id, reason").WillReturnError(errors.New("boom"))
+
+	store := PostgresQualityReportStore{DB: mockSQLDB}
+	_, err = store.FetchSyncJobReport(context.Background(), "job-1")
+	require.Error(t, err)
+	require.Nil(t, mock.ExpectationsWereMet())
+}
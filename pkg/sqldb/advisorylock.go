@@ -0,0 +1,57 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+const (
+	lockStatement    = `SELECT pg_advisory_lock($1)`
+	tryLockStatement = `SELECT pg_try_advisory_lock($1)`
+	unlockStatement  = `SELECT pg_advisory_unlock($1)`
+)
+
+// errLockHeld is returned when an advisory lock is taken without waiting while another
+// session holds it.
+var errLockHeld = errors.New("the advisory lock is held by another session")
+
+// lockAdvisory takes a session level advisory lock, waiting for it or failing with
+// errLockHeld when another session holds it. The lock belongs to the session, so a connection
+// is set aside until the returned function releases the lock and returns the connection to
+// the pool. The lock is also released by the database if the instance holding it dies.
+func lockAdvisory(ctx context.Context, db *sql.DB, id int64, wait bool) (*sql.Conn, func(), error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if wait {
+		_, err = conn.ExecContext(ctx, lockStatement, id)
+	} else {
+		var locked bool
+		err = conn.QueryRowContext(ctx, tryLockStatement, id).Scan(&locked)
+		if err == nil && !locked {
+			err = errLockHeld
+		}
+	}
+	if err != nil {
+		_ = conn.Close()
+		return nil, nil, err
+	}
+	return conn, func() {
+		// the lock is released even if the context is done, as the connection returns to the pool
+		_, _ = conn.ExecContext(context.Background(), unlockStatement, id)
+		_ = conn.Close()
+	}, nil
+}
+
+// withAdvisoryLock runs a function with a connection that holds a session level advisory
+// lock, as taken by lockAdvisory, and releases the lock once the function returns.
+func withAdvisoryLock(ctx context.Context, db *sql.DB, id int64, wait bool, f func(conn *sql.Conn) error) error {
+	conn, unlock, err := lockAdvisory(ctx, db, id, wait)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return f(conn)
+}
@@ -2,6 +2,9 @@ package sqldb
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
 	packr "github.com/gobuffalo/packr/v2"
 )
@@ -13,6 +16,24 @@ type PostgresConfig struct {
 	Username     string
 	Password     string
 	DatabaseName string
+	// SkipMigrations leaves the schema unchanged at startup, for deployments that apply
+	// migrations with the "migrate up" command before starting the service.
+	SkipMigrations bool `description:"Do not apply pending schema migrations at startup."`
+	// ReadHostname is a streaming replica of the database, with the same credentials,
+	// for lookups to use instead of the primary.
+	ReadHostname         string        `description:"Hostname of a read replica for lookups. Lookups use the primary if empty."`
+	ReadPort             string        `description:"Port of the read replica, if different from the port of the primary."`
+	MaxReplicaLag        time.Duration `description:"Lookups use the primary while the read replica lags it by more than this."`
+	ReplicaCheckInterval time.Duration `description:"How often the health and lag of the read replica are checked."`
+	SSLMode              string        `description:"One of disable, require, verify-ca, or verify-full. If empty, SSL is required for hosts other than localhost and postgres."`
+	SSLRootCert          string        `description:"Path of the CA certificate file used to verify the server, for the verify-ca and verify-full SSL modes."`
+	SSLCert              string        `description:"Path of the client certificate file, for certificate authentication."`
+	SSLKey               string        `description:"Path of the client private key file, which must not be readable by other users."`
+	ApplicationName      string        `description:"Name of the service in pg_stat_activity and the server logs."`
+	StatementTimeout     time.Duration `description:"Cancel any statement that runs for longer than this, if positive. Some connection poolers must be configured to ignore the statement_timeout startup parameter."`
+	MaxOpenConns         int           `description:"Maximum number of open connections to each of the primary and the read replica, or unlimited if zero."`
+	MaxIdleConns         int           `description:"Number of idle connections kept open to each of the primary and the read replica."`
+	ConnMaxLifetime      time.Duration `description:"Close connections once they are this old, or never if zero."`
 }
 
 // Name is used by the settings library to replace the default naming convention.
@@ -31,17 +52,64 @@ type PostgresComponent struct{}
 
 // Settings populates a set of defaults if none are provided via config.
 func (*PostgresComponent) Settings() *PostgresConfig {
-	return &PostgresConfig{}
+	return &PostgresConfig{
+		MaxReplicaLag:        30 * time.Second,
+		ReplicaCheckInterval: 5 * time.Second,
+		ApplicationName:      "ipam-facade",
+		MaxOpenConns:         10,
+		MaxIdleConns:         5,
+		ConnMaxLifetime:      30 * time.Minute,
+	}
 }
 
-// New constructs a DB from a config
+// New constructs a DB from a config, applying any pending schema migrations.
 func (*PostgresComponent) New(ctx context.Context, c *PostgresConfig) (*PostgresDB, error) {
+	if c.SSLMode != "" && !sslModes[c.SSLMode] {
+		return nil, fmt.Errorf("unknown SSL mode %q", c.SSLMode)
+	}
+	if c.MaxOpenConns < 0 || c.MaxIdleConns < 0 {
+		return nil, errors.New("connection limits must not be negative")
+	}
+	if c.MaxOpenConns > 0 && c.MaxIdleConns > c.MaxOpenConns {
+		return nil, errors.New("the idle connection limit must not exceed the open connection limit")
+	}
 	scripts := packr.New("scripts", "../../scripts")
+	migrationScripts := packr.New("migrations", "../../scripts/migrations")
+	migrations, err := LoadMigrations(migrationScripts.List(), migrationScripts.FindString)
+	if err != nil {
+		return nil, err
+	}
 	db := &PostgresDB{
-		scripts: scripts.FindString,
+		scripts:        scripts.FindString,
+		migrations:     migrations,
+		skipMigrations: c.SkipMigrations,
+		options: connectionOptions{
+			SSLMode:          c.SSLMode,
+			SSLRootCert:      c.SSLRootCert,
+			SSLCert:          c.SSLCert,
+			SSLKey:           c.SSLKey,
+			ApplicationName:  c.ApplicationName,
+			StatementTimeout: c.StatementTimeout,
+			MaxOpenConns:     c.MaxOpenConns,
+			MaxIdleConns:     c.MaxIdleConns,
+			ConnMaxLifetime:  c.ConnMaxLifetime,
+		},
 	}
 	if err := db.Init(ctx, c.Hostname, c.Port, c.Username, c.Password, c.DatabaseName); err != nil {
 		return nil, err
 	}
+	if c.ReadHostname == "" {
+		return db, nil
+	}
+	if c.ReplicaCheckInterval <= 0 {
+		return nil, errors.New("the replica check interval must be positive")
+	}
+	readPort := c.ReadPort
+	if readPort == "" {
+		readPort = c.Port
+	}
+	if err := db.InitReplica(ctx, c.ReadHostname, readPort, c.Username, c.Password, c.DatabaseName, c.MaxReplicaLag, c.ReplicaCheckInterval); err != nil {
+		return nil, err
+	}
 	return db, nil
 }
@@ -32,3 +32,24 @@ func TestShouldFailToMakeNewDB(t *testing.T) {
 	_, err := postgresComponent.New(context.Background(), &postgresConfig)
 	assert.NotNil(t, err)
 }
+
+func TestBadConnectionOptions(t *testing.T) {
+	tc := []struct {
+		name   string
+		modify func(*PostgresConfig)
+	}{
+		{name: "SSL mode", modify: func(c *PostgresConfig) { c.SSLMode = "prefer" }},
+		{name: "negative limit", modify: func(c *PostgresConfig) { c.MaxOpenConns = -1 }},
+		{name: "idle above open", modify: func(c *PostgresConfig) { c.MaxOpenConns, c.MaxIdleConns = 2, 5 }},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			postgresComponent := NewPostgresComponent()
+			postgresConfig := postgresComponent.Settings()
+			tt.modify(postgresConfig)
+			_, err := postgresComponent.New(context.Background(), postgresConfig)
+			assert.Error(t, err)
+		})
+	}
+}
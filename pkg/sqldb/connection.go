@@ -0,0 +1,94 @@
+package sqldb
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// sslModes are the sslmode values supported by the PostgreSQL driver.
+var sslModes = map[string]bool{
+	"disable":     true,
+	"require":     true,
+	"verify-ca":   true,
+	"verify-full": true,
+}
+
+// connectionOptions are the settings shared by every connection to the database: how it is
+// secured, what it is called, and how the connection pools are sized.
+type connectionOptions struct {
+	// SSLMode is one of the sslModes. When empty, SSL is required for any host other than a
+	// local or docker-compose one.
+	SSLMode     string
+	SSLRootCert string
+	SSLCert     string
+	SSLKey      string
+	// ApplicationName identifies the service in pg_stat_activity and in the server logs.
+	ApplicationName string
+	// StatementTimeout, when positive, cancels any statement that runs for longer. It is sent as
+	// a startup parameter, which some connection poolers reject unless configured to ignore it.
+	StatementTimeout time.Duration
+	// MaxOpenConns limits the connections of each pool, when positive.
+	MaxOpenConns int
+	// MaxIdleConns is the number of idle connections each pool keeps open.
+	MaxIdleConns int
+	// ConnMaxLifetime, when positive, closes connections once they are this old, so that
+	// connections are rebalanced after a failover or a change behind a load balancer.
+	ConnMaxLifetime time.Duration
+}
+
+// connectionString returns the connection string of a database on a host.
+func (o connectionOptions) connectionString(host, port, username, password, dbname string) string {
+	sslmode := o.SSLMode
+	if sslmode == "" {
+		sslmode = "disable"
+		if host != "localhost" && host != "postgres" {
+			sslmode = "require"
+		}
+	}
+	parameters := map[string]string{
+		"host":             host,
+		"port":             port,
+		"user":             username,
+		"password":         password,
+		"dbname":           dbname,
+		"sslmode":          sslmode,
+		"sslrootcert":      o.SSLRootCert,
+		"sslcert":          o.SSLCert,
+		"sslkey":           o.SSLKey,
+		"application_name": o.ApplicationName,
+	}
+	if o.StatementTimeout > 0 {
+		parameters["statement_timeout"] = fmt.Sprintf("%d", o.StatementTimeout/time.Millisecond)
+	}
+
+	keys := make([]string, 0, len(parameters))
+	for key, value := range parameters {
+		if value != "" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, key+"="+quoteParameter(parameters[key]))
+	}
+	return strings.Join(pairs, " ")
+}
+
+// quoteParameter quotes a connection string value, so that values such as passwords may
+// contain spaces and quotes.
+func quoteParameter(value string) string {
+	value = strings.Replace(value, `\`, `\\`, -1)
+	value = strings.Replace(value, `'`, `\'`, -1)
+	return "'" + value + "'"
+}
+
+// configurePool applies the pool limits to a connection pool.
+func (o connectionOptions) configurePool(pool *sql.DB) {
+	pool.SetMaxOpenConns(o.MaxOpenConns)
+	pool.SetMaxIdleConns(o.MaxIdleConns)
+	pool.SetConnMaxLifetime(o.ConnMaxLifetime)
+}
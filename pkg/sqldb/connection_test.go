@@ -0,0 +1,74 @@
+package sqldb
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	pq "github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnectionString(t *testing.T) {
+	tc := []struct {
+		name     string
+		options  connectionOptions
+		host     string
+		password string
+		expected string
+	}{
+		{
+			name:     "local host",
+			host:     "localhost",
+			password: "secret",
+			expected: "dbname='db' host='localhost' password='secret' port='5432' sslmode='disable' user='me'",
+		},
+		{
+			name:     "remote host",
+			host:     "db.example.com",
+			password: "secret",
+			expected: "dbname='db' host='db.example.com' password='secret' port='5432' sslmode='require' user='me'",
+		},
+		{
+			name:     "quoted password",
+			host:     "localhost",
+			password: `it's a \secret`,
+			expected: `dbname='db' host='localhost' password='it\'s a \\secret' port='5432' sslmode='disable' user='me'`,
+		},
+		{
+			name: "options",
+			options: connectionOptions{
+				SSLMode:          "verify-full",
+				SSLRootCert:      "/etc/ca.pem",
+				SSLCert:          "/etc/client.pem",
+				SSLKey:           "/etc/client.key",
+				ApplicationName:  "ipam-facade",
+				StatementTimeout: 1500 * time.Millisecond,
+			},
+			host:     "localhost",
+			password: "secret",
+			expected: "application_name='ipam-facade' dbname='db' host='localhost' password='secret' port='5432' " +
+				"sslcert='/etc/client.pem' sslkey='/etc/client.key' sslmode='verify-full' sslrootcert='/etc/ca.pem' " +
+				"statement_timeout='1500' user='me'",
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			connectionString := tt.options.connectionString(tt.host, "5432", "me", tt.password, "db")
+			assert.Equal(t, tt.expected, connectionString)
+			_, err := pq.NewConnector(connectionString)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestConfigurePool(t *testing.T) {
+	pool, err := sql.Open("postgres", "host=localhost")
+	require.NoError(t, err)
+	defer pool.Close()
+
+	connectionOptions{MaxOpenConns: 7, MaxIdleConns: 3, ConnMaxLifetime: time.Minute}.configurePool(pool)
+	assert.Equal(t, 7, pool.Stats().MaxOpenConnections)
+}
@@ -0,0 +1,97 @@
+package sqldb
+
+import (
+	"context"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+	"github.com/lib/pq"
+)
+
+const (
+	fetchIncrementalSyncStateQuery = `SELECT incremental_watermark, incremental_last_full FROM sync_generation`
+
+	storeIncrementalSyncStateStatement = `UPDATE sync_generation SET incremental_watermark = $1, incremental_last_full = $2`
+
+	fetchStoredSubnetsQuery = `SELECT id, host(network), masklen(network), location, COALESCE(customer_id, 0)
+						FROM subnets
+						WHERE source = $1
+						ORDER BY id`
+
+	fetchStoredIPsQuery = `SELECT host(ip), subnet_id, COALESCE(device_id, 0), record_id
+						FROM ips
+						WHERE source = $1
+						ORDER BY id`
+)
+
+// FetchIncrementalSyncState returns the state of the last stored incremental sync, which is
+// empty until one is stored, and after a rollback. The state is read with the primary
+// connection, as a sync must build on the state of the last one even when replicas lag behind.
+func (db *PostgresDB) FetchIncrementalSyncState(ctx context.Context) (domain.IncrementalSyncState, error) {
+	var watermark, lastFull pq.NullTime
+	if err := db.conn.QueryRowContext(ctx, fetchIncrementalSyncStateQuery).Scan(&watermark, &lastFull); err != nil {
+		return domain.IncrementalSyncState{}, err
+	}
+	if !lastFull.Valid {
+		return domain.IncrementalSyncState{}, nil
+	}
+	return domain.IncrementalSyncState{Watermark: watermark.Time, LastFull: lastFull.Time}, nil
+}
+
+// StoreIncrementalSyncState replaces the state of the last stored incremental sync.
+func (db *PostgresDB) StoreIncrementalSyncState(ctx context.Context, state domain.IncrementalSyncState) error {
+	_, err := db.conn.ExecContext(ctx, storeIncrementalSyncStateStatement, state.Watermark, state.LastFull)
+	return err
+}
+
+// FetchStoredSource returns the stored subnets and IPs that came from a single source, in the
+// form the source fetched them, for an incremental sync of the source to merge its changes
+// into. The records are only those of the source as fetched when it is the only one, as the
+// records of merged sources are renumbered. Records quarantined by the last sync are not
+// stored, so they are only synced again once they change in the source.
+func (db *PostgresDB) FetchStoredSource(ctx context.Context, source string) (domain.IPAMData, error) {
+	subnets, err := db.fetchStoredSubnets(ctx, source)
+	if err != nil {
+		return domain.IPAMData{}, err
+	}
+	devices, err := db.fetchStoredIPs(ctx, source)
+	if err != nil {
+		return domain.IPAMData{}, err
+	}
+	return domain.IPAMData{Subnets: subnets, Devices: devices}, nil
+}
+
+func (db *PostgresDB) fetchStoredSubnets(ctx context.Context, source string) ([]domain.Subnet, error) {
+	rows, err := db.conn.QueryContext(ctx, fetchStoredSubnetsQuery, source)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	subnets := make([]domain.Subnet, 0)
+	for rows.Next() {
+		var subnet domain.Subnet
+		if err := rows.Scan(&subnet.ID, &subnet.Network, &subnet.MaskBits, &subnet.Location, &subnet.CustomerID); err != nil {
+			return nil, err
+		}
+		subnets = append(subnets, subnet)
+	}
+	return subnets, rows.Err()
+}
+
+func (db *PostgresDB) fetchStoredIPs(ctx context.Context, source string) ([]domain.Device, error) {
+	rows, err := db.conn.QueryContext(ctx, fetchStoredIPsQuery, source)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	devices := make([]domain.Device, 0)
+	for rows.Next() {
+		var device domain.Device
+		if err := rows.Scan(&device.IP, &device.SubnetID, &device.ID, &device.RecordID); err != nil {
+			return nil, err
+		}
+		devices = append(devices, device)
+	}
+	return devices, rows.Err()
+}
@@ -0,0 +1,107 @@
+package sqldb
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchIncrementalSyncState(t *testing.T) {
+	watermark := time.Date(2024, 3, 1, 12, 55, 0, 0, time.UTC)
+	lastFull := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	tc := []struct {
+		name     string
+		rows     *sqlmock.Rows
+		expected domain.IncrementalSyncState
+	}{
+		{
+			name:     "stored",
+			rows:     sqlmock.NewRows([]string{"incremental_watermark", "incremental_last_full"}).AddRow(watermark, lastFull),
+			expected: domain.IncrementalSyncState{Watermark: watermark, LastFull: lastFull},
+		},
+		{
+			name: "empty",
+			rows: sqlmock.NewRows([]string{"incremental_watermark", "incremental_last_full"}).AddRow(nil, nil),
+		},
+	}
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			mockdb, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer mockdb.Close()
+
+			mock.ExpectQuery(regexp.QuoteMeta(fetchIncrementalSyncStateQuery)).WillReturnRows(tt.rows)
+
+			thedb := PostgresDB{conn: mockdb}
+			state, err := thedb.FetchIncrementalSyncState(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, state)
+			require.Nil(t, mock.ExpectationsWereMet(), "there were unfulfilled expectations: %s", mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestStoreIncrementalSyncState(t *testing.T) {
+	mockdb, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockdb.Close()
+
+	watermark := time.Date(2024, 3, 1, 12, 55, 0, 0, time.UTC)
+	lastFull := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	mock.ExpectExec(regexp.QuoteMeta(storeIncrementalSyncStateStatement)).WithArgs(watermark, lastFull).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	thedb := PostgresDB{conn: mockdb}
+	require.NoError(t, thedb.StoreIncrementalSyncState(context.Background(), domain.IncrementalSyncState{Watermark: watermark, LastFull: lastFull}))
+	require.Nil(t, mock.ExpectationsWereMet(), "there were unfulfilled expectations: %s", mock.ExpectationsWereMet())
+}
+
+func TestFetchStoredSource(t *testing.T) {
+	mockdb, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockdb.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(fetchStoredSubnetsQuery)).WithArgs("device42").WillReturnRows(
+		sqlmock.NewRows([]string{"id", "host", "masklen", "location", "customer_id"}).
+			AddRow(1, "10.0.0.0", 24, "Austin", 3).
+			AddRow(2, "2001:db8::1", 128, "", 0))
+	mock.ExpectQuery(regexp.QuoteMeta(fetchStoredIPsQuery)).WithArgs("device42").WillReturnRows(
+		sqlmock.NewRows([]string{"host", "subnet_id", "device_id", "record_id"}).
+			AddRow("10.0.0.1", 1, 9, "4").
+			AddRow("2001:db8::1", 2, 0, ""))
+
+	thedb := PostgresDB{conn: mockdb}
+	ipamData, err := thedb.FetchStoredSource(context.Background(), "device42")
+	require.NoError(t, err)
+	require.Equal(t, domain.IPAMData{
+		Subnets: []domain.Subnet{
+			{ID: "1", Network: "10.0.0.0", MaskBits: 24, Location: "Austin", CustomerID: "3"},
+			{ID: "2", Network: "2001:db8::1", MaskBits: 128, CustomerID: "0"},
+		},
+		Devices: []domain.Device{
+			{ID: "9", IP: "10.0.0.1", SubnetID: "1", RecordID: "4"},
+			{ID: "0", IP: "2001:db8::1", SubnetID: "2"},
+		},
+	}, ipamData)
+	require.Nil(t, mock.ExpectationsWereMet(), "there were unfulfilled expectations: %s", mock.ExpectationsWereMet())
+}
+
+func TestFetchStoredSourceError(t *testing.T) {
+	mockdb, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockdb.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(fetchStoredSubnetsQuery)).WithArgs("device42").WillReturnRows(
+		sqlmock.NewRows([]string{"id", "host", "masklen", "location", "customer_id"}))
+	mock.ExpectQuery(regexp.QuoteMeta(fetchStoredIPsQuery)).WithArgs("device42").WillReturnError(errors.New("column \"record_id\" does not exist"))
+
+	thedb := PostgresDB{conn: mockdb}
+	_, err = thedb.FetchStoredSource(context.Background(), "device42")
+	require.Error(t, err)
+	require.Nil(t, mock.ExpectationsWereMet(), "there were unfulfilled expectations: %s", mock.ExpectationsWereMet())
+}
@@ -0,0 +1,223 @@
+package sqldb
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// migrationLockID is the key of the session level advisory lock held while migrating, so
+	// that instances starting at the same time apply each migration only once.
+	migrationLockID = 5102040100
+
+	createMigrationsTableStatement = `CREATE TABLE
+IF NOT EXISTS schema_migrations
+(
+    version INTEGER PRIMARY KEY,
+    name TEXT NOT NULL,
+    checksum TEXT NOT NULL,
+    applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);`
+	insertMigrationStatement = `INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)`
+	// dropPreviousGenerationStatement drops the previous generation of asset tables kept by
+	// swap syncs for rollbacks. Migrations only change the live tables, so the previous
+	// generation would be restored without their changes.
+	dropPreviousGenerationStatement = `DROP TABLE IF EXISTS ips_previous, subnets_previous, customer_contacts_previous, customers_previous;`
+)
+
+const fetchMigrationsQuery = `SELECT version, name, checksum, applied_at
+						FROM schema_migrations
+						ORDER BY version;`
+
+// migrationName is the form of a migration file name: a version number, which orders the
+// migrations, and a description.
+var migrationName = regexp.MustCompile(`^(\d+)_(\w+)\.sql$`)
+
+// Migration is a versioned change to the database schema. Migrations are applied in the order
+// of their versions, and each one is applied once. Once applied, a migration must not be
+// changed; its checksum is recorded so that changes are detected.
+type Migration struct {
+	Version  int
+	Name     string
+	Checksum string
+	Script   string
+}
+
+// MigrationStatus is the state of a migration in a database. A migration that has been applied
+// but is not known to this version of the service, such as one added by a newer version, is
+// Unknown and has no Script.
+type MigrationStatus struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+	Modified  bool
+	Unknown   bool
+}
+
+// LoadMigrations reads the migrations among the named scripts. Scripts that are not SQL files
+// are ignored, and SQL files must be named like 0001_create_schema.sql, with a unique version.
+func LoadMigrations(names []string, scripts func(name string) (string, error)) ([]Migration, error) {
+	migrations := make([]Migration, 0, len(names))
+	versions := make(map[int]string, len(names))
+	for _, name := range names {
+		if !strings.HasSuffix(name, ".sql") {
+			continue
+		}
+		match := migrationName.FindStringSubmatch(name)
+		if match == nil {
+			return nil, fmt.Errorf("migration %s is not named like 0001_description.sql", name)
+		}
+		version, err := strconv.Atoi(match[1])
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: %v", name, err)
+		}
+		if other, ok := versions[version]; ok {
+			return nil, fmt.Errorf("migrations %s and %s have the same version", other, name)
+		}
+		versions[version] = name
+		script, err := scripts(name)
+		if err != nil {
+			return nil, err
+		}
+		migrations = append(migrations, Migration{
+			Version:  version,
+			Name:     match[2],
+			Checksum: checksum(script),
+			Script:   script,
+		})
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+func checksum(script string) string {
+	sum := sha256.Sum256([]byte(script))
+	return hex.EncodeToString(sum[:])
+}
+
+// Migrate applies the migrations that have not yet been applied, in order, each in its own
+// transaction, and returns them. It fails without applying any migration if an applied
+// migration has been modified. Migrations applied by a newer version of the service are
+// ignored, so that older instances can still start during a rolling deployment.
+func (db *PostgresDB) Migrate(ctx context.Context) ([]Migration, error) {
+	applied := make([]Migration, 0)
+	err := db.withMigrationLock(ctx, func(conn *sql.Conn) error {
+		statuses, err := db.migrationStatus(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			if status.Modified {
+				return fmt.Errorf("migration %04d_%s has been modified since it was applied", status.Version, status.Name)
+			}
+		}
+		for _, status := range statuses {
+			if status.Applied {
+				continue
+			}
+			if err := applyMigration(ctx, conn, status.Migration); err != nil {
+				return fmt.Errorf("migration %04d_%s failed: %v", status.Version, status.Name, err)
+			}
+			applied = append(applied, status.Migration)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return applied, nil
+}
+
+// MigrationStatus returns the state of every known and every applied migration, in order.
+func (db *PostgresDB) MigrationStatus(ctx context.Context) ([]MigrationStatus, error) {
+	var statuses []MigrationStatus
+	err := db.withMigrationLock(ctx, func(conn *sql.Conn) error {
+		var err error
+		statuses, err = db.migrationStatus(ctx, conn)
+		return err
+	})
+	return statuses, err
+}
+
+// withMigrationLock runs a function with a connection that holds the migration lock, waiting
+// for it, and creates the schema_migrations table if it does not exist.
+func (db *PostgresDB) withMigrationLock(ctx context.Context, f func(conn *sql.Conn) error) error {
+	return withAdvisoryLock(ctx, db.conn, migrationLockID, true, func(conn *sql.Conn) error {
+		if _, err := conn.ExecContext(ctx, createMigrationsTableStatement); err != nil {
+			return err
+		}
+		return f(conn)
+	})
+}
+
+func (db *PostgresDB) migrationStatus(ctx context.Context, conn *sql.Conn) ([]MigrationStatus, error) {
+	rows, err := conn.QueryContext(ctx, fetchMigrationsQuery)
+	if err != nil {
+		return nil, err
+	}
+	applied := make(map[int]MigrationStatus)
+	for rows.Next() {
+		var status MigrationStatus
+		if err := rows.Scan(&status.Version, &status.Name, &status.Checksum, &status.AppliedAt); err != nil {
+			_ = rows.Close()
+			return nil, err
+		}
+		status.Applied = true
+		applied[status.Version] = status
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(db.migrations)+len(applied))
+	for _, migration := range db.migrations {
+		status := MigrationStatus{Migration: migration}
+		if recorded, ok := applied[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = recorded.AppliedAt
+			status.Modified = recorded.Checksum != migration.Checksum
+			delete(applied, migration.Version)
+		}
+		statuses = append(statuses, status)
+	}
+	for _, status := range applied {
+		status.Unknown = true
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses, nil
+}
+
+// applyMigration runs a migration and records it in a single transaction, so that a failed
+// migration leaves no trace and is tried again the next time. The previous generation of
+// asset tables is dropped in the same transaction, so that a rollback never restores tables
+// that predate the migration.
+func applyMigration(ctx context.Context, conn *sql.Conn, migration Migration) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	for _, statement := range []string{migration.Script, dropPreviousGenerationStatement} {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				return fmt.Errorf("failed to rollback from %s because of %s", err.Error(), rbErr.Error())
+			}
+			return err
+		}
+	}
+	if _, err := tx.ExecContext(ctx, insertMigrationStatement, migration.Version, migration.Name, migration.Checksum); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("failed to rollback from %s because of %s", err.Error(), rbErr.Error())
+		}
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,178 @@
+package sqldb
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	packr "github.com/gobuffalo/packr/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testMigrations = []Migration{
+	{Version: 1, Name: "create_schema", Checksum: checksum("CREATE TABLE one"), Script: "CREATE TABLE one"},
+	{Version: 2, Name: "add_index", Checksum: checksum("CREATE INDEX two"), Script: "CREATE INDEX two"},
+}
+
+var migrationColumns = []string{"version", "name", "checksum", "applied_at"}
+
+func expectMigrationLock(mock sqlmock.Sqlmock) {
+	mock.ExpectExec(regexp.QuoteMeta(lockStatement)).WithArgs(migrationLockID).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("CREATE TABLE\\s+IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func expectMigrationUnlock(mock sqlmock.Sqlmock) {
+	mock.ExpectExec(regexp.QuoteMeta(unlockStatement)).WithArgs(migrationLockID).WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func TestLoadMigrations(t *testing.T) {
+	scripts := map[string]string{
+		"0002_add_index.sql":     "CREATE INDEX two",
+		"0001_create_schema.sql": "CREATE TABLE one",
+		"README.md":              "not a migration",
+	}
+	find := func(name string) (string, error) { return scripts[name], nil }
+
+	migrations, err := LoadMigrations([]string{"0002_add_index.sql", "README.md", "0001_create_schema.sql"}, find)
+	require.NoError(t, err)
+	assert.Equal(t, testMigrations, migrations)
+
+	_, err = LoadMigrations([]string{"create_schema.sql"}, find)
+	assert.Error(t, err)
+	_, err = LoadMigrations([]string{"0001_create_schema.sql", "1_add_index.sql"}, find)
+	assert.Error(t, err)
+	_, err = LoadMigrations([]string{"0001_create_schema.sql"}, func(string) (string, error) { return "", errors.New("not found") })
+	assert.Error(t, err)
+}
+
+func TestLoadEmbeddedMigrations(t *testing.T) {
+	box := packr.New("migrations", "../../scripts/migrations")
+	migrations, err := LoadMigrations(box.List(), box.FindString)
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+	for i, migration := range migrations {
+		assert.Equal(t, i+1, migration.Version, "migration versions should be consecutive")
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	mockdb, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockdb.Close()
+
+	expectMigrationLock(mock)
+	mock.ExpectQuery("SELECT version, name, checksum, applied_at").WillReturnRows(
+		sqlmock.NewRows(migrationColumns).AddRow(1, "create_schema", testMigrations[0].Checksum, time.Now()))
+	mock.ExpectBegin()
+	mock.ExpectExec("CREATE INDEX two").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(dropPreviousGenerationStatement)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO schema_migrations").WithArgs(2, "add_index", testMigrations[1].Checksum).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	expectMigrationUnlock(mock)
+
+	thedb := PostgresDB{conn: mockdb, migrations: testMigrations}
+	applied, err := thedb.Migrate(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, testMigrations[1:], applied)
+	require.Nil(t, mock.ExpectationsWereMet(), "there were unfulfilled expectations: %s", mock.ExpectationsWereMet())
+}
+
+func TestMigrateUpToDate(t *testing.T) {
+	mockdb, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockdb.Close()
+
+	expectMigrationLock(mock)
+	mock.ExpectQuery("SELECT version, name, checksum, applied_at").WillReturnRows(
+		sqlmock.NewRows(migrationColumns).
+			AddRow(1, "create_schema", testMigrations[0].Checksum, time.Now()).
+			AddRow(2, "add_index", testMigrations[1].Checksum, time.Now()).
+			AddRow(3, "from_a_newer_version", "abc", time.Now()))
+	expectMigrationUnlock(mock)
+
+	thedb := PostgresDB{conn: mockdb, migrations: testMigrations}
+	applied, err := thedb.Migrate(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+	require.Nil(t, mock.ExpectationsWereMet(), "there were unfulfilled expectations: %s", mock.ExpectationsWereMet())
+}
+
+func TestMigrateModified(t *testing.T) {
+	mockdb, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockdb.Close()
+
+	expectMigrationLock(mock)
+	mock.ExpectQuery("SELECT version, name, checksum, applied_at").WillReturnRows(
+		sqlmock.NewRows(migrationColumns).AddRow(1, "create_schema", "abc", time.Now()))
+	expectMigrationUnlock(mock)
+
+	thedb := PostgresDB{conn: mockdb, migrations: testMigrations}
+	_, err = thedb.Migrate(context.Background())
+	require.Error(t, err)
+	require.Nil(t, mock.ExpectationsWereMet(), "there were unfulfilled expectations: %s", mock.ExpectationsWereMet())
+}
+
+func TestMigrateFailure(t *testing.T) {
+	mockdb, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockdb.Close()
+
+	expectMigrationLock(mock)
+	mock.ExpectQuery("SELECT version, name, checksum, applied_at").WillReturnRows(sqlmock.NewRows(migrationColumns))
+	mock.ExpectBegin()
+	mock.ExpectExec("CREATE TABLE one").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(dropPreviousGenerationStatement)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO schema_migrations").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec("CREATE INDEX two").WillReturnError(errors.New("bad migration"))
+	mock.ExpectRollback()
+	expectMigrationUnlock(mock)
+
+	thedb := PostgresDB{conn: mockdb, migrations: testMigrations}
+	_, err = thedb.Migrate(context.Background())
+	require.Error(t, err)
+	require.Nil(t, mock.ExpectationsWereMet(), "there were unfulfilled expectations: %s", mock.ExpectationsWereMet())
+}
+
+func TestMigrateLockFailure(t *testing.T) {
+	mockdb, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockdb.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(lockStatement)).WillReturnError(errors.New("no lock"))
+
+	thedb := PostgresDB{conn: mockdb, migrations: testMigrations}
+	_, err = thedb.Migrate(context.Background())
+	require.Error(t, err)
+	require.Nil(t, mock.ExpectationsWereMet(), "there were unfulfilled expectations: %s", mock.ExpectationsWereMet())
+}
+
+func TestMigrationStatus(t *testing.T) {
+	mockdb, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockdb.Close()
+
+	appliedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	expectMigrationLock(mock)
+	mock.ExpectQuery("SELECT version, name, checksum, applied_at").WillReturnRows(
+		sqlmock.NewRows(migrationColumns).
+			AddRow(1, "create_schema", "abc", appliedAt).
+			AddRow(3, "from_a_newer_version", "def", appliedAt))
+	expectMigrationUnlock(mock)
+
+	thedb := PostgresDB{conn: mockdb, migrations: testMigrations}
+	statuses, err := thedb.MigrationStatus(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []MigrationStatus{
+		{Migration: testMigrations[0], Applied: true, AppliedAt: appliedAt, Modified: true},
+		{Migration: testMigrations[1]},
+		{Migration: Migration{Version: 3, Name: "from_a_newer_version", Checksum: "def"}, Applied: true, AppliedAt: appliedAt, Unknown: true},
+	}, statuses)
+	require.Nil(t, mock.ExpectationsWereMet(), "there were unfulfilled expectations: %s", mock.ExpectationsWereMet())
+}
@@ -5,20 +5,27 @@ import (
 	"database/sql"
 	"fmt"
 	"sync"
+	"time"
 
 	pq "github.com/lib/pq"
 )
 
 const (
-	createScript             = "2_create.sql"
 	pqUniqueViolationErrCode = "23505"
 )
 
 // PostgresDB is a SQLDB implementation that uses a PostgreSQL database connection pool.
+// Init applies the pending schema migrations unless skipMigrations is set. Reads are sent to
+// a separate pool for a replica when one is initialized with InitReplica. Every connection
+// and pool is configured by the options.
 type PostgresDB struct {
-	conn    *sql.DB
-	scripts func(name string) (string, error)
-	once    sync.Once
+	conn           *sql.DB
+	replica        *replica
+	options        connectionOptions
+	scripts        func(name string) (string, error)
+	migrations     []Migration
+	skipMigrations bool
+	once           sync.Once
 }
 
 // RunScript executes a SQL script from disk against the database.
@@ -46,20 +53,15 @@ func (db *PostgresDB) Init(ctx context.Context, host, port, username, password,
 	db.once.Do(func() {
 
 		if db.conn == nil {
-			sslmode := "disable"
-			if host != "localhost" && host != "postgres" {
-				sslmode = "require"
-			}
 			// we establish a connection against a known-to-exist dbname so we can check
 			// if we need to create our desired dbname
-			psqlInfo := fmt.Sprintf("host=%s port=%s user=%s "+
-				"password=%s dbname=%s sslmode=%s",
-				host, port, username, password, "postgres", sslmode)
+			psqlInfo := db.options.connectionString(host, port, username, password, "postgres")
 			pgdb, err := sql.Open("postgres", psqlInfo)
 			if err != nil {
 				initerr = err
 				return // from the unnamed once.Do function
 			}
+			db.options.configurePool(pgdb)
 
 			db.conn = pgdb
 
@@ -88,9 +90,7 @@ func (db *PostgresDB) Init(ctx context.Context, host, port, username, password,
 				}
 			}
 
-			psqlInfo = fmt.Sprintf("host=%s port=%s user=%s "+
-				"password=%s dbname=%s sslmode=%s",
-				host, port, username, password, dbname, sslmode)
+			psqlInfo = db.options.connectionString(host, port, username, password, dbname)
 			err = db.Use(ctx, psqlInfo)
 			if err != nil {
 				initerr = err
@@ -98,31 +98,52 @@ func (db *PostgresDB) Init(ctx context.Context, host, port, username, password,
 			}
 
 		}
-		err := db.RunScript(ctx, createScript)
-		if err != nil {
-			pqErr, ok := err.(*pq.Error)
-			if ok {
-				// The same race condition for creating the database exists for
-				// concurrently creating the tables, in which case the error
-				// is ignored.
-				if pqErr.Code != pqUniqueViolationErrCode {
-					initerr = pqErr
-					return
-				}
-			} else {
-				initerr = err
-				return // from the unnamed once.Do function
-			}
+		if db.skipMigrations {
+			return // from the unnamed once.Do function
+		}
+		// concurrent instances are serialized by the advisory lock taken while migrating
+		if _, err := db.Migrate(ctx); err != nil {
+			initerr = err
+			return // from the unnamed once.Do function
 		}
 	})
 	return initerr
 }
 
+// InitReplica opens a connection pool to a read replica of the database, to be returned by
+// ReadConn. The replica is not required to be available, as reads fall back to the primary
+// until it is healthy: reachable, and lagging the primary by no more than maxLag. Its health
+// is checked once before returning, then at most once per checkInterval.
+func (db *PostgresDB) InitReplica(ctx context.Context, host, port, username, password, dbname string, maxLag, checkInterval time.Duration) error {
+	pgdb, err := sql.Open("postgres", db.options.connectionString(host, port, username, password, dbname))
+	if err != nil {
+		return err
+	}
+	db.options.configurePool(pgdb)
+	db.replica = &replica{
+		conn:          pgdb,
+		maxLag:        maxLag,
+		checkInterval: checkInterval,
+		now:           time.Now,
+	}
+	db.replica.check(ctx)
+	return nil
+}
+
 // Conn returns the currently initialized and open DB connection if one exists, or nil
 func (db *PostgresDB) Conn() *sql.DB {
 	return db.conn
 }
 
+// ReadConn returns the connection pool of the read replica while it is healthy, and the
+// connection pool of the primary otherwise.
+func (db *PostgresDB) ReadConn() *sql.DB {
+	if db.replica != nil && db.replica.usable() {
+		return db.replica.conn
+	}
+	return db.conn
+}
+
 // Use closes any existing database connection, then opens, pings, and sets a new one
 // based on the connection string provided in format:
 // "host=%s port=%s user=%s password=%s dbname=%s sslmode=%s"
@@ -136,6 +157,7 @@ func (db *PostgresDB) Use(ctx context.Context, psqlInfo string) error {
 	if err != nil {
 		return err
 	}
+	db.options.configurePool(pgdb)
 
 	err = pgdb.Ping()
 	if err != nil {
@@ -0,0 +1,68 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+)
+
+// replicaLagQuery returns whether a replica is streaming from its primary, and how far, in
+// seconds, it is behind. A replica whose WAL receiver is not streaming, because it lost its
+// connection to the primary, cannot tell how far behind it is, as it has replayed everything
+// it received. A replica that is streaming and has replayed everything it received is not
+// behind, even if the last transaction it replayed is old because the primary has been idle.
+// A server that is not in recovery is not a replica, and is never behind. The status of the
+// WAL receiver is only visible to roles with the privileges of pg_read_all_stats.
+const replicaLagQuery = `SELECT NOT pg_is_in_recovery() OR EXISTS (
+							SELECT 1 FROM pg_stat_wal_receiver WHERE status = 'streaming'
+						),
+						CASE
+							WHEN NOT pg_is_in_recovery() THEN 0
+							WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+							ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+						END;`
+
+// replica is a read-only connection pool whose health is checked at most once per
+// checkInterval. A replica is healthy when the lag query succeeds and reports that it is
+// streaming with a lag of no more than maxLag.
+type replica struct {
+	conn          *sql.DB
+	maxLag        time.Duration
+	checkInterval time.Duration
+	now           func() time.Time
+
+	lock      sync.Mutex
+	healthy   bool
+	checkedAt time.Time
+	checking  bool
+}
+
+// usable reports whether the replica was healthy when last checked. When the last check is
+// older than checkInterval, another one is started in the background, so that lookups never
+// wait on it.
+func (r *replica) usable() bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if !r.checking && r.now().Sub(r.checkedAt) >= r.checkInterval {
+		r.checking = true
+		go r.check(context.Background())
+	}
+	return r.healthy
+}
+
+// check queries the lag of the replica and records whether it is healthy.
+func (r *replica) check(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, r.checkInterval)
+	defer cancel()
+	var streaming bool
+	var lag float64
+	err := r.conn.QueryRowContext(ctx, replicaLagQuery).Scan(&streaming, &lag)
+	healthy := err == nil && streaming && time.Duration(lag*float64(time.Second)) <= r.maxLag
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.healthy = healthy
+	r.checkedAt = r.now()
+	r.checking = false
+}
@@ -0,0 +1,87 @@
+package sqldb
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplicaCheck(t *testing.T) {
+	tc := []struct {
+		name         string
+		disconnected bool
+		lag          float64
+		err          error
+		expected     bool
+	}{
+		{name: "in sync", lag: 0, expected: true},
+		{name: "within the maximum lag", lag: 29.5, expected: true},
+		{name: "beyond the maximum lag", lag: 31, expected: false},
+		{name: "disconnected from the primary", disconnected: true, lag: 0, expected: false},
+		{name: "unreachable", err: errors.New("connection refused"), expected: false},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			mockdb, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer mockdb.Close()
+
+			query := mock.ExpectQuery("SELECT NOT pg_is_in_recovery()")
+			if tt.err != nil {
+				query.WillReturnError(tt.err)
+			} else {
+				query.WillReturnRows(sqlmock.NewRows([]string{"streaming", "lag"}).AddRow(!tt.disconnected, tt.lag))
+			}
+
+			now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+			r := &replica{conn: mockdb, maxLag: 30 * time.Second, checkInterval: time.Second, now: func() time.Time { return now }}
+			r.check(context.Background())
+			assert.Equal(t, tt.expected, r.healthy)
+			assert.Equal(t, now, r.checkedAt)
+			require.Nil(t, mock.ExpectationsWereMet(), "there were unfulfilled expectations: %s", mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestReadConn(t *testing.T) {
+	primary, _, err := sqlmock.New()
+	require.NoError(t, err)
+	defer primary.Close()
+	replicaDB, _, err := sqlmock.New()
+	require.NoError(t, err)
+	defer replicaDB.Close()
+
+	now := time.Now()
+	thedb := PostgresDB{conn: primary}
+	assert.Equal(t, primary, thedb.ReadConn())
+
+	thedb.replica = &replica{conn: replicaDB, checkInterval: time.Minute, now: func() time.Time { return now }, checkedAt: now, healthy: true}
+	assert.Equal(t, replicaDB, thedb.ReadConn())
+
+	thedb.replica.healthy = false
+	assert.Equal(t, primary, thedb.ReadConn())
+}
+
+func TestReplicaRecheck(t *testing.T) {
+	mockdb, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockdb.Close()
+	mock.ExpectQuery("SELECT NOT pg_is_in_recovery()").WillReturnRows(sqlmock.NewRows([]string{"streaming", "lag"}).AddRow(true, 0))
+
+	now := time.Now()
+	r := &replica{conn: mockdb, maxLag: time.Second, checkInterval: time.Minute, now: func() time.Time { return now }, checkedAt: now.Add(-time.Hour)}
+
+	// a stale result is returned while the replica is checked again in the background
+	assert.False(t, r.usable())
+	for i := 0; i < 100 && !r.usable(); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.True(t, r.usable())
+	require.Nil(t, mock.ExpectationsWereMet(), "there were unfulfilled expectations: %s", mock.ExpectationsWereMet())
+}
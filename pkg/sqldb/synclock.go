@@ -0,0 +1,25 @@
+package sqldb
+
+import (
+	"context"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+)
+
+// syncLockID is the key of the session level advisory lock held for the whole of a sync, and
+// for a rollback, so that instances do not change the stored physical assets at the same time.
+const syncLockID = 5102040102
+
+// LockSync takes the sync lock without waiting for it, returning domain.SyncInProgress when
+// another session holds it. A connection is set aside until the returned function releases
+// the lock.
+func (db *PostgresDB) LockSync(ctx context.Context) (func(), error) {
+	_, unlock, err := lockAdvisory(ctx, db.conn, syncLockID, false)
+	if err == errLockHeld {
+		return nil, domain.SyncInProgress{}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return unlock, nil
+}
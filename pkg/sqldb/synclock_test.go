@@ -0,0 +1,53 @@
+package sqldb
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLockSync(t *testing.T) {
+	tc := []struct {
+		name     string
+		locked   bool
+		queryErr error
+		err      error
+	}{
+		{name: "acquired", locked: true},
+		{name: "in progress", locked: false, err: domain.SyncInProgress{}},
+		{name: "query error", queryErr: errors.New("connection reset"), err: errors.New("connection reset")},
+	}
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			mockdb, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer mockdb.Close()
+
+			query := mock.ExpectQuery(regexp.QuoteMeta(tryLockStatement)).WithArgs(syncLockID)
+			if tt.queryErr != nil {
+				query.WillReturnError(tt.queryErr)
+			} else {
+				query.WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_lock"}).AddRow(tt.locked))
+			}
+			if tt.locked {
+				mock.ExpectExec(regexp.QuoteMeta(unlockStatement)).WithArgs(syncLockID).WillReturnResult(sqlmock.NewResult(0, 0))
+			}
+
+			thedb := PostgresDB{conn: mockdb}
+			unlock, err := thedb.LockSync(context.Background())
+			if tt.err != nil {
+				require.Equal(t, tt.err, err)
+				require.Nil(t, unlock)
+			} else {
+				require.NoError(t, err)
+				unlock()
+			}
+			require.Nil(t, mock.ExpectationsWereMet(), "there were unfulfilled expectations: %s", mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,59 @@
+// Package subnetassign links IP addresses to the subnets that contain them, for the IPAM data
+// sources that do not link them.
+package subnetassign
+
+import (
+	"fmt"
+	"net"
+	"sort"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+)
+
+// AssignSubnets sets the SubnetID of each device to the most specific subnet containing its
+// IP address, for IPAM data sources that do not link IP addresses to subnets. Devices outside
+// of every subnet are left without one. Subnets are indexed by network and prefix length so
+// that each device takes at most one lookup per distinct prefix length rather than a scan
+// of every subnet.
+func AssignSubnets(devices []domain.Device, subnets []domain.Subnet) {
+	index := make(map[string]string, len(subnets))
+	lengths := make(map[int]bool)
+	for _, subnet := range subnets {
+		_, network, err := net.ParseCIDR(fmt.Sprintf("%s/%d", subnet.Network, subnet.MaskBits))
+		if err != nil {
+			continue
+		}
+		ones, bits := network.Mask.Size()
+		index[networkKey(network.IP, ones, bits)] = subnet.ID
+		lengths[ones] = true
+	}
+	sortedLengths := make([]int, 0, len(lengths))
+	for ones := range lengths {
+		sortedLengths = append(sortedLengths, ones)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(sortedLengths)))
+
+	for i := range devices {
+		ip := net.ParseIP(devices[i].IP)
+		if ip == nil {
+			continue
+		}
+		bits := net.IPv6len * 8
+		if ip4 := ip.To4(); ip4 != nil {
+			ip, bits = ip4, net.IPv4len*8
+		}
+		for _, ones := range sortedLengths {
+			if ones > bits {
+				continue
+			}
+			if id, ok := index[networkKey(ip.Mask(net.CIDRMask(ones, bits)), ones, bits)]; ok {
+				devices[i].SubnetID = id
+				break
+			}
+		}
+	}
+}
+
+func networkKey(ip net.IP, ones int, bits int) string {
+	return fmt.Sprintf("%s/%d/%d", ip, ones, bits)
+}
@@ -0,0 +1,39 @@
+package subnetassign
+
+import (
+	"testing"
+
+	"github.com/asecurityteam/ipam-facade/pkg/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssignSubnets(t *testing.T) {
+	subnets := []domain.Subnet{
+		{ID: "1", Network: "10.0.0.0", MaskBits: 8},
+		{ID: "2", Network: "10.1.0.0", MaskBits: 16},
+		{ID: "3", Network: "10.1.2.0", MaskBits: 24},
+		{ID: "4", Network: "2001:db8::", MaskBits: 32},
+		{ID: "5", Network: "0.0.0.0", MaskBits: 0},
+		{ID: "6", Network: "garbage", MaskBits: -1},
+	}
+	devices := []domain.Device{
+		{IP: "10.1.2.3"},
+		{IP: "10.1.3.3"},
+		{IP: "10.2.0.1"},
+		{IP: "2001:db8::1"},
+		{IP: "2001:db9::1"},
+		{IP: "192.168.0.1"},
+		{IP: "not-an-ip"},
+	}
+
+	AssignSubnets(devices, subnets)
+	assert.Equal(t, []domain.Device{
+		{IP: "10.1.2.3", SubnetID: "3"},
+		{IP: "10.1.3.3", SubnetID: "2"},
+		{IP: "10.2.0.1", SubnetID: "1"},
+		{IP: "2001:db8::1", SubnetID: "4"},
+		{IP: "2001:db9::1"},
+		{IP: "192.168.0.1", SubnetID: "5"},
+		{IP: "not-an-ip"},
+	}, devices)
+}
@@ -0,0 +1,71 @@
+CREATE TABLE
+IF NOT EXISTS customers
+(
+    id INTEGER PRIMARY KEY,
+    resource_owner TEXT NOT NULL,
+    business_unit TEXT NOT NULL
+);
+
+ALTER TABLE customers
+ADD COLUMN IF NOT EXISTS owner_rule TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE
+IF NOT EXISTS subnets
+(
+    id INTEGER PRIMARY KEY,
+    network CIDR NOT NULL,
+    location TEXT NOT NULL,
+    -- intentionally nullable:
+    customer_id INTEGER,
+    FOREIGN KEY (customer_id) REFERENCES customers (id) ON DELETE CASCADE
+);
+
+CREATE TABLE
+IF NOT EXISTS ips
+(
+    id SERIAL PRIMARY KEY,
+    ip INET NOT NULL,
+    subnet_id INTEGER NOT NULL,
+    FOREIGN KEY (subnet_id) REFERENCES subnets (id) ON DELETE CASCADE,
+    device_id INTEGER
+);
+
+ALTER TABLE customers
+ADD COLUMN IF NOT EXISTS source TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE subnets
+ADD COLUMN IF NOT EXISTS source TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE ips
+ADD COLUMN IF NOT EXISTS source TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE
+IF NOT EXISTS customer_contacts
+(
+    id SERIAL PRIMARY KEY,
+    customer_id INTEGER NOT NULL,
+    FOREIGN KEY (customer_id) REFERENCES customers (id) ON DELETE CASCADE,
+    type TEXT NOT NULL,
+    name TEXT NOT NULL,
+    email TEXT NOT NULL,
+    phone TEXT NOT NULL
+);
+
+CREATE TABLE
+IF NOT EXISTS quality_reports
+(
+    id SERIAL PRIMARY KEY,
+    generated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE
+IF NOT EXISTS quality_findings
+(
+    id SERIAL PRIMARY KEY,
+    report_id INTEGER NOT NULL,
+    FOREIGN KEY (report_id) REFERENCES quality_reports (id) ON DELETE CASCADE,
+    category TEXT NOT NULL,
+    record_type TEXT NOT NULL,
+    record_id TEXT NOT NULL,
+    detail TEXT NOT NULL
+);
@@ -0,0 +1,23 @@
+-- lookups find the subnets that contain an address with the >>= and >> operators,
+-- which a GiST index with the inet_ops operator class supports
+CREATE INDEX
+IF NOT EXISTS subnets_network_idx
+ON subnets USING gist (network inet_ops);
+
+-- lookups match the IP address itself within the subnets that contain it
+CREATE INDEX
+IF NOT EXISTS ips_ip_idx
+ON ips (ip);
+
+-- contacts are fetched by customer, and the foreign keys cascade deletes by these columns
+CREATE INDEX
+IF NOT EXISTS customer_contacts_customer_id_idx
+ON customer_contacts (customer_id);
+
+CREATE INDEX
+IF NOT EXISTS subnets_customer_id_idx
+ON subnets (customer_id);
+
+CREATE INDEX
+IF NOT EXISTS ips_subnet_id_idx
+ON ips (subnet_id);
@@ -0,0 +1,11 @@
+-- a single row holding the generation of the stored assets, which is incremented after each
+-- sync so that every instance can discard its cached lookups
+CREATE TABLE
+IF NOT EXISTS sync_generation
+(
+    singleton BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (singleton),
+    generation BIGINT NOT NULL
+);
+
+INSERT INTO sync_generation (generation) VALUES (0)
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,4 @@
+-- the time the stored assets last changed, which is set along with each new generation and
+-- is null until the first sync
+ALTER TABLE sync_generation
+ADD COLUMN IF NOT EXISTS synced_at TIMESTAMPTZ;
@@ -0,0 +1,3 @@
+-- the source each contact was synced from, as recorded for customers, subnets, and IPs
+ALTER TABLE customer_contacts
+ADD COLUMN IF NOT EXISTS source TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,10 @@
+-- the state an incremental sync carries over to the next one: the high-water mark of the last
+-- stored sync and the time of the last full sync, which are null until the first incremental
+-- sync is stored, or after a rollback that requires the next sync to be a full one
+ALTER TABLE sync_generation
+ADD COLUMN IF NOT EXISTS incremental_watermark TIMESTAMPTZ,
+ADD COLUMN IF NOT EXISTS incremental_last_full TIMESTAMPTZ;
+
+-- the ID of the IP record in its source, which incremental syncs rebuild the stored IPs by
+ALTER TABLE ips
+ADD COLUMN IF NOT EXISTS record_id TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,23 @@
+-- the job of the sync that generated a quality report, and the records the sync quarantined,
+-- so that the outcome of a sync that ran as a job can be read back by its job ID
+ALTER TABLE quality_reports
+ADD COLUMN IF NOT EXISTS job_id TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX
+IF NOT EXISTS quality_reports_job_id_idx
+ON quality_reports (job_id);
+
+CREATE TABLE
+IF NOT EXISTS quarantined_records
+(
+    id SERIAL PRIMARY KEY,
+    report_id INTEGER NOT NULL,
+    FOREIGN KEY (report_id) REFERENCES quality_reports (id) ON DELETE CASCADE,
+    record_type TEXT NOT NULL,
+    record_id TEXT NOT NULL,
+    reason TEXT NOT NULL
+);
+
+CREATE INDEX
+IF NOT EXISTS quarantined_records_report_id_idx
+ON quarantined_records (report_id);
@@ -0,0 +1,165 @@
+//go:build integration
+// +build integration
+
+package inttest
+
+import (
+	"context"
+	"database/sql"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/asecurityteam/ipam-facade/pkg/assetfetcher"
+	"github.com/asecurityteam/ipam-facade/pkg/sqldb"
+	"github.com/asecurityteam/settings"
+)
+
+// The synthetic dataset has one /24 subnet per lookup target, nested in a /16 subnet for every
+// 256 of them, with a device at the first address of each /24. The subnets are numbered from
+// 10.0.0.0, and the customers are shared among them.
+const (
+	benchmarkCustomers = 1000
+
+	clearBenchmarkStatement = `DELETE FROM customers;
+DELETE FROM subnets;
+DELETE FROM ips;`
+	insertBenchmarkCustomersStatement = `INSERT INTO customers (id, resource_owner, business_unit)
+SELECT n, 'owner' || n || '@example.com', 'unit' || n
+FROM generate_series(1, $1::integer) n`
+	insertBenchmarkSubnetsStatement = `INSERT INTO subnets (id, network, location, customer_id)
+SELECT n + 1, set_masklen('10.0.0.0'::inet + n::bigint * 256, 24)::cidr, 'DC', n % $2::integer + 1
+FROM generate_series(0, $1::integer - 1) n`
+	insertBenchmarkSupernetsStatement = `INSERT INTO subnets (id, network, location)
+SELECT $1::integer + n + 1, set_masklen('10.0.0.0'::inet + n::bigint * 65536, 16)::cidr, 'DC'
+FROM generate_series(0, ($1::integer - 1) / 256) n`
+	insertBenchmarkIPsStatement = `INSERT INTO ips (ip, subnet_id, device_id)
+SELECT '10.0.0.0'::inet + n::bigint * 256 + 1, n + 1, n + 1
+FROM generate_series(0, $1::integer - 1) n`
+	analyzeBenchmarkStatement = `ANALYZE customers;
+ANALYZE subnets;
+ANALYZE ips;`
+)
+
+// BenchmarkFetchPhysicalAsset measures lookups as the number of subnets grows to a million.
+// Half of the lookups match a device and half match only a subnet. Lookups are served by the
+// subnet and IP indexes, so the time per lookup stays roughly flat across the sizes. Run it
+// with: go test -tags integration -run '^$' -bench FetchPhysicalAsset -timeout 30m ./tests/
+func BenchmarkFetchPhysicalAsset(b *testing.B) {
+	ctx := context.Background()
+	db := newBenchmarkDB(b)
+	defer func() {
+		if _, dbErr := db.Conn().ExecContext(ctx, clearBenchmarkStatement); dbErr != nil {
+			fmt.Println("Error when clearing:", dbErr)
+		}
+		if dbErr := db.Conn().Close(); dbErr != nil {
+			fmt.Println("Error when closing:", dbErr)
+		}
+	}()
+
+	fetcher := &assetfetcher.PostgresPhysicalAssetFetcher{DB: db}
+	for _, subnets := range []int{1000, 10000, 100000, 1000000} {
+		require.Nil(b, loadBenchmarkSubnets(ctx, db.Conn(), subnets))
+		b.Run(fmt.Sprintf("%d subnets", subnets), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				n := i % subnets
+				asset, err := fetcher.FetchPhysicalAsset(ctx, benchmarkIP(n, n%2 == 0))
+				if err != nil {
+					b.Fatal(err)
+				}
+				if asset.SubnetID != int64(n+1) {
+					b.Fatalf("expected subnet %d but got %d", n+1, asset.SubnetID)
+				}
+			}
+		})
+	}
+}
+
+// TestLookupIndexes verifies that the conditions of lookups are served by indexes rather than
+// by scanning the subnets and IPs.
+func TestLookupIndexes(t *testing.T) {
+	ctx := context.Background()
+	db := newBenchmarkDB(t)
+	defer func() {
+		if _, dbErr := db.Conn().ExecContext(ctx, clearBenchmarkStatement); dbErr != nil {
+			fmt.Println("Error when clearing:", dbErr)
+		}
+		if dbErr := db.Conn().Close(); dbErr != nil {
+			fmt.Println("Error when closing:", dbErr)
+		}
+	}()
+	require.Nil(t, loadBenchmarkSubnets(ctx, db.Conn(), 100000))
+
+	ip := benchmarkIP(4242, true)
+	queries := []string{
+		`EXPLAIN SELECT id FROM subnets WHERE network >>= $1::inet ORDER BY masklen(network) DESC LIMIT 1`,
+		`EXPLAIN SELECT id FROM subnets WHERE network >> $1::cidr ORDER BY masklen(network) DESC LIMIT 1`,
+		`EXPLAIN SELECT device_id FROM ips WHERE ip = $1::inet`,
+	}
+	for _, query := range queries {
+		rows, err := db.Conn().QueryContext(ctx, query, ip)
+		require.Nil(t, err)
+		var plan []string
+		for rows.Next() {
+			var line string
+			require.Nil(t, rows.Scan(&line))
+			plan = append(plan, line)
+		}
+		require.Nil(t, rows.Close())
+		require.NotContains(t, strings.Join(plan, "\n"), "Seq Scan", query)
+	}
+}
+
+func newBenchmarkDB(tb testing.TB) *sqldb.PostgresDB {
+	source, err := settings.NewEnvSource(os.Environ())
+	require.Nil(tb, err)
+	db := new(sqldb.PostgresDB)
+	require.Nil(tb, settings.NewComponent(context.Background(), source, &sqldb.PostgresComponent{}, db))
+	return db
+}
+
+// loadBenchmarkSubnets replaces the stored assets with the synthetic dataset, generated by the
+// database so that a million subnets load in seconds.
+func loadBenchmarkSubnets(ctx context.Context, db *sql.DB, subnets int) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	statements := []struct {
+		statement string
+		args      []interface{}
+	}{
+		{clearBenchmarkStatement, nil},
+		{insertBenchmarkCustomersStatement, []interface{}{benchmarkCustomers}},
+		{insertBenchmarkSubnetsStatement, []interface{}{subnets, benchmarkCustomers}},
+		{insertBenchmarkSupernetsStatement, []interface{}{subnets}},
+		{insertBenchmarkIPsStatement, []interface{}{subnets}},
+	}
+	for _, s := range statements {
+		if _, err := tx.ExecContext(ctx, s.statement, s.args...); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	_, err = db.ExecContext(ctx, analyzeBenchmarkStatement)
+	return err
+}
+
+// benchmarkIP returns an address in the nth /24 subnet: that of its device, or another one.
+func benchmarkIP(n int, device bool) string {
+	address := uint32(10)<<24 + uint32(n)*256 + 2
+	if device {
+		address--
+	}
+	ip := make(net.IP, net.IPv4len)
+	binary.BigEndian.PutUint32(ip, address)
+	return ip.String()
+}
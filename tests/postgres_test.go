@@ -13,14 +13,18 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	packr "github.com/gobuffalo/packr/v2"
 	pq "github.com/lib/pq"
 	"github.com/stretchr/testify/require"
 
+	"github.com/asecurityteam/ipam-facade/pkg/assetcache"
 	"github.com/asecurityteam/ipam-facade/pkg/assetfetcher"
 	"github.com/asecurityteam/ipam-facade/pkg/assetstorer"
+	"github.com/asecurityteam/ipam-facade/pkg/assettest"
 	"github.com/asecurityteam/ipam-facade/pkg/domain"
+	"github.com/asecurityteam/ipam-facade/pkg/qualityanalyzer"
 	"github.com/asecurityteam/ipam-facade/pkg/sqldb"
 	"github.com/asecurityteam/settings"
 )
@@ -418,6 +422,217 @@ func TestOverlappingSubnetWithDevice(t *testing.T) {
 	require.Equal(t, expected, asset)
 }
 
+// TestCustomerContacts verifies that every contact stored for the owning customer is
+// returned alongside the chosen resource owner
+func TestCustomerContacts(t *testing.T) {
+	customerID, _ := rand.Int(rand.Reader, big.NewInt(1000))
+	subnetID, _ := rand.Int(rand.Reader, big.NewInt(1000))
+
+	contacts := []domain.Contact{
+		{Type: "Technical", Name: "Alice", Email: "alice@example.com", Phone: "555-0100"},
+		{Type: "Escalation", Name: "Bob", Email: "bob@example.com", Phone: "555-0199"},
+	}
+	sourcedContacts := make([]domain.Contact, 0, len(contacts))
+	for _, contact := range contacts {
+		contact.Source = "device42"
+		sourcedContacts = append(sourcedContacts, contact)
+	}
+	ipamData := domain.IPAMData{
+		Customers: []domain.Customer{
+			{
+				ID:                strconv.FormatInt(customerID.Int64(), 10),
+				ResourceOwner:     "alice@example.com",
+				ResourceOwnerRule: "contact-type:Technical",
+				BusinessUnit:      "Example Team",
+				Contacts:          sourcedContacts,
+			},
+		},
+		Subnets: []domain.Subnet{
+			{
+				ID:         strconv.FormatInt(subnetID.Int64(), 10),
+				Network:    "5.0.0.0",
+				MaskBits:   24,
+				Location:   "Home",
+				CustomerID: strconv.FormatInt(customerID.Int64(), 10),
+			},
+		},
+	}
+
+	ctx := context.Background()
+	source, err := settings.NewEnvSource(os.Environ())
+	require.Nil(t, err)
+
+	postgresComponent := &sqldb.PostgresComponent{}
+	db := new(sqldb.PostgresDB)
+	require.Nil(t, settings.NewComponent(ctx, source, postgresComponent, db))
+	defer func() {
+		if dbErr := db.Conn().Close(); dbErr != nil {
+			fmt.Println("Error when closing:", dbErr)
+		}
+	}()
+
+	storer := &assetstorer.PostgresPhysicalAssetStorer{DB: db}
+	err = storer.StorePhysicalAssets(ctx, ipamData)
+	require.Nil(t, err)
+
+	fetcher := &assetfetcher.PostgresPhysicalAssetFetcher{DB: db}
+	asset, err := fetcher.FetchPhysicalAsset(ctx, "5.0.0.1")
+	require.Nil(t, err)
+
+	expected := domain.PhysicalAsset{
+		IP:                "5.0.0.1",
+		ResourceOwner:     "alice@example.com",
+		ResourceOwnerRule: "contact-type:Technical",
+		BusinessUnit:      "Example Team",
+		Network:           "5.0.0.0/24",
+		Location:          "Home",
+		DeviceID:          0,
+		SubnetID:          subnetID.Int64(),
+		CustomerID:        customerID.Int64(),
+		Contacts:          contacts,
+	}
+
+	require.Equal(t, expected, asset)
+
+	// the source of each contact is stored along with it, but not returned by lookups
+	rows, err := db.Conn().QueryContext(ctx, `SELECT source FROM customer_contacts WHERE customer_id = $1`, customerID.Int64())
+	require.Nil(t, err)
+	defer rows.Close()
+	sources := make([]string, 0)
+	for rows.Next() {
+		var contactSource string
+		require.Nil(t, rows.Scan(&contactSource))
+		sources = append(sources, contactSource)
+	}
+	require.Nil(t, rows.Err())
+	require.Equal(t, []string{"device42", "device42"}, sources)
+}
+
+// TestInheritedOwnership verifies that an IP address in a subnet without a customer
+// inherits the ownership of the nearest ancestor subnet that has one
+func TestInheritedOwnership(t *testing.T) {
+	ipamData := domain.IPAMData{
+		Customers: []domain.Customer{
+			{
+				ID:            "1",
+				ResourceOwner: "alice@example.com",
+				BusinessUnit:  "Example Team",
+			},
+			{
+				ID:            "2",
+				ResourceOwner: "bob@example.com",
+				BusinessUnit:  "Team Example",
+			},
+		},
+		Subnets: []domain.Subnet{
+			{
+				ID:         "1",
+				Network:    "6.0.0.0",
+				MaskBits:   16,
+				Location:   "Home",
+				CustomerID: "1",
+			},
+			{
+				ID:         "2",
+				Network:    "6.0.0.0",
+				MaskBits:   20,
+				Location:   "Home",
+				CustomerID: "2",
+			},
+			{
+				ID:       "3",
+				Network:  "6.0.1.0",
+				MaskBits: 24,
+				Location: "Home - Den",
+			},
+		},
+	}
+
+	ctx := context.Background()
+	source, err := settings.NewEnvSource(os.Environ())
+	require.Nil(t, err)
+
+	postgresComponent := &sqldb.PostgresComponent{}
+	db := new(sqldb.PostgresDB)
+	require.Nil(t, settings.NewComponent(ctx, source, postgresComponent, db))
+	defer func() {
+		if dbErr := db.Conn().Close(); dbErr != nil {
+			fmt.Println("Error when closing:", dbErr)
+		}
+	}()
+
+	storer := &assetstorer.PostgresPhysicalAssetStorer{DB: db}
+	err = storer.StorePhysicalAssets(ctx, ipamData)
+	require.Nil(t, err)
+
+	fetcher := &assetfetcher.PostgresPhysicalAssetFetcher{DB: db, InheritOwnership: true}
+	asset, err := fetcher.FetchPhysicalAsset(ctx, "6.0.1.1")
+	require.Nil(t, err)
+
+	expected := domain.PhysicalAsset{
+		IP:                    "6.0.1.1",
+		ResourceOwner:         "bob@example.com",
+		BusinessUnit:          "Team Example",
+		Network:               "6.0.1.0/24",
+		Location:              "Home - Den",
+		DeviceID:              0,
+		SubnetID:              3,
+		CustomerID:            2,
+		Contacts:              []domain.Contact{},
+		InheritedFields:       []string{"resourceOwner", "businessUnit", "customerID", "contacts"},
+		InheritedFromSubnetID: 2,
+	}
+
+	require.Equal(t, expected, asset)
+}
+
+func TestQualityReport(t *testing.T) {
+	findings := []domain.QualityFinding{
+		{Category: domain.QualityCustomerEmptyOwner, RecordType: domain.QualityRecordCustomer, RecordID: "1", Detail: "customer has no resource owner"},
+		{Category: domain.QualitySubnetWithoutCustomer, RecordType: domain.QualityRecordSubnet, RecordID: "2", Detail: "subnet 7.0.0.0/24 has no customer"},
+		{Category: domain.QualitySubnetWithoutCustomer, RecordType: domain.QualityRecordSubnet, RecordID: "3", Detail: "subnet 7.0.1.0/24 has no customer"},
+	}
+
+	ctx := context.Background()
+	source, err := settings.NewEnvSource(os.Environ())
+	require.Nil(t, err)
+
+	postgresComponent := &sqldb.PostgresComponent{}
+	db := new(sqldb.PostgresDB)
+	require.Nil(t, settings.NewComponent(ctx, source, postgresComponent, db))
+	defer func() {
+		if dbErr := db.Conn().Close(); dbErr != nil {
+			fmt.Println("Error when closing:", dbErr)
+		}
+	}()
+
+	store := &qualityanalyzer.PostgresQualityReportStore{DB: db}
+	quarantined := []domain.QuarantinedRecord{
+		{RecordType: domain.QualityRecordSubnet, RecordID: "4", Reason: "invalid network"},
+	}
+	require.Nil(t, store.StoreQualityReport(ctx, domain.QualityReport{JobID: "quality-job-1", Findings: findings[:1], Quarantined: quarantined}))
+	// the most recent report is the one served
+	require.Nil(t, store.StoreQualityReport(ctx, domain.QualityReport{JobID: "quality-job-2", Findings: findings}))
+
+	report, err := store.FetchQualityReport(ctx, "")
+	require.Nil(t, err)
+	require.False(t, report.GeneratedAt.IsZero())
+	require.Equal(t, findings, report.Findings)
+
+	report, err = store.FetchQualityReport(ctx, domain.QualitySubnetWithoutCustomer)
+	require.Nil(t, err)
+	require.Equal(t, findings[1:], report.Findings)
+
+	// the reports of earlier jobs are kept with their quarantined records
+	report, err = store.FetchSyncJobReport(ctx, "quality-job-1")
+	require.Nil(t, err)
+	require.Equal(t, findings[:1], report.Findings)
+	require.Equal(t, quarantined, report.Quarantined)
+
+	_, err = store.FetchSyncJobReport(ctx, "quality-job-0")
+	require.Equal(t, domain.SyncJobNotFound{JobID: "quality-job-0"}, err)
+}
+
 func TestFetchSubnet(t *testing.T) {
 	customerID1, _ := rand.Int(rand.Reader, big.NewInt(1000))
 	customerID2, _ := rand.Int(rand.Reader, big.NewInt(1000))
@@ -713,6 +928,326 @@ func TestFetchIPs(t *testing.T) {
 	require.Equal(t, expected, ips)
 }
 
+// TestMigrationsRerunnable verifies that migrating an already migrated database applies
+// nothing, and that every migration is recorded as applied
+func TestMigrationsRerunnable(t *testing.T) {
+	ctx := context.Background()
+	source, err := settings.NewEnvSource(os.Environ())
+	require.Nil(t, err)
+
+	postgresComponent := &sqldb.PostgresComponent{}
+	db := new(sqldb.PostgresDB)
+	require.Nil(t, settings.NewComponent(ctx, source, postgresComponent, db))
+
+	defer func() {
+		if dbErr := db.Conn().Close(); dbErr != nil {
+			fmt.Println("Error when closing:", dbErr)
+		}
+	}()
+
+	applied, err := db.Migrate(ctx)
+	require.Nil(t, err)
+	require.Empty(t, applied)
+
+	statuses, err := db.MigrationStatus(ctx)
+	require.Nil(t, err)
+	require.NotEmpty(t, statuses)
+	for _, status := range statuses {
+		require.True(t, status.Applied, "migration %d should be applied", status.Version)
+		require.False(t, status.Modified)
+	}
+}
+
+// TestReadReplica verifies that lookups use the read pool when it is healthy. The primary
+// stands in for the replica, as a server that is not in recovery never lags.
+func TestReadReplica(t *testing.T) {
+	ctx := context.Background()
+	env, err := settings.NewEnvSource(os.Environ())
+	require.Nil(t, err)
+	source := settings.MultiSource{
+		settings.NewMapSource(map[string]interface{}{
+			"postgres": map[string]interface{}{"readhostname": os.Getenv("POSTGRES_HOSTNAME")},
+		}),
+		env,
+	}
+
+	postgresComponent := &sqldb.PostgresComponent{}
+	db := new(sqldb.PostgresDB)
+	require.Nil(t, settings.NewComponent(ctx, source, postgresComponent, db))
+
+	defer func() {
+		if dbErr := db.Conn().Close(); dbErr != nil {
+			fmt.Println("Error when closing:", dbErr)
+		}
+	}()
+	require.NotEqual(t, db.Conn(), db.ReadConn())
+
+	storer := &assetstorer.PostgresPhysicalAssetStorer{DB: db}
+	require.Nil(t, storer.StorePhysicalAssets(ctx, domain.IPAMData{
+		Subnets: []domain.Subnet{{ID: "1", Network: "10.0.0.0", MaskBits: 8, Location: "Home"}},
+	}))
+	fetcher := &assetfetcher.PostgresPhysicalAssetFetcher{DB: db}
+	asset, err := fetcher.FetchPhysicalAsset(ctx, "10.0.0.1")
+	require.Nil(t, err)
+	require.Equal(t, "10.0.0.0/8", asset.Network)
+}
+
+// TestPostgresContract runs the storage backend contract tests, which the in-memory
+// backend also runs, against PostgreSQL
+func TestPostgresContract(t *testing.T) {
+	ctx := context.Background()
+	source, err := settings.NewEnvSource(os.Environ())
+	require.Nil(t, err)
+
+	postgresComponent := &sqldb.PostgresComponent{}
+	db := new(sqldb.PostgresDB)
+	require.Nil(t, settings.NewComponent(ctx, source, postgresComponent, db))
+
+	defer func() {
+		if dbErr := db.Conn().Close(); dbErr != nil {
+			fmt.Println("Error when closing:", dbErr)
+		}
+	}()
+
+	assettest.Run(t, func(t *testing.T, inheritOwnership bool) assettest.Backend {
+		return assettest.Backend{
+			Storer:   &assetstorer.PostgresPhysicalAssetStorer{DB: db},
+			Fetcher:  &assetfetcher.PostgresPhysicalAssetFetcher{DB: db, InheritOwnership: inheritOwnership},
+			Graph:    &assetfetcher.PostgresAssetGraphFetcher{DB: db},
+			Exporter: &assetfetcher.PostgresAssetExporter{DB: db},
+		}
+	})
+}
+
+// TestSwapContract runs the storage backend contract tests against PostgreSQL with syncs
+// that swap in freshly loaded tables
+func TestSwapContract(t *testing.T) {
+	ctx := context.Background()
+	source, err := settings.NewEnvSource(os.Environ())
+	require.Nil(t, err)
+
+	postgresComponent := &sqldb.PostgresComponent{}
+	db := new(sqldb.PostgresDB)
+	require.Nil(t, settings.NewComponent(ctx, source, postgresComponent, db))
+
+	defer func() {
+		if dbErr := db.Conn().Close(); dbErr != nil {
+			fmt.Println("Error when closing:", dbErr)
+		}
+	}()
+
+	assettest.Run(t, func(t *testing.T, inheritOwnership bool) assettest.Backend {
+		return assettest.Backend{
+			Storer:   &assetstorer.PostgresSwapPhysicalAssetStorer{DB: db, LockTimeout: time.Second},
+			Fetcher:  &assetfetcher.PostgresPhysicalAssetFetcher{DB: db, InheritOwnership: inheritOwnership},
+			Graph:    &assetfetcher.PostgresAssetGraphFetcher{DB: db},
+			Exporter: &assetfetcher.PostgresAssetExporter{DB: db},
+		}
+	})
+}
+
+// TestSwapRollback verifies that rolling back restores the assets replaced by the last
+// swap, and that rolling back again restores those of the last swap.
+func TestSwapRollback(t *testing.T) {
+	ctx := context.Background()
+	source, err := settings.NewEnvSource(os.Environ())
+	require.Nil(t, err)
+
+	postgresComponent := &sqldb.PostgresComponent{}
+	db := new(sqldb.PostgresDB)
+	require.Nil(t, settings.NewComponent(ctx, source, postgresComponent, db))
+
+	defer func() {
+		if dbErr := db.Conn().Close(); dbErr != nil {
+			fmt.Println("Error when closing:", dbErr)
+		}
+	}()
+
+	_, err = db.Conn().ExecContext(ctx, `DROP TABLE IF EXISTS ips_previous, subnets_previous, customer_contacts_previous, customers_previous`)
+	require.Nil(t, err)
+	storer := &assetstorer.PostgresSwapPhysicalAssetStorer{DB: db}
+	require.Equal(t, assetstorer.ErrNoPreviousGeneration, storer.Rollback(ctx))
+
+	fetcher := &assetfetcher.PostgresPhysicalAssetFetcher{DB: db}
+	locationOf := func() string {
+		asset, fetchErr := fetcher.FetchPhysicalAsset(ctx, "10.0.0.1")
+		require.Nil(t, fetchErr)
+		return asset.Location
+	}
+	for _, location := range []string{"Old", "New"} {
+		require.Nil(t, storer.StorePhysicalAssets(ctx, domain.IPAMData{
+			Customers: []domain.Customer{{ID: "1", ResourceOwner: "alice@example.com", BusinessUnit: "Security",
+				Contacts: []domain.Contact{{Type: "Technical", Name: "Alice", Email: "alice@example.com"}}}},
+			Subnets: []domain.Subnet{{ID: "1", Network: "10.0.0.0", MaskBits: 8, Location: location, CustomerID: "1"}},
+			Devices: []domain.Device{{ID: "1", IP: "10.0.0.1", SubnetID: "1"}},
+		}))
+	}
+	require.Equal(t, "New", locationOf())
+
+	require.Nil(t, storer.Rollback(ctx))
+	require.Equal(t, "Old", locationOf())
+	require.Nil(t, storer.Rollback(ctx))
+	require.Equal(t, "New", locationOf())
+
+	// the serial columns of the swapped in tables keep working with the replace sync mode
+	replacer := &assetstorer.PostgresPhysicalAssetStorer{DB: db}
+	require.Nil(t, replacer.StorePhysicalAssets(ctx, domain.IPAMData{
+		Customers: []domain.Customer{{ID: "1", ResourceOwner: "alice@example.com", BusinessUnit: "Security",
+			Contacts: []domain.Contact{{Type: "Technical", Name: "Alice", Email: "alice@example.com"}}}},
+		Subnets: []domain.Subnet{{ID: "1", Network: "10.0.0.0", MaskBits: 8, Location: "Replaced", CustomerID: "1"}},
+		Devices: []domain.Device{{ID: "1", IP: "10.0.0.1", SubnetID: "1"}},
+	}))
+	require.Equal(t, "Replaced", locationOf())
+}
+
+// TestSyncGeneration verifies that the sync generation created by the migrations is
+// incremented along with the assets by every sync.
+func TestSyncGeneration(t *testing.T) {
+	ctx := context.Background()
+	source, err := settings.NewEnvSource(os.Environ())
+	require.Nil(t, err)
+
+	postgresComponent := &sqldb.PostgresComponent{}
+	db := new(sqldb.PostgresDB)
+	require.Nil(t, settings.NewComponent(ctx, source, postgresComponent, db))
+
+	defer func() {
+		if dbErr := db.Conn().Close(); dbErr != nil {
+			fmt.Println("Error when closing:", dbErr)
+		}
+	}()
+
+	store := &assetcache.PostgresSyncGenerationStore{DB: db}
+	generation, err := store.FetchSyncGeneration(ctx)
+	require.Nil(t, err)
+
+	ipamData := domain.IPAMData{
+		Customers: []domain.Customer{{ID: "1", ResourceOwner: "owner@atlassian.com", BusinessUnit: "Security"}},
+		Subnets:   []domain.Subnet{{ID: "1", Network: "10.0.0.0", MaskBits: 8, CustomerID: "1"}},
+	}
+	storers := []domain.PhysicalAssetStorer{
+		&assetstorer.PostgresPhysicalAssetStorer{DB: db},
+		&assetstorer.PostgresSwapPhysicalAssetStorer{DB: db},
+	}
+	for _, storer := range storers {
+		require.Nil(t, storer.StorePhysicalAssets(ctx, ipamData))
+		generation++
+		fetched, fetchErr := store.FetchSyncGeneration(ctx)
+		require.Nil(t, fetchErr)
+		require.Equal(t, generation, fetched)
+	}
+
+	// a sync that fails to store its assets leaves the generation unchanged
+	invalid := domain.IPAMData{Devices: []domain.Device{{IP: "10.0.0.1", SubnetID: "404"}}}
+	require.NotNil(t, storers[0].StorePhysicalAssets(ctx, invalid))
+	fetched, err := store.FetchSyncGeneration(ctx)
+	require.Nil(t, err)
+	require.Equal(t, generation, fetched)
+
+	// exports carry the generation and the time it started
+	recorder := &snapshotRecorder{}
+	exporter := &assetfetcher.PostgresAssetExporter{DB: db}
+	require.Nil(t, exporter.ExportAssets(ctx, domain.ExportCustomers, recorder))
+	require.Equal(t, generation, recorder.snapshot.Generation)
+	require.WithinDuration(t, time.Now(), recorder.snapshot.SyncedAt, time.Minute)
+}
+
+// TestIncrementalSyncState verifies that the state of incremental syncs is stored next to
+// the sync generation, that the records of a source are rebuilt from the stored assets, and
+// that a rollback resets the state so that the next sync is a full one.
+func TestIncrementalSyncState(t *testing.T) {
+	ctx := context.Background()
+	source, err := settings.NewEnvSource(os.Environ())
+	require.Nil(t, err)
+
+	postgresComponent := &sqldb.PostgresComponent{}
+	db := new(sqldb.PostgresDB)
+	require.Nil(t, settings.NewComponent(ctx, source, postgresComponent, db))
+
+	defer func() {
+		if dbErr := db.Conn().Close(); dbErr != nil {
+			fmt.Println("Error when closing:", dbErr)
+		}
+	}()
+
+	storer := &assetstorer.PostgresSwapPhysicalAssetStorer{DB: db}
+	ipamData := domain.IPAMData{
+		Subnets: []domain.Subnet{
+			{ID: "1", Network: "10.0.0.0", MaskBits: 24, Location: "Austin", CustomerID: "0", Source: "device42"},
+			{ID: "2", Network: "10.0.1.0", MaskBits: 24, CustomerID: "0", Source: "file"},
+		},
+		Devices: []domain.Device{
+			{ID: "9", IP: "10.0.0.1", SubnetID: "1", RecordID: "4", Source: "device42"},
+			{ID: "0", IP: "10.0.1.1", SubnetID: "2", Source: "file"},
+		},
+	}
+	require.Nil(t, storer.StorePhysicalAssets(ctx, ipamData))
+	require.Nil(t, storer.StorePhysicalAssets(ctx, ipamData))
+
+	state := domain.IncrementalSyncState{
+		Watermark: time.Date(2024, 3, 1, 12, 55, 0, 0, time.UTC),
+		LastFull:  time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+	require.Nil(t, db.StoreIncrementalSyncState(ctx, state))
+	fetched, err := db.FetchIncrementalSyncState(ctx)
+	require.Nil(t, err)
+	require.True(t, state.Watermark.Equal(fetched.Watermark))
+	require.True(t, state.LastFull.Equal(fetched.LastFull))
+
+	stored, err := db.FetchStoredSource(ctx, "device42")
+	require.Nil(t, err)
+	require.Equal(t, []domain.Subnet{{ID: "1", Network: "10.0.0.0", MaskBits: 24, Location: "Austin", CustomerID: "0"}}, stored.Subnets)
+	require.Equal(t, []domain.Device{{ID: "9", IP: "10.0.0.1", SubnetID: "1", RecordID: "4"}}, stored.Devices)
+
+	require.Nil(t, storer.Rollback(ctx))
+	fetched, err = db.FetchIncrementalSyncState(ctx)
+	require.Nil(t, err)
+	require.Equal(t, domain.IncrementalSyncState{}, fetched)
+}
+
+// snapshotRecorder records the snapshot of an export, discarding its records.
+type snapshotRecorder struct {
+	snapshot domain.AssetSnapshot
+}
+
+func (r *snapshotRecorder) WriteSnapshot(snapshot domain.AssetSnapshot) error {
+	r.snapshot = snapshot
+	return nil
+}
+
+func (*snapshotRecorder) WriteCustomer(domain.ExportedCustomer) error { return nil }
+
+func (*snapshotRecorder) WriteSubnet(domain.ExportedSubnet) error { return nil }
+
+func (*snapshotRecorder) WriteIP(domain.ExportedIP) error { return nil }
+
+func TestSyncLock(t *testing.T) {
+	ctx := context.Background()
+	source, err := settings.NewEnvSource(os.Environ())
+	require.Nil(t, err)
+
+	postgresComponent := &sqldb.PostgresComponent{}
+	db := new(sqldb.PostgresDB)
+	require.Nil(t, settings.NewComponent(ctx, source, postgresComponent, db))
+
+	defer func() {
+		if dbErr := db.Conn().Close(); dbErr != nil {
+			fmt.Println("Error when closing:", dbErr)
+		}
+	}()
+
+	// each lock is taken on its own connection, as it would be by another instance
+	unlock, err := db.LockSync(ctx)
+	require.Nil(t, err)
+	_, err = db.LockSync(ctx)
+	require.Equal(t, domain.SyncInProgress{}, err)
+
+	unlock()
+	unlock, err = db.LockSync(ctx)
+	require.Nil(t, err)
+	unlock()
+}
+
 // returns a raw sql.DB object, rather than the storage.DB abstraction, so
 // we can perform some Postgres cleanup/prep/checks that are test-specific
 func connectToDB(dbname string) (*sql.DB, error) {